@@ -8,14 +8,18 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/briancain/go-tetris/internal/server/config"
 	"github.com/briancain/go-tetris/internal/server/handlers"
 	"github.com/briancain/go-tetris/internal/server/logger"
+	"github.com/briancain/go-tetris/internal/server/metrics"
 	"github.com/briancain/go-tetris/internal/server/middleware"
 	"github.com/briancain/go-tetris/internal/server/services"
 	"github.com/briancain/go-tetris/internal/server/storage"
 	"github.com/briancain/go-tetris/internal/server/storage/memory"
 	"github.com/briancain/go-tetris/internal/server/storage/redis"
+	"github.com/briancain/go-tetris/internal/server/tracing"
 )
 
 func main() {
@@ -28,16 +32,27 @@ func main() {
 
 	logger.Logger.Info("Starting Tetris multiplayer server", "config", cfg)
 
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.TracingExporter, cfg.TracingEndpoint, cfg.TracingSampleRatio)
+	if err != nil {
+		logger.Logger.Error("Failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
+
 	// Initialize storage based on configuration
 	var gameStore storage.GameStore
 	var queueStore storage.QueueStore
+	var playerStore storage.PlayerStore
+	var accountStore storage.AccountStore
+	var roomStore storage.RoomStore
+	var replayStore storage.ReplayStore
+	var historyStore storage.MatchHistoryStore
+	var banStore storage.BanStore
 	var storageHealth storage.HealthChecker
-
-	playerStore := memory.NewPlayerStore()
+	var backplane *redis.Backplane
 
 	if cfg.RedisURL != "" {
-		// Use Redis for game and queue storage
-		logger.Logger.Info("Storage mode: Redis", "redis_url", cfg.RedisURL, "components", "games,queues")
+		// Use Redis for player, account, game, queue, room, replay, match history and ban storage
+		logger.Logger.Info("Storage mode: Redis", "redis_url", cfg.RedisURL, "components", "games,queues,players,accounts,rooms,replays,history,bans")
 
 		redisClient, err := redis.NewClient(cfg.RedisURL)
 		if err != nil {
@@ -51,52 +66,167 @@ func main() {
 			os.Exit(1)
 		}
 
+		playerStore = redis.NewPlayerStore(redisClient)
+		accountStore = redis.NewAccountStore(redisClient)
 		gameStore = redis.NewGameStore(redisClient)
 		queueStore = redis.NewQueueStore(redisClient)
+		roomStore = redis.NewRoomStore(redisClient)
+		redisReplayStore := redis.NewReplayStore(redisClient)
+		redisReplayStore.SetRetention(cfg.ReplayRetention)
+		replayStore = redisReplayStore
+		historyStore = redis.NewMatchHistoryStore(redisClient)
+		banStore = redis.NewBanStore(redisClient)
 		storageHealth = redisClient
 
+		// Route WebSocket messages to players connected to other instances
+		backplane = redis.NewBackplane(redisClient)
+
 		logger.Logger.Info("Redis storage initialized successfully")
 	} else {
 		// Use in-memory storage
-		logger.Logger.Info("Storage mode: In-Memory", "components", "games,queues,players")
+		logger.Logger.Info("Storage mode: In-Memory", "components", "games,queues,players,accounts,rooms,replays,history,bans")
+		memoryPlayerStore := memory.NewPlayerStore()
+		playerStore = memoryPlayerStore
+		accountStore = memory.NewAccountStore()
 		gameStore = memory.NewGameStore()
 		queueStore = memory.NewQueueStore()
-		storageHealth = playerStore
+		roomStore = memory.NewRoomStore()
+		memoryReplayStore := memory.NewReplayStore()
+		memoryReplayStore.SetRetention(cfg.ReplayRetention)
+		replayStore = memoryReplayStore
+		historyStore = memory.NewMatchHistoryStore()
+		banStore = memory.NewBanStore()
+		storageHealth = memoryPlayerStore
 	}
 
 	// Initialize services
-	authService := services.NewAuthService(playerStore)
+	authService := services.NewAuthService(playerStore, accountStore)
+	if cfg.TokenSigningKey == "" {
+		logger.Logger.Warn("No token signing key configured; sessions won't survive a restart or work across instances")
+	}
+	authService.SetTokenSigner(services.NewTokenSigner([]byte(cfg.TokenSigningKey), cfg.AccessTokenTTL, cfg.RefreshTokenTTL))
+	authService.SetBanStore(banStore)
 	wsManager := services.NewWebSocketManager()
+	if backplane != nil {
+		if err := wsManager.SetBackplane(backplane); err != nil {
+			logger.Logger.Error("Failed to subscribe to backplane", "error", err)
+			os.Exit(1)
+		}
+		logger.Logger.Info("Cross-instance routing enabled", "instance_id", backplane.InstanceID())
+	}
 	gameManager := services.NewGameManager(gameStore, playerStore, wsManager)
-	matchmakingService := services.NewMatchmakingService(playerStore, gameStore, queueStore, gameManager)
+	gameManager.SetReplayStore(replayStore)
+	gameManager.SetMatchHistoryStore(historyStore)
+	matchmakingService := services.NewMatchmakingService(playerStore, gameStore, queueStore, roomStore, gameManager)
+	matchmakingService.Start()
+	janitor := services.NewJanitor(authService, gameManager, matchmakingService, services.JanitorSettings{
+		Interval:              cfg.JanitorInterval,
+		IdlePlayerTimeout:     cfg.IdlePlayerTimeout,
+		IdleQueueTimeout:      cfg.IdleQueueTimeout,
+		StaleGameTimeout:      cfg.StaleGameTimeout,
+		FinishedGameRetention: cfg.FinishedGameRetention,
+	})
+	if cfg.JanitorInterval > 0 {
+		janitor.Start()
+	} else {
+		logger.Logger.Info("Janitor disabled; idle players and finished games won't be reclaimed")
+	}
 
 	// Initialize middleware
 	authMiddleware := middleware.NewAuthMiddleware(authService)
 	corsOrigins := middleware.ParseCORSOrigins(cfg.CORSOrigins)
 	corsMiddleware := middleware.CORS(corsOrigins)
+	apiLimiter := middleware.NewRateLimiter("api", cfg.RateLimit, cfg.RateLimitBurst)
+	authLimiter := middleware.NewRateLimiter("auth", cfg.AuthRateLimit, cfg.AuthRateLimitBurst)
+	trustedProxies, err := middleware.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		logger.Logger.Error("Invalid trusted proxies", "error", err)
+		os.Exit(1)
+	}
+	apiLimiter.SetTrustedProxies(trustedProxies)
+	authLimiter.SetTrustedProxies(trustedProxies)
+	if backplane != nil && len(trustedProxies) == 0 && (cfg.RateLimit > 0 || cfg.AuthRateLimit > 0) {
+		logger.Logger.Warn("No trusted proxies configured; behind a load balancer every client shares one rate limit bucket")
+	}
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
 	matchmakingHandler := handlers.NewMatchmakingHandler(matchmakingService)
+	roomHandler := handlers.NewRoomHandler(matchmakingService)
 	leaderboardHandler := handlers.NewLeaderboardHandler(playerStore)
+	playerHandler := handlers.NewPlayerHandler(playerStore, historyStore)
+	gameHandler := handlers.NewGameHandler(gameStore, replayStore)
 	wsHandler := handlers.NewWebSocketHandler(wsManager, authService, gameManager)
+	wsHandler.SetReconnectGracePeriod(cfg.ReconnectGracePeriod)
+	wsHandler.SetMessageLimits(cfg.WSMessageRate, cfg.WSMessageBurst, cfg.WSMaxMessageSize)
 	healthHandler := handlers.NewHealthHandler(wsManager, storageHealth)
+	healthHandler.SetRateLimiters(apiLimiter, authLimiter)
+	healthHandler.SetGameSources(gameStore, matchmakingService)
+	healthHandler.SetGameManager(gameManager)
+	prometheus.MustRegister(healthHandler)
+	adminHandler := handlers.NewAdminHandler(wsManager, wsHandler, gameManager, matchmakingService, playerStore, gameStore, banStore)
+
+	// Credential endpoints get a stricter per-IP limit to stop username
+	// squatting and password guessing. Other public endpoints are limited
+	// per IP and authenticated ones per player.
+	credentials := func(h http.HandlerFunc) http.HandlerFunc {
+		return corsMiddleware(middleware.RequestLogging(authLimiter.ByIP(h)))
+	}
+	public := func(h http.HandlerFunc) http.HandlerFunc {
+		return corsMiddleware(middleware.RequestLogging(apiLimiter.ByIP(h)))
+	}
+	authenticated := func(h http.HandlerFunc) http.HandlerFunc {
+		return corsMiddleware(middleware.RequestLogging(authMiddleware.RequireAuth(apiLimiter.ByPlayer(h))))
+	}
+	// The admin API isn't meant for browsers, so it gets no CORS headers
+	requireAdmin := middleware.RequireAdmin(cfg.AdminToken)
+	admin := func(h http.HandlerFunc) http.HandlerFunc {
+		return middleware.RequestLogging(apiLimiter.ByIP(requireAdmin(h)))
+	}
 
-	// Setup routes with logging and CORS middleware
-	http.HandleFunc("/api/auth/login", corsMiddleware(middleware.RequestLogging(authHandler.Login)))
-	http.HandleFunc("/api/auth/logout", corsMiddleware(middleware.RequestLogging(authMiddleware.RequireAuth(authHandler.Logout))))
+	// Setup routes with logging, CORS and rate limiting middleware
+	http.HandleFunc("/api/auth/login", credentials(authHandler.Login))
+	http.HandleFunc("/api/auth/refresh", credentials(authHandler.Refresh))
+	http.HandleFunc("/api/auth/register", credentials(authHandler.Register))
+	http.HandleFunc("/api/auth/apikey", authenticated(authHandler.CreateAPIKey))
+	http.HandleFunc("/api/auth/logout", authenticated(authHandler.Logout))
 
-	http.HandleFunc("/api/matchmaking/queue", corsMiddleware(middleware.RequestLogging(authMiddleware.RequireAuth(matchmakingHandler.JoinQueue))))
-	http.HandleFunc("/api/matchmaking/queue/leave", corsMiddleware(middleware.RequestLogging(authMiddleware.RequireAuth(matchmakingHandler.LeaveQueue))))
-	http.HandleFunc("/api/matchmaking/status", corsMiddleware(middleware.RequestLogging(authMiddleware.RequireAuth(matchmakingHandler.GetQueueStatus))))
+	http.HandleFunc("/api/matchmaking/queue", authenticated(matchmakingHandler.JoinQueue))
+	http.HandleFunc("/api/matchmaking/queue/leave", authenticated(matchmakingHandler.LeaveQueue))
+	http.HandleFunc("/api/matchmaking/status", authenticated(matchmakingHandler.GetQueueStatus))
 
-	http.HandleFunc("/api/leaderboard", corsMiddleware(middleware.RequestLogging(leaderboardHandler.GetLeaderboard)))
+	http.HandleFunc("/api/rooms", authenticated(roomHandler.CreateRoom))
+	http.HandleFunc("/api/rooms/join", authenticated(roomHandler.JoinRoom))
+	http.HandleFunc("/api/rooms/start", authenticated(roomHandler.StartRoom))
+	http.HandleFunc("/api/rooms/close", authenticated(roomHandler.CloseRoom))
 
-	http.HandleFunc("/ws", wsHandler.HandleWebSocket)
+	http.HandleFunc("/api/leaderboard", public(leaderboardHandler.GetLeaderboard))
+	http.HandleFunc("/api/players/{username}", public(playerHandler.GetProfile))
+	http.HandleFunc("/api/players/{username}/games", public(playerHandler.GetGames))
+	http.HandleFunc("/api/games/live", authenticated(gameHandler.ListLiveGames))
+	http.HandleFunc("/api/games/{id}/replay", authenticated(gameHandler.GetReplay))
 
-	// Health check and metrics endpoints
+	http.HandleFunc("/ws", apiLimiter.ByIP(wsHandler.HandleWebSocket))
+
+	if cfg.AdminToken != "" {
+		http.HandleFunc("/admin/players", admin(adminHandler.ListPlayers))
+		http.HandleFunc("/admin/players/{id}/kick", admin(adminHandler.KickPlayer))
+		http.HandleFunc("/admin/games", admin(adminHandler.ListGames))
+		http.HandleFunc("/admin/games/{id}", admin(adminHandler.GetGame))
+		http.HandleFunc("/admin/games/{id}/end", admin(adminHandler.EndGame))
+		http.HandleFunc("/admin/games/{id}/cancel", admin(adminHandler.CancelGame))
+		http.HandleFunc("/admin/bans", admin(adminHandler.Bans))
+		http.HandleFunc("/admin/bans/{username}", admin(adminHandler.Unban))
+		http.HandleFunc("/admin/queue/drain", admin(adminHandler.DrainQueue))
+		http.HandleFunc("/admin/announcements", admin(adminHandler.Announce))
+	} else {
+		logger.Logger.Info("Admin API disabled; set ADMIN_TOKEN to enable it")
+	}
+
+	// Health check, Prometheus metrics and a JSON summary of the key stats
 	http.HandleFunc("/health", healthHandler.Health)
-	http.HandleFunc("/metrics", healthHandler.Metrics)
+	http.Handle("/metrics", metrics.Handler())
+	http.HandleFunc("/stats", healthHandler.Metrics)
 
 	// Create HTTP server
 	port := ":" + cfg.Port
@@ -110,6 +240,7 @@ func main() {
 			"port", port,
 			"redis_url", cfg.RedisURL,
 			"server_url", cfg.ServerURL,
+			"reconnect_grace", cfg.ReconnectGracePeriod.String(),
 		)
 
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -126,13 +257,25 @@ func main() {
 	<-quit
 	logger.Logger.Info("Shutdown signal received, starting graceful shutdown...")
 
+	// Let games in progress finish before closing their connections
+	if cfg.DrainTimeout > 0 {
+		drain(matchmakingService, gameManager, cfg.DrainTimeout, quit)
+	}
+
 	// Create shutdown context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	// Shutdown WebSocket connections
 	logger.Logger.Info("Closing WebSocket connections...")
+	matchmakingService.Stop()
+	janitor.Stop()
 	wsManager.Shutdown()
+	if backplane != nil {
+		if err := backplane.Close(); err != nil {
+			logger.Logger.Warn("Failed to close backplane", "error", err)
+		}
+	}
 
 	// Shutdown HTTP server
 	logger.Logger.Info("Shutting down HTTP server...")
@@ -141,5 +284,41 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Flush any buffered spans
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Logger.Warn("Failed to flush traces", "error", err)
+	}
+
 	logger.Logger.Info("Server gracefully stopped")
 }
+
+// drainMessage is shown to players when the server starts draining
+const drainMessage = "The server is restarting. Games in progress will finish, but new matches are paused."
+
+// drain stops new matches and waits for this instance's games to finish,
+// giving up after timeout or on a second shutdown signal. Health checks fail
+// meanwhile, so load balancers send new players elsewhere.
+func drain(matchmaking *services.MatchmakingService, gameManager *services.GameManager, timeout time.Duration, quit <-chan os.Signal) {
+	logger.Logger.Info("Draining before shutdown", "timeout", timeout.String())
+	matchmaking.Drain(drainMessage)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-quit:
+			logger.Logger.Info("Second shutdown signal received, skipping drain")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if err := gameManager.WaitForGames(ctx); err != nil {
+		logger.Logger.Warn("Shutting down with games still in progress",
+			"games", gameManager.LocalGameCount(),
+			"error", err,
+		)
+		return
+	}
+	logger.Logger.Info("All games finished")
+}
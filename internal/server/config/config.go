@@ -3,15 +3,80 @@ package config
 import (
 	"flag"
 	"fmt"
+	"log/slog"
+	"net"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
+// minTokenSigningKeyLength is the shortest signing key accepted, matching
+// the size of the HMAC-SHA256 output
+const minTokenSigningKeyLength = 32
+
+// minAdminTokenLength is the shortest admin API token accepted
+const minAdminTokenLength = 32
+
 type Config struct {
 	Port        string
 	RedisURL    string
 	ServerURL   string
 	CORSOrigins string
+
+	// ReconnectGracePeriod is how long a disconnected player's game is
+	// paused and their session kept before the match is forfeited
+	ReconnectGracePeriod time.Duration
+
+	// DrainTimeout is how long a shutting down server waits for games in
+	// progress to finish; zero shuts down immediately
+	DrainTimeout time.Duration
+
+	// The janitor sweeps every JanitorInterval, removing guests and queued
+	// players idle past their timeouts, cancelling games stale past
+	// StaleGameTimeout and deleting games finished longer than
+	// FinishedGameRetention ago. A zero interval disables it.
+	JanitorInterval       time.Duration
+	IdlePlayerTimeout     time.Duration
+	IdleQueueTimeout      time.Duration
+	StaleGameTimeout      time.Duration
+	FinishedGameRetention time.Duration
+
+	// ReplayRetention is how long a replay is kept after it was last
+	// written. Redis expires replays itself; in memory the janitor deletes them.
+	ReplayRetention time.Duration
+
+	// TokenSigningKey signs session tokens. Every instance must share it;
+	// when empty a random key is used and tokens don't survive a restart.
+	TokenSigningKey string
+	AccessTokenTTL  time.Duration // How long a session token is accepted
+	RefreshTokenTTL time.Duration // How long a session can be refreshed
+
+	// HTTP rate limits in requests per second, per IP for public endpoints
+	// and per player for authenticated ones. Zero disables a limit.
+	RateLimit          float64
+	RateLimitBurst     int
+	AuthRateLimit      float64 // Stricter per-IP limit for login and registration
+	AuthRateLimitBurst int
+
+	// TrustedProxies is a comma-separated list of CIDR ranges of the load
+	// balancers in front of the server. Requests from them are limited by
+	// the client IP in X-Forwarded-For rather than the proxy's own address.
+	TrustedProxies string
+
+	// Per-connection limits on incoming WebSocket messages
+	WSMessageRate    float64 // Messages per second; zero disables
+	WSMessageBurst   int
+	WSMaxMessageSize int64 // Bytes; zero disables
+
+	// TracingExporter sends traces to "stdout" or an "otlp" collector at
+	// TracingEndpoint; empty disables tracing
+	TracingExporter    string
+	TracingEndpoint    string
+	TracingSampleRatio float64 // Share of traces kept, from 0 to 1
+
+	// AdminToken is the bearer token for the /admin API; empty disables it
+	AdminToken string
 }
 
 func Load() (*Config, error) {
@@ -19,19 +84,146 @@ func Load() (*Config, error) {
 }
 
 func LoadWithFlags(parseFlags bool) (*Config, error) {
-	var port, redisURL, serverURL, corsOrigins string
+	var port, redisURL, serverURL, corsOrigins, reconnectGrace, drainTimeout string
+	var tokenSigningKey, accessTokenTTL, refreshTokenTTL string
+	var janitorInterval, idlePlayerTimeout, idleQueueTimeout, staleGameTimeout, finishedGameRetention, replayRetention string
+	var rateLimit, rateLimitBurst, authRateLimit, authRateLimitBurst, trustedProxies string
+	var wsMessageRate, wsMessageBurst, wsMaxMessageSize string
+	var tracingExporter, tracingEndpoint, tracingSampleRatio string
+	var adminToken string
 
 	if parseFlags && !flag.Parsed() {
 		portFlag := flag.String("port", "", "Server port")
 		redisURLFlag := flag.String("redis-url", "", "Redis connection URL")
 		serverURLFlag := flag.String("server-url", "", "Public server URL")
 		corsOriginsFlag := flag.String("cors-origins", "", "Comma-separated list of allowed CORS origins")
+		reconnectGraceFlag := flag.String("reconnect-grace", "", "How long to hold a disconnected player's game and session (e.g. 30s)")
+		drainTimeoutFlag := flag.String("drain-timeout", "", "How long to let games finish when shutting down (e.g. 5m, 0 disables)")
+		janitorIntervalFlag := flag.String("janitor-interval", "", "How often to reclaim idle players and stale games (e.g. 1m, 0 disables)")
+		idlePlayerTimeoutFlag := flag.String("idle-player-timeout", "", "How long a guest may be inactive before being removed (e.g. 30m)")
+		idleQueueTimeoutFlag := flag.String("idle-queue-timeout", "", "How long a queued player may be inactive before being dequeued (e.g. 2m)")
+		staleGameTimeoutFlag := flag.String("stale-game-timeout", "", "How long a game may go without active players before being cancelled (e.g. 10m)")
+		finishedGameRetentionFlag := flag.String("finished-game-retention", "", "How long finished games are kept (e.g. 2h)")
+		replayRetentionFlag := flag.String("replay-retention", "", "How long game replays are kept (e.g. 168h)")
+		tokenSigningKeyFlag := flag.String("token-signing-key", "", "Key used to sign session tokens, shared by every instance")
+		accessTokenTTLFlag := flag.String("access-token-ttl", "", "How long a session token is valid (e.g. 15m)")
+		refreshTokenTTLFlag := flag.String("refresh-token-ttl", "", "How long a session can be refreshed (e.g. 168h)")
+		rateLimitFlag := flag.String("rate-limit", "", "API requests per second allowed per IP or player (0 disables)")
+		rateLimitBurstFlag := flag.String("rate-limit-burst", "", "API requests allowed in a burst")
+		authRateLimitFlag := flag.String("auth-rate-limit", "", "Login and registration requests per second allowed per IP (0 disables)")
+		authRateLimitBurstFlag := flag.String("auth-rate-limit-burst", "", "Login and registration requests allowed in a burst")
+		trustedProxiesFlag := flag.String("trusted-proxies", "", "Comma-separated CIDR ranges of proxies whose X-Forwarded-For is trusted")
+		wsMessageRateFlag := flag.String("ws-message-rate", "", "WebSocket messages per second allowed per connection (0 disables)")
+		wsMessageBurstFlag := flag.String("ws-message-burst", "", "WebSocket messages allowed in a burst")
+		wsMaxMessageSizeFlag := flag.String("ws-max-message-size", "", "Largest WebSocket message accepted, in bytes (0 disables)")
+		tracingExporterFlag := flag.String("tracing-exporter", "", "Trace exporter: stdout or otlp (empty disables tracing)")
+		tracingEndpointFlag := flag.String("tracing-endpoint", "", "OTLP/HTTP traces URL (e.g. http://localhost:4318/v1/traces)")
+		tracingSampleRatioFlag := flag.String("tracing-sample-ratio", "", "Share of traces to keep, from 0 to 1")
+		adminTokenFlag := flag.String("admin-token", "", "Bearer token for the admin API (empty disables it)")
 		flag.Parse()
 
 		port = *portFlag
 		redisURL = *redisURLFlag
 		serverURL = *serverURLFlag
 		corsOrigins = *corsOriginsFlag
+		reconnectGrace = *reconnectGraceFlag
+		drainTimeout = *drainTimeoutFlag
+		janitorInterval = *janitorIntervalFlag
+		idlePlayerTimeout = *idlePlayerTimeoutFlag
+		idleQueueTimeout = *idleQueueTimeoutFlag
+		staleGameTimeout = *staleGameTimeoutFlag
+		finishedGameRetention = *finishedGameRetentionFlag
+		replayRetention = *replayRetentionFlag
+		tokenSigningKey = *tokenSigningKeyFlag
+		accessTokenTTL = *accessTokenTTLFlag
+		refreshTokenTTL = *refreshTokenTTLFlag
+		rateLimit = *rateLimitFlag
+		rateLimitBurst = *rateLimitBurstFlag
+		authRateLimit = *authRateLimitFlag
+		authRateLimitBurst = *authRateLimitBurstFlag
+		trustedProxies = *trustedProxiesFlag
+		wsMessageRate = *wsMessageRateFlag
+		wsMessageBurst = *wsMessageBurstFlag
+		wsMaxMessageSize = *wsMaxMessageSizeFlag
+		tracingExporter = *tracingExporterFlag
+		tracingEndpoint = *tracingEndpointFlag
+		tracingSampleRatio = *tracingSampleRatioFlag
+		adminToken = *adminTokenFlag
+	}
+
+	reconnectGracePeriod, err := getDuration(reconnectGrace, "RECONNECT_GRACE_PERIOD", "30s")
+	if err != nil {
+		return nil, err
+	}
+	drainTimeoutValue, err := getDuration(drainTimeout, "DRAIN_TIMEOUT", "5m")
+	if err != nil {
+		return nil, err
+	}
+	janitorEvery, err := getDuration(janitorInterval, "JANITOR_INTERVAL", "1m")
+	if err != nil {
+		return nil, err
+	}
+	idlePlayer, err := getDuration(idlePlayerTimeout, "IDLE_PLAYER_TIMEOUT", "30m")
+	if err != nil {
+		return nil, err
+	}
+	idleQueue, err := getDuration(idleQueueTimeout, "IDLE_QUEUE_TIMEOUT", "2m")
+	if err != nil {
+		return nil, err
+	}
+	staleGame, err := getDuration(staleGameTimeout, "STALE_GAME_TIMEOUT", "10m")
+	if err != nil {
+		return nil, err
+	}
+	finishedRetention, err := getDuration(finishedGameRetention, "FINISHED_GAME_RETENTION", "2h")
+	if err != nil {
+		return nil, err
+	}
+	replayKept, err := getDuration(replayRetention, "REPLAY_RETENTION", "168h")
+	if err != nil {
+		return nil, err
+	}
+	accessTTL, err := getDuration(accessTokenTTL, "ACCESS_TOKEN_TTL", "15m")
+	if err != nil {
+		return nil, err
+	}
+	refreshTTL, err := getDuration(refreshTokenTTL, "REFRESH_TOKEN_TTL", "168h")
+	if err != nil {
+		return nil, err
+	}
+
+	apiRate, err := getFloat(rateLimit, "RATE_LIMIT", "10")
+	if err != nil {
+		return nil, err
+	}
+	apiBurst, err := getInt(rateLimitBurst, "RATE_LIMIT_BURST", "20")
+	if err != nil {
+		return nil, err
+	}
+	authRate, err := getFloat(authRateLimit, "AUTH_RATE_LIMIT", "0.2")
+	if err != nil {
+		return nil, err
+	}
+	authBurst, err := getInt(authRateLimitBurst, "AUTH_RATE_LIMIT_BURST", "5")
+	if err != nil {
+		return nil, err
+	}
+	messageRate, err := getFloat(wsMessageRate, "WS_MESSAGE_RATE", "50")
+	if err != nil {
+		return nil, err
+	}
+	messageBurst, err := getInt(wsMessageBurst, "WS_MESSAGE_BURST", "100")
+	if err != nil {
+		return nil, err
+	}
+	maxMessageSize, err := getInt(wsMaxMessageSize, "WS_MAX_MESSAGE_SIZE", "16384")
+	if err != nil {
+		return nil, err
+	}
+
+	sampleRatio, err := getFloat(tracingSampleRatio, "TRACING_SAMPLE_RATIO", "1")
+	if err != nil {
+		return nil, err
 	}
 
 	cfg := &Config{
@@ -39,6 +231,36 @@ func LoadWithFlags(parseFlags bool) (*Config, error) {
 		RedisURL:    getValue(redisURL, "REDIS_URL", ""),
 		ServerURL:   getValue(serverURL, "SERVER_URL", "http://localhost:8080"),
 		CORSOrigins: getValue(corsOrigins, "CORS_ORIGINS", "http://localhost:3000,http://localhost:8080"),
+
+		ReconnectGracePeriod: reconnectGracePeriod,
+		DrainTimeout:         drainTimeoutValue,
+
+		JanitorInterval:       janitorEvery,
+		IdlePlayerTimeout:     idlePlayer,
+		IdleQueueTimeout:      idleQueue,
+		StaleGameTimeout:      staleGame,
+		FinishedGameRetention: finishedRetention,
+		ReplayRetention:       replayKept,
+
+		TokenSigningKey: getValue(tokenSigningKey, "TOKEN_SIGNING_KEY", ""),
+		AccessTokenTTL:  accessTTL,
+		RefreshTokenTTL: refreshTTL,
+
+		RateLimit:          apiRate,
+		RateLimitBurst:     apiBurst,
+		AuthRateLimit:      authRate,
+		AuthRateLimitBurst: authBurst,
+		TrustedProxies:     getValue(trustedProxies, "TRUSTED_PROXIES", ""),
+
+		WSMessageRate:    messageRate,
+		WSMessageBurst:   messageBurst,
+		WSMaxMessageSize: int64(maxMessageSize),
+
+		TracingExporter:    getValue(tracingExporter, "TRACING_EXPORTER", ""),
+		TracingEndpoint:    getValue(tracingEndpoint, "TRACING_ENDPOINT", ""),
+		TracingSampleRatio: sampleRatio,
+
+		AdminToken: getValue(adminToken, "ADMIN_TOKEN", ""),
 	}
 
 	if err := cfg.validate(); err != nil {
@@ -61,9 +283,117 @@ func (c *Config) validate() error {
 		return fmt.Errorf("PORT must be numeric: %s", c.Port)
 	}
 
+	if c.ReconnectGracePeriod < 0 {
+		return fmt.Errorf("RECONNECT_GRACE_PERIOD must not be negative: %s", c.ReconnectGracePeriod)
+	}
+	if c.DrainTimeout < 0 {
+		return fmt.Errorf("DRAIN_TIMEOUT must not be negative: %s", c.DrainTimeout)
+	}
+
+	if c.ReplayRetention <= 0 {
+		return fmt.Errorf("REPLAY_RETENTION must be positive: %s", c.ReplayRetention)
+	}
+
+	if c.JanitorInterval < 0 {
+		return fmt.Errorf("JANITOR_INTERVAL must not be negative: %s", c.JanitorInterval)
+	}
+	if c.JanitorInterval > 0 {
+		if c.IdlePlayerTimeout <= 0 || c.IdleQueueTimeout <= 0 || c.StaleGameTimeout <= 0 || c.FinishedGameRetention <= 0 {
+			return fmt.Errorf("IDLE_PLAYER_TIMEOUT, IDLE_QUEUE_TIMEOUT, STALE_GAME_TIMEOUT and FINISHED_GAME_RETENTION must be positive")
+		}
+		// Paused games must get their full reconnect window
+		if c.StaleGameTimeout <= c.ReconnectGracePeriod {
+			return fmt.Errorf("STALE_GAME_TIMEOUT must be longer than RECONNECT_GRACE_PERIOD")
+		}
+	}
+
+	if c.TokenSigningKey != "" && len(c.TokenSigningKey) < minTokenSigningKeyLength {
+		return fmt.Errorf("TOKEN_SIGNING_KEY must be at least %d characters", minTokenSigningKeyLength)
+	}
+	if c.AccessTokenTTL < 0 || c.RefreshTokenTTL < 0 {
+		return fmt.Errorf("ACCESS_TOKEN_TTL and REFRESH_TOKEN_TTL must not be negative")
+	}
+	if c.RefreshTokenTTL < c.AccessTokenTTL {
+		return fmt.Errorf("REFRESH_TOKEN_TTL must not be shorter than ACCESS_TOKEN_TTL")
+	}
+
+	if c.RateLimit < 0 || c.AuthRateLimit < 0 || c.WSMessageRate < 0 {
+		return fmt.Errorf("RATE_LIMIT, AUTH_RATE_LIMIT and WS_MESSAGE_RATE must not be negative")
+	}
+	if c.RateLimitBurst < 0 || c.AuthRateLimitBurst < 0 || c.WSMessageBurst < 0 {
+		return fmt.Errorf("RATE_LIMIT_BURST, AUTH_RATE_LIMIT_BURST and WS_MESSAGE_BURST must not be negative")
+	}
+	for _, cidr := range strings.Split(c.TrustedProxies, ",") {
+		if cidr = strings.TrimSpace(cidr); cidr == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("TRUSTED_PROXIES must be a list of CIDR ranges (e.g. 10.0.0.0/8): %s", cidr)
+		}
+	}
+	if c.WSMaxMessageSize < 0 {
+		return fmt.Errorf("WS_MAX_MESSAGE_SIZE must not be negative: %d", c.WSMaxMessageSize)
+	}
+
+	switch c.TracingExporter {
+	case "", "stdout", "otlp":
+	default:
+		return fmt.Errorf("TRACING_EXPORTER must be stdout or otlp: %s", c.TracingExporter)
+	}
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		return fmt.Errorf("TRACING_SAMPLE_RATIO must be between 0 and 1: %v", c.TracingSampleRatio)
+	}
+
+	if c.AdminToken != "" && len(c.AdminToken) < minAdminTokenLength {
+		return fmt.Errorf("ADMIN_TOKEN must be at least %d characters", minAdminTokenLength)
+	}
+
 	return nil
 }
 
+// LogValue implements slog.LogValuer so the signing key and admin token are
+// never logged
+func (c *Config) LogValue() slog.Value {
+	signingKey := "random"
+	if c.TokenSigningKey != "" {
+		signingKey = "[REDACTED]"
+	}
+	adminToken := "disabled"
+	if c.AdminToken != "" {
+		adminToken = "[REDACTED]"
+	}
+
+	return slog.GroupValue(
+		slog.String("port", c.Port),
+		slog.String("redisURL", c.RedisURL),
+		slog.String("serverURL", c.ServerURL),
+		slog.String("corsOrigins", c.CORSOrigins),
+		slog.Duration("reconnectGracePeriod", c.ReconnectGracePeriod),
+		slog.Duration("drainTimeout", c.DrainTimeout),
+		slog.Duration("janitorInterval", c.JanitorInterval),
+		slog.Duration("idlePlayerTimeout", c.IdlePlayerTimeout),
+		slog.Duration("idleQueueTimeout", c.IdleQueueTimeout),
+		slog.Duration("staleGameTimeout", c.StaleGameTimeout),
+		slog.Duration("finishedGameRetention", c.FinishedGameRetention),
+		slog.Duration("replayRetention", c.ReplayRetention),
+		slog.String("tokenSigningKey", signingKey),
+		slog.Duration("accessTokenTTL", c.AccessTokenTTL),
+		slog.Duration("refreshTokenTTL", c.RefreshTokenTTL),
+		slog.Float64("rateLimit", c.RateLimit),
+		slog.Int("rateLimitBurst", c.RateLimitBurst),
+		slog.Float64("authRateLimit", c.AuthRateLimit),
+		slog.Int("authRateLimitBurst", c.AuthRateLimitBurst),
+		slog.String("trustedProxies", c.TrustedProxies),
+		slog.Float64("wsMessageRate", c.WSMessageRate),
+		slog.Int("wsMessageBurst", c.WSMessageBurst),
+		slog.Int64("wsMaxMessageSize", c.WSMaxMessageSize),
+		slog.String("tracingExporter", c.TracingExporter),
+		slog.String("tracingEndpoint", c.TracingEndpoint),
+		slog.Float64("tracingSampleRatio", c.TracingSampleRatio),
+		slog.String("adminToken", adminToken),
+	)
+}
+
 // getValue returns CLI flag value, then env var, then default
 func getValue(flagValue, envKey, defaultValue string) string {
 	if flagValue != "" {
@@ -74,3 +404,33 @@ func getValue(flagValue, envKey, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getDuration resolves a duration setting with the same priority as getValue
+func getDuration(flagValue, envKey, defaultValue string) (time.Duration, error) {
+	value := getValue(flagValue, envKey, defaultValue)
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a duration (e.g. 30s): %s", envKey, value)
+	}
+	return d, nil
+}
+
+// getFloat resolves a numeric setting with the same priority as getValue
+func getFloat(flagValue, envKey, defaultValue string) (float64, error) {
+	value := getValue(flagValue, envKey, defaultValue)
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number: %s", envKey, value)
+	}
+	return f, nil
+}
+
+// getInt resolves an integer setting with the same priority as getValue
+func getInt(flagValue, envKey, defaultValue string) (int, error) {
+	value := getValue(flagValue, envKey, defaultValue)
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer: %s", envKey, value)
+	}
+	return i, nil
+}
@@ -2,7 +2,9 @@ package config
 
 import (
 	"os"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestGetValue(t *testing.T) {
@@ -93,9 +95,10 @@ func TestValidation(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := &Config{
-				Port:      tt.port,
-				RedisURL:  "redis://localhost:6379",
-				ServerURL: "http://localhost:8080",
+				Port:            tt.port,
+				RedisURL:        "redis://localhost:6379",
+				ServerURL:       "http://localhost:8080",
+				ReplayRetention: time.Hour,
 			}
 
 			err := cfg.validate()
@@ -105,3 +108,250 @@ func TestValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestReconnectGracePeriod(t *testing.T) {
+	cfg, err := LoadWithFlags(false)
+	if err != nil {
+		t.Fatalf("LoadWithFlags() failed: %v", err)
+	}
+	if cfg.ReconnectGracePeriod != 30*time.Second {
+		t.Errorf("Expected default reconnect grace period 30s, got %s", cfg.ReconnectGracePeriod)
+	}
+
+	os.Setenv("RECONNECT_GRACE_PERIOD", "5s")
+	defer os.Unsetenv("RECONNECT_GRACE_PERIOD")
+
+	cfg, err = LoadWithFlags(false)
+	if err != nil {
+		t.Fatalf("LoadWithFlags() failed: %v", err)
+	}
+	if cfg.ReconnectGracePeriod != 5*time.Second {
+		t.Errorf("Expected reconnect grace period 5s, got %s", cfg.ReconnectGracePeriod)
+	}
+
+	os.Setenv("RECONNECT_GRACE_PERIOD", "soon")
+	if _, err := LoadWithFlags(false); err == nil {
+		t.Error("Expected error for invalid reconnect grace period")
+	}
+}
+
+func TestDrainTimeout(t *testing.T) {
+	cfg, err := LoadWithFlags(false)
+	if err != nil {
+		t.Fatalf("LoadWithFlags() failed: %v", err)
+	}
+	if cfg.DrainTimeout != 5*time.Minute {
+		t.Errorf("Expected default drain timeout 5m, got %s", cfg.DrainTimeout)
+	}
+
+	os.Setenv("DRAIN_TIMEOUT", "0")
+	defer os.Unsetenv("DRAIN_TIMEOUT")
+
+	cfg, err = LoadWithFlags(false)
+	if err != nil {
+		t.Fatalf("LoadWithFlags() failed: %v", err)
+	}
+	if cfg.DrainTimeout != 0 {
+		t.Errorf("Expected draining to be disabled, got %s", cfg.DrainTimeout)
+	}
+
+	os.Setenv("DRAIN_TIMEOUT", "-1m")
+	if _, err := LoadWithFlags(false); err == nil {
+		t.Error("Expected error for a negative drain timeout")
+	}
+}
+
+func TestJanitorSettings(t *testing.T) {
+	cfg, err := LoadWithFlags(false)
+	if err != nil {
+		t.Fatalf("LoadWithFlags() failed: %v", err)
+	}
+	if cfg.JanitorInterval != time.Minute || cfg.IdlePlayerTimeout != 30*time.Minute || cfg.IdleQueueTimeout != 2*time.Minute ||
+		cfg.StaleGameTimeout != 10*time.Minute || cfg.FinishedGameRetention != 2*time.Hour {
+		t.Errorf("Unexpected janitor defaults: %s, %s, %s, %s, %s", cfg.JanitorInterval, cfg.IdlePlayerTimeout,
+			cfg.IdleQueueTimeout, cfg.StaleGameTimeout, cfg.FinishedGameRetention)
+	}
+	if cfg.ReplayRetention != 168*time.Hour {
+		t.Errorf("Expected replays to be kept for a week, got %s", cfg.ReplayRetention)
+	}
+
+	os.Setenv("REPLAY_RETENTION", "0")
+	if _, err := LoadWithFlags(false); err == nil {
+		t.Error("Expected error for a zero replay retention")
+	}
+	os.Unsetenv("REPLAY_RETENTION")
+
+	// Games paused for a reconnect must not be cancelled as stale
+	os.Setenv("STALE_GAME_TIMEOUT", "30s")
+	defer os.Unsetenv("STALE_GAME_TIMEOUT")
+	if _, err := LoadWithFlags(false); err == nil {
+		t.Error("Expected error for a stale game timeout within the reconnect grace period")
+	}
+
+	// Thresholds only matter while the janitor runs
+	os.Setenv("JANITOR_INTERVAL", "0")
+	defer os.Unsetenv("JANITOR_INTERVAL")
+	if _, err := LoadWithFlags(false); err != nil {
+		t.Errorf("Expected a disabled janitor to ignore its thresholds, got %v", err)
+	}
+}
+
+func TestTokenSettings(t *testing.T) {
+	cfg, err := LoadWithFlags(false)
+	if err != nil {
+		t.Fatalf("LoadWithFlags() failed: %v", err)
+	}
+	if cfg.TokenSigningKey != "" || cfg.AccessTokenTTL != 15*time.Minute || cfg.RefreshTokenTTL != 168*time.Hour {
+		t.Errorf("Unexpected token defaults: %+v", cfg)
+	}
+
+	os.Setenv("TOKEN_SIGNING_KEY", "too-short")
+	defer os.Unsetenv("TOKEN_SIGNING_KEY")
+	if _, err := LoadWithFlags(false); err == nil {
+		t.Error("Expected error for a short signing key")
+	}
+
+	os.Setenv("TOKEN_SIGNING_KEY", "0123456789abcdef0123456789abcdef")
+	os.Setenv("ACCESS_TOKEN_TTL", "1h")
+	os.Setenv("REFRESH_TOKEN_TTL", "30m")
+	defer os.Unsetenv("ACCESS_TOKEN_TTL")
+	defer os.Unsetenv("REFRESH_TOKEN_TTL")
+	if _, err := LoadWithFlags(false); err == nil {
+		t.Error("Expected error for a refresh TTL shorter than the access TTL")
+	}
+
+	os.Setenv("REFRESH_TOKEN_TTL", "24h")
+	cfg, err = LoadWithFlags(false)
+	if err != nil {
+		t.Fatalf("LoadWithFlags() failed: %v", err)
+	}
+	if cfg.AccessTokenTTL != time.Hour || cfg.RefreshTokenTTL != 24*time.Hour {
+		t.Errorf("Expected TTLs 1h and 24h, got %s and %s", cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+	}
+
+	// The key never reaches the logs
+	if logged := cfg.LogValue().String(); strings.Contains(logged, cfg.TokenSigningKey) {
+		t.Errorf("Expected signing key to be redacted, got %s", logged)
+	}
+}
+
+func TestRateLimitSettings(t *testing.T) {
+	cfg, err := LoadWithFlags(false)
+	if err != nil {
+		t.Fatalf("LoadWithFlags() failed: %v", err)
+	}
+	if cfg.RateLimit != 10 || cfg.AuthRateLimit != 0.2 || cfg.WSMessageRate != 50 || cfg.WSMaxMessageSize != 16384 {
+		t.Errorf("Unexpected rate limit defaults: %+v", cfg)
+	}
+
+	os.Setenv("AUTH_RATE_LIMIT", "0.5")
+	os.Setenv("WS_MAX_MESSAGE_SIZE", "0")
+	defer os.Unsetenv("AUTH_RATE_LIMIT")
+	defer os.Unsetenv("WS_MAX_MESSAGE_SIZE")
+	cfg, err = LoadWithFlags(false)
+	if err != nil {
+		t.Fatalf("LoadWithFlags() failed: %v", err)
+	}
+	if cfg.AuthRateLimit != 0.5 || cfg.WSMaxMessageSize != 0 {
+		t.Errorf("Expected auth rate 0.5 and no size limit, got %v and %d", cfg.AuthRateLimit, cfg.WSMaxMessageSize)
+	}
+
+	invalid := map[string]string{
+		"RATE_LIMIT":       "fast",
+		"WS_MESSAGE_RATE":  "-1",
+		"RATE_LIMIT_BURST": "-5",
+	}
+	for key, value := range invalid {
+		os.Setenv(key, value)
+		if _, err := LoadWithFlags(false); err == nil {
+			t.Errorf("Expected error for %s=%s", key, value)
+		}
+		os.Unsetenv(key)
+	}
+}
+
+func TestTrustedProxies(t *testing.T) {
+	cfg, err := LoadWithFlags(false)
+	if err != nil {
+		t.Fatalf("LoadWithFlags() failed: %v", err)
+	}
+	if cfg.TrustedProxies != "" {
+		t.Errorf("Expected no trusted proxies by default, got %q", cfg.TrustedProxies)
+	}
+
+	os.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, fd00::/8")
+	defer os.Unsetenv("TRUSTED_PROXIES")
+	cfg, err = LoadWithFlags(false)
+	if err != nil {
+		t.Fatalf("LoadWithFlags() failed: %v", err)
+	}
+	if cfg.TrustedProxies != "10.0.0.0/8, fd00::/8" {
+		t.Errorf("Expected trusted proxies from the environment, got %q", cfg.TrustedProxies)
+	}
+
+	for _, value := range []string{"10.0.0.1", "10.0.0.0/8,proxy.internal", "10.0.0.0/33"} {
+		os.Setenv("TRUSTED_PROXIES", value)
+		if _, err := LoadWithFlags(false); err == nil {
+			t.Errorf("Expected error for TRUSTED_PROXIES=%s", value)
+		}
+	}
+}
+
+func TestTracingSettings(t *testing.T) {
+	cfg, err := LoadWithFlags(false)
+	if err != nil {
+		t.Fatalf("LoadWithFlags() failed: %v", err)
+	}
+	if cfg.TracingExporter != "" || cfg.TracingSampleRatio != 1 {
+		t.Errorf("Expected tracing off with every trace sampled, got %q and %v", cfg.TracingExporter, cfg.TracingSampleRatio)
+	}
+
+	os.Setenv("TRACING_EXPORTER", "jaeger")
+	if _, err := LoadWithFlags(false); err == nil {
+		t.Error("Expected error for an unknown exporter")
+	}
+
+	os.Setenv("TRACING_EXPORTER", "otlp")
+	os.Setenv("TRACING_SAMPLE_RATIO", "1.5")
+	defer os.Unsetenv("TRACING_EXPORTER")
+	defer os.Unsetenv("TRACING_SAMPLE_RATIO")
+	if _, err := LoadWithFlags(false); err == nil {
+		t.Error("Expected error for a sample ratio above 1")
+	}
+
+	os.Setenv("TRACING_SAMPLE_RATIO", "0.25")
+	cfg, err = LoadWithFlags(false)
+	if err != nil {
+		t.Fatalf("LoadWithFlags() failed: %v", err)
+	}
+	if cfg.TracingExporter != "otlp" || cfg.TracingSampleRatio != 0.25 {
+		t.Errorf("Expected otlp at 0.25, got %q and %v", cfg.TracingExporter, cfg.TracingSampleRatio)
+	}
+}
+
+func TestAdminToken(t *testing.T) {
+	cfg, err := LoadWithFlags(false)
+	if err != nil {
+		t.Fatalf("LoadWithFlags() failed: %v", err)
+	}
+	if cfg.AdminToken != "" {
+		t.Errorf("Expected the admin API to be disabled by default, got %q", cfg.AdminToken)
+	}
+
+	os.Setenv("ADMIN_TOKEN", "too-short")
+	defer os.Unsetenv("ADMIN_TOKEN")
+	if _, err := LoadWithFlags(false); err == nil {
+		t.Error("Expected error for a short admin token")
+	}
+
+	os.Setenv("ADMIN_TOKEN", "admin-0123456789abcdef0123456789")
+	cfg, err = LoadWithFlags(false)
+	if err != nil {
+		t.Fatalf("LoadWithFlags() failed: %v", err)
+	}
+
+	// The token never reaches the logs
+	if logged := cfg.LogValue().String(); strings.Contains(logged, cfg.AdminToken) {
+		t.Errorf("Expected admin token to be redacted, got %s", logged)
+	}
+}
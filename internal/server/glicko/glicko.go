@@ -0,0 +1,135 @@
+// Package glicko implements the Glicko-2 rating system
+// (http://www.glicko.net/glicko/glicko2.pdf). Each finished game is treated
+// as its own rating period.
+package glicko
+
+import (
+	"math"
+
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+const (
+	DefaultRating     = 1500.0
+	DefaultDeviation  = 350.0
+	DefaultVolatility = 0.06
+
+	scale     = 173.7178 // Converts between Glicko and Glicko-2 scales
+	tau       = 0.5      // Constrains volatility change between periods
+	tolerance = 0.000001 // Convergence tolerance for the volatility iteration
+)
+
+// Result is one game against an opponent; Score is 1 for a win, 0.5 for a
+// draw and 0 for a loss
+type Result struct {
+	Opponent models.Rating
+	Score    float64
+}
+
+// Default returns the rating given to new players
+func Default() models.Rating {
+	return models.Rating{
+		Value:      DefaultRating,
+		Deviation:  DefaultDeviation,
+		Volatility: DefaultVolatility,
+	}
+}
+
+// Normalize returns the default rating for unrated players
+func Normalize(rating models.Rating) models.Rating {
+	if rating.Deviation <= 0 || rating.Volatility <= 0 {
+		return Default()
+	}
+	return rating
+}
+
+// Update returns a player's rating after a rating period containing results
+func Update(rating models.Rating, results []Result) models.Rating {
+	rating = Normalize(rating)
+
+	mu := (rating.Value - DefaultRating) / scale
+	phi := rating.Deviation / scale
+	sigma := rating.Volatility
+
+	// Without games only the deviation grows
+	if len(results) == 0 {
+		return models.Rating{
+			Value:      rating.Value,
+			Deviation:  math.Min(math.Sqrt(phi*phi+sigma*sigma)*scale, DefaultDeviation),
+			Volatility: sigma,
+		}
+	}
+
+	// Estimated variance and improvement from the game outcomes
+	var varianceInv, improvement float64
+	for _, result := range results {
+		opponent := Normalize(result.Opponent)
+		muJ := (opponent.Value - DefaultRating) / scale
+		phiJ := opponent.Deviation / scale
+
+		g := weight(phiJ)
+		e := expected(mu, muJ, g)
+		varianceInv += g * g * e * (1 - e)
+		improvement += g * (result.Score - e)
+	}
+	v := 1 / varianceInv
+	delta := v * improvement
+
+	sigma = volatility(phi, sigma, v, delta)
+
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	phi = 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	mu += phi * phi * improvement
+
+	return models.Rating{
+		Value:      mu*scale + DefaultRating,
+		Deviation:  math.Min(phi*scale, DefaultDeviation),
+		Volatility: sigma,
+	}
+}
+
+// weight reduces the impact of games against opponents with uncertain ratings
+func weight(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+// expected returns the expected score against an opponent
+func expected(mu, muJ, g float64) float64 {
+	return 1 / (1 + math.Exp(-g*(mu-muJ)))
+}
+
+// volatility finds the new volatility with the Illinois algorithm (step 5)
+func volatility(phi, sigma, v, delta float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(tau*tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > tolerance {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+
+	return math.Exp(A / 2)
+}
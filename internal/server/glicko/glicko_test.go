@@ -0,0 +1,58 @@
+package glicko
+
+import (
+	"math"
+	"testing"
+
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func TestUpdate_GlickmanExample(t *testing.T) {
+	// Worked example from the Glicko-2 paper
+	player := models.Rating{Value: 1500, Deviation: 200, Volatility: 0.06}
+	results := []Result{
+		{Opponent: models.Rating{Value: 1400, Deviation: 30, Volatility: 0.06}, Score: 1},
+		{Opponent: models.Rating{Value: 1550, Deviation: 100, Volatility: 0.06}, Score: 0},
+		{Opponent: models.Rating{Value: 1700, Deviation: 300, Volatility: 0.06}, Score: 0},
+	}
+
+	updated := Update(player, results)
+
+	if math.Abs(updated.Value-1464.06) > 0.01 {
+		t.Errorf("Expected rating 1464.06, got %.2f", updated.Value)
+	}
+	if math.Abs(updated.Deviation-151.52) > 0.01 {
+		t.Errorf("Expected deviation 151.52, got %.2f", updated.Deviation)
+	}
+	if math.Abs(updated.Volatility-0.05999) > 0.00001 {
+		t.Errorf("Expected volatility 0.05999, got %.5f", updated.Volatility)
+	}
+}
+
+func TestUpdate_WinAndLossMoveRatings(t *testing.T) {
+	winner := Update(models.Rating{}, []Result{{Opponent: Default(), Score: 1}})
+	loser := Update(models.Rating{}, []Result{{Opponent: Default(), Score: 0}})
+
+	if winner.Value <= DefaultRating {
+		t.Errorf("Expected winner above %v, got %.2f", DefaultRating, winner.Value)
+	}
+	if loser.Value >= DefaultRating {
+		t.Errorf("Expected loser below %v, got %.2f", DefaultRating, loser.Value)
+	}
+	if winner.Deviation >= DefaultDeviation {
+		t.Errorf("Expected deviation to shrink after a game, got %.2f", winner.Deviation)
+	}
+}
+
+func TestUpdate_NoGamesIncreasesDeviation(t *testing.T) {
+	player := models.Rating{Value: 1700, Deviation: 50, Volatility: 0.06}
+
+	updated := Update(player, nil)
+
+	if updated.Value != player.Value {
+		t.Errorf("Expected rating to stay %v, got %v", player.Value, updated.Value)
+	}
+	if updated.Deviation <= player.Deviation {
+		t.Errorf("Expected deviation to grow from %v, got %v", player.Deviation, updated.Deviation)
+	}
+}
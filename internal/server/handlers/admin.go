@@ -0,0 +1,591 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/briancain/go-tetris/internal/server/logger"
+	"github.com/briancain/go-tetris/internal/server/services"
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+// Messages shown to players when an operator acts without giving a reason
+const (
+	defaultKickReason  = "Disconnected by an administrator"
+	defaultBanReason   = "Banned by an administrator"
+	defaultDrainReason = "Matchmaking was stopped by the server"
+)
+
+// maxAnnouncementLength caps announcements so they fit on a client's screen
+// and in a WebSocket close frame's reason
+const maxAnnouncementLength = 120
+
+// AdminHandler serves the operator API for inspecting and intervening in a
+// running server. With a backplane, player views and actions cover every
+// instance.
+type AdminHandler struct {
+	wsManager   *services.WebSocketManager
+	wsHandler   *WebSocketHandler
+	gameManager *services.GameManager
+	matchmaking *services.MatchmakingService
+	playerStore storage.PlayerStore
+	gameStore   storage.GameStore
+	banStore    storage.BanStore
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(
+	wsManager *services.WebSocketManager,
+	wsHandler *WebSocketHandler,
+	gameManager *services.GameManager,
+	matchmaking *services.MatchmakingService,
+	playerStore storage.PlayerStore,
+	gameStore storage.GameStore,
+	banStore storage.BanStore,
+) *AdminHandler {
+	return &AdminHandler{
+		wsManager:   wsManager,
+		wsHandler:   wsHandler,
+		gameManager: gameManager,
+		matchmaking: matchmaking,
+		playerStore: playerStore,
+		gameStore:   gameStore,
+		banStore:    banStore,
+	}
+}
+
+// AdminPlayerEntry describes a connected player
+type AdminPlayerEntry struct {
+	PlayerID     string    `json:"playerId"`
+	Username     string    `json:"username"`
+	Registered   bool      `json:"registered"`
+	GameID       string    `json:"gameId,omitempty"`
+	Queue        string    `json:"queue,omitempty"`
+	RTTMs        *float64  `json:"rttMs,omitempty"` // Only measured by the instance holding the connection
+	ConnectedAt  time.Time `json:"connectedAt"`
+	LastActivity time.Time `json:"lastActivity"`
+}
+
+// AdminGameEntry summarises a game that hasn't finished
+type AdminGameEntry struct {
+	GameID     string            `json:"gameId"`
+	Players    []string          `json:"players"` // Usernames in seat order
+	Scores     []int             `json:"scores"`
+	Mode       string            `json:"mode,omitempty"`
+	Queue      string            `json:"queue,omitempty"`
+	RoomCode   string            `json:"roomCode,omitempty"`
+	Ranked     bool              `json:"ranked"`
+	SeriesID   string            `json:"seriesId,omitempty"`
+	Spectators int               `json:"spectators"`
+	Status     models.GameStatus `json:"status"`
+	CreatedAt  time.Time         `json:"createdAt"`
+}
+
+// AdminGameDetail is a game's live state, seat by seat
+type AdminGameDetail struct {
+	GameID     string            `json:"gameId"`
+	Seed       int64             `json:"seed"`
+	Mode       string            `json:"mode,omitempty"`
+	Queue      string            `json:"queue,omitempty"`
+	RoomCode   string            `json:"roomCode,omitempty"`
+	Ranked     bool              `json:"ranked"`
+	Garbage    bool              `json:"garbage"`
+	BestOf     int               `json:"bestOf,omitempty"`
+	SeriesID   string            `json:"seriesId,omitempty"`
+	Spectators []string          `json:"spectators,omitempty"`
+	Status     models.GameStatus `json:"status"`
+	Cancelled  bool              `json:"cancelled,omitempty"`
+	CreatedAt  time.Time         `json:"createdAt"`
+	EndedAt    time.Time         `json:"endedAt,omitempty"`
+	Seats      []AdminSeat       `json:"seats"`
+}
+
+// AdminSeat is one player's seat in a game, with the last board they
+// reported if they are connected to this instance
+type AdminSeat struct {
+	PlayerID     string  `json:"playerId"`
+	Username     string  `json:"username"`
+	Score        int     `json:"score"`
+	Lines        int     `json:"lines"`
+	StackHeight  int     `json:"stackHeight"`
+	Lost         bool    `json:"lost"`
+	Placement    int     `json:"placement,omitempty"`
+	Disconnected bool    `json:"disconnected,omitempty"`
+	KOs          int     `json:"kos"`
+	Badges       int     `json:"badges"`
+	Level        int     `json:"level,omitempty"`
+	Board        [][]int `json:"board,omitempty"`
+}
+
+// AdminEndGameRequest names the winner of a force-ended game; empty for a draw
+type AdminEndGameRequest struct {
+	WinnerID string `json:"winnerId"`
+}
+
+// AdminReasonRequest carries the message shown to affected players
+type AdminReasonRequest struct {
+	Reason string `json:"reason"`
+}
+
+// AdminBanRequest bans a username
+type AdminBanRequest struct {
+	Username string `json:"username"`
+	Reason   string `json:"reason"`
+}
+
+// AdminAnnouncementRequest is a message broadcast to every connected player
+type AdminAnnouncementRequest struct {
+	Message string `json:"message"`
+}
+
+// AdminActionResponse reports the outcome of an action
+type AdminActionResponse struct {
+	Connected *bool `json:"connected,omitempty"` // Whether a kicked or banned player was connected to any instance
+	Players   *int  `json:"players,omitempty"`   // Players drained from queues or sent an announcement
+}
+
+// ListPlayers returns the players connected to any instance
+func (h *AdminHandler) ListPlayers(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value("requestID").(string)
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	playerIDs, err := h.wsManager.ConnectedPlayerIDsAnywhere()
+	if err != nil {
+		logger.Logger.Error("Failed to list connected players",
+			"requestID", requestID,
+			"error", err,
+		)
+		http.Error(w, "Failed to get players", http.StatusInternalServerError)
+		return
+	}
+
+	entries := make([]AdminPlayerEntry, 0, len(playerIDs))
+	for _, playerID := range playerIDs {
+		player, err := h.playerStore.GetPlayer(playerID)
+		if err != nil {
+			continue // Session ended while still connected
+		}
+
+		entry := AdminPlayerEntry{
+			PlayerID:     player.ID,
+			Username:     player.Username,
+			Registered:   player.Registered,
+			GameID:       player.GameID,
+			Queue:        player.Queue,
+			ConnectedAt:  player.ConnectedAt,
+			LastActivity: player.LastActivity,
+		}
+		if rtt, ok := h.wsManager.GetPlayerRTT(playerID); ok {
+			rttMs := float64(rtt.Microseconds()) / 1000
+			entry.RTTMs = &rttMs
+		}
+		entries = append(entries, entry)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(entries)
+}
+
+// ListGames returns every game that hasn't finished, oldest first
+func (h *AdminHandler) ListGames(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	games, err := h.gameStore.GetActiveGames()
+	if err != nil {
+		http.Error(w, "Failed to get games", http.StatusInternalServerError)
+		return
+	}
+
+	entries := make([]AdminGameEntry, 0, len(games))
+	for _, game := range games {
+		entry := AdminGameEntry{
+			GameID:     game.ID,
+			Players:    make([]string, len(game.Players)),
+			Scores:     make([]int, len(game.Players)),
+			Mode:       game.Mode,
+			Queue:      game.Queue,
+			RoomCode:   game.RoomCode,
+			Ranked:     game.Ranked,
+			SeriesID:   game.SeriesID,
+			Spectators: len(game.Spectators),
+			Status:     game.Status,
+			CreatedAt:  game.CreatedAt,
+		}
+		for i, seat := range game.Players {
+			entry.Players[i] = seat.Player.Username
+			entry.Scores[i] = seat.Score
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(entries)
+}
+
+// GetGame returns a game's live state, including each player's board
+func (h *AdminHandler) GetGame(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	game, states, err := h.gameManager.LiveGameState(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Game not found", http.StatusNotFound)
+		return
+	}
+
+	detail := AdminGameDetail{
+		GameID:     game.ID,
+		Seed:       game.Seed,
+		Mode:       game.Mode,
+		Queue:      game.Queue,
+		RoomCode:   game.RoomCode,
+		Ranked:     game.Ranked,
+		Garbage:    game.Garbage,
+		BestOf:     game.BestOf,
+		SeriesID:   game.SeriesID,
+		Spectators: game.Spectators,
+		Status:     game.Status,
+		Cancelled:  game.Cancelled,
+		CreatedAt:  game.CreatedAt,
+		EndedAt:    game.EndedAt,
+		Seats:      make([]AdminSeat, len(game.Players)),
+	}
+	for i, seat := range game.Players {
+		detail.Seats[i] = AdminSeat{
+			PlayerID:     seat.Player.ID,
+			Username:     seat.Player.Username,
+			Score:        seat.Score,
+			Lines:        seat.Lines,
+			StackHeight:  seat.StackHeight,
+			Lost:         seat.Lost,
+			Placement:    seat.Placement,
+			Disconnected: seat.Disconnected,
+			KOs:          seat.KOs,
+			Badges:       seat.Badges,
+		}
+		if state, ok := states[seat.Player.ID]; ok {
+			detail.Seats[i].Level = state.Level
+			detail.Seats[i].Board = state.Board
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(detail)
+}
+
+// EndGame force-ends a game, recording the result with the given winner
+func (h *AdminHandler) EndGame(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value("requestID").(string)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req AdminEndGameRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	gameID := r.PathValue("id")
+	err := h.gameManager.ForceEndGame(gameID, req.WinnerID)
+	if !h.writeGameActionError(w, err) {
+		return
+	}
+
+	logger.Logger.Info("Admin force ended game",
+		"requestID", requestID,
+		"gameID", gameID,
+		"winnerID", req.WinnerID,
+	)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CancelGame ends a game without a result
+func (h *AdminHandler) CancelGame(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value("requestID").(string)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	gameID := r.PathValue("id")
+	err := h.gameManager.CancelGame(gameID)
+	if !h.writeGameActionError(w, err) {
+		return
+	}
+
+	logger.Logger.Info("Admin cancelled game",
+		"requestID", requestID,
+		"gameID", gameID,
+	)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeGameActionError maps a game action's error to a response, reporting
+// whether the action succeeded
+func (h *AdminHandler) writeGameActionError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, services.ErrGameNotLive):
+		http.Error(w, "Game has already finished", http.StatusConflict)
+	case errors.Is(err, services.ErrNotInGame):
+		http.Error(w, "Winner is not in this game", http.StatusBadRequest)
+	default:
+		http.Error(w, "Game not found", http.StatusNotFound)
+	}
+	return false
+}
+
+// KickPlayer disconnects a player, forfeiting their game and ending their
+// session
+func (h *AdminHandler) KickPlayer(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value("requestID").(string)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req AdminReasonRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	player, err := h.playerStore.GetPlayer(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Player not found", http.StatusNotFound)
+		return
+	}
+
+	reason := cleanAdminMessage(req.Reason, defaultKickReason)
+	connected := h.wsHandler.Kick(player.ID, reason)
+
+	logger.Logger.Info("Admin kicked player",
+		"requestID", requestID,
+		"playerID", player.ID,
+		"username", player.Username,
+		"reason", reason,
+	)
+	writeAdminResponse(w, AdminActionResponse{Connected: &connected})
+}
+
+// Bans lists bans on GET and bans a username on POST. A banned player who is
+// online is kicked.
+func (h *AdminHandler) Bans(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listBans(w)
+	case http.MethodPost:
+		h.banPlayer(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *AdminHandler) listBans(w http.ResponseWriter) {
+	bans, err := h.banStore.ListBans()
+	if err != nil {
+		http.Error(w, "Failed to get bans", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(bans)
+}
+
+func (h *AdminHandler) banPlayer(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value("requestID").(string)
+
+	var req AdminBanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Username == "" {
+		http.Error(w, "Username is required", http.StatusBadRequest)
+		return
+	}
+
+	reason := cleanAdminMessage(req.Reason, defaultBanReason)
+	ban := &models.Ban{
+		Username: req.Username,
+		Reason:   reason,
+		BannedAt: time.Now(),
+	}
+	if err := h.banStore.Ban(ban); err != nil {
+		logger.Logger.Error("Failed to ban player",
+			"requestID", requestID,
+			"username", req.Username,
+			"error", err,
+		)
+		http.Error(w, "Failed to ban player", http.StatusInternalServerError)
+		return
+	}
+
+	connected := false
+	if player, err := h.playerStore.GetPlayerByUsername(req.Username); err == nil {
+		connected = h.wsHandler.Kick(player.ID, reason)
+	}
+
+	logger.Logger.Info("Admin banned player",
+		"requestID", requestID,
+		"username", req.Username,
+		"reason", reason,
+	)
+	writeAdminResponse(w, AdminActionResponse{Connected: &connected})
+}
+
+// Unban lifts the ban on a username
+func (h *AdminHandler) Unban(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value("requestID").(string)
+
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	username := r.PathValue("username")
+	err := h.banStore.Unban(username)
+	if errors.Is(err, storage.ErrBanNotFound) {
+		http.Error(w, "Ban not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to lift ban", http.StatusInternalServerError)
+		return
+	}
+
+	logger.Logger.Info("Admin lifted ban",
+		"requestID", requestID,
+		"username", username,
+	)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DrainQueue removes every player from the matchmaking queues
+func (h *AdminHandler) DrainQueue(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value("requestID").(string)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req AdminReasonRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	drained, err := h.matchmaking.DrainQueues(cleanAdminMessage(req.Reason, defaultDrainReason))
+	if err != nil {
+		logger.Logger.Error("Failed to drain matchmaking queues",
+			"requestID", requestID,
+			"drained", drained,
+			"error", err,
+		)
+		http.Error(w, "Failed to drain queues", http.StatusInternalServerError)
+		return
+	}
+
+	logger.Logger.Info("Admin drained matchmaking queues",
+		"requestID", requestID,
+		"players", drained,
+	)
+	writeAdminResponse(w, AdminActionResponse{Players: &drained})
+}
+
+// Announce broadcasts a message to every player connected to any instance
+func (h *AdminHandler) Announce(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value("requestID").(string)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req AdminAnnouncementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	message := strings.TrimSpace(req.Message)
+	if message == "" {
+		http.Error(w, "Message is required", http.StatusBadRequest)
+		return
+	}
+	if len(message) > maxAnnouncementLength {
+		http.Error(w, "Message is too long", http.StatusBadRequest)
+		return
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"type":    "announcement",
+		"message": message,
+	})
+	if err != nil {
+		http.Error(w, "Failed to encode announcement", http.StatusInternalServerError)
+		return
+	}
+
+	recipients := h.wsManager.GetConnectionCount()
+	if playerIDs, err := h.wsManager.ConnectedPlayerIDsAnywhere(); err == nil {
+		recipients = len(playerIDs)
+	}
+	h.wsManager.BroadcastToAllInstances(data)
+
+	logger.Logger.Info("Admin broadcast announcement",
+		"requestID", requestID,
+		"message", message,
+		"recipients", recipients,
+	)
+	writeAdminResponse(w, AdminActionResponse{Players: &recipients})
+}
+
+// cleanAdminMessage trims a message shown to players, falling back to a
+// default when it's empty and truncating it to fit a WebSocket close frame
+func cleanAdminMessage(message, fallback string) string {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return fallback
+	}
+	if len(message) > maxAnnouncementLength {
+		// Drop any rune cut in half
+		message = strings.ToValidUTF8(message[:maxAnnouncementLength], "")
+	}
+	return message
+}
+
+func writeAdminResponse(w http.ResponseWriter, response AdminActionResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
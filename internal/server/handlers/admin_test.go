@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/briancain/go-tetris/internal/server/services"
+	"github.com/briancain/go-tetris/internal/server/storage/memory"
+	"github.com/briancain/go-tetris/internal/server/storage/redis"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func TestAdminHandler_BanKicksConnectedPlayer(t *testing.T) {
+	playerStore := memory.NewPlayerStore()
+	gameStore := memory.NewGameStore()
+	banStore := memory.NewBanStore()
+	authService := services.NewAuthService(playerStore, memory.NewAccountStore())
+	authService.SetBanStore(banStore)
+	wsManager := services.NewWebSocketManager()
+	gameManager := services.NewGameManager(gameStore, playerStore, wsManager)
+	wsHandler := NewWebSocketHandler(wsManager, authService, gameManager)
+	handler := NewAdminHandler(wsManager, wsHandler, gameManager, nil, playerStore, gameStore, banStore)
+
+	server := httptest.NewServer(http.HandlerFunc(wsHandler.HandleWebSocket))
+	defer server.Close()
+
+	player, err := authService.Register("alice", "correct horse")
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	tokens, err := authService.IssueTokens(player)
+	if err != nil {
+		t.Fatalf("Failed to issue tokens: %v", err)
+	}
+
+	dialer := *websocket.DefaultDialer
+	dialer.Subprotocols = []string{"tetris", "bearer." + tokens.AccessToken}
+	conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	adminRequest := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), "requestID", "test-req"))
+		if username, ok := strings.CutPrefix(target, "/admin/bans/"); ok {
+			req.SetPathValue("username", username)
+		}
+		w := httptest.NewRecorder()
+		if method == http.MethodDelete {
+			handler.Unban(w, req)
+		} else {
+			handler.Bans(w, req)
+		}
+		return w
+	}
+
+	w := adminRequest(http.MethodPost, "/admin/bans", `{"username": "alice", "reason": "Cheating"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 banning alice, got %d", w.Code)
+	}
+	var response AdminActionResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Connected == nil || !*response.Connected {
+		t.Errorf("Expected alice to be reported as connected, got %+v", response)
+	}
+
+	// The connection closes with the ban reason
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err = conn.ReadMessage()
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) || closeErr.Code != websocket.ClosePolicyViolation || closeErr.Text != "Cheating" {
+		t.Errorf("Expected a policy violation close with the ban reason, got %v", err)
+	}
+
+	// The session is gone and a new one can't be started
+	if _, err := authService.ValidateSession(tokens.AccessToken); err == nil {
+		t.Error("Expected the banned player's session to be ended")
+	}
+	if _, err := authService.LoginWithPassword("alice", "correct horse"); !errors.Is(err, services.ErrPlayerBanned) {
+		t.Errorf("Expected ErrPlayerBanned logging in, got %v", err)
+	}
+
+	w = adminRequest(http.MethodGet, "/admin/bans", "")
+	var bans []models.Ban
+	if err := json.NewDecoder(w.Body).Decode(&bans); err != nil {
+		t.Fatalf("Failed to decode bans: %v", err)
+	}
+	if len(bans) != 1 || bans[0].Username != "alice" || bans[0].Reason != "Cheating" {
+		t.Errorf("Expected alice's ban to be listed, got %+v", bans)
+	}
+
+	if w := adminRequest(http.MethodDelete, "/admin/bans/alice", ""); w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204 lifting the ban, got %d", w.Code)
+	}
+	if w := adminRequest(http.MethodDelete, "/admin/bans/alice", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 lifting a missing ban, got %d", w.Code)
+	}
+	if _, err := authService.LoginWithPassword("alice", "correct horse"); err != nil {
+		t.Errorf("Expected login after the ban was lifted, got %v", err)
+	}
+}
+
+func TestAdminHandler_ReachesPlayersOnOtherInstances(t *testing.T) {
+	client, err := redis.NewClient("redis://localhost:6379")
+	if err != nil {
+		t.Fatalf("Failed to create Redis client: %v", err)
+	}
+	if err := client.HealthCheck(); err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+
+	playerStore := memory.NewPlayerStore()
+	gameStore := memory.NewGameStore()
+	banStore := memory.NewBanStore()
+	authService := services.NewAuthService(playerStore, memory.NewAccountStore())
+
+	// Two instances sharing storage and a backplane; alice connects to B
+	// and the operator's requests land on A
+	newInstance := func() (*AdminHandler, string) {
+		backplane := redis.NewBackplane(client)
+		wsManager := services.NewWebSocketManager()
+		if err := wsManager.SetBackplane(backplane); err != nil {
+			t.Fatalf("SetBackplane failed: %v", err)
+		}
+		t.Cleanup(func() { backplane.Close() })
+
+		gameManager := services.NewGameManager(gameStore, playerStore, wsManager)
+		wsHandler := NewWebSocketHandler(wsManager, authService, gameManager)
+		server := httptest.NewServer(http.HandlerFunc(wsHandler.HandleWebSocket))
+		t.Cleanup(server.Close)
+
+		handler := NewAdminHandler(wsManager, wsHandler, gameManager, nil, playerStore, gameStore, banStore)
+		return handler, "ws" + strings.TrimPrefix(server.URL, "http")
+	}
+	adminA, _ := newInstance()
+	_, instanceB := newInstance()
+
+	player, err := authService.Register("alice", "correct horse")
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	tokens, err := authService.IssueTokens(player)
+	if err != nil {
+		t.Fatalf("Failed to issue tokens: %v", err)
+	}
+
+	dialer := *websocket.DefaultDialer
+	dialer.Subprotocols = []string{"tetris", "bearer." + tokens.AccessToken}
+	conn, _, err := dialer.Dial(instanceB, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	// A pong means B has registered the connection
+	readMessage := func(messageType string) map[string]interface{} {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		var message map[string]interface{}
+		if err := conn.ReadJSON(&message); err != nil {
+			t.Fatalf("Failed to read %s: %v", messageType, err)
+		}
+		if message["type"] != messageType {
+			t.Fatalf("Expected %s, got %v", messageType, message)
+		}
+		return message
+	}
+	if err := conn.WriteJSON(map[string]interface{}{"type": "ping"}); err != nil {
+		t.Fatalf("Failed to send ping: %v", err)
+	}
+	readMessage("pong")
+
+	adminRequest := func(serve http.HandlerFunc, method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), "requestID", "test-req"))
+		req.SetPathValue("id", player.ID)
+		w := httptest.NewRecorder()
+		serve(w, req)
+		return w
+	}
+
+	w := adminRequest(adminA.ListPlayers, http.MethodGet, "/admin/players", "")
+	var entries []AdminPlayerEntry
+	if err := json.NewDecoder(w.Body).Decode(&entries); err != nil {
+		t.Fatalf("Failed to decode players: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Username != "alice" {
+		t.Errorf("Expected A to list alice connected to B, got %+v", entries)
+	}
+
+	w = adminRequest(adminA.Announce, http.MethodPost, "/admin/announcements", `{"message": "Restarting soon"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 announcing, got %d", w.Code)
+	}
+	if message := readMessage("announcement"); message["message"] != "Restarting soon" {
+		t.Errorf("Expected the announcement text, got %v", message)
+	}
+
+	w = adminRequest(adminA.KickPlayer, http.MethodPost, "/admin/players/"+player.ID+"/kick", `{"reason": "Go outside"}`)
+	var response AdminActionResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Connected == nil || !*response.Connected {
+		t.Errorf("Expected alice to be reported as connected, got %+v", response)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err = conn.ReadMessage()
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) || closeErr.Code != websocket.ClosePolicyViolation || closeErr.Text != "Go outside" {
+		t.Errorf("Expected B to close alice's connection with the kick reason, got %v", err)
+	}
+	if _, err := authService.ValidateSession(tokens.AccessToken); err == nil {
+		t.Error("Expected the kicked player's session to be ended")
+	}
+}
+
+func TestAdminHandler_GameActions(t *testing.T) {
+	playerStore := memory.NewPlayerStore()
+	gameStore := memory.NewGameStore()
+	wsManager := services.NewWebSocketManager()
+	gameManager := services.NewGameManager(gameStore, playerStore, wsManager)
+	handler := NewAdminHandler(wsManager, nil, gameManager, nil, playerStore, gameStore, memory.NewBanStore())
+
+	game := &models.GameSession{
+		ID: "game1",
+		Players: models.NewSeats(
+			&models.Player{ID: "player1", Username: "Alice"},
+			&models.Player{ID: "player2", Username: "Bob"},
+		),
+		Status:    models.GameStatusActive,
+		CreatedAt: time.Now(),
+	}
+	for _, seat := range game.Players {
+		playerStore.CreatePlayer(seat.Player)
+	}
+	gameStore.CreateGame(game)
+
+	endGame := func(gameID, body string) int {
+		req := httptest.NewRequest("POST", "/admin/games/"+gameID+"/end", strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), "requestID", "test-req"))
+		req.SetPathValue("id", gameID)
+		w := httptest.NewRecorder()
+		handler.EndGame(w, req)
+		return w.Code
+	}
+
+	if code := endGame("missing", ""); code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a missing game, got %d", code)
+	}
+	if code := endGame("game1", `{"winnerId": "player3"}`); code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a winner not in the game, got %d", code)
+	}
+	if code := endGame("game1", `{"winnerId": "player2"}`); code != http.StatusNoContent {
+		t.Fatalf("Expected status 204 ending the game, got %d", code)
+	}
+	if code := endGame("game1", ""); code != http.StatusConflict {
+		t.Errorf("Expected status 409 for a finished game, got %d", code)
+	}
+
+	req := httptest.NewRequest("GET", "/admin/games/game1", nil)
+	req.SetPathValue("id", "game1")
+	w := httptest.NewRecorder()
+	handler.GetGame(w, req)
+
+	var detail AdminGameDetail
+	if err := json.NewDecoder(w.Body).Decode(&detail); err != nil {
+		t.Fatalf("Failed to decode game: %v", err)
+	}
+	if detail.Status != models.GameStatusFinished || len(detail.Seats) != 2 {
+		t.Fatalf("Expected a finished two seat game, got %+v", detail)
+	}
+	if detail.Seats[0].Placement != 2 || detail.Seats[1].Placement != 1 {
+		t.Errorf("Expected Bob to be placed first, got %+v", detail.Seats)
+	}
+}
@@ -4,9 +4,14 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/briancain/go-tetris/internal/server/logger"
 	"github.com/briancain/go-tetris/internal/server/services"
+	"github.com/briancain/go-tetris/internal/server/tracing"
+	"github.com/briancain/go-tetris/pkg/models"
 )
 
 // AuthHandler handles authentication endpoints
@@ -21,16 +26,39 @@ func NewAuthHandler(authService *services.AuthService) *AuthHandler {
 	}
 }
 
-// LoginRequest represents a login request
+// LoginRequest represents a login request. Without a password or API key
+// the player logs in as a guest.
 type LoginRequest struct {
 	Username string `json:"username"`
+	Password string `json:"password,omitempty"`
+	APIKey   string `json:"apiKey,omitempty"`
+}
+
+// RegisterRequest represents an account registration request
+type RegisterRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// RefreshRequest represents a token refresh request
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken"`
 }
 
-// LoginResponse represents a login response
+// LoginResponse represents a login response. The session token is a signed
+// access token that expires at ExpiresAt; the refresh token gets a new pair.
 type LoginResponse struct {
-	PlayerID     string `json:"playerId"`
-	Username     string `json:"username"`
-	SessionToken string `json:"sessionToken"`
+	PlayerID     string    `json:"playerId"`
+	Username     string    `json:"username"`
+	SessionToken string    `json:"sessionToken"`
+	RefreshToken string    `json:"refreshToken"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+	Registered   bool      `json:"registered"`
+}
+
+// APIKeyResponse carries a newly issued API key
+type APIKeyResponse struct {
+	APIKey string `json:"apiKey"`
 }
 
 // Login handles player login
@@ -66,7 +94,24 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create player session
-	player, err := h.authService.Login(req.Username)
+	method := "guest"
+	var player *models.Player
+	_, span := tracing.Start(r.Context(), "auth.login", attribute.String("player.username", req.Username))
+	switch {
+	case req.Password != "":
+		method = "password"
+		player, err = h.authService.LoginWithPassword(req.Username, req.Password)
+	case req.APIKey != "":
+		method = "api_key"
+		player, err = h.authService.LoginWithAPIKey(req.Username, req.APIKey)
+	default:
+		player, err = h.authService.Login(req.Username)
+	}
+	span.SetAttributes(attribute.String("auth.method", method))
+	if err == nil {
+		span.SetAttributes(tracing.PlayerID(player.ID))
+	}
+	tracing.End(span, err)
 	if err != nil {
 		// Check if it's a username conflict error
 		if errors.Is(err, services.ErrUsernameInUse) {
@@ -77,6 +122,30 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "Username is already in use. Please choose a different username.", http.StatusConflict)
 			return
 		}
+		if errors.Is(err, services.ErrUsernameRegistered) {
+			logger.Logger.Warn("Guest login attempt with registered username",
+				"requestID", requestID,
+				"username", req.Username,
+			)
+			http.Error(w, "Username is registered. Log in with its password or choose a different username.", http.StatusConflict)
+			return
+		}
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			logger.Logger.Warn("Login attempt with invalid credentials",
+				"requestID", requestID,
+				"username", req.Username,
+			)
+			http.Error(w, "Invalid username or password", http.StatusUnauthorized)
+			return
+		}
+		if errors.Is(err, services.ErrPlayerBanned) {
+			logger.Logger.Warn("Login attempt by banned player",
+				"requestID", requestID,
+				"username", req.Username,
+			)
+			http.Error(w, "This username is banned", http.StatusForbidden)
+			return
+		}
 
 		logger.Logger.Error("Failed to create player session",
 			"requestID", requestID,
@@ -91,13 +160,185 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		"requestID", requestID,
 		"playerID", player.ID,
 		"username", player.Username,
+		"registered", player.Registered,
 	)
 
-	// Return response
+	h.writeLoginResponse(w, requestID, player)
+}
+
+// Register handles account registration, logging the new account in
+func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value("requestID").(string)
+
+	if r.Method != http.MethodPost {
+		logger.Logger.Warn("Invalid method for register",
+			"requestID", requestID,
+			"method", r.Method,
+		)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req RegisterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Logger.Error("Failed to decode register request",
+			"requestID", requestID,
+			"error", err,
+		)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Username == "" {
+		http.Error(w, "Username is required", http.StatusBadRequest)
+		return
+	}
+
+	player, err := h.authService.Register(req.Username, req.Password)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPassword) {
+			http.Error(w, "Password must be between 8 and 72 characters", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, services.ErrUsernameInUse) {
+			logger.Logger.Warn("Registration attempt with username already in use",
+				"requestID", requestID,
+				"username", req.Username,
+			)
+			http.Error(w, "Username is already in use. Please choose a different username.", http.StatusConflict)
+			return
+		}
+		if errors.Is(err, services.ErrPlayerBanned) {
+			logger.Logger.Warn("Registration attempt by banned player",
+				"requestID", requestID,
+				"username", req.Username,
+			)
+			http.Error(w, "This username is banned", http.StatusForbidden)
+			return
+		}
+
+		logger.Logger.Error("Failed to register account",
+			"requestID", requestID,
+			"username", req.Username,
+			"error", err,
+		)
+		http.Error(w, "Failed to register account", http.StatusInternalServerError)
+		return
+	}
+
+	logger.Logger.Info("Account registered",
+		"requestID", requestID,
+		"playerID", player.ID,
+		"username", player.Username,
+	)
+
+	h.writeLoginResponse(w, requestID, player)
+}
+
+// Refresh exchanges a refresh token for a new session and refresh token
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value("requestID").(string)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		http.Error(w, "Refresh token is required", http.StatusBadRequest)
+		return
+	}
+
+	player, tokens, err := h.authService.RefreshTokens(req.RefreshToken)
+	if errors.Is(err, services.ErrPlayerBanned) {
+		logger.Logger.Warn("Token refresh by banned player",
+			"requestID", requestID,
+		)
+		http.Error(w, "This username is banned", http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		logger.Logger.Warn("Token refresh rejected",
+			"requestID", requestID,
+			"error", err,
+		)
+		http.Error(w, "Invalid or expired refresh token", http.StatusUnauthorized)
+		return
+	}
+
+	writeSession(w, requestID, player, tokens)
+}
+
+// CreateAPIKey issues a new API key for the logged in account, replacing any
+// previous key
+func (h *AuthHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value("requestID").(string)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	playerID := r.Context().Value("playerID").(string)
+
+	apiKey, err := h.authService.CreateAPIKey(playerID)
+	if err != nil {
+		if errors.Is(err, services.ErrNotRegistered) {
+			http.Error(w, "Only registered accounts can create API keys", http.StatusForbidden)
+			return
+		}
+
+		logger.Logger.Error("Failed to create API key",
+			"requestID", requestID,
+			"playerID", playerID,
+			"error", err,
+		)
+		http.Error(w, "Failed to create API key", http.StatusInternalServerError)
+		return
+	}
+
+	logger.Logger.Info("API key created",
+		"requestID", requestID,
+		"playerID", playerID,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(APIKeyResponse{APIKey: apiKey}); err != nil {
+		logger.Logger.Error("Failed to encode API key response",
+			"requestID", requestID,
+			"playerID", playerID,
+			"error", err,
+		)
+	}
+}
+
+// writeLoginResponse signs tokens for a newly started session and returns
+// them to the client
+func (h *AuthHandler) writeLoginResponse(w http.ResponseWriter, requestID string, player *models.Player) {
+	tokens, err := h.authService.IssueTokens(player)
+	if err != nil {
+		logger.Logger.Error("Failed to issue session tokens",
+			"requestID", requestID,
+			"playerID", player.ID,
+			"error", err,
+		)
+		http.Error(w, "Failed to create session", http.StatusInternalServerError)
+		return
+	}
+
+	writeSession(w, requestID, player, tokens)
+}
+
+// writeSession returns a player's session tokens to the client
+func writeSession(w http.ResponseWriter, requestID string, player *models.Player, tokens *services.SessionTokens) {
 	response := LoginResponse{
 		PlayerID:     player.ID,
 		Username:     player.Username,
-		SessionToken: player.SessionToken,
+		SessionToken: tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresAt:    tokens.ExpiresAt,
+		Registered:   player.Registered,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/briancain/go-tetris/internal/server/services"
+	"github.com/briancain/go-tetris/internal/server/storage/memory"
+)
+
+// postJSON sends body to an auth handler, optionally as a logged in player
+func postJSON(handler http.HandlerFunc, body interface{}, playerID string) *httptest.ResponseRecorder {
+	encoded, _ := json.Marshal(body)
+	req := httptest.NewRequest("POST", "/api/auth", bytes.NewBuffer(encoded))
+	ctx := context.WithValue(req.Context(), "requestID", "test-req")
+	if playerID != "" {
+		ctx = context.WithValue(ctx, "playerID", playerID)
+	}
+	w := httptest.NewRecorder()
+	handler(w, req.WithContext(ctx))
+	return w
+}
+
+func TestAuthHandler_Accounts(t *testing.T) {
+	authService := services.NewAuthService(memory.NewPlayerStore(), memory.NewAccountStore())
+	handler := NewAuthHandler(authService)
+
+	w := postJSON(handler.Register, RegisterRequest{Username: "alice", Password: "short"}, "")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a short password, got %d", w.Code)
+	}
+
+	w = postJSON(handler.Register, RegisterRequest{Username: "alice", Password: "correct horse"}, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected registration to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+	var registered LoginResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &registered); err != nil {
+		t.Fatalf("Failed to parse register response: %v", err)
+	}
+	if !registered.Registered || registered.SessionToken == "" {
+		t.Errorf("Expected a registered session, got %+v", registered)
+	}
+
+	if w = postJSON(handler.Register, RegisterRequest{Username: "alice", Password: "correct horse"}, ""); w.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for a taken account, got %d", w.Code)
+	}
+	if w = postJSON(handler.Login, LoginRequest{Username: "alice"}, ""); w.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for a guest login with a registered name, got %d", w.Code)
+	}
+	if w = postJSON(handler.Login, LoginRequest{Username: "alice", Password: "wrong password"}, ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a wrong password, got %d", w.Code)
+	}
+
+	w = postJSON(handler.Login, LoginRequest{Username: "alice", Password: "correct horse"}, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected password login to succeed, got %d", w.Code)
+	}
+	var loggedIn LoginResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &loggedIn); err != nil {
+		t.Fatalf("Failed to parse login response: %v", err)
+	}
+	if loggedIn.PlayerID != registered.PlayerID {
+		t.Errorf("Expected the account's player %s, got %s", registered.PlayerID, loggedIn.PlayerID)
+	}
+
+	// API keys
+	w = postJSON(handler.CreateAPIKey, nil, loggedIn.PlayerID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected API key creation to succeed, got %d", w.Code)
+	}
+	var key APIKeyResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &key); err != nil || key.APIKey == "" {
+		t.Fatalf("Expected an API key, got %q (%v)", w.Body.String(), err)
+	}
+	if w = postJSON(handler.Login, LoginRequest{Username: "alice", APIKey: key.APIKey}, ""); w.Code != http.StatusOK {
+		t.Errorf("Expected API key login to succeed, got %d", w.Code)
+	}
+
+	guest := postJSON(handler.Login, LoginRequest{Username: "guest"}, "")
+	var guestResp LoginResponse
+	if err := json.Unmarshal(guest.Body.Bytes(), &guestResp); err != nil {
+		t.Fatalf("Failed to parse login response: %v", err)
+	}
+	if w = postJSON(handler.CreateAPIKey, nil, guestResp.PlayerID); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a guest API key, got %d", w.Code)
+	}
+}
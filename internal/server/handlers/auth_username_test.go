@@ -15,7 +15,7 @@ import (
 func TestAuthHandler_UsernameConflicts(t *testing.T) {
 	// Setup
 	playerStore := memory.NewPlayerStore()
-	authService := services.NewAuthService(playerStore)
+	authService := services.NewAuthService(playerStore, memory.NewAccountStore())
 	handler := NewAuthHandler(authService)
 
 	t.Run("should return 409 for duplicate username", func(t *testing.T) {
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+// GameHandler handles game listing and replay requests
+type GameHandler struct {
+	gameStore   storage.GameStore
+	replayStore storage.ReplayStore
+}
+
+// NewGameHandler creates a new game handler
+func NewGameHandler(gameStore storage.GameStore, replayStore storage.ReplayStore) *GameHandler {
+	return &GameHandler{
+		gameStore:   gameStore,
+		replayStore: replayStore,
+	}
+}
+
+// LiveGameEntry describes a game that can be spectated
+type LiveGameEntry struct {
+	GameID    string            `json:"gameId"`
+	Players   []string          `json:"players"` // Usernames in seat order
+	Mode      string            `json:"mode,omitempty"`
+	Ranked    bool              `json:"ranked"`
+	BestOf    int               `json:"bestOf,omitempty"`
+	Status    models.GameStatus `json:"status"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// ListLiveGames returns the games in progress, oldest first, for spectators
+// to pick from
+func (h *GameHandler) ListLiveGames(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	games, err := h.gameStore.GetActiveGames()
+	if err != nil {
+		http.Error(w, "Failed to get games", http.StatusInternalServerError)
+		return
+	}
+
+	entries := make([]LiveGameEntry, 0, len(games))
+	for _, game := range games {
+		// Waiting games have nothing to watch yet
+		if game.Status != models.GameStatusActive && game.Status != models.GameStatusPaused {
+			continue
+		}
+
+		usernames := make([]string, len(game.Players))
+		for i, seat := range game.Players {
+			usernames[i] = seat.Player.Username
+		}
+		entries = append(entries, LiveGameEntry{
+			GameID:    game.ID,
+			Players:   usernames,
+			Mode:      game.Mode,
+			Ranked:    game.Ranked,
+			BestOf:    game.BestOf,
+			Status:    game.Status,
+			CreatedAt: game.CreatedAt,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(entries)
+}
+
+// GetReplay returns the recorded moves, states and result of a finished game
+func (h *GameHandler) GetReplay(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	replay, err := h.replayStore.GetReplay(r.PathValue("id"))
+	if errors.Is(err, storage.ErrReplayNotFound) {
+		http.Error(w, "Replay not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to get replay", http.StatusInternalServerError)
+		return
+	}
+
+	// Replays of running games would show players each other's upcoming moves
+	if !replay.Finished {
+		http.Error(w, "Replay is available once the game finishes", http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(replay)
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/briancain/go-tetris/internal/server/storage/memory"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func TestListLiveGames_OnlyGamesInProgress(t *testing.T) {
+	gameStore := memory.NewGameStore()
+	handler := NewGameHandler(gameStore, memory.NewReplayStore())
+
+	seats := func() []*models.SessionPlayer {
+		return models.NewSeats(
+			&models.Player{ID: "player1", Username: "Alice"},
+			&models.Player{ID: "player2", Username: "Bob"},
+		)
+	}
+	now := time.Now()
+	gameStore.CreateGame(&models.GameSession{ID: "later", Players: seats(), Status: models.GameStatusPaused, CreatedAt: now})
+	gameStore.CreateGame(&models.GameSession{ID: "earlier", Players: seats(), Status: models.GameStatusActive, BestOf: 3, CreatedAt: now.Add(-time.Minute)})
+	gameStore.CreateGame(&models.GameSession{ID: "waiting", Players: seats(), Status: models.GameStatusWaiting, CreatedAt: now})
+	gameStore.CreateGame(&models.GameSession{ID: "finished", Players: seats(), Status: models.GameStatusFinished, CreatedAt: now})
+
+	req := httptest.NewRequest("GET", "/api/games/live", nil)
+	w := httptest.NewRecorder()
+	handler.ListLiveGames(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var games []LiveGameEntry
+	if err := json.NewDecoder(w.Body).Decode(&games); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(games) != 2 || games[0].GameID != "earlier" || games[1].GameID != "later" {
+		t.Fatalf("Expected the active and paused games oldest first, got %+v", games)
+	}
+	if games[0].BestOf != 3 || len(games[0].Players) != 2 || games[0].Players[1] != "Bob" {
+		t.Errorf("Expected game details in the entry, got %+v", games[0])
+	}
+}
+
+func TestGetReplay(t *testing.T) {
+	replayStore := memory.NewReplayStore()
+	handler := NewGameHandler(memory.NewGameStore(), replayStore)
+
+	getReplay := func(gameID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/games/"+gameID+"/replay", nil)
+		req.SetPathValue("id", gameID)
+		w := httptest.NewRecorder()
+		handler.GetReplay(w, req)
+		return w
+	}
+
+	if w := getReplay("missing"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown game, got %d", w.Code)
+	}
+
+	replayStore.CreateReplay(&models.Replay{GameID: "game1", Seed: 5})
+	replayStore.AppendReplayEvent("game1", &models.ReplayEvent{Type: models.ReplayEventMove, PlayerID: "player1"})
+	if w := getReplay("game1"); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 while the game is running, got %d", w.Code)
+	}
+
+	replayStore.FinishReplay("game1", "player1", []*models.ReplayPlayer{{ID: "player1", Placement: 1}})
+	w := getReplay("game1")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var replay models.Replay
+	if err := json.NewDecoder(w.Body).Decode(&replay); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if replay.Seed != 5 || replay.WinnerID != "player1" || len(replay.Events) != 1 {
+		t.Errorf("Expected the finished replay, got %+v", replay)
+	}
+}
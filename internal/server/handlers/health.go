@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/briancain/go-tetris/internal/server/middleware"
 	"github.com/briancain/go-tetris/internal/server/services"
 	"github.com/briancain/go-tetris/internal/server/storage"
 )
@@ -12,6 +13,13 @@ import (
 type HealthHandler struct {
 	wsManager     *services.WebSocketManager
 	storageHealth storage.HealthChecker
+	rateLimiters  []*middleware.RateLimiter
+
+	// Optional sources for game-level gauges in the Prometheus collector
+	gameStore   storage.GameStore
+	matchmaking *services.MatchmakingService
+
+	gameManager *services.GameManager // Optional; reports whether the server is draining
 }
 
 type HealthResponse struct {
@@ -24,8 +32,17 @@ type HealthResponse struct {
 
 type MetricsResponse struct {
 	WebSocketConnections int       `json:"websocket_connections"`
+	WebSocketAvgRTTMs    float64   `json:"websocket_avg_rtt_ms"`
+	SendQueueDepth       int       `json:"websocket_send_queue_depth"`
+	SendQueueMaxDepth    int       `json:"websocket_send_queue_max_depth"`
+	SlowConsumerDrops    int64     `json:"websocket_slow_consumer_drops"`
+	RateLimitedMessages  int64     `json:"websocket_rate_limited_messages"`
+	OversizedMessages    int64     `json:"websocket_oversized_messages"`
 	Uptime               string    `json:"uptime"`
 	Timestamp            time.Time `json:"timestamp"`
+
+	// RateLimitedRequests counts HTTP requests rejected by each rate limiter
+	RateLimitedRequests map[string]int64 `json:"http_rate_limited_requests"`
 }
 
 var startTime = time.Now()
@@ -37,6 +54,17 @@ func NewHealthHandler(wsManager *services.WebSocketManager, storageHealth storag
 	}
 }
 
+// SetGameManager lets Health fail while the server drains for shutdown, so
+// load balancers stop sending it new players
+func (h *HealthHandler) SetGameManager(gameManager *services.GameManager) {
+	h.gameManager = gameManager
+}
+
+// SetRateLimiters registers the HTTP rate limiters reported by Metrics
+func (h *HealthHandler) SetRateLimiters(limiters ...*middleware.RateLimiter) {
+	h.rateLimiters = limiters
+}
+
 func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
 	checks := make(map[string]string)
 	status := "healthy"
@@ -62,6 +90,11 @@ func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
 		status = "degraded"
 	}
 
+	if h.gameManager != nil && h.gameManager.Draining() {
+		checks["drain"] = "draining"
+		status = "draining"
+	}
+
 	response := HealthResponse{
 		Status:      status,
 		Timestamp:   time.Now(),
@@ -85,8 +118,18 @@ func (h *HealthHandler) Metrics(w http.ResponseWriter, r *http.Request) {
 
 	response := MetricsResponse{
 		WebSocketConnections: h.getConnectionCount(),
+		WebSocketAvgRTTMs:    h.getAverageRTTMs(),
 		Uptime:               uptime.String(),
 		Timestamp:            time.Now(),
+		RateLimitedRequests:  make(map[string]int64),
+	}
+	if h.wsManager != nil {
+		response.SendQueueDepth, response.SendQueueMaxDepth = h.wsManager.GetQueueStats()
+		response.SlowConsumerDrops = h.wsManager.GetSlowConsumerDrops()
+		response.RateLimitedMessages, response.OversizedMessages = h.wsManager.GetMessageLimitStats()
+	}
+	for _, limiter := range h.rateLimiters {
+		response.RateLimitedRequests[limiter.Name()] = limiter.Rejected()
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -100,3 +143,10 @@ func (h *HealthHandler) getConnectionCount() int {
 	}
 	return h.wsManager.GetConnectionCount()
 }
+
+func (h *HealthHandler) getAverageRTTMs() float64 {
+	if h.wsManager == nil {
+		return 0
+	}
+	return float64(h.wsManager.GetAverageRTT().Microseconds()) / 1000
+}
@@ -7,7 +7,9 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/briancain/go-tetris/internal/server/middleware"
 	"github.com/briancain/go-tetris/internal/server/services"
+	"github.com/briancain/go-tetris/internal/server/storage/memory"
 )
 
 // mockHealthChecker for testing
@@ -128,6 +130,37 @@ func TestHealthHandler_Metrics(t *testing.T) {
 	}
 }
 
+func TestHealthHandler_MetricsRateLimits(t *testing.T) {
+	wsManager := services.NewWebSocketManager()
+	wsManager.RecordRateLimitedMessage()
+	wsManager.RecordRateLimitedMessage()
+	wsManager.RecordOversizedMessage()
+
+	authLimiter := middleware.NewRateLimiter("auth", 1, 1)
+	authLimiter.Allow("ip:10.0.0.1")
+	authLimiter.Allow("ip:10.0.0.1")
+
+	handler := NewHealthHandler(wsManager, &mockHealthChecker{})
+	handler.SetRateLimiters(middleware.NewRateLimiter("api", 1, 1), authLimiter)
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	handler.Metrics(w, req)
+
+	var response MetricsResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response.RateLimitedMessages != 2 || response.OversizedMessages != 1 {
+		t.Errorf("Expected 2 rate limited and 1 oversized message, got %d and %d",
+			response.RateLimitedMessages, response.OversizedMessages)
+	}
+	if response.RateLimitedRequests["api"] != 0 || response.RateLimitedRequests["auth"] != 1 {
+		t.Errorf("Expected only 1 auth request rate limited, got %v", response.RateLimitedRequests)
+	}
+}
+
 func TestHealthHandler_MetricsWithNilWebSocketManager(t *testing.T) {
 	healthChecker := &mockHealthChecker{}
 	handler := NewHealthHandler(nil, healthChecker)
@@ -255,3 +288,28 @@ func TestHealthHandler_StorageHealthCheckFailure(t *testing.T) {
 		t.Errorf("Expected storage error message, got '%s'", response.Checks["storage"])
 	}
 }
+
+func TestHealthHandler_Draining(t *testing.T) {
+	wsManager := services.NewWebSocketManager()
+	gameManager := services.NewGameManager(memory.NewGameStore(), memory.NewPlayerStore(), wsManager)
+	handler := NewHealthHandler(wsManager, &mockHealthChecker{})
+	handler.SetGameManager(gameManager)
+
+	gameManager.Drain("Restarting")
+
+	req := httptest.NewRequest("GET", "/health", nil)
+	w := httptest.NewRecorder()
+	handler.Health(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d while draining, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+
+	var response HealthResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Status != "draining" || response.Checks["drain"] != "draining" {
+		t.Errorf("Expected status 'draining', got '%s' with checks %v", response.Status, response.Checks)
+	}
+}
@@ -2,10 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"math"
 	"net/http"
 	"sort"
 	"strconv"
 
+	"github.com/briancain/go-tetris/internal/server/glicko"
 	"github.com/briancain/go-tetris/internal/server/storage"
 	"github.com/briancain/go-tetris/pkg/models"
 )
@@ -68,6 +70,7 @@ func (h *LeaderboardHandler) GetLeaderboard(w http.ResponseWriter, r *http.Reque
 			TotalGames: player.TotalGames,
 			Wins:       player.Wins,
 			Losses:     player.Losses,
+			Rating:     int(math.Round(glicko.Normalize(player.Rating).Value)),
 		}
 	}
 
@@ -83,6 +86,7 @@ type LeaderboardEntry struct {
 	TotalGames int    `json:"totalGames"`
 	Wins       int    `json:"wins"`
 	Losses     int    `json:"losses"`
+	Rating     int    `json:"rating"`
 }
 
 // getAllPlayers gets all players
@@ -2,10 +2,16 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/briancain/go-tetris/internal/server/logger"
 	"github.com/briancain/go-tetris/internal/server/services"
+	"github.com/briancain/go-tetris/internal/server/tracing"
+	"github.com/briancain/go-tetris/pkg/models"
 )
 
 // MatchmakingHandler handles matchmaking endpoints
@@ -22,8 +28,9 @@ func NewMatchmakingHandler(matchmakingService *services.MatchmakingService) *Mat
 
 // QueueStatusResponse represents queue status response
 type QueueStatusResponse struct {
-	Position int  `json:"position"`
-	InQueue  bool `json:"inQueue"`
+	Position int    `json:"position"`
+	InQueue  bool   `json:"inQueue"`
+	Queue    string `json:"queue,omitempty"`
 }
 
 // JoinQueue handles joining the matchmaking queue
@@ -42,7 +49,40 @@ func (h *MatchmakingHandler) JoinQueue(w http.ResponseWriter, r *http.Request) {
 	// Get player from context (set by auth middleware)
 	playerID := r.Context().Value("playerID").(string)
 
-	err := h.matchmakingService.JoinQueue(playerID)
+	// Queue options are optional; an empty body joins the default ranked queue
+	var options models.QueueOptions
+	if err := json.NewDecoder(r.Body).Decode(&options); err != nil && !errors.Is(err, io.EOF) {
+		logger.Logger.Warn("Invalid join queue request",
+			"requestID", requestID,
+			"playerID", playerID,
+			"error", err,
+		)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	_, span := tracing.Start(r.Context(), "matchmaking.join_queue",
+		tracing.PlayerID(playerID),
+		attribute.String("queue.name", options.Queue),
+		attribute.String("game.mode", options.Mode),
+	)
+	err := h.matchmakingService.JoinQueue(playerID, options)
+	tracing.End(span, err)
+	if errors.Is(err, services.ErrUnknownQueue) || errors.Is(err, services.ErrInvalidGameMode) {
+		logger.Logger.Warn("Rejected join queue request",
+			"requestID", requestID,
+			"playerID", playerID,
+			"queue", options.Queue,
+			"mode", options.Mode,
+			"error", err,
+		)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, services.ErrServerDraining) {
+		http.Error(w, "Server is shutting down", http.StatusServiceUnavailable)
+		return
+	}
 	if err != nil {
 		logger.Logger.Error("Failed to join matchmaking queue",
 			"requestID", requestID,
@@ -56,6 +96,8 @@ func (h *MatchmakingHandler) JoinQueue(w http.ResponseWriter, r *http.Request) {
 	logger.Logger.Info("Player joined matchmaking queue",
 		"requestID", requestID,
 		"playerID", playerID,
+		"queue", options.Queue,
+		"mode", options.Mode,
 	)
 
 	w.WriteHeader(http.StatusOK)
@@ -112,7 +154,7 @@ func (h *MatchmakingHandler) GetQueueStatus(w http.ResponseWriter, r *http.Reque
 	// Get player from context (set by auth middleware)
 	playerID := r.Context().Value("playerID").(string)
 
-	position, err := h.matchmakingService.GetQueueStatus(playerID)
+	queue, position, err := h.matchmakingService.GetQueueStatus(playerID)
 	if err != nil {
 		logger.Logger.Error("Failed to get queue status",
 			"requestID", requestID,
@@ -126,6 +168,7 @@ func (h *MatchmakingHandler) GetQueueStatus(w http.ResponseWriter, r *http.Reque
 	response := QueueStatusResponse{
 		Position: position,
 		InQueue:  position >= 0,
+		Queue:    queue,
 	}
 
 	logger.Logger.Debug("Queue status retrieved",
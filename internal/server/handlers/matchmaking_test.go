@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/briancain/go-tetris/internal/server/services"
+	"github.com/briancain/go-tetris/internal/server/storage/memory"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func TestJoinQueue_QueueOptions(t *testing.T) {
+	playerStore := memory.NewPlayerStore()
+	gameStore := memory.NewGameStore()
+	queueStore := memory.NewQueueStore()
+	roomStore := memory.NewRoomStore()
+	wsManager := services.NewWebSocketManager()
+	gameManager := services.NewGameManager(gameStore, playerStore, wsManager)
+	matchmaker := services.NewMatchmakingService(playerStore, gameStore, queueStore, roomStore, gameManager)
+	handler := NewMatchmakingHandler(matchmaker)
+
+	playerStore.CreatePlayer(&models.Player{ID: "player1", Username: "user1"})
+
+	joinQueue := func(body string) int {
+		req := httptest.NewRequest("POST", "/api/matchmaking/queue", strings.NewReader(body))
+		ctx := context.WithValue(req.Context(), "requestID", "test-req")
+		ctx = context.WithValue(ctx, "playerID", "player1")
+		w := httptest.NewRecorder()
+		handler.JoinQueue(w, req.WithContext(ctx))
+		return w.Code
+	}
+
+	t.Run("empty body joins ranked", func(t *testing.T) {
+		if code := joinQueue(""); code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", code)
+		}
+		if players, _ := queueStore.GetQueuedPlayers("ranked:classic"); len(players) != 1 {
+			t.Errorf("Expected player in ranked:classic, got %v", players)
+		}
+	})
+
+	t.Run("options move player to another queue", func(t *testing.T) {
+		if code := joinQueue(`{"queue":"casual","mode":"sprint"}`); code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", code)
+		}
+		if players, _ := queueStore.GetQueuedPlayers("casual:sprint"); len(players) != 1 {
+			t.Errorf("Expected player in casual:sprint, got %v", players)
+		}
+		if players, _ := queueStore.GetQueuedPlayers("ranked:classic"); len(players) != 0 {
+			t.Errorf("Expected player to leave ranked:classic, got %v", players)
+		}
+	})
+
+	t.Run("unknown queue is rejected", func(t *testing.T) {
+		if code := joinQueue(`{"queue":"tournament"}`); code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", code)
+		}
+	})
+
+	t.Run("malformed body is rejected", func(t *testing.T) {
+		if code := joinQueue(`{"queue":`); code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", code)
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/briancain/go-tetris/internal/server/logger"
+	"github.com/briancain/go-tetris/internal/server/services"
+	"github.com/briancain/go-tetris/internal/server/storage"
+)
+
+// Gauges and counters read from live server state each time Prometheus
+// scrapes, so they never drift from what Metrics reports as JSON
+var (
+	wsConnectionsDesc = prometheus.NewDesc("tetris_websocket_connections",
+		"Open WebSocket connections on this instance.", nil, nil)
+	wsAvgRTTDesc = prometheus.NewDesc("tetris_websocket_rtt_average_seconds",
+		"Average heartbeat round-trip time across WebSocket connections.", nil, nil)
+	wsSendQueueDepthDesc = prometheus.NewDesc("tetris_websocket_send_queue_depth",
+		"Messages waiting in all WebSocket send queues.", nil, nil)
+	wsSendQueueMaxDepthDesc = prometheus.NewDesc("tetris_websocket_send_queue_max_depth",
+		"Messages waiting in the fullest WebSocket send queue.", nil, nil)
+	wsSlowConsumerDropsDesc = prometheus.NewDesc("tetris_websocket_slow_consumer_drops_total",
+		"WebSocket connections closed because their send queue filled up.", nil, nil)
+	wsRateLimitedDesc = prometheus.NewDesc("tetris_websocket_rate_limited_messages_total",
+		"Incoming WebSocket messages dropped by the per-connection rate limit.", nil, nil)
+	wsOversizedDesc = prometheus.NewDesc("tetris_websocket_oversized_messages_total",
+		"WebSocket connections closed for sending a message over the size limit.", nil, nil)
+	httpRateLimitedDesc = prometheus.NewDesc("tetris_http_rate_limited_requests_total",
+		"HTTP requests rejected by a rate limiter, by limiter.", []string{"limiter"}, nil)
+	activeGamesDesc = prometheus.NewDesc("tetris_games_active",
+		"Games waiting to start, in progress or paused, across all instances.", nil, nil)
+	queueLengthDesc = prometheus.NewDesc("tetris_matchmaking_queue_length",
+		"Players waiting in a matchmaking queue, by queue.", []string{"queue"}, nil)
+)
+
+// SetGameSources lets the Prometheus collector report active games and
+// matchmaking queue lengths
+func (h *HealthHandler) SetGameSources(gameStore storage.GameStore, matchmaking *services.MatchmakingService) {
+	h.gameStore = gameStore
+	h.matchmaking = matchmaking
+}
+
+// Describe implements prometheus.Collector
+func (h *HealthHandler) Describe(ch chan<- *prometheus.Desc) {
+	ch <- wsConnectionsDesc
+	ch <- wsAvgRTTDesc
+	ch <- wsSendQueueDepthDesc
+	ch <- wsSendQueueMaxDepthDesc
+	ch <- wsSlowConsumerDropsDesc
+	ch <- wsRateLimitedDesc
+	ch <- wsOversizedDesc
+	ch <- httpRateLimitedDesc
+	ch <- activeGamesDesc
+	ch <- queueLengthDesc
+}
+
+// Collect implements prometheus.Collector
+func (h *HealthHandler) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(wsConnectionsDesc, prometheus.GaugeValue, float64(h.getConnectionCount()))
+	ch <- prometheus.MustNewConstMetric(wsAvgRTTDesc, prometheus.GaugeValue, h.getAverageRTTMs()/1000)
+
+	if h.wsManager != nil {
+		depth, maxDepth := h.wsManager.GetQueueStats()
+		rateLimited, oversized := h.wsManager.GetMessageLimitStats()
+		ch <- prometheus.MustNewConstMetric(wsSendQueueDepthDesc, prometheus.GaugeValue, float64(depth))
+		ch <- prometheus.MustNewConstMetric(wsSendQueueMaxDepthDesc, prometheus.GaugeValue, float64(maxDepth))
+		ch <- prometheus.MustNewConstMetric(wsSlowConsumerDropsDesc, prometheus.CounterValue, float64(h.wsManager.GetSlowConsumerDrops()))
+		ch <- prometheus.MustNewConstMetric(wsRateLimitedDesc, prometheus.CounterValue, float64(rateLimited))
+		ch <- prometheus.MustNewConstMetric(wsOversizedDesc, prometheus.CounterValue, float64(oversized))
+	}
+
+	for _, limiter := range h.rateLimiters {
+		ch <- prometheus.MustNewConstMetric(httpRateLimitedDesc, prometheus.CounterValue, float64(limiter.Rejected()), limiter.Name())
+	}
+
+	if h.gameStore != nil {
+		games, err := h.gameStore.GetActiveGames()
+		if err != nil {
+			logger.Logger.Warn("Failed to count active games for metrics", "error", err)
+		} else {
+			ch <- prometheus.MustNewConstMetric(activeGamesDesc, prometheus.GaugeValue, float64(len(games)))
+		}
+	}
+
+	if h.matchmaking != nil {
+		for queue, length := range h.matchmaking.QueueLengths() {
+			ch <- prometheus.MustNewConstMetric(queueLengthDesc, prometheus.GaugeValue, float64(length), queue)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/briancain/go-tetris/internal/server/middleware"
+	"github.com/briancain/go-tetris/internal/server/services"
+	"github.com/briancain/go-tetris/internal/server/storage/memory"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func TestHealthHandler_Collect(t *testing.T) {
+	wsManager := services.NewWebSocketManager()
+	wsManager.RecordRateLimitedMessage()
+
+	playerStore := memory.NewPlayerStore()
+	gameStore := memory.NewGameStore()
+	queueStore := memory.NewQueueStore()
+	gameManager := services.NewGameManager(gameStore, playerStore, wsManager)
+	matchmaking := services.NewMatchmakingService(playerStore, gameStore, queueStore, memory.NewRoomStore(), gameManager)
+
+	gameStore.CreateGame(&models.GameSession{ID: "game1", Status: models.GameStatusActive})
+	queueStore.AddToQueue(models.QueueOptions{Queue: models.QueueRanked, Mode: models.DefaultGameMode}.Key(), "player1")
+
+	authLimiter := middleware.NewRateLimiter("auth", 1, 1)
+	authLimiter.Allow("ip:10.0.0.1")
+	authLimiter.Allow("ip:10.0.0.1")
+
+	handler := NewHealthHandler(wsManager, &mockHealthChecker{})
+	handler.SetRateLimiters(authLimiter)
+	handler.SetGameSources(gameStore, matchmaking)
+
+	expected := `
+# HELP tetris_games_active Games waiting to start, in progress or paused, across all instances.
+# TYPE tetris_games_active gauge
+tetris_games_active 1
+# HELP tetris_http_rate_limited_requests_total HTTP requests rejected by a rate limiter, by limiter.
+# TYPE tetris_http_rate_limited_requests_total counter
+tetris_http_rate_limited_requests_total{limiter="auth"} 1
+# HELP tetris_matchmaking_queue_length Players waiting in a matchmaking queue, by queue.
+# TYPE tetris_matchmaking_queue_length gauge
+tetris_matchmaking_queue_length{queue="casual:classic"} 0
+tetris_matchmaking_queue_length{queue="ranked:classic"} 1
+# HELP tetris_websocket_rate_limited_messages_total Incoming WebSocket messages dropped by the per-connection rate limit.
+# TYPE tetris_websocket_rate_limited_messages_total counter
+tetris_websocket_rate_limited_messages_total 1
+`
+	err := testutil.CollectAndCompare(handler, strings.NewReader(expected),
+		"tetris_games_active",
+		"tetris_http_rate_limited_requests_total",
+		"tetris_matchmaking_queue_length",
+		"tetris_websocket_rate_limited_messages_total",
+	)
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/briancain/go-tetris/internal/server/glicko"
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+// recentFormGames is how many of a player's latest games their profile's
+// recent form covers
+const recentFormGames = 10
+
+// maxGamesPage is the most games a page of match history can hold
+const maxGamesPage = 50
+
+// PlayerHandler handles player profile and match history requests
+type PlayerHandler struct {
+	playerStore  storage.PlayerStore
+	historyStore storage.MatchHistoryStore
+}
+
+// NewPlayerHandler creates a new player handler
+func NewPlayerHandler(playerStore storage.PlayerStore, historyStore storage.MatchHistoryStore) *PlayerHandler {
+	return &PlayerHandler{
+		playerStore:  playerStore,
+		historyStore: historyStore,
+	}
+}
+
+// PlayerProfile represents a player's stats and recent form
+type PlayerProfile struct {
+	Username         string                `json:"username"`
+	Rating           int                   `json:"rating"`
+	RatingDeviation  int                   `json:"ratingDeviation"`
+	TotalGames       int                   `json:"totalGames"`
+	Wins             int                   `json:"wins"`
+	Losses           int                   `json:"losses"`
+	Draws            int                   `json:"draws"`
+	WinRate          float64               `json:"winRate"` // Fraction of games won
+	HighScore        int                   `json:"highScore"`
+	SeriesPlayed     int                   `json:"seriesPlayed"`
+	SeriesWins       int                   `json:"seriesWins"`
+	RoyaleGames      int                   `json:"royaleGames"`
+	AveragePlacement float64               `json:"averagePlacement,omitempty"`
+	RecentForm       []models.MatchOutcome `json:"recentForm"` // Results of the latest games, newest first
+}
+
+// PlayerGameEntry represents a finished game from one player's point of view
+type PlayerGameEntry struct {
+	GameID          string               `json:"gameId"`
+	Mode            string               `json:"mode,omitempty"`
+	Ranked          bool                 `json:"ranked"`
+	SeriesID        string               `json:"seriesId,omitempty"`
+	Result          models.MatchOutcome  `json:"result"` // "win", "loss" or "draw"
+	Score           int                  `json:"score"`
+	Placement       int                  `json:"placement,omitempty"`
+	Opponents       []PlayerGameOpponent `json:"opponents"`
+	Winner          string               `json:"winner,omitempty"` // Username; empty for a draw
+	DurationSeconds int                  `json:"durationSeconds"`
+	PlayedAt        time.Time            `json:"playedAt"`
+}
+
+// PlayerGameOpponent represents an opponent's result in a game
+type PlayerGameOpponent struct {
+	Username  string `json:"username"`
+	Score     int    `json:"score"`
+	Placement int    `json:"placement,omitempty"`
+}
+
+// HeadToHead represents a player's record against one opponent
+type HeadToHead struct {
+	Opponent string `json:"opponent"`
+	models.HeadToHead
+}
+
+// PlayerGamesResponse represents a page of a player's match history
+type PlayerGamesResponse struct {
+	Games      []PlayerGameEntry `json:"games"`
+	Offset     int               `json:"offset"`
+	Limit      int               `json:"limit"`
+	HasMore    bool              `json:"hasMore"`
+	HeadToHead *HeadToHead       `json:"headToHead,omitempty"` // Set when filtered by opponent
+}
+
+// GetProfile returns a player's stats, rating and recent form
+func (h *PlayerHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	player, err := h.playerStore.GetPlayerByUsername(r.PathValue("username"))
+	if err != nil {
+		http.Error(w, "Player not found", http.StatusNotFound)
+		return
+	}
+
+	games, err := h.historyStore.GetPlayerMatches(player.ID, "", 0, recentFormGames)
+	if err != nil {
+		http.Error(w, "Failed to get games", http.StatusInternalServerError)
+		return
+	}
+
+	rating := glicko.Normalize(player.Rating)
+	profile := PlayerProfile{
+		Username:         player.Username,
+		Rating:           int(math.Round(rating.Value)),
+		RatingDeviation:  int(math.Round(rating.Deviation)),
+		TotalGames:       player.TotalGames,
+		Wins:             player.Wins,
+		Losses:           player.Losses,
+		Draws:            player.TotalGames - player.Wins - player.Losses,
+		HighScore:        player.HighScore,
+		SeriesPlayed:     player.SeriesPlayed,
+		SeriesWins:       player.SeriesWins,
+		RoyaleGames:      player.RoyaleGames,
+		AveragePlacement: player.AveragePlacement(),
+		RecentForm:       []models.MatchOutcome{},
+	}
+	if player.TotalGames > 0 {
+		profile.WinRate = float64(player.Wins) / float64(player.TotalGames)
+	}
+	for _, game := range games {
+		profile.RecentForm = append(profile.RecentForm, game.Outcome(player.ID))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(profile)
+}
+
+// GetGames returns a page of a player's finished games, newest first. An
+// opponent query parameter only lists games against that player and adds
+// the head-to-head record.
+func (h *PlayerHandler) GetGames(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Get paging from query parameters (default the first 10)
+	limit := 10
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= maxGamesPage {
+		limit = l
+	}
+	offset := 0
+	if o, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && o > 0 {
+		offset = o
+	}
+	opponent := r.URL.Query().Get("opponent")
+
+	player, err := h.playerStore.GetPlayerByUsername(r.PathValue("username"))
+	if err != nil {
+		http.Error(w, "Player not found", http.StatusNotFound)
+		return
+	}
+
+	// Fetch one extra game to tell whether there's another page
+	games, err := h.historyStore.GetPlayerMatches(player.ID, opponent, offset, limit+1)
+	if err != nil {
+		http.Error(w, "Failed to get games", http.StatusInternalServerError)
+		return
+	}
+
+	var response PlayerGamesResponse
+	if opponent != "" {
+		record, err := h.historyStore.GetHeadToHead(player.ID, opponent)
+		if err != nil {
+			http.Error(w, "Failed to get head-to-head record", http.StatusInternalServerError)
+			return
+		}
+		response.HeadToHead = &HeadToHead{Opponent: opponent, HeadToHead: *record}
+	}
+
+	response.Offset = offset
+	response.Limit = limit
+	response.HasMore = len(games) > limit
+	response.Games = []PlayerGameEntry{}
+	for i := 0; i < len(games) && i < limit; i++ {
+		response.Games = append(response.Games, playerGameEntry(games[i], player.ID))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// playerGameEntry describes a finished game from a player's point of view
+func playerGameEntry(game *models.MatchRecord, playerID string) PlayerGameEntry {
+	entry := PlayerGameEntry{
+		GameID:    game.GameID,
+		Mode:      game.Mode,
+		Ranked:    game.Ranked,
+		SeriesID:  game.SeriesID,
+		Result:    game.Outcome(playerID),
+		Opponents: []PlayerGameOpponent{},
+		PlayedAt:  game.StartedAt,
+	}
+	if seat := game.Player(playerID); seat != nil {
+		entry.Score = seat.Score
+		entry.Placement = seat.Placement
+	}
+	for _, seat := range game.Opponents(playerID) {
+		entry.Opponents = append(entry.Opponents, PlayerGameOpponent{
+			Username:  seat.Username,
+			Score:     seat.Score,
+			Placement: seat.Placement,
+		})
+	}
+	for _, seat := range game.Players {
+		if seat.Placement == 1 {
+			entry.Winner = seat.Username
+		}
+	}
+	if !game.EndedAt.IsZero() {
+		entry.DurationSeconds = int(game.EndedAt.Sub(game.StartedAt).Seconds())
+	}
+	return entry
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/briancain/go-tetris/internal/server/storage/memory"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+// setupPlayerHistory records three finished games for alice, newest last:
+// a win over bob, a loss to carol and a draw with bob
+func setupPlayerHistory(t *testing.T) *PlayerHandler {
+	t.Helper()
+	playerStore := memory.NewPlayerStore()
+	historyStore := memory.NewMatchHistoryStore()
+
+	alice := &models.Player{ID: "alice", Username: "Alice", TotalGames: 3, Wins: 1, Losses: 1, HighScore: 900}
+	bob := &models.Player{ID: "bob", Username: "Bob"}
+	carol := &models.Player{ID: "carol", Username: "Carol"}
+	playerStore.CreatePlayer(alice)
+
+	start := time.Now().Add(-time.Hour)
+	finished := func(id string, opponent *models.Player, alicePlacement, opponentPlacement int, offset time.Duration) {
+		game := &models.GameSession{
+			ID:        id,
+			Players:   models.NewSeats(alice, opponent),
+			Status:    models.GameStatusFinished,
+			CreatedAt: start.Add(offset),
+			EndedAt:   start.Add(offset + 90*time.Second),
+		}
+		game.Players[0].Score = 900
+		game.Players[0].Placement = alicePlacement
+		game.Players[1].Placement = opponentPlacement
+		historyStore.AddPlayerMatch(alice.ID, models.NewMatchRecord(game))
+	}
+	finished("g1", bob, 1, 2, 0)
+	finished("g2", carol, 2, 1, 10*time.Minute)
+	finished("g3", bob, 0, 0, 20*time.Minute)
+
+	return NewPlayerHandler(playerStore, historyStore)
+}
+
+func TestGetProfile(t *testing.T) {
+	handler := setupPlayerHistory(t)
+
+	req := httptest.NewRequest("GET", "/api/players/Alice", nil)
+	req.SetPathValue("username", "Alice")
+	w := httptest.NewRecorder()
+	handler.GetProfile(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var profile PlayerProfile
+	if err := json.NewDecoder(w.Body).Decode(&profile); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if profile.Username != "Alice" || profile.Draws != 1 || profile.HighScore != 900 {
+		t.Errorf("Unexpected profile stats: %+v", profile)
+	}
+	want := []models.MatchOutcome{models.MatchDraw, models.MatchLoss, models.MatchWin}
+	if len(profile.RecentForm) != len(want) {
+		t.Fatalf("Expected recent form %v, got %v", want, profile.RecentForm)
+	}
+	for i := range want {
+		if profile.RecentForm[i] != want[i] {
+			t.Errorf("Expected recent form %v, got %v", want, profile.RecentForm)
+			break
+		}
+	}
+
+	req = httptest.NewRequest("GET", "/api/players/Nobody", nil)
+	req.SetPathValue("username", "Nobody")
+	w = httptest.NewRecorder()
+	handler.GetProfile(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown player, got %d", w.Code)
+	}
+}
+
+func TestGetGames(t *testing.T) {
+	handler := setupPlayerHistory(t)
+
+	getGames := func(query string) PlayerGamesResponse {
+		req := httptest.NewRequest("GET", "/api/players/Alice/games?"+query, nil)
+		req.SetPathValue("username", "Alice")
+		w := httptest.NewRecorder()
+		handler.GetGames(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		var response PlayerGamesResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return response
+	}
+
+	page := getGames("limit=2")
+	if len(page.Games) != 2 || !page.HasMore || page.Games[0].GameID != "g3" {
+		t.Fatalf("Expected the 2 newest finished games with more to come, got %+v", page)
+	}
+	loss := page.Games[1]
+	if loss.Result != models.MatchLoss || loss.Winner != "Carol" || loss.Opponents[0].Username != "Carol" || loss.DurationSeconds != 90 {
+		t.Errorf("Unexpected game entry: %+v", loss)
+	}
+
+	page = getGames("limit=2&offset=2")
+	if len(page.Games) != 1 || page.HasMore || page.Games[0].GameID != "g1" {
+		t.Errorf("Expected the oldest game on the last page, got %+v", page)
+	}
+
+	page = getGames("opponent=Bob")
+	if len(page.Games) != 2 || page.HeadToHead == nil {
+		t.Fatalf("Expected the 2 games against Bob, got %+v", page)
+	}
+	if page.HeadToHead.Wins != 1 || page.HeadToHead.Losses != 0 || page.HeadToHead.Draws != 1 {
+		t.Errorf("Expected a 1-0-1 record against Bob, got %+v", page.HeadToHead)
+	}
+}
+
+func TestGetGames_PagesThroughLongHistory(t *testing.T) {
+	playerStore := memory.NewPlayerStore()
+	historyStore := memory.NewMatchHistoryStore()
+	handler := NewPlayerHandler(playerStore, historyStore)
+
+	alice := &models.Player{ID: "alice", Username: "Alice"}
+	bob := &models.Player{ID: "bob", Username: "Bob"}
+	playerStore.CreatePlayer(alice)
+	for i := 0; i < 60; i++ {
+		game := &models.GameSession{ID: fmt.Sprintf("g%d", i), Players: models.NewSeats(alice, bob)}
+		historyStore.AddPlayerMatch(alice.ID, models.NewMatchRecord(game))
+	}
+
+	req := httptest.NewRequest("GET", "/api/players/Alice/games?offset=50&limit=10", nil)
+	req.SetPathValue("username", "Alice")
+	w := httptest.NewRecorder()
+	handler.GetGames(w, req)
+
+	var page PlayerGamesResponse
+	if err := json.NewDecoder(w.Body).Decode(&page); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(page.Games) != 10 || page.HasMore || page.Games[9].GameID != "g0" {
+		t.Errorf("Expected the 10 oldest games on the last page, got %d games ending %+v (hasMore %v)", len(page.Games), page.Games, page.HasMore)
+	}
+}
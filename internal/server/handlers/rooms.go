@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/briancain/go-tetris/internal/server/logger"
+	"github.com/briancain/go-tetris/internal/server/services"
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+// RoomHandler handles private room endpoints
+type RoomHandler struct {
+	matchmakingService *services.MatchmakingService
+}
+
+// NewRoomHandler creates a new room handler
+func NewRoomHandler(matchmakingService *services.MatchmakingService) *RoomHandler {
+	return &RoomHandler{
+		matchmakingService: matchmakingService,
+	}
+}
+
+// RoomResponse represents a room and who is seated in it
+type RoomResponse struct {
+	Code     string              `json:"code"`
+	HostID   string              `json:"hostId"`
+	Players  []string            `json:"players"`
+	Settings models.RoomSettings `json:"settings"`
+}
+
+// JoinRoomRequest represents a request to join a room
+type JoinRoomRequest struct {
+	Code string `json:"code"`
+}
+
+// JoinRoomResponse represents the room joined and, once it filled, the game started
+type JoinRoomResponse struct {
+	GameID string        `json:"gameId,omitempty"`
+	Room   *RoomResponse `json:"room,omitempty"`
+}
+
+// StartRoomRequest represents a host starting their room before it fills
+type StartRoomRequest struct {
+	Code string `json:"code"`
+}
+
+// StartRoomResponse represents the game started from a room
+type StartRoomResponse struct {
+	GameID string `json:"gameId"`
+}
+
+// CreateRoom handles opening a private room with the host's settings
+func (h *RoomHandler) CreateRoom(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value("requestID").(string)
+
+	if r.Method != http.MethodPost {
+		logger.Logger.Warn("Invalid method for create room",
+			"requestID", requestID,
+			"method", r.Method,
+		)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Get player from context (set by auth middleware)
+	playerID := r.Context().Value("playerID").(string)
+
+	// Settings are optional; an empty body uses the defaults
+	var settings models.RoomSettings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	room, err := h.matchmakingService.CreateRoom(playerID, settings)
+	if err != nil {
+		logger.Logger.Warn("Failed to create room",
+			"requestID", requestID,
+			"playerID", playerID,
+			"error", err,
+		)
+		http.Error(w, err.Error(), roomErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(newRoomResponse(room))
+}
+
+// JoinRoom handles joining a private room by code, which starts the match once the room is full
+func (h *RoomHandler) JoinRoom(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value("requestID").(string)
+
+	if r.Method != http.MethodPost {
+		logger.Logger.Warn("Invalid method for join room",
+			"requestID", requestID,
+			"method", r.Method,
+		)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Get player from context (set by auth middleware)
+	playerID := r.Context().Value("playerID").(string)
+
+	var req JoinRoomRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" {
+		http.Error(w, "Room code is required", http.StatusBadRequest)
+		return
+	}
+
+	room, game, err := h.matchmakingService.JoinRoom(r.Context(), playerID, req.Code)
+	if err != nil {
+		logger.Logger.Warn("Failed to join room",
+			"requestID", requestID,
+			"playerID", playerID,
+			"roomCode", req.Code,
+			"error", err,
+		)
+		http.Error(w, err.Error(), roomErrorStatus(err))
+		return
+	}
+
+	resp := JoinRoomResponse{Room: newRoomResponse(room)}
+	if game != nil {
+		resp.GameID = game.ID
+	}
+
+	logger.Logger.Info("Player joined room",
+		"requestID", requestID,
+		"playerID", playerID,
+		"roomCode", req.Code,
+		"gameID", resp.GameID,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// StartRoom handles a host starting their room with the players seated so far
+func (h *RoomHandler) StartRoom(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value("requestID").(string)
+
+	if r.Method != http.MethodPost {
+		logger.Logger.Warn("Invalid method for start room",
+			"requestID", requestID,
+			"method", r.Method,
+		)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Get player from context (set by auth middleware)
+	playerID := r.Context().Value("playerID").(string)
+
+	var req StartRoomRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" {
+		http.Error(w, "Room code is required", http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.matchmakingService.StartRoom(r.Context(), playerID, req.Code)
+	if err != nil {
+		logger.Logger.Warn("Failed to start room",
+			"requestID", requestID,
+			"playerID", playerID,
+			"roomCode", req.Code,
+			"error", err,
+		)
+		http.Error(w, err.Error(), roomErrorStatus(err))
+		return
+	}
+
+	logger.Logger.Info("Room started",
+		"requestID", requestID,
+		"playerID", playerID,
+		"roomCode", req.Code,
+		"gameID", game.ID,
+		"players", len(game.Players),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(StartRoomResponse{GameID: game.ID})
+}
+
+// CloseRoom handles a host closing their room before anyone joins
+func (h *RoomHandler) CloseRoom(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Context().Value("requestID").(string)
+
+	if r.Method != http.MethodDelete {
+		logger.Logger.Warn("Invalid method for close room",
+			"requestID", requestID,
+			"method", r.Method,
+		)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Get player from context (set by auth middleware)
+	playerID := r.Context().Value("playerID").(string)
+
+	code := r.URL.Query().Get("code")
+	if code == "" {
+		http.Error(w, "Room code is required", http.StatusBadRequest)
+		return
+	}
+
+	err := h.matchmakingService.CloseRoom(playerID, code)
+	if err != nil {
+		http.Error(w, err.Error(), roomErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// newRoomResponse converts a room to its API representation
+func newRoomResponse(room *models.Room) *RoomResponse {
+	return &RoomResponse{
+		Code:     room.Code,
+		HostID:   room.HostID,
+		Players:  room.PlayerIDs(),
+		Settings: room.Settings,
+	}
+}
+
+// roomErrorStatus maps room errors to HTTP status codes
+func roomErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrInvalidRoomSettings), errors.Is(err, services.ErrInvalidGameMode),
+		errors.Is(err, services.ErrOwnRoom), errors.Is(err, services.ErrInvalidTargeting):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrNotRoomHost):
+		return http.StatusForbidden
+	case errors.Is(err, storage.ErrRoomNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, storage.ErrRoomFull), errors.Is(err, services.ErrAlreadyInGame),
+		errors.Is(err, services.ErrRoomNotReady):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrServerDraining):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
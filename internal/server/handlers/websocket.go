@@ -1,21 +1,39 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/briancain/go-tetris/internal/server/logger"
+	"github.com/briancain/go-tetris/internal/server/metrics"
+	"github.com/briancain/go-tetris/internal/server/middleware"
 	"github.com/briancain/go-tetris/internal/server/services"
+	"github.com/briancain/go-tetris/internal/server/tracing"
 	"github.com/briancain/go-tetris/pkg/models"
 )
 
+// Clients offer the game's WebSocket subprotocol plus a second "bearer."
+// protocol carrying their session token, which keeps the token out of URLs
+// and access logs. Only the game protocol is echoed back.
+const (
+	wsSubprotocol      = "tetris"
+	wsTokenProtocolTag = "bearer."
+)
+
 var upgrader = websocket.Upgrader{
 	CheckOrigin: func(r *http.Request) bool {
 		return true // Allow all origins for development
 	},
+	Subprotocols: []string{wsSubprotocol},
 }
 
 // WebSocketHandler handles WebSocket connections
@@ -23,6 +41,17 @@ type WebSocketHandler struct {
 	wsManager   *services.WebSocketManager
 	authService *services.AuthService
 	gameManager *services.GameManager
+
+	// reconnectGrace is how long a dropped player's game and session are
+	// held open; zero forfeits and cleans up immediately
+	reconnectGrace     time.Duration
+	pendingDisconnects map[string]*time.Timer // playerID -> forfeit/cleanup timer
+	mu                 sync.Mutex             // Protects pendingDisconnects
+
+	// Per-connection limits on incoming messages; zero disables each
+	messageRate    float64 // Messages per second
+	messageBurst   int
+	maxMessageSize int64 // Bytes
 }
 
 // NewWebSocketHandler creates a new WebSocket handler
@@ -32,16 +61,30 @@ func NewWebSocketHandler(
 	gameManager *services.GameManager,
 ) *WebSocketHandler {
 	return &WebSocketHandler{
-		wsManager:   wsManager,
-		authService: authService,
-		gameManager: gameManager,
+		wsManager:          wsManager,
+		authService:        authService,
+		gameManager:        gameManager,
+		pendingDisconnects: make(map[string]*time.Timer),
 	}
 }
 
+// SetReconnectGracePeriod sets how long disconnected players may take to reconnect
+func (h *WebSocketHandler) SetReconnectGracePeriod(gracePeriod time.Duration) {
+	h.reconnectGrace = gracePeriod
+}
+
+// SetMessageLimits caps how many messages per second, with bursts of up to
+// burst, and how many bytes per message each connection may send
+func (h *WebSocketHandler) SetMessageLimits(rate float64, burst int, maxSize int64) {
+	h.messageRate = rate
+	h.messageBurst = burst
+	h.maxMessageSize = maxSize
+}
+
 // HandleWebSocket handles WebSocket connections
 func (h *WebSocketHandler) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
-	// Get session token from query parameter
-	token := r.URL.Query().Get("token")
+	// Get session token from the subprotocol header
+	token := tokenFromSubprotocols(websocket.Subprotocols(r))
 	if token == "" {
 		logger.Logger.Warn("WebSocket connection attempt without token",
 			"remoteAddr", r.RemoteAddr,
@@ -50,8 +93,9 @@ func (h *WebSocketHandler) HandleWebSocket(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// Validate token
-	player, err := h.authService.ValidateToken(token)
+	// Validate token, making sure its session hasn't been revoked since a
+	// connection can outlive the token
+	player, err := h.authService.ValidateSession(token)
 	if err != nil {
 		logger.Logger.Warn("WebSocket connection attempt with invalid token",
 			"remoteAddr", r.RemoteAddr,
@@ -61,6 +105,13 @@ func (h *WebSocketHandler) HandleWebSocket(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// A draining server only takes back players returning to a game, so
+	// everyone else connects to another instance
+	if h.gameManager.Draining() && player.GameID == "" {
+		http.Error(w, "Server is shutting down", http.StatusServiceUnavailable)
+		return
+	}
+
 	// Upgrade connection
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
@@ -78,24 +129,75 @@ func (h *WebSocketHandler) HandleWebSocket(w http.ResponseWriter, r *http.Reques
 		"remoteAddr", r.RemoteAddr,
 	)
 
+	// A player is resuming if their old connection is still registered or
+	// they dropped, from this or another instance, within the reconnect
+	// grace window
+	replaced := h.wsManager.HasConnection(player.ID)
+
 	// Add connection to manager
 	h.wsManager.AddConnection(player.ID, conn)
 
+	// Stop any grace timer running here and release the shared hold that
+	// another instance's timer checks before forfeiting
+	cancelled := h.cancelPendingDisconnect(player.ID)
+	released := h.wsManager.ReleaseDisconnect(player.ID)
+
+	// Resume the player's game so their client can resync
+	if cancelled || released || replaced {
+		err = h.gameManager.HandlePlayerReconnect(player.ID)
+		if err != nil {
+			logger.Logger.Error("Failed to resume game after reconnect",
+				"playerID", player.ID,
+				"error", err,
+			)
+		}
+	}
+
 	// Handle messages
 	go h.handleMessages(player.ID, conn)
 }
 
+// tokenFromSubprotocols returns the session token offered as a subprotocol
+func tokenFromSubprotocols(protocols []string) string {
+	for _, protocol := range protocols {
+		if token, found := strings.CutPrefix(protocol, wsTokenProtocolTag); found {
+			return token
+		}
+	}
+	return ""
+}
+
 // handleMessages processes incoming WebSocket messages
 func (h *WebSocketHandler) handleMessages(playerID string, conn *websocket.Conn) {
 	defer func() {
-		h.handlePlayerDisconnect(playerID)
-		h.wsManager.RemoveConnection(playerID)
+		h.wsManager.RemoveConnectionIfCurrent(playerID, conn)
 		conn.Close()
+
+		// A newer connection for the same player, here or on another
+		// instance, replaced this one, so the player has not actually gone away
+		if h.wsManager.ConnectedAnywhere(playerID) {
+			return
+		}
+		h.handlePlayerDisconnect(playerID)
 	}()
 
+	// Oversized messages fail the read and close the connection
+	if h.maxMessageSize > 0 {
+		conn.SetReadLimit(h.maxMessageSize)
+	}
+	limiter := middleware.NewTokenBucket(h.messageRate, h.messageBurst)
+	throttled := false
+
 	for {
 		_, messageData, err := conn.ReadMessage()
 		if err != nil {
+			if errors.Is(err, websocket.ErrReadLimit) {
+				h.wsManager.RecordOversizedMessage()
+				logger.Logger.Warn("WebSocket message too large",
+					"playerID", playerID,
+					"maxMessageSize", h.maxMessageSize,
+				)
+			}
 			logger.Logger.Info("WebSocket connection closed",
 				"playerID", playerID,
 				"error", err,
@@ -103,6 +205,22 @@ func (h *WebSocketHandler) handleMessages(playerID string, conn *websocket.Conn)
 			break
 		}
 
+		// Reject messages over the rate limit before they touch storage or
+		// the game manager, telling the client which message was refused so
+		// it can resend it. Logs once per run of rejected messages.
+		if !limiter.Allow() {
+			h.wsManager.RecordRateLimitedMessage()
+			if !throttled {
+				logger.Logger.Warn("WebSocket messages rate limited",
+					"playerID", playerID,
+				)
+				throttled = true
+			}
+			h.sendRateLimited(playerID, messageData)
+			continue
+		}
+		throttled = false
+
 		// Update player activity on any message
 		err = h.authService.UpdatePlayerActivity(playerID)
 		if err != nil {
@@ -121,6 +239,7 @@ func (h *WebSocketHandler) handleMessages(playerID string, conn *websocket.Conn)
 				"error", err,
 				"rawMessage", string(messageData),
 			)
+			metrics.WebSocketMessages.WithLabelValues("invalid").Inc()
 			continue
 		}
 
@@ -131,6 +250,7 @@ func (h *WebSocketHandler) handleMessages(playerID string, conn *websocket.Conn)
 				"playerID", playerID,
 				"message", message,
 			)
+			metrics.WebSocketMessages.WithLabelValues("invalid").Inc()
 			continue
 		}
 
@@ -139,6 +259,14 @@ func (h *WebSocketHandler) handleMessages(playerID string, conn *websocket.Conn)
 			"messageType", messageType,
 		)
 
+		// Unknown types share a label so clients can't mint new series
+		messageLabel := messageType
+		_, span := tracing.Start(context.Background(), "websocket.message",
+			tracing.PlayerID(playerID),
+		)
+		if gameID, ok := message["gameId"].(string); ok && gameID != "" {
+			span.SetAttributes(tracing.GameID(gameID))
+		}
 		switch messageType {
 		case "game_move":
 			h.handleGameMove(playerID, message)
@@ -148,14 +276,24 @@ func (h *WebSocketHandler) handleMessages(playerID string, conn *websocket.Conn)
 			h.handleGameOver(playerID, message)
 		case "rematch_request":
 			h.handleRematchRequest(playerID, message)
+		case "set_targeting":
+			h.handleSetTargeting(playerID, message)
+		case "spectate":
+			h.handleSpectate(playerID, message)
+		case "stop_spectating":
+			h.gameManager.StopSpectating(playerID)
 		case "ping":
-			h.handlePing(playerID)
+			h.handlePing(playerID, message)
 		default:
 			logger.Logger.Warn("Unknown WebSocket message type",
 				"playerID", playerID,
 				"messageType", messageType,
 			)
+			messageLabel = "unknown"
 		}
+		span.SetAttributes(attribute.String("message.type", messageLabel))
+		span.End()
+		metrics.WebSocketMessages.WithLabelValues(messageLabel).Inc()
 	}
 }
 
@@ -172,6 +310,7 @@ func (h *WebSocketHandler) handleGameMove(playerID string, message map[string]in
 	move := &models.GameMove{
 		PlayerID:  playerID,
 		MoveType:  moveType,
+		Piece:     parsePiece(message["piece"]),
 		Timestamp: time.Now(),
 	}
 
@@ -211,12 +350,15 @@ func (h *WebSocketHandler) handleGameState(playerID string, message map[string]i
 	}
 
 	state := &models.GameState{
-		PlayerID:  playerID,
-		Board:     gameBoard,
-		Score:     int(score),
-		Level:     int(level),
-		Lines:     int(lines),
-		Timestamp: time.Now(),
+		PlayerID:     playerID,
+		Board:        gameBoard,
+		Score:        int(score),
+		Level:        int(level),
+		Lines:        int(lines),
+		CurrentPiece: parsePiece(message["currentPiece"]),
+		HoldPiece:    parsePiece(message["holdPiece"]),
+		NextPiece:    parsePiece(message["nextPiece"]),
+		Timestamp:    time.Now(),
 	}
 
 	logger.Logger.Debug("Game state update received",
@@ -236,6 +378,30 @@ func (h *WebSocketHandler) handleGameState(playerID string, message map[string]i
 	}
 }
 
+// parsePiece extracts an optional piece from a message field
+func parsePiece(value interface{}) *models.Piece {
+	pieceData, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	pieceType, ok := pieceData["type"].(float64)
+	if !ok {
+		return nil
+	}
+
+	x, _ := pieceData["x"].(float64)
+	y, _ := pieceData["y"].(float64)
+	rotation, _ := pieceData["rotation"].(float64)
+
+	return &models.Piece{
+		Type:     int(pieceType),
+		X:        int(x),
+		Y:        int(y),
+		Rotation: int(rotation),
+	}
+}
+
 // handleGameOver processes a game over message
 func (h *WebSocketHandler) handleGameOver(playerID string, message map[string]interface{}) {
 	gameID, ok := message["gameId"].(string)
@@ -261,6 +427,42 @@ func (h *WebSocketHandler) handleGameOver(playerID string, message map[string]in
 	}
 }
 
+// handleSetTargeting changes who a player's garbage is sent to
+func (h *WebSocketHandler) handleSetTargeting(playerID string, message map[string]interface{}) {
+	strategy, _ := message["strategy"].(string)
+
+	err := h.gameManager.SetTargeting(playerID, models.TargetingStrategy(strategy))
+	if err != nil {
+		logger.Logger.Warn("Failed to set targeting",
+			"playerID", playerID,
+			"strategy", strategy,
+			"error", err,
+		)
+	}
+}
+
+// handleSpectate subscribes a player to a live game, telling them if they can't
+// watch it
+func (h *WebSocketHandler) handleSpectate(playerID string, message map[string]interface{}) {
+	gameID, _ := message["gameId"].(string)
+
+	err := h.gameManager.Spectate(playerID, gameID)
+	if err != nil {
+		logger.Logger.Warn("Failed to spectate game",
+			"playerID", playerID,
+			"gameID", gameID,
+			"error", err,
+		)
+
+		data, _ := json.Marshal(map[string]interface{}{
+			"type":   "spectate_error",
+			"gameId": gameID,
+			"error":  err.Error(),
+		})
+		h.wsManager.SendToPlayer(playerID, data)
+	}
+}
+
 // handleRematchRequest processes a rematch request
 func (h *WebSocketHandler) handleRematchRequest(playerID string, message map[string]interface{}) {
 	logger.Logger.Info("Rematch request received",
@@ -276,11 +478,18 @@ func (h *WebSocketHandler) handleRematchRequest(playerID string, message map[str
 	}
 }
 
-// handlePing responds to ping messages
-func (h *WebSocketHandler) handlePing(playerID string) {
+// handlePing responds to ping messages, echoing the client's timestamp so it
+// can measure round-trip time
+func (h *WebSocketHandler) handlePing(playerID string, message map[string]interface{}) {
 	pongMsg := map[string]interface{}{
 		"type": "pong",
 	}
+	if timestamp, ok := message["timestamp"]; ok {
+		pongMsg["timestamp"] = timestamp
+	}
+	if rtt, ok := h.wsManager.GetPlayerRTT(playerID); ok {
+		pongMsg["serverRttMillis"] = rtt.Milliseconds()
+	}
 
 	data, _ := json.Marshal(pongMsg)
 	h.wsManager.SendToPlayer(playerID, data)
@@ -290,11 +499,130 @@ func (h *WebSocketHandler) handlePing(playerID string) {
 	)
 }
 
-// handlePlayerDisconnect handles when a player disconnects
+// sendRateLimited tells a client one of its messages was refused by the
+// rate limit, and how long to wait before sending it again
+func (h *WebSocketHandler) sendRateLimited(playerID string, messageData []byte) {
+	var refused struct {
+		Type string `json:"type"`
+	}
+	// A message that doesn't parse is refused with an empty type
+	_ = json.Unmarshal(messageData, &refused)
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"type":         "rate_limited",
+		"messageType":  refused.Type,
+		"retryAfterMs": int64(math.Ceil(1000 / h.messageRate)),
+	})
+	h.wsManager.SendToPlayer(playerID, data)
+}
+
+// handlePlayerDisconnect handles when a player's connection drops, holding
+// their game and session open for the reconnect grace period
 func (h *WebSocketHandler) handlePlayerDisconnect(playerID string) {
 	logger.Logger.Info("Player disconnected from WebSocket",
 		"playerID", playerID,
+		"reconnectGrace", h.reconnectGrace.String(),
+	)
+	metrics.Disconnects.Inc()
+
+	if h.reconnectGrace <= 0 {
+		h.finalizeDisconnect(playerID)
+		return
+	}
+
+	err := h.gameManager.PauseForDisconnect(playerID, h.reconnectGrace)
+	if err != nil {
+		logger.Logger.Error("Failed to pause game for disconnect",
+			"playerID", playerID,
+			"error", err,
+		)
+	}
+
+	// Share the hold so the instance the player reconnects through can
+	// release it, stopping this instance's timer from forfeiting
+	h.wsManager.HoldDisconnect(playerID, h.reconnectGrace)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if timer, exists := h.pendingDisconnects[playerID]; exists {
+		timer.Stop()
+	}
+	h.pendingDisconnects[playerID] = time.AfterFunc(h.reconnectGrace, func() {
+		if !h.cancelPendingDisconnect(playerID) {
+			return // Player reconnected to this instance in the meantime
+		}
+		h.expireDisconnect(playerID)
+	})
+}
+
+// expireDisconnect forfeits a player whose reconnect grace period ran out,
+// unless they came back through another instance
+func (h *WebSocketHandler) expireDisconnect(playerID string) {
+	held := h.wsManager.ExpireDisconnect(playerID)
+
+	if h.wsManager.ConnectedAnywhere(playerID) {
+		// They reconnected elsewhere before that instance could release the
+		// hold, so it didn't resume their game
+		if held {
+			if err := h.gameManager.HandlePlayerReconnect(playerID); err != nil {
+				logger.Logger.Error("Failed to resume game after reconnect",
+					"playerID", playerID,
+					"error", err,
+				)
+			}
+		}
+		return
+	}
+	if !held {
+		return // Released by the instance the player reconnected through
+	}
+
+	logger.Logger.Info("Reconnect grace period expired",
+		"playerID", playerID,
 	)
+	h.finalizeDisconnect(playerID)
+}
+
+// Kick disconnects a player on an operator's behalf without a chance to
+// reconnect: their game is forfeited, their session ended and their
+// connection closed with the reason, on whichever instance holds it. It
+// reports whether they were connected anywhere.
+func (h *WebSocketHandler) Kick(playerID, reason string) bool {
+	h.cancelPendingDisconnect(playerID)
+	h.wsManager.ReleaseDisconnect(playerID)
+
+	// Forfeit before the session ends, since ending a guest's session
+	// deletes the player their game is found through
+	h.finalizeDisconnect(playerID)
+
+	connected := h.wsManager.CloseConnectionAnywhere(playerID, reason)
+	logger.Logger.Info("Player kicked",
+		"playerID", playerID,
+		"reason", reason,
+		"connected", connected,
+	)
+	return connected
+}
+
+// cancelPendingDisconnect stops a player's grace timer, reporting whether one was pending
+func (h *WebSocketHandler) cancelPendingDisconnect(playerID string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	timer, exists := h.pendingDisconnects[playerID]
+	if !exists {
+		return false
+	}
+
+	timer.Stop()
+	delete(h.pendingDisconnects, playerID)
+	return true
+}
+
+// finalizeDisconnect forfeits the player's game and removes their session
+func (h *WebSocketHandler) finalizeDisconnect(playerID string) {
+	h.gameManager.StopSpectating(playerID)
 
 	// Notify game manager of disconnect
 	err := h.gameManager.HandlePlayerDisconnect(playerID)
@@ -305,10 +633,10 @@ func (h *WebSocketHandler) handlePlayerDisconnect(playerID string) {
 		)
 	}
 
-	// Clean up player session to allow reconnection with same username
+	// End the session; guests are removed to free their username for reuse
 	player, err := h.authService.GetPlayerByID(playerID)
 	if err == nil && player != nil {
-		err = h.authService.DeletePlayer(player.ID)
+		err = h.authService.EndSession(player.ID)
 		if err != nil {
 			logger.Logger.Error("Failed to clean up disconnected player",
 				"playerID", playerID,
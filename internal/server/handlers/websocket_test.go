@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/briancain/go-tetris/internal/server/metrics"
+	"github.com/briancain/go-tetris/internal/server/services"
+	"github.com/briancain/go-tetris/internal/server/storage/memory"
+	"github.com/briancain/go-tetris/internal/server/storage/redis"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func TestHandleWebSocket_TokenSubprotocol(t *testing.T) {
+	playerStore := memory.NewPlayerStore()
+	authService := services.NewAuthService(playerStore, memory.NewAccountStore())
+	wsManager := services.NewWebSocketManager()
+	gameManager := services.NewGameManager(memory.NewGameStore(), playerStore, wsManager)
+	handler := NewWebSocketHandler(wsManager, authService, gameManager)
+
+	server := httptest.NewServer(http.HandlerFunc(handler.HandleWebSocket))
+	defer server.Close()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	// Registered, so closing the test connection ends the session without
+	// deleting the player out from under the test
+	player, err := authService.Register("alice", "correct horse")
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	tokens, err := authService.IssueTokens(player)
+	if err != nil {
+		t.Fatalf("Failed to issue tokens: %v", err)
+	}
+
+	dial := func(rawURL string, protocols ...string) (*websocket.Conn, *http.Response, error) {
+		dialer := *websocket.DefaultDialer
+		dialer.Subprotocols = protocols
+		return dialer.Dial(rawURL, nil)
+	}
+
+	// Tokens in the query string are no longer accepted
+	if _, resp, err := dial(wsURL + "?token=" + tokens.AccessToken); err == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a query string token, got %v", err)
+	}
+	if _, resp, err := dial(wsURL, "tetris", "bearer.not-a-token"); err == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for an invalid token, got %v", err)
+	}
+
+	conn, resp, err := dial(wsURL, "tetris", "bearer."+tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("Expected connection with a subprotocol token, got %v", err)
+	}
+	conn.Close()
+	if protocol := resp.Header.Get("Sec-WebSocket-Protocol"); protocol != "tetris" {
+		t.Errorf("Expected only the game subprotocol to be echoed, got %q", protocol)
+	}
+
+	// A revoked session can't open new connections
+	if err := authService.EndSession(player.ID); err != nil {
+		t.Fatalf("EndSession failed: %v", err)
+	}
+	if _, resp, err := dial(wsURL, "tetris", "bearer."+tokens.AccessToken); err == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a revoked session, got %v", err)
+	}
+}
+
+func TestHandleWebSocket_MessageLimits(t *testing.T) {
+	playerStore := memory.NewPlayerStore()
+	authService := services.NewAuthService(playerStore, memory.NewAccountStore())
+	wsManager := services.NewWebSocketManager()
+	gameManager := services.NewGameManager(memory.NewGameStore(), playerStore, wsManager)
+	handler := NewWebSocketHandler(wsManager, authService, gameManager)
+	handler.SetMessageLimits(0.001, 2, 256)
+
+	server := httptest.NewServer(http.HandlerFunc(handler.HandleWebSocket))
+	defer server.Close()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	player, err := authService.Register("alice", "correct horse")
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	tokens, err := authService.IssueTokens(player)
+	if err != nil {
+		t.Fatalf("Failed to issue tokens: %v", err)
+	}
+
+	dialer := *websocket.DefaultDialer
+	dialer.Subprotocols = []string{"tetris", "bearer." + tokens.AccessToken}
+	conn, _, err := dialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+	pings := testutil.ToFloat64(metrics.WebSocketMessages.WithLabelValues("ping"))
+
+	// Only the burst of two pings is answered; the third is refused
+	for i := 1; i <= 3; i++ {
+		if err := conn.WriteJSON(map[string]interface{}{"type": "ping", "timestamp": i}); err != nil {
+			t.Fatalf("Failed to send ping: %v", err)
+		}
+	}
+	for i := 1; i <= 2; i++ {
+		var pong map[string]interface{}
+		if err := conn.ReadJSON(&pong); err != nil {
+			t.Fatalf("Failed to read pong: %v", err)
+		}
+		if pong["timestamp"] != float64(i) {
+			t.Errorf("Expected pong for ping %d, got %v", i, pong["timestamp"])
+		}
+	}
+	var refused map[string]interface{}
+	if err := conn.ReadJSON(&refused); err != nil {
+		t.Fatalf("Failed to read rate limit error: %v", err)
+	}
+	if refused["type"] != "rate_limited" || refused["messageType"] != "ping" || refused["retryAfterMs"] != float64(1000000) {
+		t.Errorf("Expected the refused ping to be reported with its retry delay, got %v", refused)
+	}
+
+	// An oversized message closes the connection
+	if err := conn.WriteMessage(websocket.TextMessage, make([]byte, 512)); err != nil {
+		t.Fatalf("Failed to send oversized message: %v", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseMessageTooBig) {
+		t.Errorf("Expected close for a message too big, got %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		rateLimited, oversized := wsManager.GetMessageLimitStats()
+		if rateLimited == 1 && oversized == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected 1 rate limited and 1 oversized message, got %d and %d", rateLimited, oversized)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// The read loop has finished, so every accepted message is counted
+	if got := testutil.ToFloat64(metrics.WebSocketMessages.WithLabelValues("ping")) - pings; got != 2 {
+		t.Errorf("Expected 2 pings counted, got %v", got)
+	}
+}
+
+func TestHandleWebSocket_Draining(t *testing.T) {
+	playerStore := memory.NewPlayerStore()
+	authService := services.NewAuthService(playerStore, memory.NewAccountStore())
+	wsManager := services.NewWebSocketManager()
+	gameManager := services.NewGameManager(memory.NewGameStore(), playerStore, wsManager)
+	handler := NewWebSocketHandler(wsManager, authService, gameManager)
+
+	server := httptest.NewServer(http.HandlerFunc(handler.HandleWebSocket))
+	defer server.Close()
+
+	player, err := authService.Register("alice", "correct horse")
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	tokens, err := authService.IssueTokens(player)
+	if err != nil {
+		t.Fatalf("Failed to issue tokens: %v", err)
+	}
+
+	dial := func() (*websocket.Conn, *http.Response, error) {
+		dialer := *websocket.DefaultDialer
+		dialer.Subprotocols = []string{"tetris", "bearer." + tokens.AccessToken}
+		return dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	}
+
+	gameManager.Drain("Restarting")
+
+	// New connections go to another instance
+	if _, resp, err := dial(); err == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 while draining, got %v", err)
+	}
+
+	// Players coming back to a game can still finish it here
+	player.GameID = "game1"
+	if err := playerStore.UpdatePlayer(player); err != nil {
+		t.Fatalf("UpdatePlayer failed: %v", err)
+	}
+	conn, _, err := dial()
+	if err != nil {
+		t.Fatalf("Expected a player in a game to reconnect while draining, got %v", err)
+	}
+	conn.Close()
+}
+
+func TestHandleWebSocket_ReconnectThroughAnotherInstance(t *testing.T) {
+	client, err := redis.NewClient("redis://localhost:6379")
+	if err != nil {
+		t.Fatalf("Failed to create Redis client: %v", err)
+	}
+	if err := client.HealthCheck(); err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+
+	playerStore := memory.NewPlayerStore()
+	gameStore := memory.NewGameStore()
+	authService := services.NewAuthService(playerStore, memory.NewAccountStore())
+	gracePeriod := 200 * time.Millisecond
+
+	// Two instances behind a load balancer, sharing storage and a backplane
+	newInstance := func() string {
+		backplane := redis.NewBackplane(client)
+		wsManager := services.NewWebSocketManager()
+		if err := wsManager.SetBackplane(backplane); err != nil {
+			t.Fatalf("SetBackplane failed: %v", err)
+		}
+		t.Cleanup(func() { backplane.Close() })
+
+		gameManager := services.NewGameManager(gameStore, playerStore, wsManager)
+		handler := NewWebSocketHandler(wsManager, authService, gameManager)
+		handler.SetReconnectGracePeriod(gracePeriod)
+
+		server := httptest.NewServer(http.HandlerFunc(handler.HandleWebSocket))
+		t.Cleanup(server.Close)
+		return "ws" + strings.TrimPrefix(server.URL, "http")
+	}
+	instanceA, instanceB := newInstance(), newInstance()
+
+	game := &models.GameSession{ID: "cross-instance-game", Status: models.GameStatusActive, CreatedAt: time.Now()}
+	tokens := make(map[string]string)
+	for _, username := range []string{"alice", "bob"} {
+		player, err := authService.Register(username, "correct horse")
+		if err != nil {
+			t.Fatalf("Register failed: %v", err)
+		}
+		issued, err := authService.IssueTokens(player)
+		if err != nil {
+			t.Fatalf("Failed to issue tokens: %v", err)
+		}
+		tokens[username] = issued.AccessToken
+
+		player.GameID = game.ID
+		playerStore.UpdatePlayer(player)
+		game.Players = append(game.Players, models.NewSeats(player)...)
+	}
+	gameStore.CreateGame(game)
+
+	dial := func(rawURL, username string) *websocket.Conn {
+		dialer := *websocket.DefaultDialer
+		dialer.Subprotocols = []string{"tetris", "bearer." + tokens[username]}
+		conn, _, err := dialer.Dial(rawURL, nil)
+		if err != nil {
+			t.Fatalf("Failed to connect %s: %v", username, err)
+		}
+		return conn
+	}
+	readMessage := func(conn *websocket.Conn, messageType string) map[string]interface{} {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		for {
+			var message map[string]interface{}
+			if err := conn.ReadJSON(&message); err != nil {
+				t.Fatalf("Expected a %s message, got %v", messageType, err)
+			}
+			if message["type"] == messageType {
+				return message
+			}
+		}
+	}
+
+	bob := dial(instanceA, "bob")
+	defer bob.Close()
+	alice := dial(instanceA, "alice")
+
+	// Alice drops from A, pausing the game, and comes back through B
+	alice.Close()
+	readMessage(bob, "opponent_reconnecting")
+
+	alice = dial(instanceB, "alice")
+	defer alice.Close()
+	readMessage(alice, "game_resync")
+	if message := readMessage(bob, "opponent_reconnected"); message["status"] != string(models.GameStatusActive) {
+		t.Errorf("Expected the game to resume, got %v", message)
+	}
+
+	// A's grace timer runs out without forfeiting the game or ending the session
+	time.Sleep(2 * gracePeriod)
+	if current, _ := gameStore.GetGame(game.ID); current.Status != models.GameStatusActive {
+		t.Errorf("Expected the game to carry on, got %s", current.Status)
+	}
+	if _, err := authService.ValidateSession(tokens["alice"]); err != nil {
+		t.Errorf("Expected alice's session to survive the expired timer, got %v", err)
+	}
+}
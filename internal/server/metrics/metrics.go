@@ -0,0 +1,106 @@
+// Package metrics defines the server's Prometheus instrumentation. Counters
+// and histograms live here so handlers, services and storage can record to
+// them without depending on each other; gauges that read live state are
+// collected by handlers.HealthHandler at scrape time.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "tetris"
+
+var (
+	// HTTPRequests counts HTTP requests by route pattern, method and status
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests handled, by route, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	// HTTPRequestDuration observes HTTP request latency by route pattern and method
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency, by route and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	// WebSocketMessages counts incoming WebSocket messages by type
+	WebSocketMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "websocket",
+		Name:      "messages_received_total",
+		Help:      "WebSocket messages received from clients, by message type.",
+	}, []string{"type"})
+
+	// MatchWait observes how long matched players waited in queue
+	MatchWait = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "matchmaking",
+		Name:      "wait_seconds",
+		Help:      "Time players spent in a matchmaking queue before being matched, by queue.",
+		Buckets:   []float64{1, 2, 5, 10, 20, 30, 60, 120, 300, 600},
+	}, []string{"queue"})
+
+	// GameDuration observes how long finished games lasted
+	GameDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "games",
+		Name:      "duration_seconds",
+		Help:      "Time from a game being created to it finishing.",
+		Buckets:   []float64{30, 60, 120, 180, 300, 450, 600, 900, 1200, 1800},
+	})
+
+	// GamesFinished counts finished games
+	GamesFinished = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "games",
+		Name:      "finished_total",
+		Help:      "Games finished on this instance.",
+	})
+
+	// Disconnects counts players whose WebSocket connection dropped
+	Disconnects = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "players",
+		Name:      "disconnects_total",
+		Help:      "Players whose last WebSocket connection closed.",
+	})
+
+	// Forfeits counts players who lost a game by disconnecting
+	Forfeits = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "players",
+		Name:      "forfeits_total",
+		Help:      "Players who forfeited or were eliminated from a game by disconnecting.",
+	})
+
+	// JanitorReclaimed counts what the janitor removed, by kind
+	JanitorReclaimed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "janitor",
+		Name:      "reclaimed_total",
+		Help:      "Idle players, abandoned queue entries, stale games, finished games, series and replays removed by the janitor, by kind.",
+	}, []string{"kind"})
+
+	// StorageOperationDuration observes storage backend latency by operation
+	StorageOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "storage",
+		Name:      "operation_duration_seconds",
+		Help:      "Storage backend call latency, by operation.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"operation"})
+)
+
+// Handler serves every registered metric in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireAdmin(t *testing.T) {
+	handler := RequireAdmin("admin-secret")(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name          string
+		authorization string
+		expected      int
+	}{
+		{"Admin token", "Bearer admin-secret", http.StatusOK},
+		{"Missing header", "", http.StatusUnauthorized},
+		{"Wrong token", "Bearer admin-secre", http.StatusUnauthorized},
+		{"Wrong scheme", "Basic admin-secret", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/admin/players", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			handler(w, req)
+
+			if w.Code != tt.expected {
+				t.Errorf("Expected status %d, got %d", tt.expected, w.Code)
+			}
+		})
+	}
+
+	// An empty token disables the API rather than accepting an empty bearer
+	disabled := RequireAdmin("")(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	req := httptest.NewRequest("GET", "/admin/players", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	w := httptest.NewRecorder()
+	disabled(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 with no admin token configured, got %d", w.Code)
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"net/http"
 	"strings"
 
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/briancain/go-tetris/internal/server/services"
+	"github.com/briancain/go-tetris/internal/server/tracing"
 )
 
 // AuthMiddleware provides authentication middleware
@@ -39,16 +42,17 @@ func (m *AuthMiddleware) RequireAuth(next http.HandlerFunc) http.HandlerFunc {
 
 		token := parts[1]
 
-		// Validate token
-		player, err := m.authService.ValidateToken(token)
+		// Validate token and that its session hasn't been ended or replaced,
+		// so logging out or being kicked revokes access straight away
+		player, err := m.authService.ValidateSession(token)
 		if err != nil {
 			http.Error(w, "Invalid token", http.StatusUnauthorized)
 			return
 		}
 
-		// Add player info to context
+		// Add player info to context and the request's span
+		trace.SpanFromContext(r.Context()).SetAttributes(tracing.PlayerID(player.ID))
 		ctx := context.WithValue(r.Context(), "playerID", player.ID)
-		ctx = context.WithValue(ctx, "player", player)
 
 		// Call next handler
 		next.ServeHTTP(w, r.WithContext(ctx))
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/briancain/go-tetris/internal/server/services"
+	"github.com/briancain/go-tetris/internal/server/storage/memory"
+)
+
+func TestRequireAuth(t *testing.T) {
+	authService := services.NewAuthService(memory.NewPlayerStore(), memory.NewAccountStore())
+	player, err := authService.Login("alice")
+	if err != nil {
+		t.Fatalf("Failed to log in: %v", err)
+	}
+	tokens, err := authService.IssueTokens(player)
+	if err != nil {
+		t.Fatalf("Failed to issue tokens: %v", err)
+	}
+
+	var gotPlayerID string
+	handler := NewAuthMiddleware(authService).RequireAuth(func(w http.ResponseWriter, r *http.Request) {
+		gotPlayerID, _ = r.Context().Value("playerID").(string)
+		w.WriteHeader(http.StatusOK)
+	})
+	request := func(authorization string) int {
+		req := httptest.NewRequest("GET", "/api/players/me", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w.Code
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		expected      int
+	}{
+		{"Valid token", "Bearer " + tokens.AccessToken, http.StatusOK},
+		{"Missing header", "", http.StatusUnauthorized},
+		{"Wrong scheme", "Basic " + tokens.AccessToken, http.StatusUnauthorized},
+		{"Refresh token", "Bearer " + tokens.RefreshToken, http.StatusUnauthorized},
+		{"Garbage token", "Bearer not-a-token", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := request(tt.authorization); code != tt.expected {
+				t.Errorf("Expected status %d, got %d", tt.expected, code)
+			}
+		})
+	}
+	if gotPlayerID != player.ID {
+		t.Errorf("Expected player %s in the request context, got %q", player.ID, gotPlayerID)
+	}
+
+	// Ending the session revokes its access token before it expires
+	if err := authService.EndSession(player.ID); err != nil {
+		t.Fatalf("Failed to end session: %v", err)
+	}
+	if code := request("Bearer " + tokens.AccessToken); code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 for a revoked session, got %d", code)
+	}
+}
@@ -5,9 +5,17 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"net/http"
+	"strconv"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+
 	"github.com/briancain/go-tetris/internal/server/logger"
+	"github.com/briancain/go-tetris/internal/server/metrics"
+	"github.com/briancain/go-tetris/internal/server/tracing"
 )
 
 type responseWriter struct {
@@ -20,7 +28,9 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
-// RequestLogging middleware logs HTTP requests and responses
+// RequestLogging middleware logs HTTP requests and responses, records their
+// count and latency by route and traces them, continuing any trace the
+// client propagated
 func RequestLogging(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
@@ -28,8 +38,22 @@ func RequestLogging(next http.HandlerFunc) http.HandlerFunc {
 		// Generate request ID
 		requestID := generateRequestID()
 
-		// Add request ID to context
-		ctx := context.WithValue(r.Context(), "requestID", requestID)
+		// Label by the mux pattern rather than the path so IDs and
+		// usernames in URLs don't create a series or span name each
+		route := r.Pattern
+		if route == "" {
+			route = "unmatched"
+		}
+
+		// Add request ID and span to context
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracing.Start(ctx, r.Method+" "+route,
+			attribute.String("http.request.method", r.Method),
+			attribute.String("http.route", route),
+			attribute.String("request.id", requestID),
+		)
+		defer span.End()
+		ctx = context.WithValue(ctx, "requestID", requestID)
 		r = r.WithContext(ctx)
 
 		// Wrap response writer to capture status code
@@ -49,13 +73,24 @@ func RequestLogging(next http.HandlerFunc) http.HandlerFunc {
 
 		// Log response
 		duration := time.Since(start)
-		logger.Logger.Info("HTTP request completed",
+		completed := []any{
 			"requestID", requestID,
 			"method", r.Method,
 			"path", r.URL.Path,
 			"statusCode", rw.statusCode,
 			"duration", duration.String(),
-		)
+		}
+		if spanContext := span.SpanContext(); spanContext.IsValid() {
+			completed = append(completed, "traceID", spanContext.TraceID().String())
+		}
+		logger.Logger.Info("HTTP request completed", completed...)
+
+		span.SetAttributes(attribute.Int("http.response.status_code", rw.statusCode))
+		if rw.statusCode >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(rw.statusCode))
+		}
+		metrics.HTTPRequests.WithLabelValues(route, r.Method, strconv.Itoa(rw.statusCode)).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(route, r.Method).Observe(duration.Seconds())
 	}
 }
 
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/briancain/go-tetris/internal/server/metrics"
+)
+
+func TestRequestLogging_RecordsMetricsByRoute(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/players/{username}", RequestLogging(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Player not found", http.StatusNotFound)
+	}))
+
+	counter := metrics.HTTPRequests.WithLabelValues("/api/players/{username}", "GET", "404")
+	before := testutil.ToFloat64(counter)
+
+	for _, username := range []string{"alice", "bob"} {
+		req := httptest.NewRequest("GET", "/api/players/"+username, nil)
+		mux.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// Both usernames share the route's series
+	if got := testutil.ToFloat64(counter) - before; got != 2 {
+		t.Errorf("Expected 2 requests counted for the route, got %v", got)
+	}
+}
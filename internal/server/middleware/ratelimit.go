@@ -0,0 +1,253 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/briancain/go-tetris/internal/server/logger"
+)
+
+// bucketSweepInterval is how often idle buckets are dropped from a RateLimiter
+const bucketSweepInterval = time.Minute
+
+// TokenBucket allows up to burst events at once, refilling at rate tokens
+// per second. A rate of zero or less never limits. It is not safe for
+// concurrent use; RateLimiter guards its buckets with a mutex.
+type TokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket creates a full bucket
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	b := float64(max(burst, 1))
+	return &TokenBucket{
+		rate:   rate,
+		burst:  b,
+		tokens: b,
+		last:   time.Now(),
+	}
+}
+
+// Allow takes a token if one is available
+func (b *TokenBucket) Allow() bool {
+	return b.allowAt(time.Now())
+}
+
+func (b *TokenBucket) allowAt(now time.Time) bool {
+	if b.rate <= 0 {
+		return true
+	}
+
+	b.refill(now)
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (b *TokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+}
+
+// full reports whether the bucket has refilled completely, meaning it holds
+// no state worth keeping
+func (b *TokenBucket) full(now time.Time) bool {
+	b.refill(now)
+	return b.tokens >= b.burst
+}
+
+// RateLimiter throttles HTTP requests with a token bucket per client,
+// keyed by remote IP or by authenticated player
+type RateLimiter struct {
+	name  string
+	rate  float64
+	burst int
+
+	// trustedProxies are the networks whose X-Forwarded-For is believed
+	trustedProxies []*net.IPNet
+
+	mu        sync.Mutex
+	buckets   map[string]*TokenBucket
+	lastSweep time.Time
+
+	rejected atomic.Int64
+}
+
+// NewRateLimiter creates a limiter allowing rate requests per second per
+// client with bursts of up to burst. A rate of zero or less disables it.
+func NewRateLimiter(name string, rate float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		name:      name,
+		rate:      rate,
+		burst:     burst,
+		buckets:   make(map[string]*TokenBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// Name returns the name the limiter reports its metrics under
+func (l *RateLimiter) Name() string {
+	return l.name
+}
+
+// SetTrustedProxies sets the load balancers and proxies in front of the
+// server. Requests from them are keyed on the client address they forwarded.
+func (l *RateLimiter) SetTrustedProxies(proxies []*net.IPNet) {
+	l.trustedProxies = proxies
+}
+
+// Rejected returns how many requests the limiter has turned away
+func (l *RateLimiter) Rejected() int64 {
+	return l.rejected.Load()
+}
+
+// Allow reports whether the client identified by key may make a request
+func (l *RateLimiter) Allow(key string) bool {
+	if l.rate <= 0 {
+		return true
+	}
+
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) >= bucketSweepInterval {
+		l.sweep(now)
+	}
+
+	bucket, exists := l.buckets[key]
+	if !exists {
+		bucket = NewTokenBucket(l.rate, l.burst)
+		l.buckets[key] = bucket
+	}
+
+	if !bucket.allowAt(now) {
+		l.rejected.Add(1)
+		return false
+	}
+	return true
+}
+
+// sweep drops buckets that have refilled, since a fresh bucket behaves the
+// same. Callers must hold l.mu.
+func (l *RateLimiter) sweep(now time.Time) {
+	for key, bucket := range l.buckets {
+		if bucket.full(now) {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// ByIP limits requests per remote IP address
+func (l *RateLimiter) ByIP(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !l.Allow("ip:" + l.clientIP(r)) {
+			l.reject(w, r)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// ByPlayer limits requests per authenticated player, so it must be wrapped
+// by RequireAuth. Requests without a player fall back to their IP.
+func (l *RateLimiter) ByPlayer(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := "ip:" + l.clientIP(r)
+		if playerID, ok := r.Context().Value("playerID").(string); ok && playerID != "" {
+			key = "player:" + playerID
+		}
+
+		if !l.Allow(key) {
+			l.reject(w, r)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (l *RateLimiter) reject(w http.ResponseWriter, r *http.Request) {
+	logger.Logger.Warn("Request rate limited",
+		"limiter", l.name,
+		"path", r.URL.Path,
+		"remoteAddr", r.RemoteAddr,
+		"clientIP", l.clientIP(r),
+	)
+
+	retryAfter := int(math.Ceil(1 / l.rate))
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	http.Error(w, "Too many requests", http.StatusTooManyRequests)
+}
+
+// clientIP returns the address of the client that made the request. When
+// the peer is a trusted proxy, X-Forwarded-For is read from the right and
+// the first hop that isn't a trusted proxy is used; anything to its left
+// came from the client and may be forged.
+func (l *RateLimiter) clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !l.trusted(ip) {
+		return host
+	}
+
+	client := ip.String()
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(hops[i]))
+		if hop == nil {
+			// A malformed hop can't be trusted, so stop at the last good one
+			break
+		}
+		client = hop.String()
+		if !l.trusted(hop) {
+			break
+		}
+	}
+	return client
+}
+
+// trusted reports whether ip belongs to a trusted proxy
+func (l *RateLimiter) trusted(ip net.IP) bool {
+	for _, network := range l.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTrustedProxies parses a comma-separated list of CIDR ranges
+func ParseTrustedProxies(proxiesStr string) ([]*net.IPNet, error) {
+	var proxies []*net.IPNet
+	for _, cidr := range strings.Split(proxiesStr, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
@@ -0,0 +1,213 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	bucket := NewTokenBucket(2, 3)
+	start := bucket.last
+
+	for i := 0; i < 3; i++ {
+		if !bucket.allowAt(start) {
+			t.Fatalf("Expected event %d of the burst to be allowed", i+1)
+		}
+	}
+	if bucket.allowAt(start) {
+		t.Error("Expected event beyond the burst to be limited")
+	}
+
+	// Two tokens per second refill one token every 500ms
+	if !bucket.allowAt(start.Add(500 * time.Millisecond)) {
+		t.Error("Expected a refilled token to be allowed")
+	}
+	if bucket.allowAt(start.Add(600 * time.Millisecond)) {
+		t.Error("Expected the bucket to be empty again")
+	}
+
+	// Refilling never exceeds the burst
+	later := start.Add(time.Hour)
+	if !bucket.full(later) || bucket.tokens != 3 {
+		t.Errorf("Expected bucket capped at 3 tokens, got %v", bucket.tokens)
+	}
+}
+
+func TestTokenBucket_Unlimited(t *testing.T) {
+	bucket := NewTokenBucket(0, 1)
+	for i := 0; i < 100; i++ {
+		if !bucket.Allow() {
+			t.Fatal("Expected a zero rate never to limit")
+		}
+	}
+}
+
+func TestRateLimiter_ByIP(t *testing.T) {
+	limiter := NewRateLimiter("test", 1, 2)
+	handler := limiter.ByIP(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	request := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/leaderboard", nil)
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w
+	}
+
+	// The port differs per connection, so only the host should count
+	for _, addr := range []string{"10.0.0.1:1000", "10.0.0.1:1001"} {
+		if w := request(addr); w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 within the burst, got %d", w.Code)
+		}
+	}
+
+	w := request("10.0.0.1:1002")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status 429 beyond the burst, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") != "1" {
+		t.Errorf("Expected Retry-After 1, got %q", w.Header().Get("Retry-After"))
+	}
+
+	if w := request("10.0.0.2:1000"); w.Code != http.StatusOK {
+		t.Errorf("Expected another IP to be unaffected, got %d", w.Code)
+	}
+
+	if limiter.Rejected() != 1 {
+		t.Errorf("Expected 1 rejected request, got %d", limiter.Rejected())
+	}
+}
+
+func TestRateLimiter_ByPlayer(t *testing.T) {
+	limiter := NewRateLimiter("test", 1, 1)
+	handler := limiter.ByPlayer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	request := func(playerID string) int {
+		req := httptest.NewRequest("GET", "/api/matchmaking/status", nil)
+		req.RemoteAddr = "10.0.0.1:1000"
+		req = req.WithContext(context.WithValue(req.Context(), "playerID", playerID))
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w.Code
+	}
+
+	// Players sharing an address get their own buckets
+	if code := request("player-1"); code != http.StatusOK {
+		t.Fatalf("Expected status 200 for player-1, got %d", code)
+	}
+	if code := request("player-2"); code != http.StatusOK {
+		t.Fatalf("Expected status 200 for player-2, got %d", code)
+	}
+	if code := request("player-1"); code != http.StatusTooManyRequests {
+		t.Errorf("Expected status 429 for player-1's second request, got %d", code)
+	}
+}
+
+func TestRateLimiter_BehindProxy(t *testing.T) {
+	proxies, err := ParseTrustedProxies("10.0.0.0/8, 172.16.0.0/12")
+	if err != nil {
+		t.Fatalf("Failed to parse trusted proxies: %v", err)
+	}
+	limiter := NewRateLimiter("test", 1, 1)
+	limiter.SetTrustedProxies(proxies)
+	handler := limiter.ByIP(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	request := func(remoteAddr, forwardedFor string) int {
+		req := httptest.NewRequest("GET", "/api/leaderboard", nil)
+		req.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w.Code
+	}
+
+	// Clients behind the same load balancer get their own buckets
+	if code := request("10.0.0.5:1000", "203.0.113.1"); code != http.StatusOK {
+		t.Fatalf("Expected status 200 for the first client, got %d", code)
+	}
+	if code := request("10.0.0.5:1001", "203.0.113.2"); code != http.StatusOK {
+		t.Fatalf("Expected status 200 for a second client through the same proxy, got %d", code)
+	}
+
+	// A client prepending its own hops can't dodge its bucket
+	if code := request("10.0.0.6:1000", "198.51.100.9, 203.0.113.1"); code != http.StatusTooManyRequests {
+		t.Errorf("Expected status 429 for a client forging X-Forwarded-For, got %d", code)
+	}
+
+	// Untrusted peers are keyed on their own address whatever they send
+	if code := request("192.0.2.1:1000", "203.0.113.3"); code != http.StatusOK {
+		t.Fatalf("Expected status 200 for a direct client, got %d", code)
+	}
+	if code := request("192.0.2.1:1001", "203.0.113.4"); code != http.StatusTooManyRequests {
+		t.Errorf("Expected status 429 for a direct client forging X-Forwarded-For, got %d", code)
+	}
+}
+
+func TestRateLimiter_ClientIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies("10.0.0.0/8,fd00::/8")
+	if err != nil {
+		t.Fatalf("Failed to parse trusted proxies: %v", err)
+	}
+	limiter := NewRateLimiter("test", 1, 1)
+	limiter.SetTrustedProxies(proxies)
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		expected     string
+	}{
+		{"Direct client", "192.0.2.1:1000", nil, "192.0.2.1"},
+		{"Direct client forging header", "192.0.2.1:1000", []string{"203.0.113.1"}, "192.0.2.1"},
+		{"Behind proxy", "10.0.0.5:1000", []string{"203.0.113.1"}, "203.0.113.1"},
+		{"Behind proxy chain", "10.0.0.5:1000", []string{"203.0.113.1, 10.1.0.1, 10.2.0.1"}, "203.0.113.1"},
+		{"Forged hops ignored", "10.0.0.5:1000", []string{"198.51.100.9, 203.0.113.1"}, "203.0.113.1"},
+		{"Repeated headers", "10.0.0.5:1000", []string{"198.51.100.9", "203.0.113.1"}, "203.0.113.1"},
+		{"Malformed hop", "10.0.0.5:1000", []string{"203.0.113.1, not-an-ip"}, "10.0.0.5"},
+		{"No header", "10.0.0.5:1000", nil, "10.0.0.5"},
+		{"IPv6 proxy", "[fd00::1]:1000", []string{"2001:db8::1"}, "2001:db8::1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/leaderboard", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			if got := limiter.clientIP(req); got != tt.expected {
+				t.Errorf("Expected client IP %s, got %s", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := ParseTrustedProxies("10.0.0.1"); err == nil {
+		t.Error("Expected error for an address without a prefix length")
+	}
+}
+
+func TestRateLimiter_SweepsIdleBuckets(t *testing.T) {
+	limiter := NewRateLimiter("test", 10, 5)
+	limiter.Allow("ip:10.0.0.1")
+	limiter.Allow("ip:10.0.0.2")
+
+	limiter.mu.Lock()
+	limiter.sweep(time.Now().Add(time.Second))
+	remaining := len(limiter.buckets)
+	limiter.mu.Unlock()
+
+	if remaining != 0 {
+		t.Errorf("Expected refilled buckets to be swept, %d remain", remaining)
+	}
+}
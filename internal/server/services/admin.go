@@ -0,0 +1,151 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/briancain/go-tetris/internal/server/logger"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+// ErrNotInGame is returned when a player named for a game isn't seated in it
+var ErrNotInGame = errors.New("player is not in the game")
+
+// ForceEndGame ends a live game on an operator's behalf and records its
+// result as if it had been played out. The winner must be seated in the
+// game; with no winner the game is drawn. In a battle royale everyone else
+// still standing ties for second.
+func (gm *GameManager) ForceEndGame(gameID, winnerID string) error {
+	unlock := gm.lockGame(gameID)
+	defer unlock()
+
+	game, err := gm.gameStore.GetGame(gameID)
+	if err != nil {
+		return err
+	}
+	if game.Status == models.GameStatusFinished {
+		return ErrGameNotLive
+	}
+	if winnerID != "" && game.Seat(winnerID) == nil {
+		return ErrNotInGame
+	}
+
+	if winnerID != "" && !game.IsDuel() {
+		for _, seat := range game.Alive() {
+			if seat.Player.ID != winnerID {
+				seat.Placement = 2
+			}
+		}
+	}
+
+	gm.finalizeGame(game, winnerID)
+
+	logger.Logger.Info("Game force ended",
+		"gameID", game.ID,
+		"winnerID", winnerID,
+	)
+	return nil
+}
+
+// CancelGame ends a live game without a result. Nobody's stats or rating
+// change, and a match series it belongs to is called off.
+func (gm *GameManager) CancelGame(gameID string) error {
+	unlock := gm.lockGame(gameID)
+	defer unlock()
+
+	game, err := gm.gameStore.GetGame(gameID)
+	if err != nil {
+		return err
+	}
+	if game.Status == models.GameStatusFinished {
+		return ErrGameNotLive
+	}
+
+	game.Cancelled = true
+	gm.finalizeGame(game, "")
+
+	logger.Logger.Info("Game cancelled",
+		"gameID", game.ID,
+		"usernames", gameUsernames(game),
+	)
+	return nil
+}
+
+// LiveGameState returns a copy of a game along with the last board each of
+// its players reported to this instance. Players connected to another
+// instance have no board here.
+func (gm *GameManager) LiveGameState(gameID string) (*models.GameSession, map[string]*models.GameState, error) {
+	unlock := gm.lockGame(gameID)
+	defer unlock()
+
+	game, err := gm.gameStore.GetGame(gameID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gm.statesMu.Lock()
+	defer gm.statesMu.Unlock()
+
+	states := make(map[string]*models.GameState, len(game.Players))
+	for _, playerID := range game.PlayerIDs() {
+		if state, ok := gm.lastStates[playerID]; ok {
+			states[playerID] = copyState(state)
+		}
+	}
+	return copyGame(game), states, nil
+}
+
+// copyGame copies a game and its seats so they can be read without the
+// game's lock. Seated players are shared; only their ID and username, which
+// never change, should be read.
+func copyGame(game *models.GameSession) *models.GameSession {
+	result := *game
+	result.Players = make([]*models.SessionPlayer, len(game.Players))
+	for i, seat := range game.Players {
+		seatCopy := *seat
+		result.Players[i] = &seatCopy
+	}
+	result.Spectators = append([]string(nil), game.Spectators...)
+	return &result
+}
+
+// copyState copies a player's reported state, including their board
+func copyState(state *models.GameState) *models.GameState {
+	result := *state
+	result.Board = make([][]int, len(state.Board))
+	for i, row := range state.Board {
+		result.Board[i] = append([]int(nil), row...)
+	}
+	return &result
+}
+
+// cancelSeries calls off a match series without a winner or recording it in
+// either player's stats
+func (gm *GameManager) cancelSeries(seriesID string) {
+	gm.seriesMu.Lock()
+	defer gm.seriesMu.Unlock()
+
+	series, err := gm.gameStore.GetSeries(seriesID)
+	if err != nil || series.Status == models.SeriesStatusFinished {
+		return
+	}
+
+	series.Finish("")
+	if err := gm.gameStore.UpdateSeries(series); err != nil {
+		logger.Logger.Error("Failed to update match series",
+			"seriesID", series.ID,
+			"error", err,
+		)
+		return
+	}
+
+	gm.sendSeriesMessage(series, map[string]interface{}{
+		"type":      "series_over",
+		"winnerId":  "",
+		"cancelled": true,
+	})
+
+	logger.Logger.Info("Series cancelled",
+		"seriesID", series.ID,
+		"games", len(series.GameIDs),
+	)
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/briancain/go-tetris/internal/server/storage/memory"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func TestForceEndGame(t *testing.T) {
+	gameStore := memory.NewGameStore()
+	playerStore := memory.NewPlayerStore()
+	gm := NewGameManager(gameStore, playerStore, NewWebSocketManager())
+
+	player1 := &models.Player{ID: "admin_player1", Username: "Admin1"}
+	player2 := &models.Player{ID: "admin_player2", Username: "Admin2"}
+	playerStore.CreatePlayer(player1)
+	playerStore.CreatePlayer(player2)
+
+	game := &models.GameSession{ID: "admin_game1", Players: models.NewSeats(player1, player2), Ranked: true}
+	gameStore.CreateGame(game)
+	gm.setPlayerGameID(player1, game.ID)
+	gm.setPlayerGameID(player2, game.ID)
+	gm.StartGame(context.Background(), game)
+
+	if err := gm.ForceEndGame(game.ID, "someone_else"); !errors.Is(err, ErrNotInGame) {
+		t.Errorf("Expected ErrNotInGame for a winner outside the game, got %v", err)
+	}
+
+	if err := gm.ForceEndGame(game.ID, "admin_player2"); err != nil {
+		t.Fatalf("ForceEndGame failed: %v", err)
+	}
+
+	ended, _ := gameStore.GetGame(game.ID)
+	if ended.Status != models.GameStatusFinished || ended.Cancelled {
+		t.Errorf("Expected a finished game with a result, got status %s cancelled %v", ended.Status, ended.Cancelled)
+	}
+
+	// The result counts as if the game had been played out
+	winner, _ := playerStore.GetPlayer("admin_player2")
+	loser, _ := playerStore.GetPlayer("admin_player1")
+	if winner.Wins != 1 || loser.Losses != 1 {
+		t.Errorf("Expected a recorded win and loss, got %d wins and %d losses", winner.Wins, loser.Losses)
+	}
+	if loser.GameID != "" || winner.GameID != "" {
+		t.Error("Expected both players to be released from the game")
+	}
+
+	if err := gm.ForceEndGame(game.ID, ""); !errors.Is(err, ErrGameNotLive) {
+		t.Errorf("Expected ErrGameNotLive for a finished game, got %v", err)
+	}
+}
+
+func TestCancelGame_CallsOffSeries(t *testing.T) {
+	gameStore := memory.NewGameStore()
+	playerStore := memory.NewPlayerStore()
+	gm := NewGameManager(gameStore, playerStore, NewWebSocketManager())
+	gm.SetSeriesCountdown(10 * time.Millisecond)
+
+	player1 := &models.Player{ID: "cancel_player1", Username: "Cancel1"}
+	player2 := &models.Player{ID: "cancel_player2", Username: "Cancel2"}
+	playerStore.CreatePlayer(player1)
+	playerStore.CreatePlayer(player2)
+
+	game := &models.GameSession{ID: "cancel_game1", Players: models.NewSeats(player1, player2), BestOf: 3}
+	gameStore.CreateGame(game)
+	gm.setPlayerGameID(player1, game.ID)
+	gm.setPlayerGameID(player2, game.ID)
+	gm.StartGame(context.Background(), game)
+
+	if err := gm.CancelGame(game.ID); err != nil {
+		t.Fatalf("CancelGame failed: %v", err)
+	}
+
+	cancelled, _ := gameStore.GetGame(game.ID)
+	if cancelled.Status != models.GameStatusFinished || !cancelled.Cancelled {
+		t.Errorf("Expected a cancelled game, got status %s cancelled %v", cancelled.Status, cancelled.Cancelled)
+	}
+
+	// Nobody's record changes
+	for _, id := range []string{"cancel_player1", "cancel_player2"} {
+		player, _ := playerStore.GetPlayer(id)
+		if player.TotalGames != 0 || player.SeriesPlayed != 0 {
+			t.Errorf("Expected no stats for %s, got %d games and %d series", id, player.TotalGames, player.SeriesPlayed)
+		}
+	}
+
+	series, _ := gameStore.GetSeries(game.SeriesID)
+	if series.Status != models.SeriesStatusFinished || series.WinnerID != "" {
+		t.Errorf("Expected the series called off without a winner, got status %s winner %q", series.Status, series.WinnerID)
+	}
+
+	// No next game is scheduled
+	time.Sleep(50 * time.Millisecond)
+	if gameID, _ := gm.playerGameID("cancel_player1"); gameID != "" {
+		t.Errorf("Expected no game after the series was called off, got %s", gameID)
+	}
+}
+
+func TestLiveGameState(t *testing.T) {
+	gm, _, game := setupSpectatedGame(t, 0)
+
+	board := [][]int{{0, 1}, {1, 1}}
+	if err := gm.HandleGameState("spec_player1", &models.GameState{Board: board, Score: 300, Level: 2}); err != nil {
+		t.Fatalf("HandleGameState failed: %v", err)
+	}
+
+	live, states, err := gm.LiveGameState(game.ID)
+	if err != nil {
+		t.Fatalf("LiveGameState failed: %v", err)
+	}
+	if live.Seat("spec_player1").Score != 300 {
+		t.Errorf("Expected score 300, got %d", live.Seat("spec_player1").Score)
+	}
+	if state := states["spec_player1"]; state == nil || state.Level != 2 || len(state.Board) != 2 {
+		t.Errorf("Expected player 1's board, got %+v", state)
+	}
+	if _, ok := states["spec_player2"]; ok {
+		t.Error("Expected no board for a player who hasn't reported one")
+	}
+
+	// The game and boards returned are copies
+	live.Seat("spec_player1").Score = 0
+	states["spec_player1"].Board[0][0] = 7
+	live, states, _ = gm.LiveGameState(game.ID)
+	if live.Seat("spec_player1").Score != 300 || states["spec_player1"].Board[0][0] != 0 {
+		t.Error("Expected changes to a returned game and board not to reach the live ones")
+	}
+
+	if _, _, err := gm.LiveGameState("missing"); err == nil {
+		t.Error("Expected an error for an unknown game")
+	}
+}
+
+func TestMatchmakingService_DrainQueues(t *testing.T) {
+	playerStore := memory.NewPlayerStore()
+	gameStore := memory.NewGameStore()
+	queueStore := memory.NewQueueStore()
+	wsManager := NewWebSocketManager()
+	gameManager := NewGameManager(gameStore, playerStore, wsManager)
+	matchmaker := NewMatchmakingService(playerStore, gameStore, queueStore, memory.NewRoomStore(), gameManager)
+
+	// One player per queue, so nobody is matched before the drain
+	playerStore.CreatePlayer(&models.Player{ID: "drain_ranked", Username: "Ranked"})
+	playerStore.CreatePlayer(&models.Player{ID: "drain_casual", Username: "Casual"})
+	playerStore.CreatePlayer(&models.Player{ID: "drain_sprint", Username: "Sprint"})
+	if err := matchmaker.JoinQueue("drain_ranked", models.QueueOptions{}); err != nil {
+		t.Fatalf("JoinQueue failed: %v", err)
+	}
+	if err := matchmaker.JoinQueue("drain_casual", models.QueueOptions{Queue: models.QueueCasual}); err != nil {
+		t.Fatalf("JoinQueue failed: %v", err)
+	}
+	if err := matchmaker.JoinQueue("drain_sprint", models.QueueOptions{Queue: models.QueueCasual, Mode: "sprint"}); err != nil {
+		t.Fatalf("JoinQueue failed: %v", err)
+	}
+	defer matchmaker.Stop()
+
+	client := connectSpectator(t, wsManager, "drain_casual")
+
+	drained, err := matchmaker.DrainQueues("Server restarting")
+	if err != nil {
+		t.Fatalf("DrainQueues failed: %v", err)
+	}
+	if drained != 3 {
+		t.Errorf("Expected 3 players drained, got %d", drained)
+	}
+
+	for queue, length := range matchmaker.QueueLengths() {
+		if length != 0 {
+			t.Errorf("Expected queue %s to be empty, got %d", queue, length)
+		}
+	}
+	for _, id := range []string{"drain_ranked", "drain_casual", "drain_sprint"} {
+		if queue, position, _ := matchmaker.GetQueueStatus(id); queue != "" || position != -1 {
+			t.Errorf("Expected %s out of the queue, got %q at %d", id, queue, position)
+		}
+	}
+
+	message := readMessageType(t, client, "queue_drained")
+	if message["message"] != "Server restarting" {
+		t.Errorf("Expected the drain reason, got %v", message["message"])
+	}
+}
@@ -2,30 +2,103 @@ package services
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/hex"
 	"errors"
 	"time"
 
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/briancain/go-tetris/internal/server/glicko"
 	"github.com/briancain/go-tetris/internal/server/storage"
 	"github.com/briancain/go-tetris/pkg/models"
 )
 
-var ErrUsernameInUse = errors.New("username is already in use")
+var (
+	ErrUsernameInUse      = errors.New("username is already in use")
+	ErrUsernameRegistered = errors.New("username is registered to an account")
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrInvalidPassword    = errors.New("password must be between 8 and 72 characters")
+	ErrNotRegistered      = errors.New("player is not registered")
+	ErrSessionEnded       = errors.New("session has ended")
+	ErrPlayerBanned       = errors.New("player is banned")
+)
+
+// Password length limits; bcrypt ignores anything past 72 bytes
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 72
+)
+
+// apiKeyPrefix marks API keys so they're recognisable in config files
+const apiKeyPrefix = "tk_"
 
-// AuthService handles player authentication
+// AuthService handles player authentication. Guests get a player that lasts
+// as long as their session; registered accounts own a player that is kept
+// between sessions.
+//
+// Each session has a random ID stored as the player's session token. Clients
+// never see it directly; they get signed tokens carrying it. Every request,
+// refresh and WebSocket connection checks the signature and then looks the
+// session up in the store, so ending or replacing a session revokes its
+// tokens immediately.
 type AuthService struct {
-	playerStore storage.PlayerStore
+	playerStore  storage.PlayerStore
+	accountStore storage.AccountStore
+	tokens       *TokenSigner
+	banStore     storage.BanStore // Optional; nobody is banned without one
 }
 
-// NewAuthService creates a new authentication service
-func NewAuthService(playerStore storage.PlayerStore) *AuthService {
+// NewAuthService creates a new authentication service. Tokens are signed
+// with a random key until SetTokenSigner is called.
+func NewAuthService(playerStore storage.PlayerStore, accountStore storage.AccountStore) *AuthService {
 	return &AuthService{
-		playerStore: playerStore,
+		playerStore:  playerStore,
+		accountStore: accountStore,
+		tokens:       NewTokenSigner(nil, DefaultAccessTokenTTL, DefaultRefreshTokenTTL),
+	}
+}
+
+// SetTokenSigner replaces the signer used to issue and verify tokens. Every
+// instance must use the same key for tokens to work across them.
+func (s *AuthService) SetTokenSigner(signer *TokenSigner) {
+	s.tokens = signer
+}
+
+// SetBanStore enables banning usernames. Banned players can't log in,
+// register or refresh their tokens.
+func (s *AuthService) SetBanStore(banStore storage.BanStore) {
+	s.banStore = banStore
+}
+
+// checkBan returns ErrPlayerBanned if a username is banned
+func (s *AuthService) checkBan(username string) error {
+	if s.banStore == nil {
+		return nil
+	}
+
+	_, err := s.banStore.GetBan(username)
+	if errors.Is(err, storage.ErrBanNotFound) {
+		return nil
 	}
+	if err != nil {
+		return err
+	}
+	return ErrPlayerBanned
 }
 
-// Login creates a new player session
+// Login creates a new guest player session
 func (s *AuthService) Login(username string) (*models.Player, error) {
+	if err := s.checkBan(username); err != nil {
+		return nil, err
+	}
+
+	// Registered usernames are only available to their owner
+	if _, err := s.accountStore.GetAccount(username); err == nil {
+		return nil, ErrUsernameRegistered
+	}
+
 	// Check if username is already taken by an active player
 	_, err := s.playerStore.GetPlayerByUsername(username)
 	if err == nil {
@@ -35,9 +108,9 @@ func (s *AuthService) Login(username string) (*models.Player, error) {
 		return nil, ErrUsernameInUse
 	}
 
-	// Generate unique player ID and session token
+	// Generate unique player ID and session
 	playerID := generateID()
-	sessionToken := generateSessionToken()
+	sessionToken := generateSecret()
 
 	player := &models.Player{
 		ID:           playerID,
@@ -46,19 +119,217 @@ func (s *AuthService) Login(username string) (*models.Player, error) {
 		ConnectedAt:  time.Now(),
 		LastActivity: time.Now(),
 		InQueue:      false,
+		Rating:       glicko.Default(),
+	}
+
+	err = s.playerStore.CreatePlayer(player)
+	if errors.Is(err, storage.ErrUsernameTaken) {
+		return nil, ErrUsernameInUse // Lost a race with another login
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return player, nil
+}
+
+// Register creates an account and logs its new player in
+func (s *AuthService) Register(username, password string) (*models.Player, error) {
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return nil, ErrInvalidPassword
+	}
+	if err := s.checkBan(username); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.accountStore.GetAccount(username); err == nil {
+		return nil, ErrUsernameInUse
+	}
+	if _, err := s.playerStore.GetPlayerByUsername(username); err == nil {
+		return nil, ErrUsernameInUse // Held by a guest
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	player := &models.Player{
+		ID:           generateID(),
+		Username:     username,
+		SessionToken: generateSecret(),
+		ConnectedAt:  time.Now(),
+		LastActivity: time.Now(),
+		Registered:   true,
+		Rating:       glicko.Default(),
 	}
 
 	err = s.playerStore.CreatePlayer(player)
+	if errors.Is(err, storage.ErrUsernameTaken) {
+		return nil, ErrUsernameInUse
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.accountStore.CreateAccount(&models.Account{
+		Username:     username,
+		PlayerID:     player.ID,
+		PasswordHash: string(passwordHash),
+		CreatedAt:    time.Now(),
+	})
 	if err != nil {
+		_ = s.playerStore.DeletePlayer(player.ID)
+		if errors.Is(err, storage.ErrAccountExists) {
+			return nil, ErrUsernameInUse // Lost a race with another registration
+		}
 		return nil, err
 	}
 
 	return player, nil
 }
 
-// ValidateToken checks if a session token is valid and returns the player
-func (s *AuthService) ValidateToken(token string) (*models.Player, error) {
-	return s.playerStore.GetPlayerByToken(token)
+// LoginWithPassword starts a new session for a registered account
+func (s *AuthService) LoginWithPassword(username, password string) (*models.Player, error) {
+	account, err := s.accountStore.GetAccount(username)
+	if err != nil {
+		if errors.Is(err, storage.ErrAccountNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(account.PasswordHash), []byte(password)) != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return s.startSession(account)
+}
+
+// LoginWithAPIKey starts a new session for a registered account using an API
+// key issued by CreateAPIKey
+func (s *AuthService) LoginWithAPIKey(username, apiKey string) (*models.Player, error) {
+	account, err := s.accountStore.GetAccount(username)
+	if err != nil {
+		if errors.Is(err, storage.ErrAccountNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if account.APIKeyHash == "" || subtle.ConstantTimeCompare([]byte(account.APIKeyHash), []byte(hashAPIKey(apiKey))) != 1 {
+		return nil, ErrInvalidCredentials
+	}
+
+	return s.startSession(account)
+}
+
+// CreateAPIKey issues a new long-lived API key for a registered player,
+// revoking any previous one. Only a hash of the key is stored, so it can't
+// be shown again.
+func (s *AuthService) CreateAPIKey(playerID string) (string, error) {
+	player, err := s.playerStore.GetPlayer(playerID)
+	if err != nil {
+		return "", err
+	}
+	if !player.Registered {
+		return "", ErrNotRegistered
+	}
+
+	account, err := s.accountStore.GetAccount(player.Username)
+	if err != nil {
+		return "", err
+	}
+
+	apiKey := apiKeyPrefix + generateSecret()
+	account.APIKeyHash = hashAPIKey(apiKey)
+	if err := s.accountStore.UpdateAccount(account); err != nil {
+		return "", err
+	}
+
+	return apiKey, nil
+}
+
+// startSession gives an account's player a fresh session, revoking any
+// session they already had
+func (s *AuthService) startSession(account *models.Account) (*models.Player, error) {
+	if err := s.checkBan(account.Username); err != nil {
+		return nil, err
+	}
+
+	player, err := s.playerStore.GetPlayer(account.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	player.ConnectedAt = time.Now()
+	player.LastActivity = time.Now()
+	if err := s.playerStore.UpdatePlayer(player); err != nil {
+		return nil, err
+	}
+
+	sessionToken := generateSecret()
+	if err := s.playerStore.SetSessionToken(player.ID, sessionToken); err != nil {
+		return nil, err
+	}
+	player.SessionToken = sessionToken
+
+	return player, nil
+}
+
+// IssueTokens signs an access and refresh token for a player's current session
+func (s *AuthService) IssueTokens(player *models.Player) (*SessionTokens, error) {
+	return s.tokens.Issue(player.ID, player.Username, player.SessionToken)
+}
+
+// ValidateSession checks an access token's signature and expiry and that its
+// session hasn't been ended or replaced, returning the player
+func (s *AuthService) ValidateSession(token string) (*models.Player, error) {
+	claims, err := s.tokens.Verify(token, tokenTypeAccess)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.currentSession(claims)
+}
+
+// RefreshTokens exchanges a refresh token for a new token pair, as long as
+// its session is still current
+func (s *AuthService) RefreshTokens(refreshToken string) (*models.Player, *SessionTokens, error) {
+	claims, err := s.tokens.Verify(refreshToken, tokenTypeRefresh)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	player, err := s.currentSession(claims)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.checkBan(player.Username); err != nil {
+		return nil, nil, err
+	}
+
+	// Refreshing counts as activity, keeping a guest's session alive
+	player.LastActivity = time.Now()
+	if err := s.playerStore.TouchPlayer(player.ID, player.LastActivity); err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.IssueTokens(player)
+	if err != nil {
+		return nil, nil, err
+	}
+	return player, tokens, nil
+}
+
+// currentSession returns the player a token's session belongs to, or
+// ErrSessionEnded if it was revoked
+func (s *AuthService) currentSession(claims *TokenClaims) (*models.Player, error) {
+	player, err := s.playerStore.GetPlayerByToken(claims.SessionID)
+	if err != nil || player.ID != claims.PlayerID {
+		return nil, ErrSessionEnded
+	}
+	return player, nil
 }
 
 // GetPlayerByID retrieves a player by their ID
@@ -71,38 +342,53 @@ func (s *AuthService) DeletePlayer(playerID string) error {
 	return s.playerStore.DeletePlayer(playerID)
 }
 
-// Logout removes a player session
+// Logout ends a player session
 func (s *AuthService) Logout(playerID string) error {
-	return s.playerStore.DeletePlayer(playerID)
+	return s.EndSession(playerID)
 }
 
-// UpdatePlayerActivity updates the last activity time for a player
-func (s *AuthService) UpdatePlayerActivity(playerID string) error {
+// EndSession ends a player's session. Guests are removed to free their
+// username; registered players are kept and only lose their session token.
+func (s *AuthService) EndSession(playerID string) error {
 	player, err := s.playerStore.GetPlayer(playerID)
 	if err != nil {
 		return err
 	}
 
-	player.LastActivity = time.Now()
-	return s.playerStore.UpdatePlayer(player)
+	if player.Registered {
+		return s.playerStore.SetSessionToken(playerID, "")
+	}
+	return s.playerStore.DeletePlayer(playerID)
 }
 
-// CleanupInactivePlayers removes players who have been inactive for too long
-func (s *AuthService) CleanupInactivePlayers(inactiveThreshold time.Duration) error {
+// UpdatePlayerActivity updates the last activity time for a player
+func (s *AuthService) UpdatePlayerActivity(playerID string) error {
+	return s.playerStore.TouchPlayer(playerID, time.Now())
+}
+
+// CleanupInactivePlayers removes guests who have been inactive for too long,
+// returning how many were removed. Guests still seated in a game are kept
+// until it ends.
+func (s *AuthService) CleanupInactivePlayers(inactiveThreshold time.Duration) (int, error) {
 	players, err := s.playerStore.GetAllPlayers()
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	now := time.Now()
+	removed := 0
 	for _, player := range players {
-		if now.Sub(player.LastActivity) > inactiveThreshold {
-			// Remove inactive player to free up username
-			_ = s.playerStore.DeletePlayer(player.ID)
+		if player.Registered || player.GameID != "" || now.Sub(player.LastActivity) <= inactiveThreshold {
+			continue
+		}
+
+		// Remove inactive player to free up username
+		if err := s.playerStore.DeletePlayer(player.ID); err == nil {
+			removed++
 		}
 	}
 
-	return nil
+	return removed, nil
 }
 
 // generateID creates a unique identifier
@@ -112,8 +398,14 @@ func generateID() string {
 	return hex.EncodeToString(bytes)
 }
 
-// generateSessionToken creates a session token
-func generateSessionToken() string {
+// hashAPIKey returns the hash an API key is stored as
+func hashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateSecret creates a random secret for session IDs and API keys
+func generateSecret() string {
 	bytes := make([]byte, 32)
 	_, _ = rand.Read(bytes)
 	return hex.EncodeToString(bytes)
@@ -1,14 +1,18 @@
 package services
 
 import (
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/briancain/go-tetris/internal/server/storage"
 	"github.com/briancain/go-tetris/internal/server/storage/memory"
+	"github.com/briancain/go-tetris/pkg/models"
 )
 
 func TestAuthService_Login(t *testing.T) {
 	playerStore := memory.NewPlayerStore()
-	authService := NewAuthService(playerStore)
+	authService := NewAuthService(playerStore, memory.NewAccountStore())
 
 	// Test successful login
 	player, err := authService.Login("testuser")
@@ -29,9 +33,9 @@ func TestAuthService_Login(t *testing.T) {
 	}
 }
 
-func TestAuthService_ValidateToken(t *testing.T) {
+func TestAuthService_ValidateSession(t *testing.T) {
 	playerStore := memory.NewPlayerStore()
-	authService := NewAuthService(playerStore)
+	authService := NewAuthService(playerStore, memory.NewAccountStore())
 
 	// Create a player
 	player, err := authService.Login("testuser")
@@ -39,18 +43,32 @@ func TestAuthService_ValidateToken(t *testing.T) {
 		t.Fatalf("Failed to create player: %v", err)
 	}
 
+	tokens, err := authService.IssueTokens(player)
+	if err != nil {
+		t.Fatalf("Failed to issue tokens: %v", err)
+	}
+
 	// Test valid token
-	validatedPlayer, err := authService.ValidateToken(player.SessionToken)
+	validated, err := authService.ValidateSession(tokens.AccessToken)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
 
-	if validatedPlayer.ID != player.ID {
-		t.Errorf("Expected player ID %s, got %s", player.ID, validatedPlayer.ID)
+	if validated.ID != player.ID {
+		t.Errorf("Expected player ID %s, got %s", player.ID, validated.ID)
+	}
+
+	// The session ID alone isn't a token, and refresh tokens aren't accepted
+	// as access tokens
+	if _, err := authService.ValidateSession(player.SessionToken); err == nil {
+		t.Error("Expected error for an unsigned session ID")
+	}
+	if _, err := authService.ValidateSession(tokens.RefreshToken); err == nil {
+		t.Error("Expected error for a refresh token")
 	}
 
 	// Test invalid token
-	_, err = authService.ValidateToken("invalid-token")
+	_, err = authService.ValidateSession("invalid-token")
 	if err == nil {
 		t.Error("Expected error for invalid token")
 	}
@@ -58,7 +76,7 @@ func TestAuthService_ValidateToken(t *testing.T) {
 
 func TestAuthService_Logout(t *testing.T) {
 	playerStore := memory.NewPlayerStore()
-	authService := NewAuthService(playerStore)
+	authService := NewAuthService(playerStore, memory.NewAccountStore())
 
 	// Create a player
 	player, err := authService.Login("testuser")
@@ -66,15 +84,244 @@ func TestAuthService_Logout(t *testing.T) {
 		t.Fatalf("Failed to create player: %v", err)
 	}
 
+	tokens, err := authService.IssueTokens(player)
+	if err != nil {
+		t.Fatalf("Failed to issue tokens: %v", err)
+	}
+
 	// Logout
 	err = authService.Logout(player.ID)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
 
-	// Verify token is invalid after logout
-	_, err = authService.ValidateToken(player.SessionToken)
+	// Verify the session is revoked after logout
+	_, err = authService.ValidateSession(tokens.AccessToken)
 	if err == nil {
 		t.Error("Expected error for token after logout")
 	}
+	if _, _, err := authService.RefreshTokens(tokens.RefreshToken); err == nil {
+		t.Error("Expected refresh to fail after logout")
+	}
+}
+
+func TestAuthService_Register(t *testing.T) {
+	playerStore := memory.NewPlayerStore()
+	authService := NewAuthService(playerStore, memory.NewAccountStore())
+
+	if _, err := authService.Register("shortpw", "1234567"); !errors.Is(err, ErrInvalidPassword) {
+		t.Errorf("Expected ErrInvalidPassword, got %v", err)
+	}
+
+	player, err := authService.Register("alice", "correct horse")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !player.Registered || player.SessionToken == "" {
+		t.Errorf("Expected a registered player with a session, got %+v", player)
+	}
+
+	if _, err := authService.Register("alice", "another password"); !errors.Is(err, ErrUsernameInUse) {
+		t.Errorf("Expected ErrUsernameInUse for a taken account, got %v", err)
+	}
+
+	// Guests can't take a registered name, even while its owner is offline
+	if err := authService.Logout(player.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := authService.Login("alice"); !errors.Is(err, ErrUsernameRegistered) {
+		t.Errorf("Expected ErrUsernameRegistered, got %v", err)
+	}
+
+	// Nor can an account take a name a guest is using
+	if _, err := authService.Login("bob"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := authService.Register("bob", "correct horse"); !errors.Is(err, ErrUsernameInUse) {
+		t.Errorf("Expected ErrUsernameInUse for a guest's name, got %v", err)
+	}
+}
+
+func TestAuthService_RegisteredPlayerOutlivesSession(t *testing.T) {
+	playerStore := memory.NewPlayerStore()
+	authService := NewAuthService(playerStore, memory.NewAccountStore())
+
+	player, err := authService.Register("alice", "correct horse")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	oldTokens, err := authService.IssueTokens(player)
+	if err != nil {
+		t.Fatalf("Failed to issue tokens: %v", err)
+	}
+	if _, err := playerStore.RecordGameResult(player.ID, storage.OutcomeWin, 1200, 0, player.Rating); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := authService.EndSession(player.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := authService.ValidateSession(oldTokens.AccessToken); !errors.Is(err, ErrSessionEnded) {
+		t.Errorf("Expected ErrSessionEnded after the session ended, got %v", err)
+	}
+
+	if _, err := authService.LoginWithPassword("alice", "wrong password"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials for a wrong password, got %v", err)
+	}
+	if _, err := authService.LoginWithPassword("nobody", "correct horse"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials for a missing account, got %v", err)
+	}
+
+	loggedIn, err := authService.LoginWithPassword("alice", "correct horse")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if loggedIn.ID != player.ID || loggedIn.Wins != 1 || loggedIn.HighScore != 1200 {
+		t.Errorf("Expected the same player with their stats, got %+v", loggedIn)
+	}
+
+	newTokens, err := authService.IssueTokens(loggedIn)
+	if err != nil {
+		t.Fatalf("Failed to issue tokens: %v", err)
+	}
+	validated, err := authService.ValidateSession(newTokens.AccessToken)
+	if err != nil || validated.ID != player.ID {
+		t.Errorf("Expected new token to validate, got %v", err)
+	}
+	if _, _, err := authService.RefreshTokens(oldTokens.RefreshToken); !errors.Is(err, ErrSessionEnded) {
+		t.Errorf("Expected old session to stay revoked, got %v", err)
+	}
+}
+
+func TestAuthService_RefreshTokens(t *testing.T) {
+	playerStore := memory.NewPlayerStore()
+	authService := NewAuthService(playerStore, memory.NewAccountStore())
+
+	player, err := authService.Login("testuser")
+	if err != nil {
+		t.Fatalf("Failed to create player: %v", err)
+	}
+	tokens, err := authService.IssueTokens(player)
+	if err != nil {
+		t.Fatalf("Failed to issue tokens: %v", err)
+	}
+
+	if _, _, err := authService.RefreshTokens(tokens.AccessToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for an access token, got %v", err)
+	}
+
+	refreshed, newTokens, err := authService.RefreshTokens(tokens.RefreshToken)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if refreshed.ID != player.ID {
+		t.Errorf("Expected player %s, got %s", player.ID, refreshed.ID)
+	}
+	if validated, err := authService.ValidateSession(newTokens.AccessToken); err != nil || validated.ID != player.ID {
+		t.Errorf("Expected refreshed token for the same session, got %v", err)
+	}
+
+	// Tokens from another server's key are rejected
+	other := NewAuthService(playerStore, memory.NewAccountStore())
+	if _, err := other.ValidateSession(newTokens.AccessToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for a token signed with another key, got %v", err)
+	}
+
+	// A shared key works across instances
+	key := []byte("0123456789abcdef0123456789abcdef")
+	authService.SetTokenSigner(NewTokenSigner(key, time.Minute, time.Hour))
+	other.SetTokenSigner(NewTokenSigner(key, time.Minute, time.Hour))
+	shared, err := authService.IssueTokens(player)
+	if err != nil {
+		t.Fatalf("Failed to issue tokens: %v", err)
+	}
+	if _, err := other.ValidateSession(shared.AccessToken); err != nil {
+		t.Errorf("Expected token to validate on another instance, got %v", err)
+	}
+}
+
+func TestAuthService_APIKey(t *testing.T) {
+	playerStore := memory.NewPlayerStore()
+	authService := NewAuthService(playerStore, memory.NewAccountStore())
+
+	guest, err := authService.Login("guest")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := authService.CreateAPIKey(guest.ID); !errors.Is(err, ErrNotRegistered) {
+		t.Errorf("Expected ErrNotRegistered for a guest, got %v", err)
+	}
+
+	player, err := authService.Register("alice", "correct horse")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := authService.LoginWithAPIKey("alice", ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials before a key is issued, got %v", err)
+	}
+
+	oldKey, err := authService.CreateAPIKey(player.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	loggedIn, err := authService.LoginWithAPIKey("alice", oldKey)
+	if err != nil || loggedIn.ID != player.ID {
+		t.Fatalf("Expected API key login as alice, got %v", err)
+	}
+
+	// Issuing a new key revokes the old one
+	newKey, err := authService.CreateAPIKey(player.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := authService.LoginWithAPIKey("alice", oldKey); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected old key to be revoked, got %v", err)
+	}
+	if _, err := authService.LoginWithAPIKey("alice", newKey); err != nil {
+		t.Errorf("Expected new key to work, got %v", err)
+	}
+}
+
+func TestAuthService_Bans(t *testing.T) {
+	playerStore := memory.NewPlayerStore()
+	banStore := memory.NewBanStore()
+	authService := NewAuthService(playerStore, memory.NewAccountStore())
+	authService.SetBanStore(banStore)
+
+	player, err := authService.Register("alice", "correct horse")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	tokens, err := authService.IssueTokens(player)
+	if err != nil {
+		t.Fatalf("Failed to issue tokens: %v", err)
+	}
+
+	if err := banStore.Ban(&models.Ban{Username: "alice", BannedAt: time.Now()}); err != nil {
+		t.Fatalf("Failed to ban: %v", err)
+	}
+	if err := banStore.Ban(&models.Ban{Username: "mallory", BannedAt: time.Now()}); err != nil {
+		t.Fatalf("Failed to ban: %v", err)
+	}
+
+	if _, err := authService.LoginWithPassword("alice", "correct horse"); !errors.Is(err, ErrPlayerBanned) {
+		t.Errorf("Expected ErrPlayerBanned for a banned account, got %v", err)
+	}
+	if _, _, err := authService.RefreshTokens(tokens.RefreshToken); !errors.Is(err, ErrPlayerBanned) {
+		t.Errorf("Expected ErrPlayerBanned refreshing a banned player's session, got %v", err)
+	}
+	if _, err := authService.Login("mallory"); !errors.Is(err, ErrPlayerBanned) {
+		t.Errorf("Expected ErrPlayerBanned for a banned guest name, got %v", err)
+	}
+	if _, err := authService.Register("mallory", "correct horse"); !errors.Is(err, ErrPlayerBanned) {
+		t.Errorf("Expected ErrPlayerBanned registering a banned name, got %v", err)
+	}
+
+	// Lifting the ban lets them back in
+	if err := banStore.Unban("alice"); err != nil {
+		t.Fatalf("Failed to unban: %v", err)
+	}
+	if _, err := authService.LoginWithPassword("alice", "correct horse"); err != nil {
+		t.Errorf("Expected login after unban, got %v", err)
+	}
 }
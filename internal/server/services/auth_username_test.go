@@ -11,7 +11,7 @@ import (
 
 func TestAuthService_UsernameConflicts(t *testing.T) {
 	playerStore := memory.NewPlayerStore()
-	authService := NewAuthService(playerStore)
+	authService := NewAuthService(playerStore, memory.NewAccountStore())
 
 	t.Run("should allow unique usernames", func(t *testing.T) {
 		// First player should succeed
@@ -94,7 +94,7 @@ func TestAuthService_UsernameConflicts(t *testing.T) {
 
 func TestAuthService_CleanupInactivePlayers(t *testing.T) {
 	playerStore := memory.NewPlayerStore()
-	authService := NewAuthService(playerStore)
+	authService := NewAuthService(playerStore, memory.NewAccountStore())
 
 	t.Run("should cleanup inactive players", func(t *testing.T) {
 		// Create a player
@@ -111,10 +111,13 @@ func TestAuthService_CleanupInactivePlayers(t *testing.T) {
 		}
 
 		// Run cleanup with 1 hour threshold
-		err = authService.CleanupInactivePlayers(1 * time.Hour)
+		removed, err := authService.CleanupInactivePlayers(1 * time.Hour)
 		if err != nil {
 			t.Fatalf("Expected cleanup to succeed, got error: %v", err)
 		}
+		if removed != 1 {
+			t.Errorf("Expected 1 player removed, got %d", removed)
+		}
 
 		// Player should be removed, so username should be available again
 		_, err = authService.Login("inactive_user")
@@ -131,7 +134,7 @@ func TestAuthService_CleanupInactivePlayers(t *testing.T) {
 		}
 
 		// Run cleanup with 1 hour threshold (player is recent)
-		err = authService.CleanupInactivePlayers(1 * time.Hour)
+		_, err = authService.CleanupInactivePlayers(1 * time.Hour)
 		if err != nil {
 			t.Fatalf("Expected cleanup to succeed, got error: %v", err)
 		}
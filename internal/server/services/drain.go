@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/briancain/go-tetris/internal/server/logger"
+)
+
+// drainPollInterval is how often WaitForGames checks for running games
+const drainPollInterval = time.Second
+
+// ErrServerDraining is returned for requests that would start new play on a
+// server that is shutting down
+var ErrServerDraining = errors.New("server is shutting down")
+
+// Drain stops the game manager starting new games, such as rematches, and
+// sends every player connected here a server_draining message. Games in
+// progress carry on. It reports false if the server was already draining.
+func (gm *GameManager) Drain(message string) bool {
+	if !gm.draining.CompareAndSwap(false, true) {
+		return false
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"type":    "server_draining",
+		"message": message,
+	})
+	if err == nil {
+		gm.wsManager.BroadcastToAll(data)
+	}
+
+	logger.Logger.Info("Server draining",
+		"games", gm.LocalGameCount(),
+		"message", message,
+	)
+	return true
+}
+
+// Draining reports whether the server is shutting down
+func (gm *GameManager) Draining() bool {
+	return gm.draining.Load()
+}
+
+// LocalGameCount returns how many unfinished games have a player connected
+// to this instance, plus any series waiting here to start its next game
+func (gm *GameManager) LocalGameCount() int {
+	games := make(map[string]bool)
+	for _, playerID := range gm.wsManager.ConnectedPlayerIDs() {
+		if gameID, _ := gm.playerGameID(playerID); gameID != "" {
+			games[gameID] = true
+		}
+	}
+	return len(games) + int(gm.pendingSeriesGames.Load())
+}
+
+// WaitForGames blocks until no games are running on this instance, returning
+// ctx's error if it's done first
+func (gm *GameManager) WaitForGames(ctx context.Context) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for gm.LocalGameCount() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/briancain/go-tetris/internal/server/storage/memory"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func TestDrain_LetsGamesFinish(t *testing.T) {
+	gm, gameStore, game := setupSpectatedGame(t, 1)
+	playerStore := gm.playerStore.(*memory.PlayerStore)
+	matchmaking := NewMatchmakingService(playerStore, gameStore, memory.NewQueueStore(), memory.NewRoomStore(), gm)
+	defer matchmaking.Stop()
+
+	playerClient := connectSpectator(t, gm.wsManager, "spec_player1")
+	queuedClient := connectSpectator(t, gm.wsManager, "spec_watcher")
+	if err := matchmaking.JoinQueue("spec_watcher", models.QueueOptions{}); err != nil {
+		t.Fatalf("JoinQueue failed: %v", err)
+	}
+
+	matchmaking.Drain("Restarting")
+
+	if msg := readMessageType(t, playerClient, "server_draining"); msg["message"] != "Restarting" {
+		t.Errorf("Expected the drain message, got %v", msg)
+	}
+
+	// Queued players are sent back to queue elsewhere
+	readMessageType(t, queuedClient, "queue_drained")
+	if watcher, _ := playerStore.GetPlayer("spec_watcher"); watcher.Queue != "" {
+		t.Errorf("Expected the queued player to be dequeued, got queue %q", watcher.Queue)
+	}
+
+	// Nothing new starts
+	if err := matchmaking.JoinQueue("spec_watcher", models.QueueOptions{}); !errors.Is(err, ErrServerDraining) {
+		t.Errorf("Expected ErrServerDraining joining a queue, got %v", err)
+	}
+	if _, err := matchmaking.CreateRoom("spec_watcher", models.RoomSettings{}); !errors.Is(err, ErrServerDraining) {
+		t.Errorf("Expected ErrServerDraining creating a room, got %v", err)
+	}
+	if gm.Drain("Again") {
+		t.Error("Expected a second drain to be ignored")
+	}
+
+	// The game in progress holds up shutdown until it finishes
+	if count := gm.LocalGameCount(); count != 1 {
+		t.Fatalf("Expected 1 local game, got %d", count)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := gm.WaitForGames(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the wait to time out with a game running, got %v", err)
+	}
+
+	if err := gm.ForceEndGame(game.ID, "spec_player1"); err != nil {
+		t.Fatalf("ForceEndGame failed: %v", err)
+	}
+	if err := gm.WaitForGames(context.Background()); err != nil {
+		t.Errorf("Expected the wait to end with the game, got %v", err)
+	}
+
+	if err := gm.HandleRematchRequest("spec_player1"); !errors.Is(err, ErrServerDraining) {
+		t.Errorf("Expected ErrServerDraining requesting a rematch, got %v", err)
+	}
+}
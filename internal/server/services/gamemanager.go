@@ -1,24 +1,56 @@
 package services
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/briancain/go-tetris/internal/server/glicko"
 	"github.com/briancain/go-tetris/internal/server/logger"
+	"github.com/briancain/go-tetris/internal/server/metrics"
 	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/internal/server/tracing"
 	"github.com/briancain/go-tetris/pkg/models"
 )
 
+// gameLockShards is the number of lock stripes games are spread across.
+// Independent matches only contend when their IDs hash to the same stripe.
+const gameLockShards = 64
+
+// defaultSeriesCountdown is the pause between games of a match series
+const defaultSeriesCountdown = 5 * time.Second
+
 // GameManager handles active game sessions
 type GameManager struct {
-	gameStore   storage.GameStore
-	playerStore storage.PlayerStore
-	wsManager   *WebSocketManager
-	mu          sync.RWMutex // Protects concurrent game operations
+	gameStore    storage.GameStore
+	playerStore  storage.PlayerStore
+	wsManager    *WebSocketManager
+	replayStore  storage.ReplayStore       // Optional; games aren't recorded without one
+	historyStore storage.MatchHistoryStore // Optional; finished games aren't kept in match history without one
+
+	gameLocks [gameLockShards]sync.Mutex // Serializes operations on a single game
+	routeMu   sync.RWMutex               // Protects Player.GameID, which routes players to games
+
+	lastStates map[string]*models.GameState // playerID -> last reported state, used for resync
+	statesMu   sync.Mutex                   // Protects lastStates
+
+	seriesMu           sync.Mutex // Serializes match series updates
+	seriesCountdown    time.Duration
+	pendingSeriesGames atomic.Int64 // Series counting down to their next game here
+
+	draining atomic.Bool // Set once the server starts shutting down
+
+	spectating map[string]string // spectator playerID -> watched gameID, for spectators connected here
+	spectateMu sync.Mutex        // Protects spectating
 }
 
 // NewGameManager creates a new game manager
@@ -31,130 +63,212 @@ func NewGameManager(
 		gameStore:   gameStore,
 		playerStore: playerStore,
 		wsManager:   wsManager,
+		lastStates:  make(map[string]*models.GameState),
+		spectating:  make(map[string]string),
+
+		seriesCountdown: defaultSeriesCountdown,
+	}
+}
+
+// SetSeriesCountdown sets how long to wait before starting the next game of a
+// match series
+func (gm *GameManager) SetSeriesCountdown(d time.Duration) {
+	gm.seriesCountdown = d
+}
+
+// lockGame locks the stripe for a game and returns the matching unlock
+func (gm *GameManager) lockGame(gameID string) func() {
+	h := fnv.New32a()
+	h.Write([]byte(gameID))
+	mu := &gm.gameLocks[h.Sum32()%gameLockShards]
+	mu.Lock()
+	return mu.Unlock
+}
+
+// playerGameID returns the ID of the game a player is currently routed to
+func (gm *GameManager) playerGameID(playerID string) (string, error) {
+	gm.routeMu.RLock()
+	defer gm.routeMu.RUnlock()
+
+	player, err := gm.playerStore.GetPlayer(playerID)
+	if err != nil {
+		return "", err
+	}
+	return player.GameID, nil
+}
+
+// setPlayerGameID routes a player to a game ("" when they leave it)
+func (gm *GameManager) setPlayerGameID(player *models.Player, gameID string) {
+	gm.routeMu.Lock()
+	defer gm.routeMu.Unlock()
+
+	player.GameID = gameID
+	_ = gm.playerStore.SetPlayerGameID(player.ID, gameID)
+}
+
+// lockPlayerGame locks and returns the game a player is currently in. The
+// caller must call unlock when done.
+func (gm *GameManager) lockPlayerGame(playerID string) (*models.GameSession, func(), error) {
+	gameID, err := gm.playerGameID(playerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unlock := gm.lockGame(gameID)
+	game, err := gm.gameStore.GetGame(gameID)
+	if err != nil {
+		unlock()
+		return nil, nil, err
 	}
+	return game, unlock, nil
 }
 
 // StartGame initializes a new game session
-func (gm *GameManager) StartGame(game *models.GameSession) {
+func (gm *GameManager) StartGame(ctx context.Context, game *models.GameSession) {
+	_, span := tracing.Start(ctx, "game.start",
+		tracing.GameID(game.ID),
+		tracing.PlayerIDs(game.PlayerIDs()),
+		attribute.String("queue.name", game.Queue),
+		attribute.String("game.mode", game.Mode),
+	)
+	defer span.End()
+
+	unlock := gm.lockGame(game.ID)
+	defer unlock()
+
+	if err := gm.beginSeries(game); err != nil {
+		logger.Logger.Error("Failed to create match series, playing a single game",
+			"gameID", game.ID,
+			"bestOf", game.BestOf,
+			"error", err,
+		)
+	}
+
 	// Update game status
 	game.Status = models.GameStatusActive
 	err := gm.gameStore.UpdateGame(game)
 	if err != nil {
 		logger.Logger.Error("Failed to update game status",
 			"gameID", game.ID,
-			"player1ID", game.Player1.ID,
-			"player2ID", game.Player2.ID,
+			"playerIDs", game.PlayerIDs(),
 			"error", err,
 		)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return
 	}
-
-	// Send match found message to both players
-	matchMsg := map[string]interface{}{
-		"type":       "match_found",
-		"gameId":     game.ID,
-		"seed":       game.Seed,
-		"opponent":   game.Player2.Username,
-		"opponentId": game.Player2.ID,
+	gm.beginReplay(game)
+
+	// Send match found message to every player
+	roster := gameRoster(game)
+	for _, seat := range game.Players {
+		matchMsg := map[string]interface{}{
+			"type":      "match_found",
+			"gameId":    game.ID,
+			"seed":      game.Seed,
+			"players":   roster,
+			"queue":     game.Queue,
+			"mode":      game.Mode,
+			"ranked":    game.Ranked,
+			"roomCode":  game.RoomCode,
+			"garbage":   game.Garbage,
+			"targeting": game.Targeting,
+			"bestOf":    game.BestOf,
+			"seriesId":  game.SeriesID,
+		}
+		if game.IsDuel() {
+			opponent := game.Opponents(seat.Player.ID)[0]
+			matchMsg["opponent"] = opponent.Player.Username
+			matchMsg["opponentId"] = opponent.Player.ID
+		}
+		gm.sendToPlayer(seat.Player.ID, matchMsg)
 	}
 
-	gm.sendToPlayer(game.Player1.ID, matchMsg)
-
-	matchMsg["opponent"] = game.Player1.Username
-	matchMsg["opponentId"] = game.Player1.ID
-	gm.sendToPlayer(game.Player2.ID, matchMsg)
-
 	logger.Logger.Info("Game started",
 		"gameID", game.ID,
-		"player1Username", game.Player1.Username,
-		"player2Username", game.Player2.Username,
+		"usernames", gameUsernames(game),
 		"seed", game.Seed,
 	)
 }
 
 // HandleGameMove processes a player's move
 func (gm *GameManager) HandleGameMove(playerID string, move *models.GameMove) error {
-	gm.mu.Lock()
-	defer gm.mu.Unlock()
-
-	// Get player
-	player, err := gm.playerStore.GetPlayer(playerID)
-	if err != nil {
-		return err
-	}
-
-	// Get game
-	game, err := gm.gameStore.GetGame(player.GameID)
+	// Get the player's game
+	game, unlock, err := gm.lockPlayerGame(playerID)
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
 	// Validate player is in this game
-	if game.Player1.ID != playerID && game.Player2.ID != playerID {
+	if game.Seat(playerID) == nil {
 		return nil // Invalid player for this game
 	}
 
-	// Broadcast move to opponent
-	var opponentID string
-	if game.Player1.ID == playerID {
-		opponentID = game.Player2.ID
-	} else {
-		opponentID = game.Player1.ID
-	}
+	gm.recordReplay(game.ID, &models.ReplayEvent{
+		Type:     models.ReplayEventMove,
+		PlayerID: playerID,
+		Move:     move,
+	})
 
 	moveMsg := map[string]interface{}{
 		"type":      "game_move",
 		"gameId":    game.ID,
 		"playerId":  playerID,
 		"moveType":  move.MoveType,
+		"piece":     move.Piece,
 		"timestamp": move.Timestamp,
 	}
 
-	gm.sendToPlayer(opponentID, moveMsg)
+	// Broadcast move to opponents
+	gm.broadcast(game, moveMsg, playerID)
 
 	return nil
 }
 
 // HandleGameState processes a player's game state update
 func (gm *GameManager) HandleGameState(playerID string, state *models.GameState) error {
-	gm.mu.Lock()
-	defer gm.mu.Unlock()
-
-	// Get player
-	player, err := gm.playerStore.GetPlayer(playerID)
-	if err != nil {
-		return err
-	}
-
-	// Get game
-	game, err := gm.gameStore.GetGame(player.GameID)
+	// Get the player's game
+	game, unlock, err := gm.lockPlayerGame(playerID)
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
 	// Validate player is in this game
-	if game.Player1.ID != playerID && game.Player2.ID != playerID {
+	seat := game.Seat(playerID)
+	if seat == nil {
 		return nil // Invalid player for this game
 	}
 
-	// Update player's score in game session
-	if game.Player1.ID == playerID {
-		game.Player1Score = state.Score
-	} else {
-		game.Player2Score = state.Score
+	// Remember the latest state so a reconnecting opponent can be resynced
+	gm.statesMu.Lock()
+	gm.lastStates[playerID] = state
+	gm.statesMu.Unlock()
+
+	gm.recordReplay(game.ID, &models.ReplayEvent{
+		Type:     models.ReplayEventState,
+		PlayerID: playerID,
+		State:    state,
+	})
+
+	// Update player's seat, sending garbage for any new line clears
+	cleared := state.Lines - seat.Lines
+	seat.Score = state.Score
+	seat.Lines = state.Lines
+	seat.StackHeight = stackHeight(state.Board)
+	if game.Garbage && cleared > 0 && !seat.Lost {
+		gm.sendGarbage(game, seat, cleared)
 	}
 
-	// Check if surviving player has won by score
-	gm.checkScoreWin(game)
-
-	// Broadcast state to opponent
-	var opponentID string
-	if game.Player1.ID == playerID {
-		opponentID = game.Player2.ID
-	} else {
-		opponentID = game.Player1.ID
+	err = gm.gameStore.UpdateGame(game)
+	if err != nil {
+		return err
 	}
 
+	// Check if surviving player has won
+	gm.checkGameOver(game)
+
 	stateMsg := map[string]interface{}{
 		"type":         "game_state",
 		"gameId":       game.ID,
@@ -164,34 +278,56 @@ func (gm *GameManager) HandleGameState(playerID string, state *models.GameState)
 		"level":        state.Level,
 		"lines":        state.Lines,
 		"currentPiece": state.CurrentPiece,
+		"holdPiece":    state.HoldPiece,
+		"nextPiece":    state.NextPiece,
 		"timestamp":    state.Timestamp,
 	}
 
-	gm.sendToPlayer(opponentID, stateMsg)
+	// Broadcast state to opponents
+	gm.broadcast(game, stateMsg, playerID)
 
 	return nil
 }
 
+// SetTargeting changes who a player's garbage is sent to for the rest of the game
+func (gm *GameManager) SetTargeting(playerID string, strategy models.TargetingStrategy) error {
+	if !strategy.Valid() {
+		return ErrInvalidTargeting
+	}
+
+	game, unlock, err := gm.lockPlayerGame(playerID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	seat := game.Seat(playerID)
+	if seat == nil {
+		return nil
+	}
+
+	seat.Targeting = strategy
+	return gm.gameStore.UpdateGame(game)
+}
+
 // EndGame handles when a player loses
 func (gm *GameManager) EndGame(gameID, loserID string) error {
-	gm.mu.Lock()
-	defer gm.mu.Unlock()
+	unlock := gm.lockGame(gameID)
+	defer unlock()
 
 	game, err := gm.gameStore.GetGame(gameID)
 	if err != nil {
 		return err
 	}
 
-	// Mark player as lost and get their final score
-	var loserScore int
-	if game.Player1.ID == loserID {
-		game.Player1Lost = true
-		loserScore = game.Player1Score
-	} else if game.Player2.ID == loserID {
-		game.Player2Lost = true
-		loserScore = game.Player2Score
+	seat := game.Seat(loserID)
+	if seat == nil || seat.Lost || game.Status == models.GameStatusFinished {
+		return nil
 	}
 
+	// Mark player as lost
+	koBy := gm.eliminate(game, seat)
+
 	// Update game in storage
 	err = gm.gameStore.UpdateGame(game)
 	if err != nil {
@@ -203,288 +339,830 @@ func (gm *GameManager) EndGame(gameID, loserID string) error {
 		"type":       "player_lost",
 		"gameId":     gameID,
 		"playerId":   loserID,
-		"loserScore": loserScore,
+		"loserScore": seat.Score,
+		"placement":  seat.Placement,
+		"koBy":       koBy,
 	}
+	gm.broadcast(game, playerLostMsg, "")
 
-	gm.sendToPlayer(game.Player1.ID, playerLostMsg)
-	gm.sendToPlayer(game.Player2.ID, playerLostMsg)
-
-	// Check if both players lost
-	if game.Player1Lost && game.Player2Lost {
-		gm.finalizeGame(game, "")
+	// A duel's survivor wins on their next update once they beat this score
+	if !game.IsDuel() || len(game.Alive()) == 0 {
+		gm.checkGameOver(game)
 	}
 
 	logger.Logger.Info("Player lost in game",
 		"playerID", loserID,
 		"gameID", gameID,
-		"score", loserScore,
+		"score", seat.Score,
+		"placement", seat.Placement,
+		"koBy", koBy,
 	)
 	return nil
 }
 
-// checkScoreWin checks if surviving player has won by achieving higher score
-func (gm *GameManager) checkScoreWin(game *models.GameSession) {
-	// Only check if exactly one player has lost
-	if game.Player1Lost && !game.Player2Lost {
-		// Player 1 lost, check if Player 2 has beaten their score
-		if game.Player2Score > game.Player1Score {
-			gm.finalizeGame(game, game.Player2.ID)
-		}
-	} else if game.Player2Lost && !game.Player1Lost {
-		// Player 2 lost, check if Player 1 has beaten their score
-		if game.Player1Score > game.Player2Score {
-			gm.finalizeGame(game, game.Player1.ID)
+// eliminate tops a player out. Everyone still standing finishes ahead of
+// them, and whoever last sent them garbage is credited with the KO and takes
+// their badges. It returns the ID of the player credited, if any.
+func (gm *GameManager) eliminate(game *models.GameSession, seat *models.SessionPlayer) string {
+	seat.Lost = true
+	seat.Placement = len(game.Alive()) + 1
+
+	gm.recordReplay(game.ID, &models.ReplayEvent{
+		Type:      models.ReplayEventLost,
+		PlayerID:  seat.Player.ID,
+		Placement: seat.Placement,
+	})
+
+	attacker := game.Seat(seat.AttackerID)
+	if attacker == nil || attacker.Lost || attacker == seat {
+		return ""
+	}
+	attacker.KOs++
+	attacker.Badges += 1 + seat.Badges
+	return attacker.Player.ID
+}
+
+// checkGameOver ends the game once it's decided. A battle royale ends when one
+// player is left standing; in a duel the survivor must also beat the loser's
+// score, and the game is drawn if both top out.
+func (gm *GameManager) checkGameOver(game *models.GameSession) {
+	if game.Status == models.GameStatusFinished {
+		return
+	}
+
+	alive := game.Alive()
+	switch {
+	case len(alive) == 0:
+		gm.finalizeGame(game, "")
+	case len(alive) == 1 && !game.IsDuel():
+		gm.finalizeGame(game, alive[0].Player.ID)
+	case len(alive) == 1:
+		survivor := alive[0]
+		if survivor.Score > game.Opponents(survivor.Player.ID)[0].Score {
+			gm.finalizeGame(game, survivor.Player.ID)
 		}
 	}
 }
 
-// finalizeGame ends the game with final results
+// finalizeGame ends the game with final results. A cancelled game has no
+// winner and doesn't count toward stats, ratings or its series.
 func (gm *GameManager) finalizeGame(game *models.GameSession, winnerID string) {
+	_, span := tracing.Start(context.Background(), "game.finalize",
+		tracing.GameID(game.ID),
+		tracing.PlayerIDs(game.PlayerIDs()),
+		attribute.String("game.winner_id", winnerID),
+	)
+	defer span.End()
+
+	// Settle placements; a drawn game has none
+	for _, seat := range game.Players {
+		switch {
+		case winnerID == "":
+			seat.Placement = 0
+		case seat.Player.ID == winnerID:
+			seat.Placement = 1
+		case game.IsDuel():
+			seat.Placement = 2
+		}
+	}
+
 	// Update game status
 	game.Status = models.GameStatusFinished
+	game.EndedAt = time.Now()
 	err := gm.gameStore.UpdateGame(game)
 	if err != nil {
 		logger.Logger.Error("Failed to update game status",
 			"gameID", game.ID,
 			"error", err,
 		)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return
 	}
 
+	metrics.GamesFinished.Inc()
+	metrics.GameDuration.Observe(game.EndedAt.Sub(game.CreatedAt).Seconds())
+
+	gm.finishReplay(game, winnerID)
+
+	// Resync data is only needed while the game is running
+	gm.statesMu.Lock()
+	for _, playerID := range game.PlayerIDs() {
+		delete(gm.lastStates, playerID)
+	}
+	gm.statesMu.Unlock()
+
 	// Clear player game IDs
-	game.Player1.GameID = ""
-	game.Player2.GameID = ""
-	_ = gm.playerStore.UpdatePlayer(game.Player1)
-	_ = gm.playerStore.UpdatePlayer(game.Player2)
+	for _, seat := range game.Players {
+		gm.setPlayerGameID(seat.Player, "")
+	}
+
+	results := make([]map[string]interface{}, len(game.Players))
+	for i, seat := range game.Players {
+		results[i] = map[string]interface{}{
+			"id":        seat.Player.ID,
+			"username":  seat.Player.Username,
+			"score":     seat.Score,
+			"placement": seat.Placement,
+			"kos":       seat.KOs,
+		}
+	}
 
 	// Send final game over message
 	gameOverMsg := map[string]interface{}{
-		"type":         "game_over",
-		"gameId":       game.ID,
-		"winnerId":     winnerID,
-		"final":        true,
-		"player1Score": game.Player1Score,
-		"player2Score": game.Player2Score,
+		"type":     "game_over",
+		"gameId":   game.ID,
+		"winnerId": winnerID,
+		"final":    true,
+		"players":  results,
+		"seriesId": game.SeriesID,
+	}
+	if game.Cancelled {
+		gameOverMsg["cancelled"] = true
 	}
+	gm.broadcast(game, gameOverMsg, "")
 
-	gm.sendToPlayer(game.Player1.ID, gameOverMsg)
-	gm.sendToPlayer(game.Player2.ID, gameOverMsg)
+	// Update player statistics and history; a cancelled game has no result to record
+	if !game.Cancelled {
+		gm.updatePlayerStats(game, winnerID)
+		gm.recordMatchHistory(game)
+	}
 
-	// Update player statistics
-	gm.updatePlayerStats(game, winnerID)
+	switch {
+	case game.SeriesID == "":
+	case game.Cancelled:
+		gm.cancelSeries(game.SeriesID)
+	default:
+		gm.advanceSeries(game, winnerID)
+	}
 
 	logger.Logger.Info("Game finalized",
 		"gameID", game.ID,
 		"winnerID", winnerID,
-		"player1Score", game.Player1Score,
-		"player2Score", game.Player2Score,
-		"player1Username", game.Player1.Username,
-		"player2Username", game.Player2.Username,
+		"cancelled", game.Cancelled,
+		"usernames", gameUsernames(game),
+		"scores", gameScores(game),
 	)
 }
 
-// HandleRematchRequest processes a rematch request from a player
+// HandleRematchRequest processes a rematch request from a player. The rematch
+// starts once everyone from the last game has asked for it.
 func (gm *GameManager) HandleRematchRequest(playerID string) error {
-	gm.mu.Lock()
-	defer gm.mu.Unlock()
+	if gm.Draining() {
+		return ErrServerDraining
+	}
 
-	// Find the most recent finished game for this player
-	games, err := gm.gameStore.GetAllGames()
+	// Rematches only apply to the player's most recent game
+	last, err := gm.gameStore.GetLastGame(playerID)
 	if err != nil {
-		return err
+		return fmt.Errorf("no finished game found for player %s", playerID)
 	}
 
-	var lastGame *models.GameSession
-	for _, game := range games {
-		if game.Status == models.GameStatusFinished &&
-			(game.Player1.ID == playerID || game.Player2.ID == playerID) {
-			lastGame = game
-			break
-		}
-	}
+	unlock := gm.lockGame(last.ID)
+	defer unlock()
 
-	if lastGame == nil {
+	// Reload under the lock so every player's request lands on the same copy
+	lastGame, err := gm.gameStore.GetGame(last.ID)
+	if err != nil {
+		return err
+	}
+	if lastGame.Status != models.GameStatusFinished {
 		return fmt.Errorf("no finished game found for player %s", playerID)
 	}
 
-	// Mark rematch request
-	if lastGame.Player1.ID == playerID {
-		lastGame.Player1RematchReq = true
-	} else {
-		lastGame.Player2RematchReq = true
+	if lastGame.SeriesID != "" {
+		series, err := gm.gameStore.GetSeries(lastGame.SeriesID)
+		if err == nil && series.Status != models.SeriesStatusFinished {
+			return fmt.Errorf("series %s is still in progress", series.ID)
+		}
 	}
 
+	// Mark rematch request
+	seat := lastGame.Seat(playerID)
+	seat.RematchReq = true
+
 	// Update game
 	err = gm.gameStore.UpdateGame(lastGame)
 	if err != nil {
 		return err
 	}
 
-	// Notify opponent of rematch request
-	var opponentID string
-	if lastGame.Player1.ID == playerID {
-		opponentID = lastGame.Player2.ID
-	} else {
-		opponentID = lastGame.Player1.ID
-	}
-
+	// Notify opponents of rematch request
 	rematchMsg := map[string]interface{}{
-		"type": "rematch_request",
+		"type":     "rematch_request",
+		"playerId": playerID,
 	}
-	gm.sendToPlayer(opponentID, rematchMsg)
+	gm.broadcast(lastGame, rematchMsg, playerID)
 
-	// Check if both players want rematch
-	if lastGame.Player1RematchReq && lastGame.Player2RematchReq {
+	// Check if everyone wants a rematch
+	ready := true
+	for _, other := range lastGame.Players {
+		ready = ready && other.RematchReq
+	}
+	if ready {
 		gm.startRematch(lastGame)
 	}
 
 	logger.Logger.Info("Rematch requested",
 		"playerID", playerID,
 		"gameID", lastGame.ID,
-		"player1Username", lastGame.Player1.Username,
-		"player2Username", lastGame.Player2.Username,
+		"usernames", gameUsernames(lastGame),
 	)
 	return nil
 }
 
 // startRematch creates a new game with the same players
 func (gm *GameManager) startRematch(oldGame *models.GameSession) {
+	players := make([]*models.Player, len(oldGame.Players))
+	for i, seat := range oldGame.Players {
+		players[i] = seat.Player
+	}
+
 	// Create new game with same players but new seed
 	newGame := &models.GameSession{
 		ID:        generateGameID(),
-		Player1:   oldGame.Player1,
-		Player2:   oldGame.Player2,
+		Players:   models.NewSeats(players...),
 		Seed:      generateSeed(),
+		Queue:     oldGame.Queue,
+		Mode:      oldGame.Mode,
+		Ranked:    oldGame.Ranked,
+		RoomCode:  oldGame.RoomCode,
+		Garbage:   oldGame.Garbage,
+		Targeting: oldGame.Targeting,
+		BestOf:    oldGame.BestOf,
 		Status:    models.GameStatusActive,
 		CreatedAt: time.Now(),
 	}
+	gm.carrySpectators(oldGame, newGame)
 
-	// Update player game IDs
-	newGame.Player1.GameID = newGame.ID
-	newGame.Player2.GameID = newGame.ID
-	_ = gm.playerStore.UpdatePlayer(newGame.Player1)
-	_ = gm.playerStore.UpdatePlayer(newGame.Player2)
+	// A rematch of a finished series is a fresh series
+	if err := gm.beginSeries(newGame); err != nil {
+		logger.Logger.Error("Failed to create match series, playing a single game",
+			"gameID", newGame.ID,
+			"bestOf", newGame.BestOf,
+			"error", err,
+		)
+	}
 
-	// Store new game
+	// Store new game before routing players to it
 	err := gm.gameStore.CreateGame(newGame)
 	if err != nil {
 		logger.Logger.Error("Failed to create rematch game",
 			"error", err,
 			"gameID", newGame.ID,
-			"player1Username", newGame.Player1.Username,
-			"player2Username", newGame.Player2.Username,
+			"usernames", gameUsernames(newGame),
 		)
 		return
 	}
+	gm.beginReplay(newGame)
 
-	// Send rematch start to both players
-	rematchStartMsg := map[string]interface{}{
-		"type":   "rematch_start",
-		"gameId": newGame.ID,
-		"seed":   newGame.Seed,
+	// Update player game IDs
+	for _, player := range players {
+		gm.setPlayerGameID(player, newGame.ID)
 	}
 
-	gm.sendToPlayer(newGame.Player1.ID, rematchStartMsg)
-	gm.sendToPlayer(newGame.Player2.ID, rematchStartMsg)
+	// Send rematch start to every player
+	rematchStartMsg := map[string]interface{}{
+		"type":     "rematch_start",
+		"gameId":   newGame.ID,
+		"seed":     newGame.Seed,
+		"players":  gameRoster(newGame),
+		"bestOf":   newGame.BestOf,
+		"seriesId": newGame.SeriesID,
+	}
+	gm.broadcast(newGame, rematchStartMsg, "")
 
 	logger.Logger.Info("Rematch started",
 		"gameID", newGame.ID,
 		"seed", newGame.Seed,
-		"player1Username", newGame.Player1.Username,
-		"player2Username", newGame.Player2.Username,
+		"usernames", gameUsernames(newGame),
 	)
 }
 
-// HandlePlayerDisconnect handles when a player disconnects mid-game
-func (gm *GameManager) HandlePlayerDisconnect(playerID string) error {
-	gm.mu.Lock()
-	defer gm.mu.Unlock()
+// beginSeries starts a match series for a best-of-N duel that isn't already
+// part of one
+func (gm *GameManager) beginSeries(game *models.GameSession) error {
+	if game.BestOf <= 1 || game.SeriesID != "" || !game.IsDuel() {
+		return nil
+	}
 
-	// Find any active game this player is in
-	games, err := gm.gameStore.GetActiveGames()
-	if err != nil {
+	series := &models.MatchSeries{
+		ID:        generateGameID(),
+		Player1ID: game.Players[0].Player.ID,
+		Player2ID: game.Players[1].Player.ID,
+		BestOf:    game.BestOf,
+		GameIDs:   []string{game.ID},
+		Status:    models.SeriesStatusActive,
+		CreatedAt: time.Now(),
+	}
+	if err := gm.gameStore.CreateSeries(series); err != nil {
 		return err
 	}
 
-	for _, game := range games {
-		if game.Player1.ID == playerID || game.Player2.ID == playerID {
-			// Player disconnected from active game - opponent wins by forfeit
-			var opponentID string
-			if game.Player1.ID == playerID {
-				opponentID = game.Player2.ID
-			} else {
-				opponentID = game.Player1.ID
-			}
+	game.SeriesID = series.ID
+	return nil
+}
 
-			// End the game with opponent as winner
-			gm.finalizeGame(game, opponentID)
+// advanceSeries scores a finished game in its series, then either ends the
+// series or schedules the next game after the countdown. Drawn games don't
+// count, so another game is played.
+func (gm *GameManager) advanceSeries(game *models.GameSession, winnerID string) {
+	gm.seriesMu.Lock()
+	defer gm.seriesMu.Unlock()
 
-			// Notify opponent of disconnect
-			disconnectMsg := map[string]interface{}{
-				"type":    "opponent_disconnected",
-				"message": "Opponent disconnected - You win!",
-			}
-			gm.sendToPlayer(opponentID, disconnectMsg)
+	series, err := gm.gameStore.GetSeries(game.SeriesID)
+	if err != nil {
+		logger.Logger.Error("Failed to load match series",
+			"seriesID", game.SeriesID,
+			"gameID", game.ID,
+			"error", err,
+		)
+		return
+	}
+	if series.Status == models.SeriesStatusFinished {
+		return
+	}
+
+	if winnerID != "" && series.RecordWin(winnerID) {
+		gm.finishSeries(series)
+		return
+	}
+
+	if err := gm.gameStore.UpdateSeries(series); err != nil {
+		logger.Logger.Error("Failed to update match series",
+			"seriesID", series.ID,
+			"error", err,
+		)
+		return
+	}
+
+	gm.sendSeriesMessage(series, map[string]interface{}{
+		"type":           "series_update",
+		"nextGameMillis": gm.seriesCountdown.Milliseconds(),
+	})
+
+	gm.pendingSeriesGames.Add(1)
+	time.AfterFunc(gm.seriesCountdown, func() {
+		defer gm.pendingSeriesGames.Add(-1)
+		gm.startNextSeriesGame(series.ID, game)
+	})
+}
+
+// startNextSeriesGame starts the next game of a series with the previous
+// game's settings. A player who left, or started another game, during the
+// countdown forfeits the series.
+func (gm *GameManager) startNextSeriesGame(seriesID string, lastGame *models.GameSession) {
+	gm.seriesMu.Lock()
+	defer gm.seriesMu.Unlock()
+
+	series, err := gm.gameStore.GetSeries(seriesID)
+	if err != nil || series.Status == models.SeriesStatusFinished {
+		return
+	}
 
-			logger.Logger.Info("Player disconnected from game",
+	player1, err1 := gm.playerStore.GetPlayer(series.Player1ID)
+	player2, err2 := gm.playerStore.GetPlayer(series.Player2ID)
+
+	gm.routeMu.RLock()
+	player1Gone := err1 != nil || player1.GameID != ""
+	player2Gone := err2 != nil || player2.GameID != ""
+	gm.routeMu.RUnlock()
+
+	if player1Gone || player2Gone {
+		winnerID := ""
+		if !player1Gone {
+			winnerID = series.Player1ID
+		} else if !player2Gone {
+			winnerID = series.Player2ID
+		}
+		series.Finish(winnerID)
+		gm.finishSeries(series)
+		return
+	}
+
+	newGame := &models.GameSession{
+		ID:        generateGameID(),
+		Players:   models.NewSeats(player1, player2),
+		Seed:      generateSeed(),
+		Queue:     lastGame.Queue,
+		Mode:      lastGame.Mode,
+		Ranked:    lastGame.Ranked,
+		RoomCode:  lastGame.RoomCode,
+		Garbage:   lastGame.Garbage,
+		Targeting: lastGame.Targeting,
+		BestOf:    lastGame.BestOf,
+		SeriesID:  series.ID,
+		Status:    models.GameStatusActive,
+		CreatedAt: time.Now(),
+	}
+	gm.carrySpectators(lastGame, newGame)
+
+	// Spectators may come and go once the game is stored and unlocked, so
+	// take the recipients of the start message now
+	recipients := append(newGame.PlayerIDs(), newGame.Spectators...)
+
+	if err := gm.gameStore.CreateGame(newGame); err != nil {
+		logger.Logger.Error("Failed to create series game",
+			"seriesID", series.ID,
+			"gameID", newGame.ID,
+			"error", err,
+		)
+		return
+	}
+	gm.beginReplay(newGame)
+
+	series.GameIDs = append(series.GameIDs, newGame.ID)
+	if err := gm.gameStore.UpdateSeries(series); err != nil {
+		logger.Logger.Error("Failed to update match series",
+			"seriesID", series.ID,
+			"error", err,
+		)
+	}
+
+	gm.setPlayerGameID(player1, newGame.ID)
+	gm.setPlayerGameID(player2, newGame.ID)
+
+	startMsg := map[string]interface{}{
+		"type":       "rematch_start",
+		"gameId":     newGame.ID,
+		"seed":       newGame.Seed,
+		"bestOf":     newGame.BestOf,
+		"seriesId":   series.ID,
+		"gameNumber": len(series.GameIDs),
+	}
+	for _, recipientID := range recipients {
+		gm.sendToPlayer(recipientID, startMsg)
+	}
+
+	logger.Logger.Info("Series game started",
+		"seriesID", series.ID,
+		"gameID", newGame.ID,
+		"gameNumber", len(series.GameIDs),
+		"player1Wins", series.Player1Wins,
+		"player2Wins", series.Player2Wins,
+	)
+}
+
+// finishSeries stores a finished series, records it in both players' stats
+// and announces the result. The caller must hold seriesMu.
+func (gm *GameManager) finishSeries(series *models.MatchSeries) {
+	if err := gm.gameStore.UpdateSeries(series); err != nil {
+		logger.Logger.Error("Failed to update match series",
+			"seriesID", series.ID,
+			"error", err,
+		)
+		return
+	}
+
+	for _, playerID := range []string{series.Player1ID, series.Player2ID} {
+		if _, err := gm.playerStore.RecordSeriesResult(playerID, playerID == series.WinnerID); err != nil {
+			logger.Logger.Error("Failed to record series result",
+				"seriesID", series.ID,
 				"playerID", playerID,
-				"gameID", game.ID,
-				"opponentID", opponentID,
-				"result", "forfeit_win",
-				"player1Username", game.Player1.Username,
-				"player2Username", game.Player2.Username,
+				"error", err,
 			)
-			break
 		}
 	}
 
+	gm.sendSeriesMessage(series, map[string]interface{}{
+		"type":     "series_over",
+		"winnerId": series.WinnerID,
+	})
+
+	logger.Logger.Info("Series finished",
+		"seriesID", series.ID,
+		"winnerID", series.WinnerID,
+		"bestOf", series.BestOf,
+		"player1Wins", series.Player1Wins,
+		"player2Wins", series.Player2Wins,
+		"games", len(series.GameIDs),
+	)
+}
+
+// sendSeriesMessage sends a series message to both players with the score
+// from each player's point of view
+func (gm *GameManager) sendSeriesMessage(series *models.MatchSeries, message map[string]interface{}) {
+	message["seriesId"] = series.ID
+	message["bestOf"] = series.BestOf
+
+	for _, playerID := range []string{series.Player1ID, series.Player2ID} {
+		message["wins"], message["opponentWins"] = series.Wins(playerID)
+		gm.sendToPlayer(playerID, message)
+	}
+}
+
+// PauseForDisconnect pauses a player's active game while they have a chance to reconnect
+func (gm *GameManager) PauseForDisconnect(playerID string, gracePeriod time.Duration) error {
+	game, unlock, err := gm.lockPlayerGame(playerID)
+	if err != nil {
+		return nil // Not in a game, nothing to pause
+	}
+	defer unlock()
+
+	seat := game.Seat(playerID)
+	if seat == nil || game.Status == models.GameStatusFinished {
+		return nil
+	}
+
+	seat.Disconnected = true
+	game.Status = models.GameStatusPaused
+
+	err = gm.gameStore.UpdateGame(game)
+	if err != nil {
+		return err
+	}
+
+	reconnectingMsg := map[string]interface{}{
+		"type":        "opponent_reconnecting",
+		"gameId":      game.ID,
+		"playerId":    playerID,
+		"graceMillis": gracePeriod.Milliseconds(),
+	}
+	gm.broadcast(game, reconnectingMsg, playerID)
+
+	logger.Logger.Info("Game paused for disconnect",
+		"playerID", playerID,
+		"gameID", game.ID,
+		"gracePeriod", gracePeriod.String(),
+	)
 	return nil
 }
 
-// updatePlayerStats updates player statistics after a game ends
-func (gm *GameManager) updatePlayerStats(game *models.GameSession, winnerID string) {
-	// Update Player 1 stats
-	game.Player1.TotalGames++
-	if game.Player1Score > game.Player1.HighScore {
-		game.Player1.HighScore = game.Player1Score
+// HandlePlayerReconnect resumes a paused game and resyncs the returning player
+func (gm *GameManager) HandlePlayerReconnect(playerID string) error {
+	gameID, err := gm.playerGameID(playerID)
+	if err != nil {
+		return err
 	}
-	if winnerID == game.Player1.ID {
-		game.Player1.Wins++
-	} else if winnerID != "" { // Only count as loss if there was a winner (not a draw)
-		game.Player1.Losses++
+
+	resyncMsg := map[string]interface{}{
+		"type":   "game_resync",
+		"gameId": gameID,
 	}
-	_ = gm.playerStore.UpdatePlayer(game.Player1)
 
-	// Update Player 2 stats
-	game.Player2.TotalGames++
-	if game.Player2Score > game.Player2.HighScore {
-		game.Player2.HighScore = game.Player2Score
+	if gameID == "" {
+		// The game ended (or never started) while the player was away
+		gm.sendToPlayer(playerID, resyncMsg)
+		return nil
 	}
-	if winnerID == game.Player2.ID {
-		game.Player2.Wins++
-	} else if winnerID != "" { // Only count as loss if there was a winner (not a draw)
-		game.Player2.Losses++
+
+	unlock := gm.lockGame(gameID)
+	defer unlock()
+
+	game, err := gm.gameStore.GetGame(gameID)
+	if err != nil {
+		return err
+	}
+
+	seat := game.Seat(playerID)
+	if seat == nil {
+		return fmt.Errorf("player %s is not in game %s", playerID, gameID)
+	}
+	seat.Disconnected = false
+
+	stillAway := false
+	for _, other := range game.Players {
+		stillAway = stillAway || other.Disconnected
+	}
+	if game.Status == models.GameStatusPaused && !stillAway {
+		game.Status = models.GameStatusActive
+	}
+
+	err = gm.gameStore.UpdateGame(game)
+	if err != nil {
+		return err
+	}
+
+	gm.statesMu.Lock()
+	opponents := make([]map[string]interface{}, 0, len(game.Players)-1)
+	for _, opponent := range game.Opponents(playerID) {
+		opponents = append(opponents, map[string]interface{}{
+			"id":       opponent.Player.ID,
+			"username": opponent.Player.Username,
+			"score":    opponent.Score,
+			"lost":     opponent.Lost,
+			"state":    gm.lastStates[opponent.Player.ID],
+		})
+	}
+	resyncMsg["playerState"] = gm.lastStates[playerID]
+	gm.statesMu.Unlock()
+
+	resyncMsg["status"] = game.Status
+	resyncMsg["seed"] = game.Seed
+	resyncMsg["score"] = seat.Score
+	resyncMsg["opponents"] = opponents
+	gm.sendToPlayer(playerID, resyncMsg)
+
+	reconnectedMsg := map[string]interface{}{
+		"type":     "opponent_reconnected",
+		"gameId":   game.ID,
+		"playerId": playerID,
+		"status":   game.Status,
+	}
+	gm.broadcast(game, reconnectedMsg, playerID)
+
+	logger.Logger.Info("Player reconnected to game",
+		"playerID", playerID,
+		"gameID", game.ID,
+		"status", game.Status,
+	)
+	return nil
+}
+
+// HandlePlayerDisconnect handles when a player disconnects mid-game. In a duel
+// the opponent wins by forfeit; in a battle royale the player is eliminated.
+func (gm *GameManager) HandlePlayerDisconnect(playerID string) error {
+	// Find the game this player is in, if any
+	game, unlock, err := gm.lockPlayerGame(playerID)
+	if err != nil {
+		return nil // Not in a game, nothing to forfeit
+	}
+	defer unlock()
+
+	seat := game.Seat(playerID)
+	if seat == nil || game.Status == models.GameStatusFinished {
+		return nil
+	}
+
+	if !game.IsDuel() {
+		if !seat.Lost {
+			metrics.Forfeits.Inc()
+			koBy := gm.eliminate(game, seat)
+			if err := gm.gameStore.UpdateGame(game); err != nil {
+				return err
+			}
+
+			playerLostMsg := map[string]interface{}{
+				"type":         "player_lost",
+				"gameId":       game.ID,
+				"playerId":     playerID,
+				"loserScore":   seat.Score,
+				"placement":    seat.Placement,
+				"koBy":         koBy,
+				"disconnected": true,
+			}
+			gm.broadcast(game, playerLostMsg, playerID)
+		}
+		gm.checkGameOver(game)
+
+		logger.Logger.Info("Player disconnected from game",
+			"playerID", playerID,
+			"gameID", game.ID,
+			"result", "eliminated",
+			"placement", seat.Placement,
+		)
+		return nil
+	}
+
+	// Player disconnected from active game - opponent wins by forfeit
+	opponentID := game.Opponents(playerID)[0].Player.ID
+	metrics.Forfeits.Inc()
+
+	// End the game with opponent as winner
+	gm.finalizeGame(game, opponentID)
+
+	// Notify opponent of disconnect
+	disconnectMsg := map[string]interface{}{
+		"type":    "opponent_disconnected",
+		"message": "Opponent disconnected - You win!",
+	}
+	gm.sendToPlayer(opponentID, disconnectMsg)
+
+	logger.Logger.Info("Player disconnected from game",
+		"playerID", playerID,
+		"gameID", game.ID,
+		"opponentID", opponentID,
+		"result", "forfeit_win",
+		"usernames", gameUsernames(game),
+	)
+
+	return nil
+}
+
+// updatePlayerStats updates player statistics after a game ends. Each player
+// is rated against every opponent's pre-game rating: a win over those placed
+// below them, a loss to those above and a draw if the game was drawn.
+func (gm *GameManager) updatePlayerStats(game *models.GameSession, winnerID string) {
+	ratings := make(map[string]models.Rating, len(game.Players))
+	for _, seat := range game.Players {
+		ratings[seat.Player.ID] = glicko.Normalize(seat.Player.Rating)
+	}
+
+	stats := make([]map[string]interface{}, 0, len(game.Players))
+	for _, seat := range game.Players {
+		var results []glicko.Result
+		for _, opponent := range game.Opponents(seat.Player.ID) {
+			results = append(results, glicko.Result{
+				Opponent: ratings[opponent.Player.ID],
+				Score:    placementScore(seat, opponent),
+			})
+		}
+
+		// Placement stats only track battle royales
+		placement := 0
+		if !game.IsDuel() {
+			placement = seat.Placement
+		}
+
+		seat.Player = gm.recordGameResult(seat.Player, winnerID, seat.Score, placement, game.Ranked, results)
+		stats = append(stats, map[string]interface{}{
+			"username":   seat.Player.Username,
+			"totalGames": seat.Player.TotalGames,
+			"wins":       seat.Player.Wins,
+			"losses":     seat.Player.Losses,
+			"highScore":  seat.Player.HighScore,
+			"rating":     seat.Player.Rating.Value,
+		})
 	}
-	_ = gm.playerStore.UpdatePlayer(game.Player2)
 
 	logger.Logger.Info("Player stats updated",
-		"player1Username", game.Player1.Username,
-		"player2Username", game.Player2.Username,
-		"player1Stats", map[string]interface{}{
-			"totalGames": game.Player1.TotalGames,
-			"wins":       game.Player1.Wins,
-			"losses":     game.Player1.Losses,
-			"highScore":  game.Player1.HighScore,
-		},
-		"player2Stats", map[string]interface{}{
-			"totalGames": game.Player2.TotalGames,
-			"wins":       game.Player2.Wins,
-			"losses":     game.Player2.Losses,
-			"highScore":  game.Player2.HighScore,
-		},
+		"gameID", game.ID,
+		"playerStats", stats,
 	)
 }
 
+// placementScore is a player's Glicko score against one opponent
+func placementScore(seat, opponent *models.SessionPlayer) float64 {
+	switch {
+	case seat.Placement == 0 || opponent.Placement == 0 || seat.Placement == opponent.Placement:
+		return 0.5
+	case seat.Placement < opponent.Placement:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// recordGameResult adds a finished game to one player's stats and, for ranked
+// games, their rating. It returns the updated player (or the original if the
+// store update failed).
+func (gm *GameManager) recordGameResult(player *models.Player, winnerID string, score, placement int, ranked bool, results []glicko.Result) *models.Player {
+	outcome := storage.OutcomeDraw
+	if winnerID == player.ID {
+		outcome = storage.OutcomeWin
+	} else if winnerID != "" { // Only count as loss if there was a winner (not a draw)
+		outcome = storage.OutcomeLoss
+	}
+
+	rating := player.Rating
+	if ranked {
+		rating = glicko.Update(player.Rating, results)
+	}
+
+	updated, err := gm.playerStore.RecordGameResult(player.ID, outcome, score, placement, rating)
+	if err != nil {
+		logger.Logger.Error("Failed to record game result",
+			"playerID", player.ID,
+			"error", err,
+		)
+		return player
+	}
+	return updated
+}
+
+// broadcast sends a message to every player in a game except one ("" for
+// nobody), and to everyone spectating it
+func (gm *GameManager) broadcast(game *models.GameSession, message map[string]interface{}, exceptID string) {
+	for _, seat := range game.Players {
+		if seat.Player.ID != exceptID {
+			gm.sendToPlayer(seat.Player.ID, message)
+		}
+	}
+	for _, spectatorID := range game.Spectators {
+		gm.sendToPlayer(spectatorID, message)
+	}
+}
+
+// gameRoster lists a game's players for clients to set up opponent boards
+func gameRoster(game *models.GameSession) []map[string]interface{} {
+	roster := make([]map[string]interface{}, len(game.Players))
+	for i, seat := range game.Players {
+		roster[i] = map[string]interface{}{
+			"id":       seat.Player.ID,
+			"username": seat.Player.Username,
+		}
+	}
+	return roster
+}
+
+// gameUsernames lists a game's usernames in seat order, for logging
+func gameUsernames(game *models.GameSession) []string {
+	usernames := make([]string, len(game.Players))
+	for i, seat := range game.Players {
+		usernames[i] = seat.Player.Username
+	}
+	return usernames
+}
+
+// gameScores lists a game's scores in seat order, for logging
+func gameScores(game *models.GameSession) []int {
+	scores := make([]int, len(game.Players))
+	for i, seat := range game.Players {
+		scores[i] = seat.Score
+	}
+	return scores
+}
+
 // sendToPlayer sends a message to a specific player via WebSocket
 func (gm *GameManager) sendToPlayer(playerID string, message map[string]interface{}) {
 	data, err := json.Marshal(message)
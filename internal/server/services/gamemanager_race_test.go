@@ -1,9 +1,13 @@
 package services
 
 import (
+	"fmt"
+	"log/slog"
 	"sync"
+	"sync/atomic"
 	"testing"
 
+	"github.com/briancain/go-tetris/internal/server/logger"
 	"github.com/briancain/go-tetris/internal/server/storage/memory"
 	"github.com/briancain/go-tetris/pkg/models"
 )
@@ -38,8 +42,7 @@ func TestGameManagerConcurrentAccess(t *testing.T) {
 	// Create test game
 	game := &models.GameSession{
 		ID:      "testgame",
-		Player1: player1,
-		Player2: player2,
+		Players: models.NewSeats(player1, player2),
 		Status:  models.GameStatusActive,
 		Seed:    12345,
 	}
@@ -150,8 +153,7 @@ func TestGameManagerConcurrentEndGame(t *testing.T) {
 
 			game := &models.GameSession{
 				ID:      "testgame_" + string(rune('0'+gameIndex)),
-				Player1: player1,
-				Player2: player2,
+				Players: models.NewSeats(player1, player2),
 				Status:  models.GameStatusActive,
 				Seed:    int64(12345 + gameIndex),
 			}
@@ -172,3 +174,106 @@ func TestGameManagerConcurrentEndGame(t *testing.T) {
 
 	t.Log("GameManager handled concurrent game endings without race conditions")
 }
+
+// setupMatches creates numGames active two-player games for concurrency tests
+func setupMatches(tb testing.TB, gm *GameManager, numGames int) []*models.GameSession {
+	tb.Helper()
+
+	games := make([]*models.GameSession, numGames)
+	for i := 0; i < numGames; i++ {
+		player1 := &models.Player{ID: fmt.Sprintf("match%d_p1", i), Username: fmt.Sprintf("m%dp1", i)}
+		player2 := &models.Player{ID: fmt.Sprintf("match%d_p2", i), Username: fmt.Sprintf("m%dp2", i)}
+		game := &models.GameSession{
+			ID:      fmt.Sprintf("match%d", i),
+			Players: models.NewSeats(player1, player2),
+			Status:  models.GameStatusActive,
+			Seed:    int64(i),
+		}
+		player1.GameID = game.ID
+		player2.GameID = game.ID
+
+		if err := gm.playerStore.CreatePlayer(player1); err != nil {
+			tb.Fatalf("Failed to create player: %v", err)
+		}
+		if err := gm.playerStore.CreatePlayer(player2); err != nil {
+			tb.Fatalf("Failed to create player: %v", err)
+		}
+		if err := gm.gameStore.CreateGame(game); err != nil {
+			tb.Fatalf("Failed to create game: %v", err)
+		}
+		games[i] = game
+	}
+	return games
+}
+
+// TestGameManagerIndependentMatches plays many matches to completion concurrently
+func TestGameManagerIndependentMatches(t *testing.T) {
+	gameStore := memory.NewGameStore()
+	playerStore := memory.NewPlayerStore()
+	wsManager := NewWebSocketManager()
+	gm := NewGameManager(gameStore, playerStore, wsManager)
+
+	const numGames = 200
+	games := setupMatches(t, gm, numGames)
+
+	var wg sync.WaitGroup
+	wg.Add(numGames)
+	for _, game := range games {
+		go func(game *models.GameSession) {
+			defer wg.Done()
+
+			for j := 0; j < 20; j++ {
+				_ = gm.HandleGameMove(game.Players[0].Player.ID, &models.GameMove{MoveType: "left"})
+				_ = gm.HandleGameState(game.Players[1].Player.ID, &models.GameState{Score: j * 100})
+			}
+
+			// Player 1 tops out, then player 2 beats their score
+			if err := gm.EndGame(game.ID, game.Players[0].Player.ID); err != nil {
+				t.Errorf("EndGame failed for %s: %v", game.ID, err)
+			}
+			if err := gm.HandleGameState(game.Players[1].Player.ID, &models.GameState{Score: 5000}); err != nil {
+				t.Errorf("HandleGameState failed for %s: %v", game.ID, err)
+			}
+		}(game)
+	}
+	wg.Wait()
+
+	for _, game := range games {
+		updated, err := gameStore.GetGame(game.ID)
+		if err != nil {
+			t.Fatalf("Failed to get game %s: %v", game.ID, err)
+		}
+		if updated.Status != models.GameStatusFinished {
+			t.Errorf("Expected game %s to be finished, got %s", game.ID, updated.Status)
+		}
+		if winner := updated.Players[1].Player; winner.Wins != 1 {
+			t.Errorf("Expected %s to have 1 win, got %d", winner.ID, winner.Wins)
+		}
+	}
+}
+
+// BenchmarkGameManagerConcurrentMatches measures move/state throughput with
+// hundreds of simultaneous matches
+func BenchmarkGameManagerConcurrentMatches(b *testing.B) {
+	// Players have no connections, so skip the per-send warnings
+	logger.SetLevel(slog.LevelError)
+	defer logger.SetLevel(slog.LevelInfo)
+
+	gameStore := memory.NewGameStore()
+	playerStore := memory.NewPlayerStore()
+	wsManager := NewWebSocketManager()
+	gm := NewGameManager(gameStore, playerStore, wsManager)
+
+	const numGames = 500
+	games := setupMatches(b, gm, numGames)
+
+	var next atomic.Int64
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			game := games[next.Add(1)%numGames]
+			_ = gm.HandleGameMove(game.Players[0].Player.ID, &models.GameMove{MoveType: "left"})
+			_ = gm.HandleGameState(game.Players[1].Player.ID, &models.GameState{Score: 100})
+		}
+	})
+}
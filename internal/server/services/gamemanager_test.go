@@ -1,9 +1,14 @@
 package services
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/briancain/go-tetris/internal/server/glicko"
+	"github.com/briancain/go-tetris/internal/server/metrics"
 	"github.com/briancain/go-tetris/internal/server/storage/memory"
 	"github.com/briancain/go-tetris/pkg/models"
 )
@@ -29,14 +34,13 @@ func TestEndGame(t *testing.T) {
 
 	// Create test game
 	game := &models.GameSession{
-		ID:           "gm_game1",
-		Player1:      player1,
-		Player2:      player2,
-		Player1Score: 1000,
-		Player2Score: 800,
-		Status:       models.GameStatusActive,
-		CreatedAt:    time.Now(),
+		ID:        "gm_game1",
+		Players:   models.NewSeats(player1, player2),
+		Status:    models.GameStatusActive,
+		CreatedAt: time.Now(),
 	}
+	game.Players[0].Score = 1000
+	game.Players[1].Score = 800
 
 	// Store game and players
 	gameStore.CreateGame(game)
@@ -51,11 +55,11 @@ func TestEndGame(t *testing.T) {
 
 	// Verify player 1 is marked as lost
 	updatedGame, _ := gameStore.GetGame("gm_game1")
-	if !updatedGame.Player1Lost {
-		t.Error("Player1Lost should be true")
+	if !updatedGame.Seat("gm_player1").Lost {
+		t.Error("Player 1 should have lost")
 	}
-	if updatedGame.Player2Lost {
-		t.Error("Player2Lost should be false")
+	if updatedGame.Seat("gm_player2").Lost {
+		t.Error("Player 2 should not have lost")
 	}
 
 	// Game should still be active (not finished yet)
@@ -64,7 +68,7 @@ func TestEndGame(t *testing.T) {
 	}
 }
 
-func TestCheckScoreWin(t *testing.T) {
+func TestCheckGameOver(t *testing.T) {
 	// Setup
 	gameStore := memory.NewGameStore()
 	playerStore := memory.NewPlayerStore()
@@ -73,22 +77,23 @@ func TestCheckScoreWin(t *testing.T) {
 
 	// Create test game where player1 lost with score 1000
 	game := &models.GameSession{
-		ID:           "gm_game2",
-		Player1:      &models.Player{ID: "gm_player3", GameID: "gm_game2"},
-		Player2:      &models.Player{ID: "gm_player4", GameID: "gm_game2"},
-		Player1Lost:  true,
-		Player2Lost:  false,
-		Player1Score: 1000,
-		Player2Score: 1200, // Player2 has higher score
-		Status:       models.GameStatusActive,
+		ID: "gm_game2",
+		Players: models.NewSeats(
+			&models.Player{ID: "gm_player3", GameID: "gm_game2"},
+			&models.Player{ID: "gm_player4", GameID: "gm_game2"},
+		),
+		Status: models.GameStatusActive,
 	}
+	game.Players[0].Lost = true
+	game.Players[0].Score = 1000
+	game.Players[1].Score = 1200 // Player2 has higher score
 
 	gameStore.CreateGame(game)
-	playerStore.CreatePlayer(game.Player1)
-	playerStore.CreatePlayer(game.Player2)
+	playerStore.CreatePlayer(game.Players[0].Player)
+	playerStore.CreatePlayer(game.Players[1].Player)
 
 	// Test score win detection
-	gm.checkScoreWin(game)
+	gm.checkGameOver(game)
 
 	// Game should be finished since player2 beat player1's score
 	updatedGame, _ := gameStore.GetGame("gm_game2")
@@ -97,7 +102,7 @@ func TestCheckScoreWin(t *testing.T) {
 	}
 }
 
-func TestCheckScoreWinNoWin(t *testing.T) {
+func TestCheckGameOverNoWin(t *testing.T) {
 	// Setup
 	gameStore := memory.NewGameStore()
 	playerStore := memory.NewPlayerStore()
@@ -106,20 +111,21 @@ func TestCheckScoreWinNoWin(t *testing.T) {
 
 	// Create test game where player1 lost but player2 hasn't beaten the score yet
 	game := &models.GameSession{
-		ID:           "gm_game3",
-		Player1:      &models.Player{ID: "gm_player5", GameID: "gm_game3"},
-		Player2:      &models.Player{ID: "gm_player6", GameID: "gm_game3"},
-		Player1Lost:  true,
-		Player2Lost:  false,
-		Player1Score: 1000,
-		Player2Score: 800, // Player2 has lower score
-		Status:       models.GameStatusActive,
+		ID: "gm_game3",
+		Players: models.NewSeats(
+			&models.Player{ID: "gm_player5", GameID: "gm_game3"},
+			&models.Player{ID: "gm_player6", GameID: "gm_game3"},
+		),
+		Status: models.GameStatusActive,
 	}
+	game.Players[0].Lost = true
+	game.Players[0].Score = 1000
+	game.Players[1].Score = 800 // Player2 has lower score
 
 	gameStore.CreateGame(game)
 
 	// Test score win detection
-	gm.checkScoreWin(game)
+	gm.checkGameOver(game)
 
 	// Game should still be active
 	if game.Status != models.GameStatusActive {
@@ -136,19 +142,20 @@ func TestBothPlayersLose(t *testing.T) {
 
 	// Create test game
 	game := &models.GameSession{
-		ID:           "gm_game4",
-		Player1:      &models.Player{ID: "gm_player7", GameID: "gm_game4"},
-		Player2:      &models.Player{ID: "gm_player8", GameID: "gm_game4"},
-		Player1Lost:  true, // Player1 already lost
-		Player2Lost:  false,
-		Player1Score: 1000,
-		Player2Score: 800,
-		Status:       models.GameStatusActive,
+		ID: "gm_game4",
+		Players: models.NewSeats(
+			&models.Player{ID: "gm_player7", GameID: "gm_game4"},
+			&models.Player{ID: "gm_player8", GameID: "gm_game4"},
+		),
+		Status: models.GameStatusActive,
 	}
+	game.Players[0].Lost = true // Player1 already lost
+	game.Players[0].Score = 1000
+	game.Players[1].Score = 800
 
 	gameStore.CreateGame(game)
-	playerStore.CreatePlayer(game.Player1)
-	playerStore.CreatePlayer(game.Player2)
+	playerStore.CreatePlayer(game.Players[0].Player)
+	playerStore.CreatePlayer(game.Players[1].Player)
 
 	// Player 2 also loses
 	err := gm.EndGame("gm_game4", "gm_player8")
@@ -161,8 +168,8 @@ func TestBothPlayersLose(t *testing.T) {
 	if updatedGame.Status != models.GameStatusFinished {
 		t.Error("Game should be finished when both players lose")
 	}
-	if !updatedGame.Player2Lost {
-		t.Error("Player2Lost should be true")
+	if !updatedGame.Seat("gm_player8").Lost {
+		t.Error("Player 2 should have lost")
 	}
 }
 
@@ -178,8 +185,7 @@ func TestHandleGameStateScoreTracking(t *testing.T) {
 	player2 := &models.Player{ID: "gm_player10", GameID: "gm_game5"}
 	game := &models.GameSession{
 		ID:      "gm_game5",
-		Player1: player1,
-		Player2: player2,
+		Players: models.NewSeats(player1, player2),
 		Status:  models.GameStatusActive,
 	}
 
@@ -203,8 +209,8 @@ func TestHandleGameStateScoreTracking(t *testing.T) {
 
 	// Verify score was updated
 	updatedGame, _ := gameStore.GetGame("gm_game5")
-	if updatedGame.Player1Score != 1500 {
-		t.Errorf("Expected Player1Score to be 1500, got %d", updatedGame.Player1Score)
+	if score := updatedGame.Seat("gm_player9").Score; score != 1500 {
+		t.Errorf("Expected player 1 score to be 1500, got %d", score)
 	}
 
 	// Test score update for player2
@@ -218,8 +224,8 @@ func TestHandleGameStateScoreTracking(t *testing.T) {
 
 	// Verify score was updated
 	updatedGame, _ = gameStore.GetGame("gm_game5")
-	if updatedGame.Player2Score != 1200 {
-		t.Errorf("Expected Player2Score to be 1200, got %d", updatedGame.Player2Score)
+	if score := updatedGame.Seat("gm_player10").Score; score != 1200 {
+		t.Errorf("Expected player 2 score to be 1200, got %d", score)
 	}
 }
 
@@ -232,15 +238,19 @@ func TestHandlePlayerDisconnect(t *testing.T) {
 
 	// Create active game
 	game := &models.GameSession{
-		ID:      "disconnect_game",
-		Player1: &models.Player{ID: "player1", GameID: "disconnect_game"},
-		Player2: &models.Player{ID: "player2", GameID: "disconnect_game"},
-		Status:  models.GameStatusActive,
+		ID: "disconnect_game",
+		Players: models.NewSeats(
+			&models.Player{ID: "player1", GameID: "disconnect_game"},
+			&models.Player{ID: "player2", GameID: "disconnect_game"},
+		),
+		Status: models.GameStatusActive,
 	}
 
 	gameStore.CreateGame(game)
-	playerStore.CreatePlayer(game.Player1)
-	playerStore.CreatePlayer(game.Player2)
+	playerStore.CreatePlayer(game.Players[0].Player)
+	playerStore.CreatePlayer(game.Players[1].Player)
+	forfeits := testutil.ToFloat64(metrics.Forfeits)
+	finished := testutil.ToFloat64(metrics.GamesFinished)
 
 	// Player 1 disconnects
 	err := gm.HandlePlayerDisconnect("player1")
@@ -248,6 +258,10 @@ func TestHandlePlayerDisconnect(t *testing.T) {
 		t.Fatalf("HandlePlayerDisconnect failed: %v", err)
 	}
 
+	if testutil.ToFloat64(metrics.Forfeits) != forfeits+1 || testutil.ToFloat64(metrics.GamesFinished) != finished+1 {
+		t.Error("Expected the forfeit and finished game to be counted")
+	}
+
 	// Game should be finished with player2 as winner
 	updatedGame, _ := gameStore.GetGame("disconnect_game")
 	if updatedGame.Status != models.GameStatusFinished {
@@ -308,13 +322,15 @@ func TestUpdatePlayerStats(t *testing.T) {
 
 	// Create game session
 	game := &models.GameSession{
-		ID:           "stats_game",
-		Player1:      player1,
-		Player2:      player2,
-		Player1Score: 1200, // New high score for player1
-		Player2Score: 600,  // Lower than existing high score
-		Status:       models.GameStatusFinished,
+		ID:      "stats_game",
+		Players: models.NewSeats(player1, player2),
+		Ranked:  true,
+		Status:  models.GameStatusFinished,
 	}
+	game.Players[0].Score = 1200 // New high score for player1
+	game.Players[0].Placement = 1
+	game.Players[1].Score = 600 // Lower than existing high score
+	game.Players[1].Placement = 2
 
 	// Update stats with player1 as winner
 	gm.updatePlayerStats(game, "stats_player1")
@@ -348,4 +364,373 @@ func TestUpdatePlayerStats(t *testing.T) {
 	if updatedPlayer2.HighScore != 800 {
 		t.Errorf("Expected player2 HighScore to remain 800, got %d", updatedPlayer2.HighScore)
 	}
+
+	// Unrated players start at the default rating and move apart
+	if updatedPlayer1.Rating.Value <= glicko.DefaultRating {
+		t.Errorf("Expected winner's rating above %v, got %.1f", glicko.DefaultRating, updatedPlayer1.Rating.Value)
+	}
+	if updatedPlayer2.Rating.Value >= glicko.DefaultRating {
+		t.Errorf("Expected loser's rating below %v, got %.1f", glicko.DefaultRating, updatedPlayer2.Rating.Value)
+	}
+	if updatedPlayer1.Rating.Deviation >= glicko.DefaultDeviation {
+		t.Errorf("Expected winner's deviation to shrink, got %.1f", updatedPlayer1.Rating.Deviation)
+	}
+}
+
+func TestUpdatePlayerStatsCasualKeepsRating(t *testing.T) {
+	gameStore := memory.NewGameStore()
+	playerStore := memory.NewPlayerStore()
+	wsManager := NewWebSocketManager()
+	gm := NewGameManager(gameStore, playerStore, wsManager)
+
+	rating := models.Rating{Value: 1620, Deviation: 90, Volatility: glicko.DefaultVolatility}
+	player1 := &models.Player{ID: "casual_player1", Username: "Casual1", Rating: rating}
+	player2 := &models.Player{ID: "casual_player2", Username: "Casual2", Rating: rating}
+	playerStore.CreatePlayer(player1)
+	playerStore.CreatePlayer(player2)
+
+	game := &models.GameSession{
+		ID:      "casual_game",
+		Players: models.NewSeats(player1, player2),
+		Queue:   models.QueueCasual,
+		Status:  models.GameStatusFinished,
+	}
+
+	gm.updatePlayerStats(game, "casual_player1")
+
+	// Casual games count towards stats but leave ratings alone
+	updatedPlayer1, _ := playerStore.GetPlayer("casual_player1")
+	if updatedPlayer1.Wins != 1 {
+		t.Errorf("Expected casual win to be counted, got %d wins", updatedPlayer1.Wins)
+	}
+	if updatedPlayer1.Rating != rating {
+		t.Errorf("Expected rating to stay %+v, got %+v", rating, updatedPlayer1.Rating)
+	}
+}
+
+func TestPauseForDisconnectAndReconnect(t *testing.T) {
+	// Setup
+	gameStore := memory.NewGameStore()
+	playerStore := memory.NewPlayerStore()
+	wsManager := NewWebSocketManager()
+	gm := NewGameManager(gameStore, playerStore, wsManager)
+
+	game := &models.GameSession{
+		ID: "pause_game",
+		Players: models.NewSeats(
+			&models.Player{ID: "pause_player1", GameID: "pause_game"},
+			&models.Player{ID: "pause_player2", GameID: "pause_game"},
+		),
+		Status: models.GameStatusActive,
+	}
+
+	gameStore.CreateGame(game)
+	playerStore.CreatePlayer(game.Players[0].Player)
+	playerStore.CreatePlayer(game.Players[1].Player)
+
+	// Player 1 drops: game is held open, not forfeited
+	err := gm.PauseForDisconnect("pause_player1", 30*time.Second)
+	if err != nil {
+		t.Fatalf("PauseForDisconnect failed: %v", err)
+	}
+
+	updatedGame, _ := gameStore.GetGame("pause_game")
+	if updatedGame.Status != models.GameStatusPaused {
+		t.Errorf("Expected game to be paused, got %s", updatedGame.Status)
+	}
+	if !updatedGame.Seat("pause_player1").Disconnected {
+		t.Error("Player 1 should be marked disconnected")
+	}
+
+	// Paused games still count as in progress
+	activeGames, _ := gameStore.GetActiveGames()
+	if len(activeGames) != 1 {
+		t.Errorf("Expected paused game to be listed as active, got %d games", len(activeGames))
+	}
+
+	// Player 1 comes back
+	err = gm.HandlePlayerReconnect("pause_player1")
+	if err != nil {
+		t.Fatalf("HandlePlayerReconnect failed: %v", err)
+	}
+
+	updatedGame, _ = gameStore.GetGame("pause_game")
+	if updatedGame.Status != models.GameStatusActive {
+		t.Errorf("Expected game to resume, got %s", updatedGame.Status)
+	}
+	if updatedGame.Seat("pause_player1").Disconnected {
+		t.Error("Player 1 should no longer be disconnected after reconnect")
+	}
+}
+
+// waitForNextGame polls until a player is routed to a game other than lastGameID
+func waitForNextGame(t *testing.T, gm *GameManager, playerID, lastGameID string) *models.GameSession {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		gameID, err := gm.playerGameID(playerID)
+		if err == nil && gameID != "" && gameID != lastGameID {
+			game, err := gm.gameStore.GetGame(gameID)
+			if err != nil {
+				t.Fatalf("GetGame failed: %v", err)
+			}
+			return game
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("Next series game never started for %s", playerID)
+	return nil
+}
+
+func TestMatchSeries_FirstToTargetWins(t *testing.T) {
+	gameStore := memory.NewGameStore()
+	playerStore := memory.NewPlayerStore()
+	gm := NewGameManager(gameStore, playerStore, NewWebSocketManager())
+	gm.SetSeriesCountdown(10 * time.Millisecond)
+
+	player1 := &models.Player{ID: "series_player1", Username: "Series1"}
+	player2 := &models.Player{ID: "series_player2", Username: "Series2"}
+	playerStore.CreatePlayer(player1)
+	playerStore.CreatePlayer(player2)
+
+	game := &models.GameSession{ID: "series_game1", Players: models.NewSeats(player1, player2), BestOf: 5}
+	gameStore.CreateGame(game)
+	gm.setPlayerGameID(player1, game.ID)
+	gm.setPlayerGameID(player2, game.ID)
+	gm.StartGame(context.Background(), game)
+
+	if game.SeriesID == "" {
+		t.Fatal("Expected a best-of-5 game to start a series")
+	}
+
+	// Player 1 wins, a draw doesn't count, player 2 wins, then player 1 takes two
+	winners := []string{"series_player1", "", "series_player2", "series_player1", "series_player1"}
+	for i, winnerID := range winners {
+		unlock := gm.lockGame(game.ID)
+		gm.finalizeGame(game, winnerID)
+		unlock()
+
+		if i < len(winners)-1 {
+			game = waitForNextGame(t, gm, "series_player1", game.ID)
+			if game.SeriesID == "" || game.BestOf != 5 {
+				t.Fatalf("Expected game %d to continue the series, got %+v", i+2, game)
+			}
+		}
+	}
+
+	series, err := gameStore.GetSeries(game.SeriesID)
+	if err != nil {
+		t.Fatalf("GetSeries failed: %v", err)
+	}
+	if series.Status != models.SeriesStatusFinished || series.WinnerID != "series_player1" {
+		t.Errorf("Expected series won by player 1, got status %s winner %q", series.Status, series.WinnerID)
+	}
+	if series.Player1Wins != 3 || series.Player2Wins != 1 {
+		t.Errorf("Expected score 3-1, got %d-%d", series.Player1Wins, series.Player2Wins)
+	}
+	if len(series.GameIDs) != 5 {
+		t.Errorf("Expected 5 games in the series, got %d", len(series.GameIDs))
+	}
+
+	updatedPlayer1, _ := playerStore.GetPlayer("series_player1")
+	updatedPlayer2, _ := playerStore.GetPlayer("series_player2")
+	if updatedPlayer1.SeriesPlayed != 1 || updatedPlayer1.SeriesWins != 1 {
+		t.Errorf("Expected player 1 to have won 1 of 1 series, got %d of %d", updatedPlayer1.SeriesWins, updatedPlayer1.SeriesPlayed)
+	}
+	if updatedPlayer2.SeriesPlayed != 1 || updatedPlayer2.SeriesWins != 0 {
+		t.Errorf("Expected player 2 to have lost 1 series, got %d of %d", updatedPlayer2.SeriesWins, updatedPlayer2.SeriesPlayed)
+	}
+
+	// No further game starts once the series is decided
+	time.Sleep(50 * time.Millisecond)
+	if gameID, _ := gm.playerGameID("series_player1"); gameID != "" {
+		t.Errorf("Expected no game after the series ended, got %s", gameID)
+	}
+}
+
+func TestMatchSeries_LeavingForfeitsSeries(t *testing.T) {
+	gameStore := memory.NewGameStore()
+	playerStore := memory.NewPlayerStore()
+	gm := NewGameManager(gameStore, playerStore, NewWebSocketManager())
+	gm.SetSeriesCountdown(10 * time.Millisecond)
+
+	player1 := &models.Player{ID: "forfeit_player1", Username: "Forfeit1"}
+	player2 := &models.Player{ID: "forfeit_player2", Username: "Forfeit2"}
+	playerStore.CreatePlayer(player1)
+	playerStore.CreatePlayer(player2)
+
+	game := &models.GameSession{ID: "forfeit_game1", Players: models.NewSeats(player1, player2), BestOf: 3}
+	gameStore.CreateGame(game)
+	gm.StartGame(context.Background(), game)
+
+	unlock := gm.lockGame(game.ID)
+	gm.finalizeGame(game, "forfeit_player2")
+	unlock()
+
+	// Player 2 leaves during the countdown
+	playerStore.DeletePlayer("forfeit_player2")
+
+	deadline := time.Now().Add(2 * time.Second)
+	var series models.MatchSeries
+	for time.Now().Before(deadline) {
+		gm.seriesMu.Lock()
+		stored, _ := gameStore.GetSeries(game.SeriesID)
+		series = *stored
+		gm.seriesMu.Unlock()
+		if series.Status == models.SeriesStatusFinished {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if series.Status != models.SeriesStatusFinished || series.WinnerID != "forfeit_player1" {
+		t.Fatalf("Expected player 1 to win by forfeit, got status %s winner %q", series.Status, series.WinnerID)
+	}
+	if gameID, _ := gm.playerGameID("forfeit_player1"); gameID != "" {
+		t.Errorf("Expected no new game after a forfeit, got %s", gameID)
+	}
+}
+
+func TestHandleRematchRequestDuringSeries(t *testing.T) {
+	gameStore := memory.NewGameStore()
+	playerStore := memory.NewPlayerStore()
+	gm := NewGameManager(gameStore, playerStore, NewWebSocketManager())
+	gm.SetSeriesCountdown(time.Hour)
+
+	player1 := &models.Player{ID: "midseries_player1", Username: "Mid1"}
+	player2 := &models.Player{ID: "midseries_player2", Username: "Mid2"}
+	playerStore.CreatePlayer(player1)
+	playerStore.CreatePlayer(player2)
+
+	game := &models.GameSession{ID: "midseries_game1", Players: models.NewSeats(player1, player2), BestOf: 3}
+	gameStore.CreateGame(game)
+	gm.StartGame(context.Background(), game)
+
+	unlock := gm.lockGame(game.ID)
+	gm.finalizeGame(game, "midseries_player1")
+	unlock()
+
+	// The next game starts on its own, so a rematch makes no sense yet
+	if err := gm.HandleRematchRequest("midseries_player1"); err == nil {
+		t.Error("Expected rematch to be refused while the series is in progress")
+	}
+}
+
+func TestHandleRematchRequestUsesLastGame(t *testing.T) {
+	gameStore := memory.NewGameStore()
+	playerStore := memory.NewPlayerStore()
+	gm := NewGameManager(gameStore, playerStore, NewWebSocketManager())
+
+	player1 := &models.Player{ID: "rematch_player1", Username: "Rematch1"}
+	player2 := &models.Player{ID: "rematch_player2", Username: "Rematch2"}
+	playerStore.CreatePlayer(player1)
+	playerStore.CreatePlayer(player2)
+
+	// An older finished game must never be picked up for the rematch
+	for _, id := range []string{"rematch_old", "rematch_latest"} {
+		gameStore.CreateGame(&models.GameSession{
+			ID:      id,
+			Players: models.NewSeats(player1, player2),
+			Status:  models.GameStatusFinished,
+		})
+	}
+
+	if err := gm.HandleRematchRequest("rematch_player1"); err != nil {
+		t.Fatalf("HandleRematchRequest failed: %v", err)
+	}
+	if err := gm.HandleRematchRequest("rematch_player2"); err != nil {
+		t.Fatalf("HandleRematchRequest failed: %v", err)
+	}
+
+	old, _ := gameStore.GetGame("rematch_old")
+	if old.Seat("rematch_player1").RematchReq || old.Seat("rematch_player2").RematchReq {
+		t.Error("Expected the stale game to be left alone")
+	}
+	latest, _ := gameStore.GetGame("rematch_latest")
+	if !latest.Seat("rematch_player1").RematchReq || !latest.Seat("rematch_player2").RematchReq {
+		t.Error("Expected both rematch requests on the latest game")
+	}
+
+	rematch, err := gameStore.GetLastGame("rematch_player1")
+	if err != nil || rematch.ID == "rematch_latest" || rematch.Status != models.GameStatusActive {
+		t.Fatalf("Expected a new active rematch game, got %+v (err %v)", rematch, err)
+	}
+
+	// The rematch game is still running, so there's nothing to rematch yet
+	if err := gm.HandleRematchRequest("rematch_player1"); err == nil {
+		t.Error("Expected rematch to be refused while the latest game is active")
+	}
+}
+
+func TestBattleRoyaleLastStandingWins(t *testing.T) {
+	gameStore := memory.NewGameStore()
+	playerStore := memory.NewPlayerStore()
+	gm := NewGameManager(gameStore, playerStore, NewWebSocketManager())
+
+	var players []*models.Player
+	for _, id := range []string{"royale_a", "royale_b", "royale_c", "royale_d"} {
+		player := &models.Player{ID: id, Username: id}
+		playerStore.CreatePlayer(player)
+		players = append(players, player)
+	}
+
+	game := &models.GameSession{
+		ID:        "royale_game",
+		Players:   models.NewSeats(players...),
+		Garbage:   true,
+		Targeting: models.TargetKOs,
+		Status:    models.GameStatusActive,
+	}
+	gameStore.CreateGame(game)
+	for _, player := range players {
+		gm.setPlayerGameID(player, game.ID)
+	}
+
+	// With KO targeting, a's tetris goes to d, who has the tallest stack
+	gm.HandleGameState("royale_d", &models.GameState{Board: [][]int{{1}, {1}}})
+	gm.HandleGameState("royale_a", &models.GameState{Score: 800, Lines: 4})
+	if attacker := game.Seat("royale_d").AttackerID; attacker != "royale_a" {
+		t.Fatalf("Expected d to be attacked by a, got %q", attacker)
+	}
+
+	// d tops out and a is credited with the KO
+	if err := gm.EndGame(game.ID, "royale_d"); err != nil {
+		t.Fatalf("EndGame failed: %v", err)
+	}
+	if seat := game.Seat("royale_d"); seat.Placement != 4 {
+		t.Errorf("Expected d to place 4th, got %d", seat.Placement)
+	}
+	if seat := game.Seat("royale_a"); seat.KOs != 1 || seat.Badges != 1 {
+		t.Errorf("Expected a to have 1 KO and 1 badge, got %d and %d", seat.KOs, seat.Badges)
+	}
+
+	// A royale isn't decided by score, so the game carries on
+	gm.EndGame(game.ID, "royale_b")
+	if game.Status == models.GameStatusFinished {
+		t.Fatal("Expected the game to continue with two players standing")
+	}
+
+	// Leaving mid-game is an elimination, not a forfeit for everyone else
+	if err := gm.HandlePlayerDisconnect("royale_c"); err != nil {
+		t.Fatalf("HandlePlayerDisconnect failed: %v", err)
+	}
+
+	if game.Status != models.GameStatusFinished {
+		t.Fatalf("Expected the game to end with one player standing, got %s", game.Status)
+	}
+	placements := map[string]int{"royale_a": 1, "royale_c": 2, "royale_b": 3, "royale_d": 4}
+	for id, want := range placements {
+		if got := game.Seat(id).Placement; got != want {
+			t.Errorf("Expected %s to place %d, got %d", id, want, got)
+		}
+		player, _ := playerStore.GetPlayer(id)
+		if player.RoyaleGames != 1 || player.PlacementTotal != want {
+			t.Errorf("Expected %s to have 1 royale placed %d, got %d totalling %d", id, want, player.RoyaleGames, player.PlacementTotal)
+		}
+	}
+
+	winner, _ := playerStore.GetPlayer("royale_a")
+	if winner.Wins != 1 {
+		t.Errorf("Expected the last player standing to win, got %d wins", winner.Wins)
+	}
 }
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"errors"
+	"math/rand"
+
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+// garbageBoardWidth matches the client board; garbage rows have one hole
+const garbageBoardWidth = 10
+
+// garbageForClear is how many garbage lines a clear sends, by lines cleared
+var garbageForClear = [...]int{0, 0, 1, 2, 4}
+
+var ErrInvalidTargeting = errors.New("unknown targeting strategy")
+
+// garbageLines returns the garbage sent for a clear, boosted by the sender's badges
+func garbageLines(cleared, badges int) int {
+	if cleared >= len(garbageForClear) {
+		cleared = len(garbageForClear) - 1
+	}
+	lines := garbageForClear[cleared]
+	return lines + lines*badgeBonus(badges)/100
+}
+
+// badgeBonus is the extra garbage, in percent, that a player's badges earn
+func badgeBonus(badges int) int {
+	switch {
+	case badges >= 16:
+		return 100
+	case badges >= 8:
+		return 75
+	case badges >= 4:
+		return 50
+	case badges >= 2:
+		return 25
+	default:
+		return 0
+	}
+}
+
+// stackHeight returns how many rows of a board are in use
+func stackHeight(board [][]int) int {
+	for i, row := range board {
+		for _, cell := range row {
+			if cell != 0 {
+				return len(board) - i
+			}
+		}
+	}
+	return 0
+}
+
+// chooseTargets picks the opponents still standing who receive an attacker's
+// garbage under their targeting strategy. Strategies that find nobody fall
+// back to a random opponent.
+func chooseTargets(game *models.GameSession, attacker *models.SessionPlayer) []*models.SessionPlayer {
+	var candidates []*models.SessionPlayer
+	for _, seat := range game.Alive() {
+		if seat != attacker {
+			candidates = append(candidates, seat)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	strategy := attacker.Targeting
+	if strategy == "" {
+		strategy = game.Targeting
+	}
+
+	switch strategy {
+	case models.TargetAttackers:
+		var attackers []*models.SessionPlayer
+		for _, seat := range candidates {
+			if seat.TargetID == attacker.Player.ID {
+				attackers = append(attackers, seat)
+			}
+		}
+		if len(attackers) > 0 {
+			return attackers
+		}
+	case models.TargetKOs:
+		target := candidates[0]
+		for _, seat := range candidates[1:] {
+			if seat.StackHeight > target.StackHeight {
+				target = seat
+			}
+		}
+		return []*models.SessionPlayer{target}
+	case models.TargetBadges:
+		target := candidates[0]
+		for _, seat := range candidates[1:] {
+			if seat.Badges > target.Badges {
+				target = seat
+			}
+		}
+		if target.Badges > 0 {
+			return []*models.SessionPlayer{target}
+		}
+	}
+
+	return []*models.SessionPlayer{candidates[rand.Intn(len(candidates))]}
+}
+
+// sendGarbage routes the garbage from a line clear to the attacker's targets
+func (gm *GameManager) sendGarbage(game *models.GameSession, attacker *models.SessionPlayer, cleared int) {
+	lines := garbageLines(cleared, attacker.Badges)
+	if lines == 0 {
+		return
+	}
+
+	for _, target := range chooseTargets(game, attacker) {
+		target.AttackerID = attacker.Player.ID
+		attacker.TargetID = target.Player.ID
+
+		garbageMsg := map[string]interface{}{
+			"type":   "garbage",
+			"gameId": game.ID,
+			"fromId": attacker.Player.ID,
+			"lines":  lines,
+			"hole":   rand.Intn(garbageBoardWidth),
+		}
+		gm.sendToPlayer(target.Player.ID, garbageMsg)
+	}
+}
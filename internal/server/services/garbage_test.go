@@ -0,0 +1,114 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func TestGarbageLines(t *testing.T) {
+	tests := []struct {
+		cleared, badges, want int
+	}{
+		{1, 0, 0},
+		{2, 0, 1},
+		{3, 0, 2},
+		{4, 0, 4},
+		{4, 2, 5},
+		{4, 4, 6},
+		{4, 8, 7},
+		{4, 16, 8},
+		{6, 0, 4}, // More than a tetris is still a tetris
+	}
+	for _, tt := range tests {
+		if got := garbageLines(tt.cleared, tt.badges); got != tt.want {
+			t.Errorf("garbageLines(%d, %d) = %d, want %d", tt.cleared, tt.badges, got, tt.want)
+		}
+	}
+}
+
+func TestStackHeight(t *testing.T) {
+	board := [][]int{
+		{0, 0, 0},
+		{0, 0, 0},
+		{0, 3, 0},
+		{1, 1, 0},
+	}
+	if got := stackHeight(board); got != 2 {
+		t.Errorf("Expected stack height 2, got %d", got)
+	}
+	if got := stackHeight([][]int{{0}, {0}}); got != 0 {
+		t.Errorf("Expected empty board to have height 0, got %d", got)
+	}
+}
+
+// royaleSeats returns a four player game with the attacker in the first seat
+func royaleSeats() *models.GameSession {
+	return &models.GameSession{
+		Players: models.NewSeats(
+			&models.Player{ID: "attacker"},
+			&models.Player{ID: "a"},
+			&models.Player{ID: "b"},
+			&models.Player{ID: "c"},
+		),
+	}
+}
+
+func TestChooseTargets(t *testing.T) {
+	t.Run("attackers", func(t *testing.T) {
+		game := royaleSeats()
+		game.Players[0].Targeting = models.TargetAttackers
+		game.Seat("a").TargetID = "attacker"
+		game.Seat("c").TargetID = "attacker"
+
+		targets := chooseTargets(game, game.Players[0])
+		if len(targets) != 2 || targets[0].Player.ID != "a" || targets[1].Player.ID != "c" {
+			t.Errorf("Expected to hit back at a and c, got %v", seatIDs(targets))
+		}
+	})
+
+	t.Run("kos", func(t *testing.T) {
+		game := royaleSeats()
+		game.Targeting = models.TargetKOs
+		game.Seat("a").StackHeight = 5
+		game.Seat("b").StackHeight = 17
+		game.Seat("c").StackHeight = 9
+
+		targets := chooseTargets(game, game.Players[0])
+		if len(targets) != 1 || targets[0].Player.ID != "b" {
+			t.Errorf("Expected the highest stack to be targeted, got %v", seatIDs(targets))
+		}
+	})
+
+	t.Run("badges", func(t *testing.T) {
+		game := royaleSeats()
+		game.Players[0].Targeting = models.TargetBadges
+		game.Seat("c").Badges = 3
+
+		targets := chooseTargets(game, game.Players[0])
+		if len(targets) != 1 || targets[0].Player.ID != "c" {
+			t.Errorf("Expected the badge leader to be targeted, got %v", seatIDs(targets))
+		}
+	})
+
+	t.Run("fallback skips eliminated players", func(t *testing.T) {
+		game := royaleSeats()
+		game.Players[0].Targeting = models.TargetAttackers
+		game.Seat("a").Lost = true
+		game.Seat("b").Lost = true
+
+		// Nobody is attacking, so garbage goes to whoever is left
+		targets := chooseTargets(game, game.Players[0])
+		if len(targets) != 1 || targets[0].Player.ID != "c" {
+			t.Errorf("Expected the last opponent standing, got %v", seatIDs(targets))
+		}
+	})
+}
+
+func seatIDs(seats []*models.SessionPlayer) []string {
+	ids := make([]string, len(seats))
+	for i, seat := range seats {
+		ids[i] = seat.Player.ID
+	}
+	return ids
+}
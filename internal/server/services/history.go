@@ -0,0 +1,36 @@
+package services
+
+import (
+	"github.com/briancain/go-tetris/internal/server/logger"
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+// SetMatchHistoryStore keeps registered players' finished games in store for
+// their match history. Games aren't kept without one.
+func (gm *GameManager) SetMatchHistoryStore(store storage.MatchHistoryStore) {
+	gm.historyStore = store
+}
+
+// recordMatchHistory adds a finished game to each registered player's match
+// history. Guests come and go with their sessions, so their games only count
+// toward their stats.
+func (gm *GameManager) recordMatchHistory(game *models.GameSession) {
+	if gm.historyStore == nil {
+		return
+	}
+
+	match := models.NewMatchRecord(game)
+	for _, seat := range game.Players {
+		if !seat.Player.Registered {
+			continue
+		}
+		if err := gm.historyStore.AddPlayerMatch(seat.Player.ID, match); err != nil {
+			logger.Logger.Error("Failed to record match history",
+				"gameID", game.ID,
+				"playerID", seat.Player.ID,
+				"error", err,
+			)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/briancain/go-tetris/internal/server/logger"
+	"github.com/briancain/go-tetris/internal/server/metrics"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+// JanitorSettings control how often the janitor sweeps and what it reclaims
+type JanitorSettings struct {
+	Interval          time.Duration // Time between sweeps
+	IdlePlayerTimeout time.Duration // Guests inactive this long are removed
+	IdleQueueTimeout  time.Duration // Queued players inactive this long are dequeued
+
+	// StaleGameTimeout is how long a game may wait to start, or go without
+	// any of its players being active, before it's cancelled
+	StaleGameTimeout time.Duration
+
+	// FinishedGameRetention is how long finished games are kept for
+	// rematches before they're deleted
+	FinishedGameRetention time.Duration
+}
+
+// JanitorReport counts what a sweep reclaimed
+type JanitorReport struct {
+	IdlePlayers   int
+	QueueEntries  int
+	StaleGames    int
+	FinishedGames int
+	Series        int
+	Replays       int
+}
+
+// Janitor periodically reclaims state nobody will come back for: idle guests,
+// abandoned queue entries, games left behind by crashed clients or
+// instances, and finished games and replays past their retention. Every instance can run
+// one; sweeps tolerate another instance reclaiming the same things.
+type Janitor struct {
+	authService *AuthService
+	gameManager *GameManager
+	matchmaking *MatchmakingService
+	settings    JanitorSettings
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewJanitor creates a new janitor
+func NewJanitor(
+	authService *AuthService,
+	gameManager *GameManager,
+	matchmaking *MatchmakingService,
+	settings JanitorSettings,
+) *Janitor {
+	return &Janitor{
+		authService: authService,
+		gameManager: gameManager,
+		matchmaking: matchmaking,
+		settings:    settings,
+		stop:        make(chan struct{}),
+	}
+}
+
+// Start sweeps every interval until Stop is called
+func (j *Janitor) Start() {
+	go func() {
+		ticker := time.NewTicker(j.settings.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-j.stop:
+				return
+			case <-ticker.C:
+				j.Sweep()
+			}
+		}
+	}()
+}
+
+// Stop ends the sweep loop
+func (j *Janitor) Stop() {
+	j.stopOnce.Do(func() {
+		close(j.stop)
+	})
+}
+
+// Sweep reclaims everything past its threshold once. Stale games are
+// cancelled before players are expired, so their players are freed to go.
+func (j *Janitor) Sweep() JanitorReport {
+	var report JanitorReport
+	var err error
+	now := time.Now()
+
+	if report.StaleGames, err = j.cancelStaleGames(now); err != nil {
+		logger.Logger.Warn("Janitor failed to cancel stale games", "error", err)
+	}
+	if report.FinishedGames, report.Series, err = j.deleteFinishedGames(now); err != nil {
+		logger.Logger.Warn("Janitor failed to delete finished games", "error", err)
+	}
+	if report.Replays, err = j.gameManager.deleteExpiredReplays(); err != nil {
+		logger.Logger.Warn("Janitor failed to delete expired replays", "error", err)
+	}
+	if report.QueueEntries, err = j.matchmaking.RemoveIdleQueueEntries(j.settings.IdleQueueTimeout); err != nil {
+		logger.Logger.Warn("Janitor failed to remove idle queue entries", "error", err)
+	}
+	if report.IdlePlayers, err = j.authService.CleanupInactivePlayers(j.settings.IdlePlayerTimeout); err != nil {
+		logger.Logger.Warn("Janitor failed to remove idle players", "error", err)
+	}
+
+	metrics.JanitorReclaimed.WithLabelValues("idle_players").Add(float64(report.IdlePlayers))
+	metrics.JanitorReclaimed.WithLabelValues("queue_entries").Add(float64(report.QueueEntries))
+	metrics.JanitorReclaimed.WithLabelValues("stale_games").Add(float64(report.StaleGames))
+	metrics.JanitorReclaimed.WithLabelValues("finished_games").Add(float64(report.FinishedGames))
+	metrics.JanitorReclaimed.WithLabelValues("series").Add(float64(report.Series))
+	metrics.JanitorReclaimed.WithLabelValues("replays").Add(float64(report.Replays))
+
+	if report != (JanitorReport{}) {
+		logger.Logger.Info("Janitor sweep reclaimed state",
+			"idlePlayers", report.IdlePlayers,
+			"queueEntries", report.QueueEntries,
+			"staleGames", report.StaleGames,
+			"finishedGames", report.FinishedGames,
+			"series", report.Series,
+			"replays", report.Replays,
+		)
+	}
+	return report
+}
+
+// cancelStaleGames cancels unfinished games that are stale, returning how
+// many were cancelled
+func (j *Janitor) cancelStaleGames(now time.Time) (int, error) {
+	games, err := j.gameManager.gameStore.GetActiveGames()
+	if err != nil {
+		return 0, err
+	}
+
+	cancelled := 0
+	for _, game := range games {
+		stale, status := j.gameStale(game.ID, now)
+		if !stale {
+			continue
+		}
+
+		err := j.gameManager.CancelGame(game.ID)
+		if errors.Is(err, ErrGameNotLive) {
+			continue // Finished since it was listed
+		}
+		if err != nil {
+			return cancelled, err
+		}
+		logger.Logger.Info("Janitor cancelled stale game",
+			"gameID", game.ID,
+			"status", status,
+			"createdAt", game.CreatedAt,
+		)
+		cancelled++
+	}
+	return cancelled, nil
+}
+
+// gameStale reports whether a game is older than the stale timeout and
+// either never started or has no player still active in it, along with its
+// status. The game is read under its lock since it may be in play.
+func (j *Janitor) gameStale(gameID string, now time.Time) (bool, models.GameStatus) {
+	unlock := j.gameManager.lockGame(gameID)
+	defer unlock()
+
+	game, err := j.gameManager.gameStore.GetGame(gameID)
+	if err != nil || game.Status == models.GameStatusFinished {
+		return false, ""
+	}
+
+	cutoff := now.Add(-j.settings.StaleGameTimeout)
+	if game.CreatedAt.After(cutoff) {
+		return false, game.Status
+	}
+	if game.Status == models.GameStatusWaiting {
+		return true, game.Status
+	}
+
+	for _, playerID := range game.PlayerIDs() {
+		player, err := j.gameManager.playerStore.GetPlayer(playerID)
+		if err == nil && player.GameID == game.ID && player.LastActivity.After(cutoff) {
+			return false, game.Status
+		}
+	}
+	return true, game.Status
+}
+
+// deleteFinishedGames deletes games that finished longer ago than the
+// retention period, along with any series they completed. It returns how
+// many games and series were deleted.
+func (j *Janitor) deleteFinishedGames(now time.Time) (int, int, error) {
+	gameStore := j.gameManager.gameStore
+	games, err := gameStore.GetAllGames()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	cutoff := now.Add(-j.settings.FinishedGameRetention)
+	deletedGames, deletedSeries := 0, 0
+	for _, game := range games {
+		seriesID, finished := j.finishedBefore(game.ID, cutoff)
+		if !finished {
+			continue
+		}
+
+		if err := gameStore.DeleteGame(game.ID); err != nil {
+			continue // Expired or deleted by another instance
+		}
+		deletedGames++
+
+		if seriesID == "" {
+			continue
+		}
+		series, err := gameStore.GetSeries(seriesID)
+		if err != nil || series.Status != models.SeriesStatusFinished {
+			continue
+		}
+		if err := gameStore.DeleteSeries(series.ID); err == nil {
+			deletedSeries++
+		}
+	}
+	return deletedGames, deletedSeries, nil
+}
+
+// finishedBefore reports whether a game finished before cutoff, along with
+// its series. The game is read under its lock since it may be finishing.
+func (j *Janitor) finishedBefore(gameID string, cutoff time.Time) (string, bool) {
+	unlock := j.gameManager.lockGame(gameID)
+	defer unlock()
+
+	game, err := j.gameManager.gameStore.GetGame(gameID)
+	if err != nil || game.Status != models.GameStatusFinished {
+		return "", false
+	}
+
+	endedAt := game.EndedAt
+	if endedAt.IsZero() {
+		endedAt = game.CreatedAt
+	}
+	return game.SeriesID, !endedAt.After(cutoff)
+}
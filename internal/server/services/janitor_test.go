@@ -0,0 +1,153 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/briancain/go-tetris/internal/server/metrics"
+	"github.com/briancain/go-tetris/internal/server/storage/memory"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func TestJanitor_Sweep(t *testing.T) {
+	playerStore := memory.NewPlayerStore()
+	gameStore := memory.NewGameStore()
+	queueStore := memory.NewQueueStore()
+	authService := NewAuthService(playerStore, memory.NewAccountStore())
+	replayStore := memory.NewReplayStore()
+	gm := NewGameManager(gameStore, playerStore, NewWebSocketManager())
+	gm.SetReplayStore(replayStore)
+	matchmaking := NewMatchmakingService(playerStore, gameStore, queueStore, memory.NewRoomStore(), gm)
+	janitor := NewJanitor(authService, gm, matchmaking, JanitorSettings{
+		Interval:              time.Minute,
+		IdlePlayerTimeout:     30 * time.Minute,
+		IdleQueueTimeout:      2 * time.Minute,
+		StaleGameTimeout:      10 * time.Minute,
+		FinishedGameRetention: 2 * time.Hour,
+	})
+
+	now := time.Now()
+	addPlayer := func(id string, idle time.Duration) *models.Player {
+		player := &models.Player{ID: id, Username: id, LastActivity: now.Add(-idle)}
+		playerStore.CreatePlayer(player)
+		return player
+	}
+	addGame := func(id string, status models.GameStatus, age time.Duration, players ...*models.Player) *models.GameSession {
+		game := &models.GameSession{ID: id, Players: models.NewSeats(players...), Status: status, CreatedAt: now.Add(-age)}
+		gameStore.CreateGame(game)
+		for _, player := range players {
+			if status != models.GameStatusFinished {
+				gm.setPlayerGameID(player, id)
+			}
+		}
+		return game
+	}
+	queuePlayer := func(player *models.Player) {
+		queue := models.QueueOptions{Queue: models.QueueRanked, Mode: models.DefaultGameMode}.Key()
+		player.Queue = queue
+		playerStore.UpdatePlayer(player)
+		queueStore.AddToQueue(queue, player.ID)
+	}
+
+	// Games: one never started, one whose players walked away, one still
+	// being played and one just created
+	addGame("stuck", models.GameStatusWaiting, 20*time.Minute, addPlayer("stuck1", time.Minute), addPlayer("stuck2", time.Minute))
+	addGame("abandoned", models.GameStatusPaused, time.Hour, addPlayer("gone1", 20*time.Minute), addPlayer("gone2", 20*time.Minute))
+	addGame("playing", models.GameStatusActive, time.Hour, addPlayer("busy1", time.Second), addPlayer("busy2", 20*time.Minute))
+	addGame("new", models.GameStatusWaiting, time.Minute, addPlayer("new1", time.Minute), addPlayer("new2", time.Minute))
+
+	// Finished games, the older one completing a series
+	old := addGame("old", models.GameStatusFinished, 4*time.Hour, addPlayer("old1", time.Minute), addPlayer("old2", time.Minute))
+	old.EndedAt = now.Add(-3 * time.Hour)
+	old.SeriesID = "old-series"
+	gameStore.CreateSeries(&models.MatchSeries{ID: "old-series", Status: models.SeriesStatusFinished})
+	recent := addGame("recent", models.GameStatusFinished, 2*time.Hour, addPlayer("recent1", time.Minute), addPlayer("recent2", time.Minute))
+	recent.EndedAt = now.Add(-time.Hour)
+	// Replays expire on their own retention, independent of their games
+	replayStore.SetRetention(-time.Minute)
+	replayStore.CreateReplay(models.NewReplay(recent))
+	replayStore.SetRetention(time.Hour)
+	replayStore.CreateReplay(models.NewReplay(old))
+
+	// Queue entries: a deleted player, an idle player and an active one
+	queuePlayer(addPlayer("deleted", time.Second))
+	playerStore.DeletePlayer("deleted")
+	queuePlayer(addPlayer("afk", 5*time.Minute))
+	queuePlayer(addPlayer("waiting", time.Second))
+
+	// Guests and accounts gone quiet
+	addPlayer("idle-guest", time.Hour)
+	idleMember := addPlayer("idle-member", time.Hour)
+	idleMember.Registered = true
+	playerStore.UpdatePlayer(idleMember)
+
+	reclaimedGames := testutil.ToFloat64(metrics.JanitorReclaimed.WithLabelValues("stale_games"))
+	reclaimedReplays := testutil.ToFloat64(metrics.JanitorReclaimed.WithLabelValues("replays"))
+
+	report := janitor.Sweep()
+
+	want := JanitorReport{IdlePlayers: 1, QueueEntries: 2, StaleGames: 2, FinishedGames: 1, Series: 1, Replays: 1}
+	if report != want {
+		t.Errorf("Expected report %+v, got %+v", want, report)
+	}
+	if got := testutil.ToFloat64(metrics.JanitorReclaimed.WithLabelValues("stale_games")) - reclaimedGames; got != 2 {
+		t.Errorf("Expected 2 stale games counted, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.JanitorReclaimed.WithLabelValues("replays")) - reclaimedReplays; got != 1 {
+		t.Errorf("Expected 1 replay counted, got %v", got)
+	}
+
+	for id, wantStatus := range map[string]models.GameStatus{
+		"stuck":     models.GameStatusFinished,
+		"abandoned": models.GameStatusFinished,
+		"playing":   models.GameStatusActive,
+		"new":       models.GameStatusWaiting,
+	} {
+		game, err := gameStore.GetGame(id)
+		if err != nil || game.Status != wantStatus {
+			t.Errorf("Expected game %s to be %s, got %+v (err %v)", id, wantStatus, game, err)
+		}
+	}
+	if game, _ := gameStore.GetGame("abandoned"); !game.Cancelled {
+		t.Error("Expected the abandoned game to be cancelled without a result")
+	}
+	if player, _ := playerStore.GetPlayer("gone1"); player.GameID != "" {
+		t.Errorf("Expected players of a cancelled game to be freed, got game %q", player.GameID)
+	}
+
+	if _, err := gameStore.GetGame("old"); err == nil {
+		t.Error("Expected the old finished game to be deleted")
+	}
+	if _, err := gameStore.GetSeries("old-series"); err == nil {
+		t.Error("Expected the finished series to be deleted")
+	}
+	if _, err := gameStore.GetGame("recent"); err != nil {
+		t.Errorf("Expected the recent finished game to be kept, got %v", err)
+	}
+	if _, err := replayStore.GetReplay("recent"); err == nil {
+		t.Error("Expected the expired replay to be deleted")
+	}
+	if _, err := replayStore.GetReplay("old"); err != nil {
+		t.Errorf("Expected a replay within its retention to outlive its game, got %v", err)
+	}
+
+	queued, _ := queueStore.GetQueuedPlayers(models.QueueOptions{Queue: models.QueueRanked, Mode: models.DefaultGameMode}.Key())
+	if len(queued) != 1 || queued[0] != "waiting" {
+		t.Errorf("Expected only the active player left queued, got %v", queued)
+	}
+	if player, _ := playerStore.GetPlayer("afk"); player.Queue != "" {
+		t.Errorf("Expected the idle player to be dequeued, got queue %q", player.Queue)
+	}
+
+	if _, err := playerStore.GetPlayer("idle-guest"); err == nil {
+		t.Error("Expected the idle guest to be removed")
+	}
+	if _, err := playerStore.GetPlayer("idle-member"); err != nil {
+		t.Errorf("Expected the registered player to be kept, got %v", err)
+	}
+	if _, err := playerStore.GetPlayer("gone1"); err != nil {
+		t.Errorf("Expected players idle less than the player timeout to be kept, got %v", err)
+	}
+}
@@ -1,21 +1,64 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"math"
 	"math/rand"
+	"regexp"
+	"slices"
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/briancain/go-tetris/internal/server/glicko"
+	"github.com/briancain/go-tetris/internal/server/logger"
+	"github.com/briancain/go-tetris/internal/server/metrics"
 	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/internal/server/tracing"
 	"github.com/briancain/go-tetris/pkg/models"
 )
 
-// MatchmakingService handles player matchmaking
+const (
+	ratingWindowBase    = 100.0 // Rating gap accepted as soon as a player queues
+	ratingWindowGrowth  = 25.0  // Extra rating gap accepted per second of waiting
+	matchmakingInterval = time.Second
+
+	// idleQueueMessage is sent to players removed from a queue for inactivity
+	idleQueueMessage = "You were removed from matchmaking for inactivity"
+)
+
+var (
+	ErrUnknownQueue    = errors.New("unknown matchmaking queue")
+	ErrInvalidGameMode = errors.New("invalid game mode")
+
+	gameModePattern = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+)
+
+// MatchmakingService handles player matchmaking. Each queue runs its own
+// matchmaking loop, pairing players with the closest rating available within
+// a window that widens the longer they wait.
 type MatchmakingService struct {
 	playerStore storage.PlayerStore
 	gameStore   storage.GameStore
 	queueStore  storage.QueueStore
+	roomStore   storage.RoomStore
 	gameManager *GameManager
-	mu          sync.Mutex // Protects matchmaking operations
+
+	mu       sync.Mutex
+	loops    map[string]chan struct{} // Wakes each running queue loop, by queue key
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// queuedPlayer is a matchmaking candidate with the rating gap they accept
+type queuedPlayer struct {
+	player *models.Player
+	rating float64
+	window float64
 }
 
 // NewMatchmakingService creates a new matchmaking service
@@ -23,18 +66,66 @@ func NewMatchmakingService(
 	playerStore storage.PlayerStore,
 	gameStore storage.GameStore,
 	queueStore storage.QueueStore,
+	roomStore storage.RoomStore,
 	gameManager *GameManager,
 ) *MatchmakingService {
 	return &MatchmakingService{
 		playerStore: playerStore,
 		gameStore:   gameStore,
 		queueStore:  queueStore,
+		roomStore:   roomStore,
 		gameManager: gameManager,
+		loops:       make(map[string]chan struct{}),
+		stop:        make(chan struct{}),
+	}
+}
+
+// Start runs the default queues' loops so players left queued from before
+// this instance started are matched
+func (s *MatchmakingService) Start() {
+	for _, queue := range []string{models.QueueRanked, models.QueueCasual} {
+		s.wake(models.QueueOptions{Queue: queue, Mode: models.DefaultGameMode})
+	}
+}
+
+// Stop ends all matchmaking loops
+func (s *MatchmakingService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+}
+
+// NormalizeQueueOptions fills in defaults and validates queue options. An
+// empty request joins the ranked classic queue.
+func NormalizeQueueOptions(options models.QueueOptions) (models.QueueOptions, error) {
+	if options.Queue == "" {
+		options.Queue = models.QueueRanked
+	}
+	if options.Mode == "" {
+		options.Mode = models.DefaultGameMode
+	}
+
+	if options.Queue != models.QueueRanked && options.Queue != models.QueueCasual {
+		return options, ErrUnknownQueue
+	}
+	if !gameModePattern.MatchString(options.Mode) {
+		return options, ErrInvalidGameMode
 	}
+	return options, nil
 }
 
-// JoinQueue adds a player to the matchmaking queue
-func (s *MatchmakingService) JoinQueue(playerID string) error {
+// JoinQueue adds a player to a matchmaking queue, moving them out of any
+// other queue they were waiting in
+func (s *MatchmakingService) JoinQueue(playerID string, options models.QueueOptions) error {
+	if s.gameManager.Draining() {
+		return ErrServerDraining
+	}
+
+	options, err := NormalizeQueueOptions(options)
+	if err != nil {
+		return err
+	}
+
 	// Get player
 	player, err := s.playerStore.GetPlayer(playerID)
 	if err != nil {
@@ -46,110 +137,445 @@ func (s *MatchmakingService) JoinQueue(playerID string) error {
 		return nil // Already in game
 	}
 
-	// Add to queue
-	err = s.queueStore.AddToQueue(playerID)
+	if player.Queue != "" && player.Queue != options.Key() {
+		err = s.queueStore.RemoveFromQueue(player.Queue, playerID)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update player status before queueing so matchmakers see their wait time
+	err = s.playerStore.SetPlayerQueue(playerID, options.Key(), time.Now())
 	if err != nil {
 		return err
 	}
 
-	// Update player status
-	player.InQueue = true
-	err = s.playerStore.UpdatePlayer(player)
+	// Add to queue
+	err = s.queueStore.AddToQueue(options.Key(), playerID)
 	if err != nil {
 		return err
 	}
 
 	// Try to find a match
-	go s.tryMatchmaking()
+	s.wake(options)
 
 	return nil
 }
 
-// LeaveQueue removes a player from the matchmaking queue
+// LeaveQueue removes a player from the queue they are waiting in
 func (s *MatchmakingService) LeaveQueue(playerID string) error {
-	// Remove from queue
-	err := s.queueStore.RemoveFromQueue(playerID)
+	player, err := s.playerStore.GetPlayer(playerID)
 	if err != nil {
 		return err
 	}
 
+	if player.Queue != "" {
+		err = s.queueStore.RemoveFromQueue(player.Queue, playerID)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Update player status
+	return s.playerStore.ClearPlayerQueue(playerID, "")
+}
+
+// GetQueueStatus returns the key of the queue a player is waiting in and
+// their position in it, or -1 if they aren't queued
+func (s *MatchmakingService) GetQueueStatus(playerID string) (string, int, error) {
 	player, err := s.playerStore.GetPlayer(playerID)
 	if err != nil {
+		return "", -1, err
+	}
+	if player.Queue == "" {
+		return "", -1, nil
+	}
+
+	position, err := s.queueStore.GetQueuePosition(player.Queue, playerID)
+	if err != nil || position < 0 {
+		return "", -1, err
+	}
+	return player.Queue, position, nil
+}
+
+// QueueLengths returns how many players wait in the default queues and in
+// any other queue this instance is matching
+func (s *MatchmakingService) QueueLengths() map[string]int {
+	queues := s.knownQueues()
+	lengths := make(map[string]int, len(queues))
+	for _, queue := range queues {
+		queued, err := s.queueStore.GetQueuedPlayers(queue)
+		if err != nil {
+			continue
+		}
+		lengths[queue] = len(queued)
+	}
+	return lengths
+}
+
+// DrainQueues removes every player from the default queues and any other
+// queue this instance is matching, telling each of them why. It returns how
+// many players were removed.
+func (s *MatchmakingService) DrainQueues(reason string) (int, error) {
+	drained := 0
+	for _, queue := range s.knownQueues() {
+		queued, err := s.queueStore.GetQueuedPlayers(queue)
+		if err != nil {
+			return drained, err
+		}
+
+		for _, playerID := range queued {
+			if err := s.dequeue(queue, playerID, reason); err != nil {
+				return drained, err
+			}
+			drained++
+		}
+	}
+
+	logger.Logger.Info("Matchmaking queues drained",
+		"players", drained,
+		"reason", reason,
+	)
+	return drained, nil
+}
+
+// RemoveIdleQueueEntries removes queue entries whose player is gone, has
+// moved on, or hasn't been active for idleTimeout, telling idle players why.
+// It covers the default queues and any other queue this instance is matching,
+// and returns how many entries were removed.
+func (s *MatchmakingService) RemoveIdleQueueEntries(idleTimeout time.Duration) (int, error) {
+	cutoff := time.Now().Add(-idleTimeout)
+	removed := 0
+	for _, queue := range s.knownQueues() {
+		queued, err := s.queueStore.GetQueuedPlayers(queue)
+		if err != nil {
+			return removed, err
+		}
+
+		for _, playerID := range queued {
+			player, err := s.playerStore.GetPlayer(playerID)
+			switch {
+			case err != nil || player.Queue != queue:
+				// Nobody is waiting on this entry, so there's no one to tell
+				err = s.queueStore.RemoveFromQueue(queue, playerID)
+			case player.LastActivity.Before(cutoff):
+				err = s.dequeue(queue, playerID, idleQueueMessage)
+			default:
+				continue
+			}
+			if err != nil {
+				return removed, err
+			}
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// Drain prepares matchmaking for a server shutdown. The game manager stops
+// starting games, new queue joins and rooms are refused, this instance stops
+// matching, and players connected here are taken out of their queues so they
+// can queue again on another instance.
+func (s *MatchmakingService) Drain(message string) {
+	if !s.gameManager.Drain(message) {
+		return
+	}
+	s.Stop()
+
+	for _, playerID := range s.gameManager.wsManager.ConnectedPlayerIDs() {
+		player, err := s.playerStore.GetPlayer(playerID)
+		if err != nil || player.Queue == "" {
+			continue
+		}
+		if err := s.dequeue(player.Queue, playerID, message); err != nil {
+			logger.Logger.Warn("Failed to remove player from queue while draining",
+				"playerID", playerID,
+				"queue", player.Queue,
+				"error", err,
+			)
+		}
+	}
+}
+
+// dequeue removes a player from a queue and sends them a queue_drained
+// message saying why
+func (s *MatchmakingService) dequeue(queue, playerID, reason string) error {
+	if err := s.queueStore.RemoveFromQueue(queue, playerID); err != nil {
 		return err
 	}
 
-	player.InQueue = false
-	return s.playerStore.UpdatePlayer(player)
+	_ = s.playerStore.ClearPlayerQueue(playerID, queue)
+
+	s.gameManager.sendToPlayer(playerID, map[string]interface{}{
+		"type":    "queue_drained",
+		"queue":   queue,
+		"message": reason,
+	})
+	return nil
 }
 
-// GetQueueStatus returns the player's position in queue
-func (s *MatchmakingService) GetQueueStatus(playerID string) (int, error) {
-	return s.queueStore.GetQueuePosition(playerID)
+// knownQueues returns the keys of the default queues and of any other queue
+// this instance is matching
+func (s *MatchmakingService) knownQueues() []string {
+	queues := []string{
+		models.QueueOptions{Queue: models.QueueRanked, Mode: models.DefaultGameMode}.Key(),
+		models.QueueOptions{Queue: models.QueueCasual, Mode: models.DefaultGameMode}.Key(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for queue := range s.loops {
+		if !slices.Contains(queues, queue) {
+			queues = append(queues, queue)
+		}
+	}
+	return queues
 }
 
-// tryMatchmaking attempts to create matches from queued players
-func (s *MatchmakingService) tryMatchmaking() {
+// wake nudges a queue's matchmaking loop, starting it if it isn't running
+func (s *MatchmakingService) wake(options models.QueueOptions) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	queuedPlayers, err := s.queueStore.GetQueuedPlayers()
-	if err != nil || len(queuedPlayers) < 2 {
-		return
+	trigger, running := s.loops[options.Key()]
+	if !running {
+		trigger = make(chan struct{}, 1)
+		s.loops[options.Key()] = trigger
+		go s.runQueue(options, trigger)
+	}
+
+	select {
+	case trigger <- struct{}{}:
+	default: // A pass is already pending
 	}
+}
 
-	// Take first two players
-	player1ID := queuedPlayers[0]
-	player2ID := queuedPlayers[1]
+// runQueue matches players in one queue when woken and on every tick, so
+// rating windows widen while players wait. It exits once the queue empties.
+func (s *MatchmakingService) runQueue(options models.QueueOptions, trigger chan struct{}) {
+	ticker := time.NewTicker(matchmakingInterval)
+	defer ticker.Stop()
 
-	// Get player objects
-	player1, err := s.playerStore.GetPlayer(player1ID)
-	if err != nil {
-		return
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-trigger:
+		case <-ticker.C:
+		}
+
+		s.tryMatchmaking(options)
+
+		if s.stopIfIdle(options, trigger) {
+			return
+		}
 	}
+}
 
-	player2, err := s.playerStore.GetPlayer(player2ID)
-	if err != nil {
-		return
+// stopIfIdle unregisters a queue loop with no queued players and no pending wake-up
+func (s *MatchmakingService) stopIfIdle(options models.QueueOptions, trigger chan struct{}) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(trigger) > 0 {
+		return false
+	}
+	queued, err := s.queueStore.GetQueuedPlayers(options.Key())
+	if err != nil || len(queued) > 0 {
+		return false
 	}
 
-	// Remove from queue
-	_ = s.queueStore.RemoveFromQueue(player1ID)
-	_ = s.queueStore.RemoveFromQueue(player2ID)
+	delete(s.loops, options.Key())
+	return true
+}
 
-	// Create game session
-	gameID := generateID()
-	seed := generateSeed()
+// tryMatchmaking pairs queued players until no acceptable pair remains.
+// Pairs are claimed atomically from the queue store, so concurrent calls on
+// this or other instances never match the same player twice.
+func (s *MatchmakingService) tryMatchmaking(options models.QueueOptions) {
+	queue := options.Key()
+	for {
+		candidates := s.queuedCandidates(queue, time.Now())
+		player1ID, player2ID, ok := findMatch(candidates)
+		if !ok {
+			return
+		}
+
+		ctx, span := tracing.Start(context.Background(), "matchmaking.match",
+			attribute.String("queue.key", queue),
+			tracing.PlayerIDs([]string{player1ID, player2ID}),
+		)
+		claimed, err := s.queueStore.RemovePair(queue, player1ID, player2ID)
+		if err != nil {
+			logger.Logger.Error("Failed to claim players from queue", "queue", queue, "error", err)
+			tracing.End(span, err)
+			return
+		}
+		span.SetAttributes(attribute.Bool("matchmaking.claimed", claimed))
+		if !claimed {
+			span.End()
+			continue // Another matchmaker took one of them; look again
+		}
+
+		order := make([]string, len(candidates))
+		for i, candidate := range candidates {
+			order[i] = candidate.player.ID
+		}
+		s.createMatch(ctx, options, player1ID, player2ID, order)
+		span.End()
+	}
+}
+
+// queuedCandidates loads queued players in queue order, dropping any who
+// have disconnected or already joined a game
+func (s *MatchmakingService) queuedCandidates(queue string, now time.Time) []queuedPlayer {
+	queuedIDs, err := s.queueStore.GetQueuedPlayers(queue)
+	if err != nil || len(queuedIDs) < 2 {
+		return nil
+	}
 
+	candidates := make([]queuedPlayer, 0, len(queuedIDs))
+	for _, playerID := range queuedIDs {
+		player := s.matchablePlayer(playerID)
+		if player == nil {
+			_ = s.queueStore.RemoveFromQueue(queue, playerID)
+			continue
+		}
+		candidates = append(candidates, newQueuedPlayer(player, now))
+	}
+	return candidates
+}
+
+// newQueuedPlayer computes a player's rating window from their time in queue
+func newQueuedPlayer(player *models.Player, now time.Time) queuedPlayer {
+	var waited time.Duration
+	if !player.QueuedAt.IsZero() {
+		waited = now.Sub(player.QueuedAt)
+	}
+
+	return queuedPlayer{
+		player: player,
+		rating: glicko.Normalize(player.Rating).Value,
+		window: ratingWindowBase + ratingWindowGrowth*waited.Seconds(),
+	}
+}
+
+// findMatch picks the longest-waiting player who has an acceptable opponent
+// and pairs them with the closest rating. Both players must accept the gap.
+func findMatch(candidates []queuedPlayer) (string, string, bool) {
+	for i, candidate := range candidates {
+		best := -1
+		bestGap := math.Inf(1)
+		for j, opponent := range candidates {
+			if i == j {
+				continue
+			}
+			gap := math.Abs(candidate.rating - opponent.rating)
+			if gap <= math.Min(candidate.window, opponent.window) && gap < bestGap {
+				best, bestGap = j, gap
+			}
+		}
+		if best >= 0 {
+			return candidate.player.ID, candidates[best].player.ID, true
+		}
+	}
+	return "", "", false
+}
+
+// createMatch starts a game for two players claimed from the queue, which was
+// in the given order when they were. A player who has since left or joined
+// another game is dropped and the other is returned to their place in the
+// queue.
+func (s *MatchmakingService) createMatch(ctx context.Context, options models.QueueOptions, player1ID, player2ID string, order []string) {
+	span := trace.SpanFromContext(ctx)
+	queue := options.Key()
+	player1 := s.matchablePlayer(player1ID)
+	player2 := s.matchablePlayer(player2ID)
+	if player1 == nil || player2 == nil {
+		span.AddEvent("player no longer matchable")
+		if player1 != nil {
+			s.restoreToQueue(queue, player1ID, order)
+		}
+		if player2 != nil {
+			s.restoreToQueue(queue, player2ID, order)
+		}
+		return
+	}
+
+	// Create game session
 	game := &models.GameSession{
-		ID:        gameID,
-		Player1:   player1,
-		Player2:   player2,
-		Seed:      seed,
+		ID:        generateID(),
+		Players:   models.NewSeats(player1, player2),
+		Seed:      generateSeed(),
+		Queue:     options.Queue,
+		Mode:      options.Mode,
+		Ranked:    options.Ranked(),
 		Status:    models.GameStatusWaiting,
 		CreatedAt: time.Now(),
 	}
 
-	// Store game
-	err = s.gameStore.CreateGame(game)
+	span.SetAttributes(tracing.GameID(game.ID))
+	err := s.startMatch(ctx, game)
 	if err != nil {
-		// Re-add players to queue on error
-		_ = s.queueStore.AddToQueue(player1ID)
-		_ = s.queueStore.AddToQueue(player2ID)
+		// Return players to their places in the queue on error
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		s.restoreToQueue(queue, player1ID, order)
+		s.restoreToQueue(queue, player2ID, order)
 		return
 	}
 
-	// Update players
-	player1.InQueue = false
-	player1.GameID = gameID
-	player2.InQueue = false
-	player2.GameID = gameID
+	for _, player := range []*models.Player{player1, player2} {
+		if !player.QueuedAt.IsZero() {
+			metrics.MatchWait.WithLabelValues(options.Queue).Observe(game.CreatedAt.Sub(player.QueuedAt).Seconds())
+		}
+	}
+}
 
-	_ = s.playerStore.UpdatePlayer(player1)
-	_ = s.playerStore.UpdatePlayer(player2)
+// restoreToQueue puts a claimed player back at their place in queue order
+// instead of the back of the queue. Their join time is still on their player,
+// so their rating window keeps widening from when they first joined.
+func (s *MatchmakingService) restoreToQueue(queue, playerID string, order []string) {
+	ahead := order
+	if index := slices.Index(order, playerID); index >= 0 {
+		ahead = order[:index]
+	}
+	_ = s.queueStore.RestoreToQueue(queue, playerID, ahead)
+}
+
+// startMatch stores a new game, routes its players to it and starts it
+func (s *MatchmakingService) startMatch(ctx context.Context, game *models.GameSession) error {
+	err := s.gameStore.CreateGame(game)
+	if err != nil {
+		return err
+	}
+
+	// Update players
+	for _, seat := range game.Players {
+		player := seat.Player
+		player.InQueue = false
+		player.Queue = ""
+		_ = s.playerStore.ClearPlayerQueue(player.ID, "")
+		s.gameManager.setPlayerGameID(player, game.ID)
+	}
 
 	// Notify game manager
-	s.gameManager.StartGame(game)
+	s.gameManager.StartGame(ctx, game)
+	return nil
+}
+
+// matchablePlayer returns a queued player if they can still be matched
+func (s *MatchmakingService) matchablePlayer(playerID string) *models.Player {
+	player, err := s.playerStore.GetPlayer(playerID)
+	if err != nil {
+		return nil
+	}
+
+	gameID, err := s.gameManager.playerGameID(playerID)
+	if err != nil || gameID != "" {
+		return nil
+	}
+	return player
 }
 
 // generateSeed creates a random seed for the game
@@ -1,9 +1,19 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/briancain/go-tetris/internal/server/glicko"
 	"github.com/briancain/go-tetris/internal/server/storage/memory"
 	"github.com/briancain/go-tetris/pkg/models"
 )
@@ -13,9 +23,10 @@ func TestMatchmakingService_JoinQueue(t *testing.T) {
 	playerStore := memory.NewPlayerStore()
 	gameStore := memory.NewGameStore()
 	queueStore := memory.NewQueueStore()
+	roomStore := memory.NewRoomStore()
 	wsManager := NewWebSocketManager()
 	gameManager := NewGameManager(gameStore, playerStore, wsManager)
-	matchmaker := NewMatchmakingService(playerStore, gameStore, queueStore, gameManager)
+	matchmaker := NewMatchmakingService(playerStore, gameStore, queueStore, roomStore, gameManager)
 
 	// Create a player
 	player := &models.Player{
@@ -26,13 +37,13 @@ func TestMatchmakingService_JoinQueue(t *testing.T) {
 	playerStore.CreatePlayer(player)
 
 	// Test joining queue
-	err := matchmaker.JoinQueue("player1")
+	err := matchmaker.JoinQueue("player1", models.QueueOptions{})
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
 
 	// Verify player is in queue
-	position, err := matchmaker.GetQueueStatus("player1")
+	_, position, err := matchmaker.GetQueueStatus("player1")
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -47,9 +58,10 @@ func TestMatchmakingService_LeaveQueue(t *testing.T) {
 	playerStore := memory.NewPlayerStore()
 	gameStore := memory.NewGameStore()
 	queueStore := memory.NewQueueStore()
+	roomStore := memory.NewRoomStore()
 	wsManager := NewWebSocketManager()
 	gameManager := NewGameManager(gameStore, playerStore, wsManager)
-	matchmaker := NewMatchmakingService(playerStore, gameStore, queueStore, gameManager)
+	matchmaker := NewMatchmakingService(playerStore, gameStore, queueStore, roomStore, gameManager)
 
 	// Create a player
 	player := &models.Player{
@@ -60,14 +72,14 @@ func TestMatchmakingService_LeaveQueue(t *testing.T) {
 	playerStore.CreatePlayer(player)
 
 	// Join then leave queue
-	matchmaker.JoinQueue("player1")
+	matchmaker.JoinQueue("player1", models.QueueOptions{})
 	err := matchmaker.LeaveQueue("player1")
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
 
 	// Verify player is not in queue
-	position, err := matchmaker.GetQueueStatus("player1")
+	_, position, err := matchmaker.GetQueueStatus("player1")
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -82,9 +94,10 @@ func TestMatchmakingService_TwoPlayerMatch(t *testing.T) {
 	playerStore := memory.NewPlayerStore()
 	gameStore := memory.NewGameStore()
 	queueStore := memory.NewQueueStore()
+	roomStore := memory.NewRoomStore()
 	wsManager := NewWebSocketManager()
 	gameManager := NewGameManager(gameStore, playerStore, wsManager)
-	matchmaker := NewMatchmakingService(playerStore, gameStore, queueStore, gameManager)
+	matchmaker := NewMatchmakingService(playerStore, gameStore, queueStore, roomStore, gameManager)
 
 	// Create two players
 	player1 := &models.Player{
@@ -101,8 +114,8 @@ func TestMatchmakingService_TwoPlayerMatch(t *testing.T) {
 	playerStore.CreatePlayer(player2)
 
 	// Both join queue
-	matchmaker.JoinQueue("player1")
-	matchmaker.JoinQueue("player2")
+	matchmaker.JoinQueue("player1", models.QueueOptions{})
+	matchmaker.JoinQueue("player2", models.QueueOptions{})
 
 	// Wait for matchmaking to complete with longer timeout and more checks
 	var games []*models.GameSession
@@ -113,8 +126,8 @@ func TestMatchmakingService_TwoPlayerMatch(t *testing.T) {
 		time.Sleep(25 * time.Millisecond) // Shorter sleep, more frequent checks
 
 		// Check if players are matched (out of queue)
-		pos1, _ := matchmaker.GetQueueStatus("player1")
-		pos2, _ := matchmaker.GetQueueStatus("player2")
+		_, pos1, _ := matchmaker.GetQueueStatus("player1")
+		_, pos2, _ := matchmaker.GetQueueStatus("player2")
 
 		if pos1 == -1 && pos2 == -1 {
 			matched = true
@@ -138,12 +151,291 @@ func TestMatchmakingService_TwoPlayerMatch(t *testing.T) {
 	}
 
 	game := games[0]
-	if game.Player1.ID != "player1" || game.Player2.ID != "player2" {
+	if game.Players[0].Player.ID != "player1" || game.Players[1].Player.ID != "player2" {
 		t.Errorf("Expected players player1 and player2, got %s and %s",
-			game.Player1.ID, game.Player2.ID)
+			game.Players[0].Player.ID, game.Players[1].Player.ID)
 	}
 
 	if game.Seed == 0 {
 		t.Error("Expected game seed to be generated")
 	}
 }
+
+func TestMatchmakingService_ConcurrentJoins(t *testing.T) {
+	playerStore := memory.NewPlayerStore()
+	gameStore := memory.NewGameStore()
+	queueStore := memory.NewQueueStore()
+	roomStore := memory.NewRoomStore()
+	wsManager := NewWebSocketManager()
+	gameManager := NewGameManager(gameStore, playerStore, wsManager)
+
+	// Two matchmakers share the queue, as two server instances would
+	matchmakers := []*MatchmakingService{
+		NewMatchmakingService(playerStore, gameStore, queueStore, roomStore, gameManager),
+		NewMatchmakingService(playerStore, gameStore, queueStore, roomStore, gameManager),
+	}
+
+	const numPlayers = 200
+	for i := 0; i < numPlayers; i++ {
+		playerStore.CreatePlayer(&models.Player{
+			ID:       fmt.Sprintf("player%d", i),
+			Username: fmt.Sprintf("user%d", i),
+		})
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < numPlayers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := matchmakers[i%2].JoinQueue(fmt.Sprintf("player%d", i), models.QueueOptions{}); err != nil {
+				t.Errorf("JoinQueue failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var games []*models.GameSession
+	for i := 0; i < 200; i++ {
+		games, _ = gameStore.GetAllGames()
+		if len(games) == numPlayers/2 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if len(games) != numPlayers/2 {
+		t.Fatalf("Expected %d games, got %d", numPlayers/2, len(games))
+	}
+
+	// Every player is in exactly one game
+	seen := make(map[string]int)
+	for _, game := range games {
+		seen[game.Players[0].Player.ID]++
+		seen[game.Players[1].Player.ID]++
+	}
+	for i := 0; i < numPlayers; i++ {
+		if count := seen[fmt.Sprintf("player%d", i)]; count != 1 {
+			t.Errorf("player%d is in %d games", i, count)
+		}
+	}
+}
+
+func TestMatchmakingService_PrefersCloseRatings(t *testing.T) {
+	playerStore := memory.NewPlayerStore()
+	gameStore := memory.NewGameStore()
+	queueStore := memory.NewQueueStore()
+	roomStore := memory.NewRoomStore()
+	wsManager := NewWebSocketManager()
+	gameManager := NewGameManager(gameStore, playerStore, wsManager)
+	matchmaker := NewMatchmakingService(playerStore, gameStore, queueStore, roomStore, gameManager)
+
+	ratings := map[string]float64{"newcomer": 1500, "veteran": 2300, "peer": 1520}
+	for _, id := range []string{"newcomer", "veteran", "peer"} {
+		playerStore.CreatePlayer(&models.Player{
+			ID:       id,
+			Username: id,
+			Rating:   models.Rating{Value: ratings[id], Deviation: 80, Volatility: glicko.DefaultVolatility},
+		})
+		if err := matchmaker.JoinQueue(id, models.QueueOptions{}); err != nil {
+			t.Fatalf("JoinQueue failed: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	var games []*models.GameSession
+	for i := 0; i < 50 && len(games) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+		games, _ = gameStore.GetAllGames()
+	}
+
+	if len(games) != 1 {
+		t.Fatalf("Expected 1 game, got %d", len(games))
+	}
+	matched := map[string]bool{games[0].Players[0].Player.ID: true, games[0].Players[1].Player.ID: true}
+	if !matched["newcomer"] || !matched["peer"] {
+		t.Errorf("Expected newcomer to face peer, got %s vs %s", games[0].Players[0].Player.ID, games[0].Players[1].Player.ID)
+	}
+
+	// The veteran keeps waiting rather than crushing the newcomer
+	if _, pos, _ := matchmaker.GetQueueStatus("veteran"); pos != 0 {
+		t.Errorf("Expected veteran to remain queued, got position %d", pos)
+	}
+}
+
+func TestFindMatch_WindowWidensWithQueueTime(t *testing.T) {
+	now := time.Now()
+	newQueued := func(id string, rating float64, waited time.Duration) queuedPlayer {
+		return newQueuedPlayer(&models.Player{
+			ID:       id,
+			Rating:   models.Rating{Value: rating, Deviation: 80, Volatility: glicko.DefaultVolatility},
+			QueuedAt: now.Add(-waited),
+		}, now)
+	}
+
+	// A 400 point gap is too wide for players who just joined
+	candidates := []queuedPlayer{
+		newQueued("strong", 1900, 0),
+		newQueued("weak", 1500, 0),
+	}
+	if _, _, ok := findMatch(candidates); ok {
+		t.Error("Expected no match for a wide gap right after queueing")
+	}
+
+	// Once both have waited long enough, the gap is accepted
+	candidates = []queuedPlayer{
+		newQueued("strong", 1900, 20*time.Second),
+		newQueued("weak", 1500, 20*time.Second),
+	}
+	player1, player2, ok := findMatch(candidates)
+	if !ok || player1 != "strong" || player2 != "weak" {
+		t.Errorf("Expected strong vs weak after waiting, got %q vs %q (ok=%v)", player1, player2, ok)
+	}
+
+	// The longest-waiting player is paired with the closest rating
+	candidates = []queuedPlayer{
+		newQueued("oldest", 1600, 30*time.Second),
+		newQueued("far", 1950, 30*time.Second),
+		newQueued("close", 1640, 30*time.Second),
+	}
+	player1, player2, _ = findMatch(candidates)
+	if player1 != "oldest" || player2 != "close" {
+		t.Errorf("Expected oldest vs close, got %q vs %q", player1, player2)
+	}
+}
+
+func TestMatchmakingService_SeparateQueues(t *testing.T) {
+	playerStore := memory.NewPlayerStore()
+	gameStore := memory.NewGameStore()
+	queueStore := memory.NewQueueStore()
+	roomStore := memory.NewRoomStore()
+	wsManager := NewWebSocketManager()
+	gameManager := NewGameManager(gameStore, playerStore, wsManager)
+	matchmaker := NewMatchmakingService(playerStore, gameStore, queueStore, roomStore, gameManager)
+	defer matchmaker.Stop()
+
+	joins := map[string]models.QueueOptions{
+		"ranked1": {},
+		"casual1": {Queue: models.QueueCasual},
+		"casual2": {Queue: models.QueueCasual},
+	}
+	for _, id := range []string{"ranked1", "casual1", "casual2"} {
+		playerStore.CreatePlayer(&models.Player{ID: id, Username: id})
+		if err := matchmaker.JoinQueue(id, joins[id]); err != nil {
+			t.Fatalf("JoinQueue failed: %v", err)
+		}
+	}
+
+	var games []*models.GameSession
+	for i := 0; i < 50 && len(games) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+		games, _ = gameStore.GetAllGames()
+	}
+
+	// Only the two casual players are matched, in an unranked game
+	if len(games) != 1 {
+		t.Fatalf("Expected 1 game, got %d", len(games))
+	}
+	if games[0].Ranked || games[0].Queue != models.QueueCasual || games[0].Mode != models.DefaultGameMode {
+		t.Errorf("Expected casual classic game, got queue=%q mode=%q ranked=%v", games[0].Queue, games[0].Mode, games[0].Ranked)
+	}
+
+	queue, pos, _ := matchmaker.GetQueueStatus("ranked1")
+	if queue != "ranked:classic" || pos != 0 {
+		t.Errorf("Expected ranked1 waiting in ranked:classic, got %q position %d", queue, pos)
+	}
+}
+
+func TestMatchmakingService_RejectsUnknownQueue(t *testing.T) {
+	playerStore := memory.NewPlayerStore()
+	gameStore := memory.NewGameStore()
+	queueStore := memory.NewQueueStore()
+	roomStore := memory.NewRoomStore()
+	wsManager := NewWebSocketManager()
+	gameManager := NewGameManager(gameStore, playerStore, wsManager)
+	matchmaker := NewMatchmakingService(playerStore, gameStore, queueStore, roomStore, gameManager)
+
+	playerStore.CreatePlayer(&models.Player{ID: "player1", Username: "user1"})
+
+	err := matchmaker.JoinQueue("player1", models.QueueOptions{Queue: "tournament"})
+	if !errors.Is(err, ErrUnknownQueue) {
+		t.Errorf("Expected ErrUnknownQueue, got %v", err)
+	}
+
+	err = matchmaker.JoinQueue("player1", models.QueueOptions{Mode: "Bad Mode!"})
+	if !errors.Is(err, ErrInvalidGameMode) {
+		t.Errorf("Expected ErrInvalidGameMode, got %v", err)
+	}
+}
+
+func TestMatchmakingService_FailedMatchKeepsQueuePlace(t *testing.T) {
+	playerStore := memory.NewPlayerStore()
+	gameStore := memory.NewGameStore()
+	queueStore := memory.NewQueueStore()
+	gameManager := NewGameManager(gameStore, playerStore, NewWebSocketManager())
+	matchmaker := NewMatchmakingService(playerStore, gameStore, queueStore, memory.NewRoomStore(), gameManager)
+
+	options := models.QueueOptions{Queue: models.QueueRanked, Mode: models.DefaultGameMode}
+	order := []string{"player1", "player2", "player3"}
+	for _, id := range order {
+		playerStore.CreatePlayer(&models.Player{ID: id, Username: "user_" + id, QueuedAt: time.Now()})
+		queueStore.AddToQueue(options.Key(), id)
+	}
+
+	// player2 leaves after being claimed, so the match falls through
+	if claimed, _ := queueStore.RemovePair(options.Key(), "player1", "player2"); !claimed {
+		t.Fatal("Expected player1 and player2 to be claimed")
+	}
+	playerStore.DeletePlayer("player2")
+	matchmaker.createMatch(context.Background(), options, "player1", "player2", order)
+
+	// player1 goes back ahead of player3 rather than to the back
+	queued, _ := queueStore.GetQueuedPlayers(options.Key())
+	if !slices.Equal(queued, []string{"player1", "player3"}) {
+		t.Errorf("Expected player1 back at the front of the queue, got %v", queued)
+	}
+}
+
+func TestMatchmakingService_TracesMatch(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previous)
+
+	playerStore := memory.NewPlayerStore()
+	gameStore := memory.NewGameStore()
+	queueStore := memory.NewQueueStore()
+	gameManager := NewGameManager(gameStore, playerStore, NewWebSocketManager())
+	matchmaker := NewMatchmakingService(playerStore, gameStore, queueStore, memory.NewRoomStore(), gameManager)
+
+	// Queue players directly so no background loop races the pass below
+	options := models.QueueOptions{Queue: models.QueueRanked, Mode: models.DefaultGameMode}
+	for _, id := range []string{"player1", "player2"} {
+		playerStore.CreatePlayer(&models.Player{ID: id, Username: "user_" + id, QueuedAt: time.Now()})
+		queueStore.AddToQueue(options.Key(), id)
+	}
+	matchmaker.tryMatchmaking(options)
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	match, start := spans["matchmaking.match"], spans["game.start"]
+	if match == nil || start == nil {
+		t.Fatalf("Expected match and game start spans, got %v", spans)
+	}
+
+	// Starting the game is part of the match's trace and tagged with its game
+	if start.Parent().SpanID() != match.SpanContext().SpanID() {
+		t.Error("Expected game start span to be a child of the match span")
+	}
+	var gameID string
+	for _, attr := range start.Attributes() {
+		if attr.Key == "game.id" {
+			gameID = attr.Value.AsString()
+		}
+	}
+	if games, _ := gameStore.GetActiveGames(); len(games) != 1 || games[0].ID != gameID {
+		t.Errorf("Expected game start span tagged with the new game, got %q", gameID)
+	}
+}
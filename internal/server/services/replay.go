@@ -0,0 +1,86 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/briancain/go-tetris/internal/server/logger"
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+// SetReplayStore records every game's moves, states and result in store so
+// they can be played back. Games aren't recorded without one.
+func (gm *GameManager) SetReplayStore(store storage.ReplayStore) {
+	gm.replayStore = store
+}
+
+// beginReplay starts recording a game that is about to start
+func (gm *GameManager) beginReplay(game *models.GameSession) {
+	if gm.replayStore == nil {
+		return
+	}
+
+	if err := gm.replayStore.CreateReplay(models.NewReplay(game)); err != nil {
+		logger.Logger.Error("Failed to start game replay",
+			"gameID", game.ID,
+			"error", err,
+		)
+	}
+}
+
+// recordReplay appends an event to a game's replay. The caller must hold the
+// game's lock so events are stored in the order they were relayed.
+func (gm *GameManager) recordReplay(gameID string, event *models.ReplayEvent) {
+	if gm.replayStore == nil {
+		return
+	}
+
+	event.At = time.Now()
+	err := gm.replayStore.AppendReplayEvent(gameID, event)
+	switch {
+	case err == nil:
+	case errors.Is(err, storage.ErrReplayFull):
+		// Already marked truncated; the result is still recorded
+	default:
+		logger.Logger.Warn("Failed to record replay event",
+			"gameID", gameID,
+			"eventType", event.Type,
+			"error", err,
+		)
+	}
+}
+
+// deleteExpiredReplays deletes replays past their retention, returning how
+// many were deleted
+func (gm *GameManager) deleteExpiredReplays() (int, error) {
+	if gm.replayStore == nil {
+		return 0, nil
+	}
+	return gm.replayStore.DeleteExpiredReplays()
+}
+
+// finishReplay records a finished game's result in its replay
+func (gm *GameManager) finishReplay(game *models.GameSession, winnerID string) {
+	if gm.replayStore == nil {
+		return
+	}
+
+	players := make([]*models.ReplayPlayer, len(game.Players))
+	for i, seat := range game.Players {
+		players[i] = &models.ReplayPlayer{
+			ID:        seat.Player.ID,
+			Username:  seat.Player.Username,
+			Score:     seat.Score,
+			Placement: seat.Placement,
+			KOs:       seat.KOs,
+		}
+	}
+
+	if err := gm.replayStore.FinishReplay(game.ID, winnerID, players); err != nil {
+		logger.Logger.Error("Failed to finish game replay",
+			"gameID", game.ID,
+			"error", err,
+		)
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/briancain/go-tetris/internal/server/storage/memory"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func TestReplay_RecordsMatch(t *testing.T) {
+	gameStore := memory.NewGameStore()
+	playerStore := memory.NewPlayerStore()
+	replayStore := memory.NewReplayStore()
+	gm := NewGameManager(gameStore, playerStore, NewWebSocketManager())
+	gm.SetReplayStore(replayStore)
+
+	player1 := &models.Player{ID: "replay_player1", Username: "Replay1"}
+	player2 := &models.Player{ID: "replay_player2", Username: "Replay2"}
+	playerStore.CreatePlayer(player1)
+	playerStore.CreatePlayer(player2)
+
+	game := &models.GameSession{ID: "replay_game1", Players: models.NewSeats(player1, player2), Seed: 77}
+	gameStore.CreateGame(game)
+	gm.setPlayerGameID(player1, game.ID)
+	gm.setPlayerGameID(player2, game.ID)
+	gm.StartGame(context.Background(), game)
+
+	gm.HandleGameMove("replay_player1", &models.GameMove{MoveType: "left"})
+	gm.HandleGameState("replay_player2", &models.GameState{Score: 200, Lines: 1})
+	gm.EndGame(game.ID, "replay_player2")
+	gm.HandleGameState("replay_player1", &models.GameState{Score: 500, Lines: 4})
+
+	replay, err := replayStore.GetReplay(game.ID)
+	if err != nil {
+		t.Fatalf("GetReplay failed: %v", err)
+	}
+	if replay.Seed != 77 || len(replay.Players) != 2 || replay.Players[0].Username != "Replay1" {
+		t.Errorf("Expected the game's seed and seats, got %+v", replay)
+	}
+
+	wantTypes := []string{models.ReplayEventMove, models.ReplayEventState, models.ReplayEventLost, models.ReplayEventState}
+	if len(replay.Events) != len(wantTypes) {
+		t.Fatalf("Expected %d events, got %d", len(wantTypes), len(replay.Events))
+	}
+	for i, want := range wantTypes {
+		if replay.Events[i].Type != want {
+			t.Errorf("Expected event %d to be %s, got %s", i, want, replay.Events[i].Type)
+		}
+	}
+	if replay.Events[0].Move.MoveType != "left" || replay.Events[2].Placement != 2 {
+		t.Errorf("Expected the move and elimination details, got %+v and %+v", replay.Events[0], replay.Events[2])
+	}
+
+	// Player 1 beat the loser's score, finishing the game
+	if !replay.Finished || replay.WinnerID != "replay_player1" {
+		t.Fatalf("Expected a finished replay won by player 1, got finished %v winner %q", replay.Finished, replay.WinnerID)
+	}
+	if replay.Players[0].Score != 500 || replay.Players[0].Placement != 1 || replay.Players[1].Placement != 2 {
+		t.Errorf("Expected final scores and placements, got %+v and %+v", replay.Players[0], replay.Players[1])
+	}
+}
+
+func TestMatchHistory_RecordsRegisteredPlayers(t *testing.T) {
+	gameStore := memory.NewGameStore()
+	playerStore := memory.NewPlayerStore()
+	historyStore := memory.NewMatchHistoryStore()
+	gm := NewGameManager(gameStore, playerStore, NewWebSocketManager())
+	gm.SetMatchHistoryStore(historyStore)
+
+	member := &models.Player{ID: "history_member", Username: "Member", Registered: true}
+	guest := &models.Player{ID: "history_guest", Username: "Guest"}
+	playerStore.CreatePlayer(member)
+	playerStore.CreatePlayer(guest)
+
+	start := func(id string) string {
+		game := &models.GameSession{ID: id, Players: models.NewSeats(member, guest)}
+		gameStore.CreateGame(game)
+		gm.setPlayerGameID(member, game.ID)
+		gm.setPlayerGameID(guest, game.ID)
+		gm.StartGame(context.Background(), game)
+		return game.ID
+	}
+	if err := gm.ForceEndGame(start("history_game1"), member.ID); err != nil {
+		t.Fatalf("ForceEndGame failed: %v", err)
+	}
+	if err := gm.ForceEndGame(start("history_game2"), ""); err != nil {
+		t.Fatalf("ForceEndGame failed: %v", err)
+	}
+
+	// A cancelled game has no result to keep
+	if err := gm.CancelGame(start("history_game3")); err != nil {
+		t.Fatalf("CancelGame failed: %v", err)
+	}
+
+	matches, _ := historyStore.GetPlayerMatches(member.ID, "", 0, 10)
+	if len(matches) != 2 || matches[0].GameID != "history_game2" || matches[1].Outcome(member.ID) != models.MatchWin {
+		t.Fatalf("Expected both games in the member's history, newest first, got %+v", matches)
+	}
+	if player := matches[1].Player(guest.ID); player == nil || player.Placement != 2 {
+		t.Errorf("Expected the guest's result in the record, got %+v", player)
+	}
+	if guestMatches, _ := historyStore.GetPlayerMatches(guest.ID, "", 0, 10); len(guestMatches) != 0 {
+		t.Errorf("Expected guests not to keep a history, got %+v", guestMatches)
+	}
+}
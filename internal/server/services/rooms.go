@@ -0,0 +1,296 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/briancain/go-tetris/internal/server/logger"
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+const (
+	roomCodeLength   = 6
+	roomCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // No 0/O or 1/I lookalikes
+	roomCodeAttempts = 5
+	maxBestOf        = 9
+	maxRoomPlayers   = 8
+)
+
+var (
+	ErrInvalidRoomSettings = errors.New("invalid room settings")
+	ErrNotRoomHost         = errors.New("only the host can manage a room")
+	ErrOwnRoom             = errors.New("cannot join your own room")
+	ErrAlreadyInGame       = errors.New("player is already in a game")
+	ErrRoomNotReady        = errors.New("room needs at least two players")
+)
+
+// NormalizeRoomSettings fills in defaults and validates a host's settings
+func NormalizeRoomSettings(settings models.RoomSettings) (models.RoomSettings, error) {
+	if settings.Mode == "" {
+		settings.Mode = models.DefaultGameMode
+	}
+	if settings.BestOf == 0 {
+		settings.BestOf = 1
+	}
+	if settings.MaxPlayers == 0 {
+		settings.MaxPlayers = 2
+	}
+	if settings.Targeting == "" {
+		settings.Targeting = models.TargetRandom
+	}
+
+	if !gameModePattern.MatchString(settings.Mode) {
+		return settings, ErrInvalidGameMode
+	}
+	if !settings.Targeting.Valid() {
+		return settings, ErrInvalidTargeting
+	}
+	// An odd number of games guarantees a series winner
+	if settings.BestOf < 1 || settings.BestOf > maxBestOf || settings.BestOf%2 == 0 {
+		return settings, ErrInvalidRoomSettings
+	}
+	// Series are only played as duels
+	if settings.MaxPlayers < 2 || settings.MaxPlayers > maxRoomPlayers ||
+		(settings.MaxPlayers > 2 && settings.BestOf > 1) {
+		return settings, ErrInvalidRoomSettings
+	}
+	return settings, nil
+}
+
+// CreateRoom opens a private room hosted by a player. The host leaves any
+// matchmaking queue so they aren't pulled into a random match while waiting.
+func (s *MatchmakingService) CreateRoom(hostID string, settings models.RoomSettings) (*models.Room, error) {
+	if s.gameManager.Draining() {
+		return nil, ErrServerDraining
+	}
+
+	settings, err := NormalizeRoomSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	host, err := s.playerStore.GetPlayer(hostID)
+	if err != nil {
+		return nil, err
+	}
+	if gameID, _ := s.gameManager.playerGameID(hostID); gameID != "" {
+		return nil, ErrAlreadyInGame
+	}
+	if host.Queue != "" {
+		if err := s.LeaveQueue(hostID); err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; attempt < roomCodeAttempts; attempt++ {
+		room := &models.Room{
+			Code:      generateRoomCode(),
+			HostID:    hostID,
+			Settings:  settings,
+			CreatedAt: time.Now(),
+		}
+
+		err = s.roomStore.CreateRoom(room)
+		if errors.Is(err, storage.ErrRoomCodeTaken) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		logger.Logger.Info("Room created",
+			"roomCode", room.Code,
+			"hostID", hostID,
+			"mode", settings.Mode,
+			"bestOf", settings.BestOf,
+			"maxPlayers", settings.MaxPlayers,
+		)
+		return room, nil
+	}
+
+	return nil, storage.ErrRoomCodeTaken
+}
+
+// JoinRoom seats a player in a room. The match starts with the host's
+// settings once the room is full, and the started game is returned; until
+// then the room is returned and everyone in it is told who has joined. Rooms
+// are single use; rematches continue from the game itself.
+func (s *MatchmakingService) JoinRoom(ctx context.Context, playerID, code string) (*models.Room, *models.GameSession, error) {
+	if s.gameManager.Draining() {
+		return nil, nil, ErrServerDraining
+	}
+
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	guest, err := s.playerStore.GetPlayer(playerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if gameID, _ := s.gameManager.playerGameID(playerID); gameID != "" {
+		return nil, nil, ErrAlreadyInGame
+	}
+
+	room, err := s.roomStore.GetRoom(code)
+	if err != nil {
+		return nil, nil, err
+	}
+	if room.HostID == playerID {
+		return nil, nil, ErrOwnRoom
+	}
+
+	room, err = s.roomStore.JoinRoom(code, playerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if guest.Queue != "" {
+		if err := s.LeaveQueue(playerID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if !room.Full() {
+		s.notifyRoom(room)
+		logger.Logger.Info("Player waiting in room",
+			"roomCode", room.Code,
+			"playerID", playerID,
+			"players", len(room.PlayerIDs()),
+			"maxPlayers", room.Settings.MaxPlayers,
+		)
+		return room, nil, nil
+	}
+
+	game, err := s.startRoom(ctx, code)
+	if err != nil {
+		return nil, nil, err
+	}
+	return room, game, nil
+}
+
+// StartRoom starts a room's match before it fills. Only its host may start it.
+func (s *MatchmakingService) StartRoom(ctx context.Context, playerID, code string) (*models.GameSession, error) {
+	if s.gameManager.Draining() {
+		return nil, ErrServerDraining
+	}
+
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	room, err := s.roomStore.GetRoom(code)
+	if err != nil {
+		return nil, err
+	}
+	if room.HostID != playerID {
+		return nil, ErrNotRoomHost
+	}
+	if len(room.GuestIDs) == 0 {
+		return nil, ErrRoomNotReady
+	}
+
+	return s.startRoom(ctx, code)
+}
+
+// startRoom claims a room and starts its match with everyone still available
+func (s *MatchmakingService) startRoom(ctx context.Context, code string) (*models.GameSession, error) {
+	room, err := s.roomStore.TakeRoom(code)
+	if err != nil {
+		return nil, err
+	}
+
+	// The host may have disconnected or started another game meanwhile
+	host := s.matchablePlayer(room.HostID)
+	if host == nil {
+		return nil, storage.ErrRoomNotFound
+	}
+
+	// Guests who have since left lose their seat
+	players := []*models.Player{host}
+	for _, guestID := range room.GuestIDs {
+		if guest := s.matchablePlayer(guestID); guest != nil {
+			players = append(players, guest)
+		}
+	}
+	if len(players) < 2 {
+		return nil, ErrRoomNotReady
+	}
+
+	seed := room.Settings.Seed
+	if seed == 0 {
+		seed = generateSeed()
+	}
+
+	game := &models.GameSession{
+		ID:        generateID(),
+		Players:   models.NewSeats(players...),
+		Seed:      seed,
+		Mode:      room.Settings.Mode,
+		RoomCode:  room.Code,
+		Garbage:   room.Settings.Garbage,
+		Targeting: room.Settings.Targeting,
+		BestOf:    room.Settings.BestOf,
+		Status:    models.GameStatusWaiting,
+		CreatedAt: time.Now(),
+	}
+
+	err = s.startMatch(ctx, game)
+	if err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+// notifyRoom tells everyone in a room who is waiting in it
+func (s *MatchmakingService) notifyRoom(room *models.Room) {
+	var players []map[string]interface{}
+	for _, playerID := range room.PlayerIDs() {
+		player, err := s.playerStore.GetPlayer(playerID)
+		if err != nil {
+			continue
+		}
+		players = append(players, map[string]interface{}{
+			"id":       player.ID,
+			"username": player.Username,
+		})
+	}
+
+	roomMsg := map[string]interface{}{
+		"type":       "room_update",
+		"roomCode":   room.Code,
+		"hostId":     room.HostID,
+		"players":    players,
+		"maxPlayers": room.Settings.MaxPlayers,
+	}
+	for _, playerID := range room.PlayerIDs() {
+		s.gameManager.sendToPlayer(playerID, roomMsg)
+	}
+}
+
+// CloseRoom deletes a room that hasn't started yet. Only its host may close it.
+func (s *MatchmakingService) CloseRoom(playerID, code string) error {
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	room, err := s.roomStore.GetRoom(code)
+	if err != nil {
+		return err
+	}
+	if room.HostID != playerID {
+		return ErrNotRoomHost
+	}
+
+	return s.roomStore.DeleteRoom(code)
+}
+
+// generateRoomCode creates a short, easy to read invite code
+func generateRoomCode() string {
+	bytes := make([]byte, roomCodeLength)
+	_, _ = rand.Read(bytes)
+
+	code := make([]byte, roomCodeLength)
+	for i, b := range bytes {
+		code[i] = roomCodeAlphabet[int(b)%len(roomCodeAlphabet)]
+	}
+	return string(code)
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/internal/server/storage/memory"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func setupRooms(t *testing.T) (*MatchmakingService, *memory.PlayerStore, *memory.QueueStore) {
+	t.Helper()
+
+	playerStore := memory.NewPlayerStore()
+	gameStore := memory.NewGameStore()
+	queueStore := memory.NewQueueStore()
+	roomStore := memory.NewRoomStore()
+	wsManager := NewWebSocketManager()
+	gameManager := NewGameManager(gameStore, playerStore, wsManager)
+	matchmaker := NewMatchmakingService(playerStore, gameStore, queueStore, roomStore, gameManager)
+
+	for _, id := range []string{"host", "guest", "latecomer", "guest2", "guest3"} {
+		playerStore.CreatePlayer(&models.Player{ID: id, Username: id})
+	}
+	return matchmaker, playerStore, queueStore
+}
+
+func TestRooms_JoinStartsMatchWithHostSettings(t *testing.T) {
+	matchmaker, playerStore, queueStore := setupRooms(t)
+
+	// The host was queued; opening a room takes them out of the queue
+	matchmaker.JoinQueue("host", models.QueueOptions{})
+
+	room, err := matchmaker.CreateRoom("host", models.RoomSettings{Mode: "sprint", Seed: 42, Garbage: true, BestOf: 3})
+	if err != nil {
+		t.Fatalf("CreateRoom failed: %v", err)
+	}
+	if len(room.Code) != roomCodeLength {
+		t.Errorf("Expected a %d character code, got %q", roomCodeLength, room.Code)
+	}
+	if players, _ := queueStore.GetQueuedPlayers("ranked:classic"); len(players) != 0 {
+		t.Errorf("Expected host to leave the queue, got %v", players)
+	}
+
+	_, game, err := matchmaker.JoinRoom(context.Background(), "guest", room.Code)
+	if err != nil {
+		t.Fatalf("JoinRoom failed: %v", err)
+	}
+	if game == nil {
+		t.Fatal("Expected a full room to start the match")
+	}
+	if ids := game.PlayerIDs(); len(ids) != 2 || ids[0] != "host" || ids[1] != "guest" {
+		t.Errorf("Expected host vs guest, got %v", ids)
+	}
+	if game.Seed != 42 || game.Mode != "sprint" || !game.Garbage || game.BestOf != 3 || game.Ranked {
+		t.Errorf("Expected host settings on an unranked game, got %+v", game)
+	}
+	if game.Status != models.GameStatusActive {
+		t.Errorf("Expected game to start, got status %s", game.Status)
+	}
+
+	host, _ := playerStore.GetPlayer("host")
+	if host.GameID != game.ID {
+		t.Errorf("Expected host routed to %s, got %q", game.ID, host.GameID)
+	}
+
+	// Rooms are single use
+	if _, _, err := matchmaker.JoinRoom(context.Background(), "latecomer", room.Code); !errors.Is(err, storage.ErrRoomNotFound) {
+		t.Errorf("Expected ErrRoomNotFound for a used room, got %v", err)
+	}
+}
+
+func TestRooms_BattleRoyaleStartedByHost(t *testing.T) {
+	matchmaker, _, _ := setupRooms(t)
+
+	room, err := matchmaker.CreateRoom("host", models.RoomSettings{MaxPlayers: 5, Targeting: models.TargetKOs})
+	if err != nil {
+		t.Fatalf("CreateRoom failed: %v", err)
+	}
+
+	if _, err := matchmaker.StartRoom(context.Background(), "host", room.Code); !errors.Is(err, ErrRoomNotReady) {
+		t.Errorf("Expected ErrRoomNotReady for an empty room, got %v", err)
+	}
+
+	for _, guestID := range []string{"guest", "guest2", "guest3"} {
+		joined, game, err := matchmaker.JoinRoom(context.Background(), guestID, room.Code)
+		if err != nil {
+			t.Fatalf("JoinRoom failed: %v", err)
+		}
+		if game != nil {
+			t.Fatalf("Expected the room to wait for the host, got game %s", game.ID)
+		}
+		if joined.PlayerIDs()[len(joined.PlayerIDs())-1] != guestID {
+			t.Errorf("Expected %s to be seated, got %v", guestID, joined.PlayerIDs())
+		}
+	}
+
+	if _, err := matchmaker.StartRoom(context.Background(), "guest", room.Code); !errors.Is(err, ErrNotRoomHost) {
+		t.Errorf("Expected ErrNotRoomHost, got %v", err)
+	}
+
+	game, err := matchmaker.StartRoom(context.Background(), "host", room.Code)
+	if err != nil {
+		t.Fatalf("StartRoom failed: %v", err)
+	}
+	if len(game.Players) != 4 || game.IsDuel() || game.Targeting != models.TargetKOs {
+		t.Errorf("Expected a four player royale targeting KOs, got %+v", game)
+	}
+	for _, playerID := range game.PlayerIDs() {
+		if gameID, _ := matchmaker.gameManager.playerGameID(playerID); gameID != game.ID {
+			t.Errorf("Expected %s routed to %s, got %q", playerID, game.ID, gameID)
+		}
+	}
+}
+
+func TestRooms_Validation(t *testing.T) {
+	matchmaker, _, _ := setupRooms(t)
+
+	if _, err := matchmaker.CreateRoom("host", models.RoomSettings{BestOf: 4}); !errors.Is(err, ErrInvalidRoomSettings) {
+		t.Errorf("Expected ErrInvalidRoomSettings for an even series, got %v", err)
+	}
+	if _, err := matchmaker.CreateRoom("host", models.RoomSettings{MaxPlayers: 9}); !errors.Is(err, ErrInvalidRoomSettings) {
+		t.Errorf("Expected ErrInvalidRoomSettings for an oversized room, got %v", err)
+	}
+	if _, err := matchmaker.CreateRoom("host", models.RoomSettings{MaxPlayers: 4, BestOf: 3}); !errors.Is(err, ErrInvalidRoomSettings) {
+		t.Errorf("Expected ErrInvalidRoomSettings for a battle royale series, got %v", err)
+	}
+	if _, err := matchmaker.CreateRoom("host", models.RoomSettings{Targeting: "nearest"}); !errors.Is(err, ErrInvalidTargeting) {
+		t.Errorf("Expected ErrInvalidTargeting, got %v", err)
+	}
+
+	room, err := matchmaker.CreateRoom("host", models.RoomSettings{})
+	if err != nil {
+		t.Fatalf("CreateRoom failed: %v", err)
+	}
+	if room.Settings.Mode != models.DefaultGameMode || room.Settings.BestOf != 1 ||
+		room.Settings.MaxPlayers != 2 || room.Settings.Targeting != models.TargetRandom {
+		t.Errorf("Expected default settings, got %+v", room.Settings)
+	}
+
+	if _, _, err := matchmaker.JoinRoom(context.Background(), "host", room.Code); !errors.Is(err, ErrOwnRoom) {
+		t.Errorf("Expected ErrOwnRoom, got %v", err)
+	}
+	if err := matchmaker.CloseRoom("guest", room.Code); !errors.Is(err, ErrNotRoomHost) {
+		t.Errorf("Expected ErrNotRoomHost, got %v", err)
+	}
+
+	// Codes are case-insensitive for the people typing them in
+	if err := matchmaker.CloseRoom("host", " "+strings.ToLower(room.Code)+" "); err != nil {
+		t.Errorf("CloseRoom failed: %v", err)
+	}
+	if _, _, err := matchmaker.JoinRoom(context.Background(), "guest", room.Code); !errors.Is(err, storage.ErrRoomNotFound) {
+		t.Errorf("Expected ErrRoomNotFound after close, got %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/briancain/go-tetris/internal/server/logger"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+// maxSpectators caps how many players may watch a single game
+const maxSpectators = 32
+
+var (
+	ErrGameNotLive       = errors.New("game is not live")
+	ErrTooManySpectators = errors.New("game has too many spectators")
+)
+
+// Spectate subscribes a player to a live game. They are sent a snapshot of
+// every seat, then receive the same move, state and result messages as the
+// players until they stop spectating. Players can't watch while playing.
+func (gm *GameManager) Spectate(playerID, gameID string) error {
+	currentGameID, err := gm.playerGameID(playerID)
+	if err != nil {
+		return err
+	}
+	if currentGameID != "" {
+		return ErrAlreadyInGame
+	}
+
+	// Spectators watch one game at a time
+	gm.StopSpectating(playerID)
+
+	unlock := gm.lockGame(gameID)
+	defer unlock()
+
+	game, err := gm.gameStore.GetGame(gameID)
+	if err != nil {
+		return err
+	}
+	if game.Status == models.GameStatusFinished {
+		return ErrGameNotLive
+	}
+	if game.Seat(playerID) != nil {
+		return ErrAlreadyInGame
+	}
+
+	if !slices.Contains(game.Spectators, playerID) {
+		if len(game.Spectators) >= maxSpectators {
+			return ErrTooManySpectators
+		}
+		game.Spectators = append(game.Spectators, playerID)
+		if err := gm.gameStore.UpdateGame(game); err != nil {
+			return err
+		}
+	}
+
+	gm.spectateMu.Lock()
+	gm.spectating[playerID] = game.ID
+	gm.spectateMu.Unlock()
+
+	gm.sendToPlayer(playerID, map[string]interface{}{
+		"type":     "spectate_start",
+		"gameId":   game.ID,
+		"seed":     game.Seed,
+		"status":   game.Status,
+		"mode":     game.Mode,
+		"ranked":   game.Ranked,
+		"bestOf":   game.BestOf,
+		"seriesId": game.SeriesID,
+		"players":  gm.spectatorRoster(game),
+	})
+
+	logger.Logger.Info("Spectator joined game",
+		"playerID", playerID,
+		"gameID", game.ID,
+		"spectators", len(game.Spectators),
+	)
+	return nil
+}
+
+// StopSpectating unsubscribes a player from the game they are watching, if any
+func (gm *GameManager) StopSpectating(playerID string) {
+	gm.spectateMu.Lock()
+	gameID, ok := gm.spectating[playerID]
+	delete(gm.spectating, playerID)
+	gm.spectateMu.Unlock()
+	if !ok {
+		return
+	}
+
+	unlock := gm.lockGame(gameID)
+	defer unlock()
+
+	game, err := gm.gameStore.GetGame(gameID)
+	if err != nil {
+		return // Game already cleaned up
+	}
+
+	index := slices.Index(game.Spectators, playerID)
+	if index < 0 {
+		return
+	}
+	game.Spectators = slices.Delete(game.Spectators, index, index+1)
+	if err := gm.gameStore.UpdateGame(game); err != nil {
+		logger.Logger.Error("Failed to remove spectator",
+			"playerID", playerID,
+			"gameID", gameID,
+			"error", err,
+		)
+		return
+	}
+
+	logger.Logger.Info("Spectator left game",
+		"playerID", playerID,
+		"gameID", gameID,
+	)
+}
+
+// carrySpectators moves a finished game's spectators on to the next game
+// between the same players, so a rematch or the rest of a series stays
+// watchable. The new game must not be stored yet.
+func (gm *GameManager) carrySpectators(oldGame, newGame *models.GameSession) {
+	if len(oldGame.Spectators) == 0 {
+		return
+	}
+	newGame.Spectators = slices.Clone(oldGame.Spectators)
+
+	gm.spectateMu.Lock()
+	defer gm.spectateMu.Unlock()
+	for _, spectatorID := range newGame.Spectators {
+		if gm.spectating[spectatorID] == oldGame.ID {
+			gm.spectating[spectatorID] = newGame.ID
+		}
+	}
+}
+
+// spectatorRoster lists a game's seats with their latest known state, so a
+// new spectator can draw every board straight away
+func (gm *GameManager) spectatorRoster(game *models.GameSession) []map[string]interface{} {
+	gm.statesMu.Lock()
+	defer gm.statesMu.Unlock()
+
+	roster := make([]map[string]interface{}, len(game.Players))
+	for i, seat := range game.Players {
+		entry := map[string]interface{}{
+			"id":        seat.Player.ID,
+			"username":  seat.Player.Username,
+			"score":     seat.Score,
+			"lines":     seat.Lines,
+			"lost":      seat.Lost,
+			"placement": seat.Placement,
+		}
+		if state, ok := gm.lastStates[seat.Player.ID]; ok {
+			entry["board"] = state.Board
+			entry["level"] = state.Level
+		}
+		roster[i] = entry
+	}
+	return roster
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/briancain/go-tetris/internal/server/storage/memory"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+// setupSpectatedGame stores an active duel between two players
+func setupSpectatedGame(t *testing.T, bestOf int) (*GameManager, *memory.GameStore, *models.GameSession) {
+	t.Helper()
+	gameStore := memory.NewGameStore()
+	playerStore := memory.NewPlayerStore()
+	gm := NewGameManager(gameStore, playerStore, NewWebSocketManager())
+	gm.SetSeriesCountdown(10 * time.Millisecond)
+
+	player1 := &models.Player{ID: "spec_player1", Username: "Finalist1"}
+	player2 := &models.Player{ID: "spec_player2", Username: "Finalist2"}
+	watcher := &models.Player{ID: "spec_watcher", Username: "OfficeTV"}
+	playerStore.CreatePlayer(player1)
+	playerStore.CreatePlayer(player2)
+	playerStore.CreatePlayer(watcher)
+
+	game := &models.GameSession{ID: "spec_game1", Players: models.NewSeats(player1, player2), BestOf: bestOf}
+	gameStore.CreateGame(game)
+	gm.setPlayerGameID(player1, game.ID)
+	gm.setPlayerGameID(player2, game.ID)
+	gm.StartGame(context.Background(), game)
+
+	return gm, gameStore, game
+}
+
+// connectSpectator registers a WebSocket connection for a player and returns
+// the client end to read their messages from
+func connectSpectator(t *testing.T, wsManager *WebSocketManager, playerID string) *websocket.Conn {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Failed to upgrade connection: %v", err)
+			return
+		}
+		wsManager.AddConnection(playerID, conn)
+	}))
+	t.Cleanup(server.Close)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	client, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !wsManager.HasConnection(playerID) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	return client
+}
+
+// readMessageType reads messages until one of the given type arrives
+func readMessageType(t *testing.T, client *websocket.Conn, messageType string) map[string]interface{} {
+	t.Helper()
+	_ = client.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		_, data, err := client.ReadMessage()
+		if err != nil {
+			t.Fatalf("Never received %s: %v", messageType, err)
+		}
+		var message map[string]interface{}
+		if err := json.Unmarshal(data, &message); err != nil {
+			t.Fatalf("Failed to parse message: %v", err)
+		}
+		if message["type"] == messageType {
+			return message
+		}
+	}
+}
+
+func TestSpectate_ReceivesGameStream(t *testing.T) {
+	gm, gameStore, game := setupSpectatedGame(t, 1)
+	client := connectSpectator(t, gm.wsManager, "spec_watcher")
+
+	board := [][]int{{0, 0}, {1, 1}}
+	if err := gm.HandleGameState("spec_player1", &models.GameState{Board: board, Score: 300, Lines: 2}); err != nil {
+		t.Fatalf("HandleGameState failed: %v", err)
+	}
+
+	if err := gm.Spectate("spec_watcher", game.ID); err != nil {
+		t.Fatalf("Spectate failed: %v", err)
+	}
+
+	start := readMessageType(t, client, "spectate_start")
+	players, _ := start["players"].([]interface{})
+	if start["gameId"] != game.ID || len(players) != 2 {
+		t.Fatalf("Expected a snapshot of both seats, got %v", start)
+	}
+	first, _ := players[0].(map[string]interface{})
+	if first["username"] != "Finalist1" || first["score"] != float64(300) || first["board"] == nil {
+		t.Errorf("Expected player 1's latest state in the snapshot, got %v", first)
+	}
+
+	// Both players' streams reach the spectator
+	if err := gm.HandleGameMove("spec_player2", &models.GameMove{MoveType: "left"}); err != nil {
+		t.Fatalf("HandleGameMove failed: %v", err)
+	}
+	move := readMessageType(t, client, "game_move")
+	if move["playerId"] != "spec_player2" {
+		t.Errorf("Expected player 2's move, got %v", move)
+	}
+
+	if err := gm.HandleGameState("spec_player1", &models.GameState{Board: board, Score: 500, Lines: 3}); err != nil {
+		t.Fatalf("HandleGameState failed: %v", err)
+	}
+	state := readMessageType(t, client, "game_state")
+	if state["playerId"] != "spec_player1" || state["score"] != float64(500) {
+		t.Errorf("Expected player 1's state, got %v", state)
+	}
+
+	gm.StopSpectating("spec_watcher")
+	updatedGame, _ := gameStore.GetGame(game.ID)
+	if len(updatedGame.Spectators) != 0 {
+		t.Errorf("Expected no spectators after stopping, got %v", updatedGame.Spectators)
+	}
+}
+
+func TestSpectate_Validation(t *testing.T) {
+	gm, gameStore, game := setupSpectatedGame(t, 1)
+
+	if err := gm.Spectate("spec_player1", game.ID); !errors.Is(err, ErrAlreadyInGame) {
+		t.Errorf("Expected a player in a game to be refused, got %v", err)
+	}
+	if err := gm.Spectate("spec_watcher", "missing"); err == nil {
+		t.Error("Expected an unknown game to be refused")
+	}
+
+	game.Status = models.GameStatusFinished
+	gameStore.UpdateGame(game)
+	if err := gm.Spectate("spec_watcher", game.ID); !errors.Is(err, ErrGameNotLive) {
+		t.Errorf("Expected a finished game to be refused, got %v", err)
+	}
+}
+
+func TestSpectate_FollowsSeries(t *testing.T) {
+	gm, _, game := setupSpectatedGame(t, 3)
+
+	if err := gm.Spectate("spec_watcher", game.ID); err != nil {
+		t.Fatalf("Spectate failed: %v", err)
+	}
+
+	unlock := gm.lockGame(game.ID)
+	gm.finalizeGame(game, "spec_player1")
+	unlock()
+
+	next := waitForNextGame(t, gm, "spec_player1", game.ID)
+	if len(next.Spectators) != 1 || next.Spectators[0] != "spec_watcher" {
+		t.Fatalf("Expected the spectator to follow the series, got %v", next.Spectators)
+	}
+
+	// Stopping leaves the game they are now watching
+	gm.StopSpectating("spec_watcher")
+	unlock = gm.lockGame(next.ID)
+	spectators := len(next.Spectators)
+	unlock()
+	if spectators != 0 {
+		t.Errorf("Expected the spectator to leave the next game, got %d", spectators)
+	}
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenSigner(t *testing.T) {
+	signer := NewTokenSigner([]byte("0123456789abcdef0123456789abcdef"), time.Minute, time.Hour)
+
+	tokens, err := signer.Issue("player-1", "alice", "session-1")
+	if err != nil {
+		t.Fatalf("Issue failed: %v", err)
+	}
+	if time.Until(tokens.ExpiresAt) > time.Minute || time.Until(tokens.ExpiresAt) < 50*time.Second {
+		t.Errorf("Expected access token to expire in a minute, got %v", tokens.ExpiresAt)
+	}
+
+	claims, err := signer.Verify(tokens.AccessToken, tokenTypeAccess)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if claims.PlayerID != "player-1" || claims.Username != "alice" || claims.SessionID != "session-1" {
+		t.Errorf("Unexpected claims: %+v", claims)
+	}
+
+	if _, err := signer.Verify(tokens.RefreshToken, tokenTypeAccess); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for the wrong token type, got %v", err)
+	}
+
+	// Tampering with the claims breaks the signature
+	payload, signature, _ := strings.Cut(tokens.AccessToken, ".")
+	forged := strings.Replace(payload, payload[len(payload)-4:], "AAAA", 1) + "." + signature
+	if _, err := signer.Verify(forged, tokenTypeAccess); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for a forged token, got %v", err)
+	}
+	for _, malformed := range []string{"", "nodot", "a.b", tokens.AccessToken + "x"} {
+		if _, err := signer.Verify(malformed, tokenTypeAccess); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("Expected ErrInvalidToken for %q, got %v", malformed, err)
+		}
+	}
+
+	expired := NewTokenSigner([]byte("0123456789abcdef0123456789abcdef"), -time.Second, time.Hour)
+	tokens, err = expired.Issue("player-1", "alice", "session-1")
+	if err != nil {
+		t.Fatalf("Issue failed: %v", err)
+	}
+	if _, err := signer.Verify(tokens.AccessToken, tokenTypeAccess); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("Expected ErrTokenExpired, got %v", err)
+	}
+}
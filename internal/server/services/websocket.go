@@ -1,106 +1,580 @@
 package services
 
 import (
+	"slices"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gorilla/websocket"
 
 	"github.com/briancain/go-tetris/internal/server/logger"
 )
 
-// connWrapper wraps a WebSocket connection with a mutex for safe concurrent writes
+// Heartbeat defaults. The server pings every connection each pingPeriod and
+// drops it if nothing (including the pong) is read within pongWait.
+const (
+	defaultPingPeriod = 10 * time.Second
+	defaultPongWait   = 25 * time.Second
+	writeWait         = 10 * time.Second
+)
+
+// sendQueueSize bounds each connection's outbound queue. A client that falls
+// this far behind is disconnected rather than allowed to stall senders; with
+// a reconnect grace period it can come back and resync.
+const sendQueueSize = 256
+
+// disconnectHoldSlack keeps a shared disconnect hold alive past the grace
+// period, so the instance that set it still finds it when its timer fires
+const disconnectHoldSlack = 30 * time.Second
+
+// connWrapper wraps a WebSocket connection with its outbound queue. Only the
+// connection's writePump goroutine writes data frames to conn.
 type connWrapper struct {
-	conn *websocket.Conn
-	mu   sync.Mutex
+	conn      *websocket.Conn
+	send      chan []byte   // Outbound queue drained by writePump
+	closing   chan []byte   // Close frame writePump sends once the queue is flushed
+	done      chan struct{} // Closed when the connection is removed
+	stopped   chan struct{} // Closed when writePump returns
+	closeOnce sync.Once
+	rtt       atomic.Int64 // Last measured round-trip time in nanoseconds
+}
+
+// close stops the connection's writer and closes the socket
+func (w *connWrapper) close() error {
+	w.closeOnce.Do(func() {
+		close(w.done)
+	})
+	return w.conn.Close()
+}
+
+// closeGracefully asks the writer to flush the send queue and send closeMsg
+// before closing the socket. The returned channel is closed once the writer
+// has stopped, which takes at most about two writeWaits.
+func (w *connWrapper) closeGracefully(closeMsg []byte) <-chan struct{} {
+	select {
+	case w.closing <- closeMsg:
+	default: // Already closing
+	}
+	return w.stopped
+}
+
+// Backplane routes messages to players connected to other server instances.
+// Each instance registers the players whose sockets it holds and receives
+// messages published for them.
+type Backplane interface {
+	// Register records that a player is connected to this instance
+	Register(playerID string) error
+	// Unregister removes a player's presence if it still points at this instance
+	Unregister(playerID string) error
+	// Connected reports whether any instance holds the player's socket
+	Connected(playerID string) (bool, error)
+	// HoldDisconnect records that a player who dropped from this instance
+	// may still reconnect, for up to ttl
+	HoldDisconnect(playerID string, ttl time.Duration) error
+	// ReleaseDisconnect removes a player's disconnect hold, whichever
+	// instance set it, reporting whether one was pending
+	ReleaseDisconnect(playerID string) (bool, error)
+	// ExpireDisconnect removes a player's disconnect hold if this instance
+	// set it, reporting whether it was still pending
+	ExpireDisconnect(playerID string) (bool, error)
+	// ConnectedPlayers returns the IDs of players connected to any instance, sorted
+	ConnectedPlayers() ([]string, error)
+	// Publish routes a message to the instance holding the player's socket,
+	// reporting whether the player is connected anywhere
+	Publish(playerID string, message []byte) (bool, error)
+	// Disconnect asks the instance holding the player's socket to close it,
+	// reporting whether the player is connected anywhere
+	Disconnect(playerID, reason string) (bool, error)
+	// Broadcast sends a message to every player on every instance
+	Broadcast(message []byte) error
+	// Subscribe hands over messages, disconnects and broadcasts routed to
+	// this instance until Close
+	Subscribe(
+		deliver func(playerID string, message []byte),
+		disconnect func(playerID, reason string),
+		broadcast func(message []byte),
+	) error
+	Close() error
 }
 
 // WebSocketManager handles WebSocket connections
 type WebSocketManager struct {
 	connections map[string]*connWrapper // playerID -> connection wrapper
 	mu          sync.RWMutex
+	pingPeriod  time.Duration
+	pongWait    time.Duration
+
+	slowConsumerDrops   atomic.Int64 // Connections closed for a full send queue
+	rateLimitedMessages atomic.Int64 // Incoming messages dropped for exceeding the rate limit
+	oversizedMessages   atomic.Int64 // Connections closed for sending too large a message
+
+	backplane       Backplane           // Optional cross-instance routing; nil when running alone
+	disconnectHolds map[string]struct{} // Disconnect holds when running without a backplane
 }
 
 // NewWebSocketManager creates a new WebSocket manager
 func NewWebSocketManager() *WebSocketManager {
 	return &WebSocketManager{
-		connections: make(map[string]*connWrapper),
+		connections:     make(map[string]*connWrapper),
+		pingPeriod:      defaultPingPeriod,
+		pongWait:        defaultPongWait,
+		disconnectHolds: make(map[string]struct{}),
 	}
 }
 
+// SetHeartbeat overrides how often connections are pinged and how long to
+// wait for a pong. pongWait must be longer than pingPeriod.
+func (wsm *WebSocketManager) SetHeartbeat(pingPeriod, pongWait time.Duration) {
+	wsm.mu.Lock()
+	defer wsm.mu.Unlock()
+	wsm.pingPeriod = pingPeriod
+	wsm.pongWait = pongWait
+}
+
+// SetBackplane enables routing to players connected to other instances and
+// starts handling what other instances route here
+func (wsm *WebSocketManager) SetBackplane(backplane Backplane) error {
+	wsm.mu.Lock()
+	wsm.backplane = backplane
+	wsm.mu.Unlock()
+
+	return backplane.Subscribe(wsm.deliverLocal, wsm.disconnectLocal, wsm.BroadcastToAll)
+}
+
 // AddConnection adds a WebSocket connection for a player
 func (wsm *WebSocketManager) AddConnection(playerID string, conn *websocket.Conn) {
+	wsm.addConnection(playerID, conn)
+
+	// Advertise the connection so other instances route this player's messages here
+	if backplane := wsm.getBackplane(); backplane != nil {
+		if err := backplane.Register(playerID); err != nil {
+			logger.Logger.Error("Failed to register player presence",
+				"playerID", playerID,
+				"error", err,
+			)
+		}
+	}
+}
+
+// addConnection registers a connection locally and starts its writer
+func (wsm *WebSocketManager) addConnection(playerID string, conn *websocket.Conn) {
 	wsm.mu.Lock()
 	defer wsm.mu.Unlock()
 
 	// Close existing connection if any
 	if existingWrapper, exists := wsm.connections[playerID]; exists {
-		existingWrapper.conn.Close()
+		existingWrapper.close()
 	}
 
-	wsm.connections[playerID] = &connWrapper{conn: conn}
+	wrapper := &connWrapper{
+		conn:    conn,
+		send:    make(chan []byte, sendQueueSize),
+		closing: make(chan []byte, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	wsm.connections[playerID] = wrapper
+
+	// Any read (including a pong) must arrive within pongWait, otherwise the
+	// reader's next ReadMessage fails and the connection is torn down
+	pongWait := wsm.pongWait
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(appData string) error {
+		conn.SetReadDeadline(time.Now().Add(pongWait))
+		if sentAt, err := strconv.ParseInt(appData, 10, 64); err == nil {
+			wrapper.rtt.Store(time.Now().UnixNano() - sentAt)
+		}
+		return nil
+	})
+
+	go wsm.writePump(playerID, wrapper, wsm.pingPeriod)
+
 	logger.Logger.Info("WebSocket connection added",
 		"playerID", playerID,
 	)
 }
 
+// writePump drains a connection's send queue and pings it until the
+// connection is removed. The ping payload is the send time, which the pong
+// echoes back for RTT measurement.
+func (wsm *WebSocketManager) writePump(playerID string, wrapper *connWrapper, pingPeriod time.Duration) {
+	defer close(wrapper.stopped)
+
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-wrapper.done:
+			return
+		case closeMsg := <-wrapper.closing:
+			flush(playerID, wrapper, closeMsg)
+			wrapper.close()
+			return
+		case message := <-wrapper.send:
+			wrapper.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			err := wrapper.conn.WriteMessage(websocket.TextMessage, message)
+			if err != nil {
+				logger.Logger.Error("Failed to send WebSocket message",
+					"playerID", playerID,
+					"error", err,
+				)
+				// Closing unblocks the reader, which handles the disconnect
+				wrapper.close()
+				return
+			}
+		case <-ticker.C:
+			payload := strconv.FormatInt(time.Now().UnixNano(), 10)
+			err := wrapper.conn.WriteControl(websocket.PingMessage, []byte(payload), time.Now().Add(writeWait))
+			if err != nil {
+				logger.Logger.Warn("WebSocket ping failed, closing connection",
+					"playerID", playerID,
+					"error", err,
+				)
+				// Closing unblocks the reader, which handles the disconnect
+				wrapper.close()
+				return
+			}
+		}
+	}
+}
+
+// flush writes whatever is still queued for a closing connection followed by
+// its close frame, giving up once writeWait has passed. Only writePump may
+// call it.
+func flush(playerID string, wrapper *connWrapper, closeMsg []byte) {
+	deadline := time.Now().Add(writeWait)
+	wrapper.conn.SetWriteDeadline(deadline)
+	for len(wrapper.send) > 0 {
+		if err := wrapper.conn.WriteMessage(websocket.TextMessage, <-wrapper.send); err != nil {
+			logger.Logger.Warn("Failed to flush WebSocket messages",
+				"playerID", playerID,
+				"error", err,
+			)
+			return
+		}
+	}
+
+	if err := wrapper.conn.WriteControl(websocket.CloseMessage, closeMsg, deadline); err != nil {
+		logger.Logger.Warn("Failed to send close message", "playerID", playerID, "error", err)
+	}
+}
+
 // RemoveConnection removes a WebSocket connection
 func (wsm *WebSocketManager) RemoveConnection(playerID string) {
 	wsm.mu.Lock()
-	defer wsm.mu.Unlock()
-
-	if wrapper, exists := wsm.connections[playerID]; exists {
-		wrapper.conn.Close()
+	wrapper, exists := wsm.connections[playerID]
+	if exists {
+		wrapper.close()
 		delete(wsm.connections, playerID)
 		logger.Logger.Info("WebSocket connection removed",
 			"playerID", playerID,
 		)
 	}
+	wsm.mu.Unlock()
+
+	if exists {
+		wsm.unregister(playerID)
+	}
 }
 
-// SendToPlayer sends a message to a specific player
-func (wsm *WebSocketManager) SendToPlayer(playerID string, message []byte) {
-	wsm.mu.RLock()
+// CloseConnection removes a player's connection and has its writer send
+// what's still queued and a close frame with the given reason, reporting
+// whether they were connected here. It doesn't wait for the writes.
+func (wsm *WebSocketManager) CloseConnection(playerID, reason string) bool {
+	wsm.mu.Lock()
 	wrapper, exists := wsm.connections[playerID]
-	wsm.mu.RUnlock()
+	if exists {
+		delete(wsm.connections, playerID)
+	}
+	wsm.mu.Unlock()
 
 	if !exists {
-		logger.Logger.Warn("No WebSocket connection found for player",
+		return false
+	}
+
+	wrapper.closeGracefully(websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reason))
+	logger.Logger.Info("WebSocket connection closed by server",
+		"playerID", playerID,
+		"reason", reason,
+	)
+
+	wsm.unregister(playerID)
+	return true
+}
+
+// CloseConnectionAnywhere closes a player's connection with the given reason,
+// on this instance or through the backplane on whichever instance holds it.
+// It reports whether they were connected anywhere.
+func (wsm *WebSocketManager) CloseConnectionAnywhere(playerID, reason string) bool {
+	if wsm.CloseConnection(playerID, reason) {
+		return true
+	}
+
+	backplane := wsm.getBackplane()
+	if backplane == nil {
+		return false
+	}
+
+	connected, err := backplane.Disconnect(playerID, reason)
+	if err != nil {
+		logger.Logger.Error("Failed to route disconnect",
+			"playerID", playerID,
+			"error", err,
+		)
+		return false
+	}
+	return connected
+}
+
+// disconnectLocal closes a connection another instance asked to close. Like
+// deliverLocal it never republishes.
+func (wsm *WebSocketManager) disconnectLocal(playerID, reason string) {
+	if !wsm.CloseConnection(playerID, reason) {
+		logger.Logger.Warn("Routed disconnect for player not connected here",
 			"playerID", playerID,
 		)
+	}
+}
+
+// RemoveConnectionIfCurrent removes a player's connection only if it is still
+// the given conn, reporting whether it was removed. A player who reconnects
+// replaces their old connection, whose reader must then not tear down the new one.
+func (wsm *WebSocketManager) RemoveConnectionIfCurrent(playerID string, conn *websocket.Conn) bool {
+	wsm.mu.Lock()
+	wrapper, exists := wsm.connections[playerID]
+	if !exists || wrapper.conn != conn {
+		wsm.mu.Unlock()
+		return false
+	}
+
+	wrapper.close()
+	delete(wsm.connections, playerID)
+	logger.Logger.Info("WebSocket connection removed",
+		"playerID", playerID,
+	)
+	wsm.mu.Unlock()
+
+	wsm.unregister(playerID)
+	return true
+}
+
+// unregister drops a player's cross-instance presence after their local connection closes
+func (wsm *WebSocketManager) unregister(playerID string) {
+	backplane := wsm.getBackplane()
+	if backplane == nil {
 		return
 	}
 
-	// Use per-connection mutex to prevent concurrent writes
-	wrapper.mu.Lock()
-	err := wrapper.conn.WriteMessage(websocket.TextMessage, message)
-	wrapper.mu.Unlock()
+	if err := backplane.Unregister(playerID); err != nil {
+		logger.Logger.Error("Failed to unregister player presence",
+			"playerID", playerID,
+			"error", err,
+		)
+	}
+}
 
+// getBackplane returns the configured backplane, if any
+func (wsm *WebSocketManager) getBackplane() Backplane {
+	wsm.mu.RLock()
+	defer wsm.mu.RUnlock()
+	return wsm.backplane
+}
+
+// HasConnection reports whether a player currently has a WebSocket connection
+func (wsm *WebSocketManager) HasConnection(playerID string) bool {
+	wsm.mu.RLock()
+	defer wsm.mu.RUnlock()
+	_, exists := wsm.connections[playerID]
+	return exists
+}
+
+// ConnectedAnywhere reports whether a player has a connection on this
+// instance or, through the backplane, on any other
+func (wsm *WebSocketManager) ConnectedAnywhere(playerID string) bool {
+	if wsm.HasConnection(playerID) {
+		return true
+	}
+
+	backplane := wsm.getBackplane()
+	if backplane == nil {
+		return false
+	}
+
+	connected, err := backplane.Connected(playerID)
 	if err != nil {
-		logger.Logger.Error("Failed to send WebSocket message",
+		logger.Logger.Error("Failed to check player presence",
 			"playerID", playerID,
 			"error", err,
 		)
-		wsm.RemoveConnection(playerID)
+		return false
 	}
+	return connected
 }
 
-// BroadcastToAll sends a message to all connected players
-func (wsm *WebSocketManager) BroadcastToAll(message []byte) {
+// HoldDisconnect records that a dropped player may reconnect within the
+// grace period. With a backplane the hold is shared, so whichever instance
+// the player reconnects through can release it.
+func (wsm *WebSocketManager) HoldDisconnect(playerID string, gracePeriod time.Duration) {
+	backplane := wsm.getBackplane()
+	if backplane == nil {
+		wsm.mu.Lock()
+		wsm.disconnectHolds[playerID] = struct{}{}
+		wsm.mu.Unlock()
+		return
+	}
+
+	if err := backplane.HoldDisconnect(playerID, gracePeriod+disconnectHoldSlack); err != nil {
+		logger.Logger.Error("Failed to hold disconnect",
+			"playerID", playerID,
+			"error", err,
+		)
+	}
+}
+
+// ReleaseDisconnect clears a returning player's disconnect hold, wherever
+// they dropped, reporting whether one was pending
+func (wsm *WebSocketManager) ReleaseDisconnect(playerID string) bool {
+	backplane := wsm.getBackplane()
+	if backplane == nil {
+		return wsm.releaseLocalHold(playerID)
+	}
+
+	released, err := backplane.ReleaseDisconnect(playerID)
+	if err != nil {
+		logger.Logger.Error("Failed to release disconnect hold",
+			"playerID", playerID,
+			"error", err,
+		)
+		return false
+	}
+	return released
+}
+
+// ExpireDisconnect clears a disconnect hold this instance set once its
+// grace period runs out, reporting whether it was still pending. A hold
+// released by a reconnect on another instance is gone.
+func (wsm *WebSocketManager) ExpireDisconnect(playerID string) bool {
+	backplane := wsm.getBackplane()
+	if backplane == nil {
+		return wsm.releaseLocalHold(playerID)
+	}
+
+	expired, err := backplane.ExpireDisconnect(playerID)
+	if err != nil {
+		logger.Logger.Error("Failed to expire disconnect hold",
+			"playerID", playerID,
+			"error", err,
+		)
+		return false
+	}
+	return expired
+}
+
+// releaseLocalHold clears a disconnect hold kept without a backplane
+func (wsm *WebSocketManager) releaseLocalHold(playerID string) bool {
+	wsm.mu.Lock()
+	defer wsm.mu.Unlock()
+
+	_, held := wsm.disconnectHolds[playerID]
+	delete(wsm.disconnectHolds, playerID)
+	return held
+}
+
+// SendToPlayer queues a message for a specific player without blocking,
+// routing it through the backplane if they are connected to another instance
+func (wsm *WebSocketManager) SendToPlayer(playerID string, message []byte) {
 	wsm.mu.RLock()
-	defer wsm.mu.RUnlock()
+	wrapper, exists := wsm.connections[playerID]
+	backplane := wsm.backplane
+	wsm.mu.RUnlock()
 
-	for playerID, wrapper := range wsm.connections {
-		wrapper.mu.Lock()
-		err := wrapper.conn.WriteMessage(websocket.TextMessage, message)
-		wrapper.mu.Unlock()
+	if exists {
+		wsm.enqueue(playerID, wrapper, message)
+		return
+	}
+
+	if backplane != nil {
+		delivered, err := backplane.Publish(playerID, message)
 		if err != nil {
-			logger.Logger.Error("Failed to broadcast WebSocket message",
+			logger.Logger.Error("Failed to route WebSocket message",
 				"playerID", playerID,
 				"error", err,
 			)
-			go wsm.RemoveConnection(playerID)
+			return
+		}
+		if delivered {
+			return
 		}
 	}
+
+	logger.Logger.Warn("No WebSocket connection found for player",
+		"playerID", playerID,
+	)
+}
+
+// deliverLocal queues a message routed from another instance. It never
+// republishes, so a stale presence entry can't bounce messages between instances.
+func (wsm *WebSocketManager) deliverLocal(playerID string, message []byte) {
+	wsm.mu.RLock()
+	wrapper, exists := wsm.connections[playerID]
+	wsm.mu.RUnlock()
+
+	if !exists {
+		logger.Logger.Warn("Routed message for player not connected here",
+			"playerID", playerID,
+		)
+		return
+	}
+
+	wsm.enqueue(playerID, wrapper, message)
+}
+
+// BroadcastToAll queues a message for all connected players
+func (wsm *WebSocketManager) BroadcastToAll(message []byte) {
+	wsm.mu.RLock()
+	defer wsm.mu.RUnlock()
+
+	for playerID, wrapper := range wsm.connections {
+		wsm.enqueue(playerID, wrapper, message)
+	}
+}
+
+// BroadcastToAllInstances queues a message for every player connected to any
+// instance. Without a backplane, or if it fails, only local players get it.
+func (wsm *WebSocketManager) BroadcastToAllInstances(message []byte) {
+	backplane := wsm.getBackplane()
+	if backplane == nil {
+		wsm.BroadcastToAll(message)
+		return
+	}
+
+	// Every instance, this one included, receives the broadcast
+	if err := backplane.Broadcast(message); err != nil {
+		logger.Logger.Error("Failed to route broadcast",
+			"error", err,
+		)
+		wsm.BroadcastToAll(message)
+	}
+}
+
+// enqueue adds a message to a connection's send queue, disconnecting the
+// player if the queue is full
+func (wsm *WebSocketManager) enqueue(playerID string, wrapper *connWrapper, message []byte) {
+	select {
+	case wrapper.send <- message:
+	default:
+		wsm.slowConsumerDrops.Add(1)
+		logger.Logger.Warn("WebSocket send queue full, disconnecting slow consumer",
+			"playerID", playerID,
+			"queueSize", sendQueueSize,
+		)
+		wrapper.close()
+		// May be called with wsm.mu held, so remove asynchronously
+		go wsm.RemoveConnectionIfCurrent(playerID, wrapper.conn)
+	}
 }
 
 // GetConnectionCount returns the number of active connections
@@ -110,31 +584,134 @@ func (wsm *WebSocketManager) GetConnectionCount() int {
 	return len(wsm.connections)
 }
 
-// Shutdown gracefully closes all WebSocket connections
-func (wsm *WebSocketManager) Shutdown() {
-	wsm.mu.Lock()
-	defer wsm.mu.Unlock()
+// ConnectedPlayerIDs returns the IDs of players connected to this instance, sorted
+func (wsm *WebSocketManager) ConnectedPlayerIDs() []string {
+	wsm.mu.RLock()
+	defer wsm.mu.RUnlock()
 
-	logger.Logger.Info("Shutting down WebSocket connections", "count", len(wsm.connections))
+	playerIDs := make([]string, 0, len(wsm.connections))
+	for playerID := range wsm.connections {
+		playerIDs = append(playerIDs, playerID)
+	}
+	slices.Sort(playerIDs)
+	return playerIDs
+}
 
-	for playerID, wrapper := range wsm.connections {
-		// Use per-connection mutex for safe close message
-		wrapper.mu.Lock()
-		// Send close message to client
-		err := wrapper.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "Server shutting down"))
-		if err != nil {
-			logger.Logger.Warn("Failed to send close message", "playerID", playerID, "error", err)
+// ConnectedPlayerIDsAnywhere returns the IDs of players connected to any
+// instance, sorted. Without a backplane that's this instance's players.
+func (wsm *WebSocketManager) ConnectedPlayerIDsAnywhere() ([]string, error) {
+	backplane := wsm.getBackplane()
+	if backplane == nil {
+		return wsm.ConnectedPlayerIDs(), nil
+	}
+	return backplane.ConnectedPlayers()
+}
+
+// GetPlayerRTT returns the last measured round-trip time to a player
+func (wsm *WebSocketManager) GetPlayerRTT(playerID string) (time.Duration, bool) {
+	wsm.mu.RLock()
+	defer wsm.mu.RUnlock()
+
+	wrapper, exists := wsm.connections[playerID]
+	if !exists {
+		return 0, false
+	}
+	rtt := wrapper.rtt.Load()
+	return time.Duration(rtt), rtt > 0
+}
+
+// GetAverageRTT returns the mean round-trip time across connections that
+// have answered at least one ping
+func (wsm *WebSocketManager) GetAverageRTT() time.Duration {
+	wsm.mu.RLock()
+	defer wsm.mu.RUnlock()
+
+	var total time.Duration
+	measured := 0
+	for _, wrapper := range wsm.connections {
+		if rtt := wrapper.rtt.Load(); rtt > 0 {
+			total += time.Duration(rtt)
+			measured++
 		}
+	}
 
-		// Close the connection
-		err = wrapper.conn.Close()
-		wrapper.mu.Unlock()
-		if err != nil {
-			logger.Logger.Warn("Failed to close WebSocket connection", "playerID", playerID, "error", err)
+	if measured == 0 {
+		return 0
+	}
+	return total / time.Duration(measured)
+}
+
+// GetQueueStats returns the total and largest number of messages waiting in
+// connection send queues
+func (wsm *WebSocketManager) GetQueueStats() (total, largest int) {
+	wsm.mu.RLock()
+	defer wsm.mu.RUnlock()
+
+	for _, wrapper := range wsm.connections {
+		depth := len(wrapper.send)
+		total += depth
+		if depth > largest {
+			largest = depth
 		}
 	}
+	return total, largest
+}
+
+// GetSlowConsumerDrops returns how many connections were closed because
+// their send queue filled up
+func (wsm *WebSocketManager) GetSlowConsumerDrops() int64 {
+	return wsm.slowConsumerDrops.Load()
+}
+
+// RecordRateLimitedMessage counts an incoming message dropped by the
+// per-connection rate limit
+func (wsm *WebSocketManager) RecordRateLimitedMessage() {
+	wsm.rateLimitedMessages.Add(1)
+}
 
-	// Clear all connections
+// RecordOversizedMessage counts a connection closed for exceeding the
+// maximum message size
+func (wsm *WebSocketManager) RecordOversizedMessage() {
+	wsm.oversizedMessages.Add(1)
+}
+
+// GetMessageLimitStats returns how many incoming messages were dropped for
+// their rate and how many connections were closed for message size
+func (wsm *WebSocketManager) GetMessageLimitStats() (rateLimited, oversized int64) {
+	return wsm.rateLimitedMessages.Load(), wsm.oversizedMessages.Load()
+}
+
+// Shutdown gracefully closes all WebSocket connections, giving each writer
+// up to writeWait to flush its queue and send a close frame
+func (wsm *WebSocketManager) Shutdown() {
+	wsm.mu.Lock()
+	wrappers := wsm.connections
 	wsm.connections = make(map[string]*connWrapper)
+	wsm.mu.Unlock()
+
+	logger.Logger.Info("Shutting down WebSocket connections", "count", len(wrappers))
+
+	closeMsg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "Server shutting down")
+	stopped := make(map[string]<-chan struct{}, len(wrappers))
+	for playerID, wrapper := range wrappers {
+		stopped[playerID] = wrapper.closeGracefully(closeMsg)
+	}
+
+	// Writers flush in parallel; close whatever hasn't finished in time
+	deadline := time.Now().Add(writeWait)
+	for playerID, wrapper := range wrappers {
+		select {
+		case <-stopped[playerID]:
+		case <-time.After(time.Until(deadline)):
+			if err := wrapper.close(); err != nil {
+				logger.Logger.Warn("Failed to close WebSocket connection", "playerID", playerID, "error", err)
+			}
+		}
+	}
+
+	// Drop presence so other instances stop routing to this one
+	for playerID := range wrappers {
+		wsm.unregister(playerID)
+	}
 	logger.Logger.Info("All WebSocket connections closed")
 }
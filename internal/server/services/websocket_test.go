@@ -1,9 +1,12 @@
 package services
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -71,6 +74,58 @@ func TestWebSocketManager_Shutdown(t *testing.T) {
 	}
 }
 
+func TestWebSocketManager_ShutdownFlushesQueuedMessages(t *testing.T) {
+	wsManager := NewWebSocketManager()
+
+	received := make(chan int, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Failed to upgrade connection: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		// Count messages until the close frame arrives
+		count := 0
+		for {
+			_, _, err := conn.ReadMessage()
+			if err != nil {
+				if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+					count = -1
+				}
+				received <- count
+				return
+			}
+			count++
+		}
+	}))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	wsManager.AddConnection("player1", conn)
+
+	const messages = 100
+	for i := 0; i < messages; i++ {
+		wsManager.SendToPlayer("player1", []byte(`{"type":"test"}`))
+	}
+	wsManager.Shutdown()
+
+	select {
+	case count := <-received:
+		if count != messages {
+			t.Errorf("Expected %d messages before a going away close frame, got %d", messages, count)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Client never saw the connection close")
+	}
+}
+
 func TestWebSocketManager_ShutdownEmptyConnections(t *testing.T) {
 	wsManager := NewWebSocketManager()
 
@@ -114,3 +169,455 @@ func TestWebSocketManager_ShutdownWithFailedClose(t *testing.T) {
 		t.Errorf("Expected 0 connections after shutdown, got %d", count)
 	}
 }
+
+func TestWebSocketManager_HeartbeatMeasuresRTT(t *testing.T) {
+	wsManager := NewWebSocketManager()
+	wsManager.SetHeartbeat(20*time.Millisecond, time.Second)
+
+	// Server side reads so pong handlers run
+	readErr := make(chan error, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Failed to upgrade connection: %v", err)
+			return
+		}
+		wsManager.AddConnection("player1", conn)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				readErr <- err
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	client, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	// The client must read for its default ping handler to answer with pongs
+	go func() {
+		for {
+			if _, _, err := client.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, ok := wsManager.GetPlayerRTT("player1"); ok {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if _, ok := wsManager.GetPlayerRTT("player1"); !ok {
+		t.Fatal("Expected RTT to be measured from pong")
+	}
+	if wsManager.GetAverageRTT() <= 0 {
+		t.Error("Expected average RTT to be positive")
+	}
+
+	select {
+	case err := <-readErr:
+		t.Fatalf("Expected healthy connection to stay open, got %v", err)
+	default:
+	}
+}
+
+func TestWebSocketManager_HeartbeatDropsDeadConnection(t *testing.T) {
+	wsManager := NewWebSocketManager()
+	wsManager.SetHeartbeat(20*time.Millisecond, 100*time.Millisecond)
+
+	readErr := make(chan error, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Failed to upgrade connection: %v", err)
+			return
+		}
+		wsManager.AddConnection("player1", conn)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				readErr <- err
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	// A client that never reads never answers pings, like a half-open connection
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	client, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	select {
+	case <-readErr:
+		// Read deadline expired as expected
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected server read to fail once pongs stopped arriving")
+	}
+}
+
+func TestWebSocketManager_SendToPlayerQueued(t *testing.T) {
+	wsManager := NewWebSocketManager()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Failed to upgrade connection: %v", err)
+			return
+		}
+		wsManager.AddConnection("player1", conn)
+	}))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	client, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	// Wait for the server side to register the connection
+	for i := 0; i < 100 && !wsManager.HasConnection("player1"); i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	wsManager.SendToPlayer("player1", []byte(`{"type":"one"}`))
+	wsManager.BroadcastToAll([]byte(`{"type":"two"}`))
+
+	// Messages arrive in order through the writer goroutine
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	for _, expected := range []string{`{"type":"one"}`, `{"type":"two"}`} {
+		_, data, err := client.ReadMessage()
+		if err != nil {
+			t.Fatalf("Failed to read message: %v", err)
+		}
+		if string(data) != expected {
+			t.Errorf("Expected %s, got %s", expected, data)
+		}
+	}
+}
+
+func TestWebSocketManager_SlowConsumerDisconnected(t *testing.T) {
+	wsManager := NewWebSocketManager()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Failed to upgrade connection: %v", err)
+			return
+		}
+		defer conn.Close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+
+	// Register a connection with no writer so its queue never drains
+	wsManager.mu.Lock()
+	wsManager.connections["player1"] = &connWrapper{
+		conn: conn,
+		send: make(chan []byte, sendQueueSize),
+		done: make(chan struct{}),
+	}
+	wsManager.mu.Unlock()
+
+	for i := 0; i < sendQueueSize; i++ {
+		wsManager.SendToPlayer("player1", []byte(`{"type":"test"}`))
+	}
+
+	total, largest := wsManager.GetQueueStats()
+	if total != sendQueueSize || largest != sendQueueSize {
+		t.Errorf("Expected queue depth %d, got total %d largest %d", sendQueueSize, total, largest)
+	}
+	if drops := wsManager.GetSlowConsumerDrops(); drops != 0 {
+		t.Errorf("Expected no drops before the queue overflows, got %d", drops)
+	}
+
+	// One more message overflows the queue
+	wsManager.SendToPlayer("player1", []byte(`{"type":"test"}`))
+
+	if drops := wsManager.GetSlowConsumerDrops(); drops != 1 {
+		t.Errorf("Expected 1 slow consumer drop, got %d", drops)
+	}
+
+	for i := 0; i < 100 && wsManager.HasConnection("player1"); i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if wsManager.HasConnection("player1") {
+		t.Error("Expected slow consumer to be disconnected")
+	}
+}
+
+// fakeBus connects fakeBackplanes the way Redis connects server instances
+type fakeBus struct {
+	mu        sync.Mutex
+	presence  map[string]*fakeBackplane
+	holds     map[string]*fakeBackplane
+	instances []*fakeBackplane
+}
+
+type fakeBackplane struct {
+	bus        *fakeBus
+	deliver    func(playerID string, message []byte)
+	disconnect func(playerID, reason string)
+	broadcast  func(message []byte)
+}
+
+func (b *fakeBackplane) Register(playerID string) error {
+	b.bus.mu.Lock()
+	defer b.bus.mu.Unlock()
+	b.bus.presence[playerID] = b
+	return nil
+}
+
+func (b *fakeBackplane) Unregister(playerID string) error {
+	b.bus.mu.Lock()
+	defer b.bus.mu.Unlock()
+	if b.bus.presence[playerID] == b {
+		delete(b.bus.presence, playerID)
+	}
+	return nil
+}
+
+func (b *fakeBackplane) Connected(playerID string) (bool, error) {
+	b.bus.mu.Lock()
+	defer b.bus.mu.Unlock()
+	_, ok := b.bus.presence[playerID]
+	return ok, nil
+}
+
+func (b *fakeBackplane) HoldDisconnect(playerID string, ttl time.Duration) error {
+	b.bus.mu.Lock()
+	defer b.bus.mu.Unlock()
+	b.bus.holds[playerID] = b
+	return nil
+}
+
+func (b *fakeBackplane) ReleaseDisconnect(playerID string) (bool, error) {
+	b.bus.mu.Lock()
+	defer b.bus.mu.Unlock()
+	_, ok := b.bus.holds[playerID]
+	delete(b.bus.holds, playerID)
+	return ok, nil
+}
+
+func (b *fakeBackplane) ExpireDisconnect(playerID string) (bool, error) {
+	b.bus.mu.Lock()
+	defer b.bus.mu.Unlock()
+	if b.bus.holds[playerID] != b {
+		return false, nil
+	}
+	delete(b.bus.holds, playerID)
+	return true, nil
+}
+
+func (b *fakeBackplane) ConnectedPlayers() ([]string, error) {
+	b.bus.mu.Lock()
+	defer b.bus.mu.Unlock()
+	playerIDs := make([]string, 0, len(b.bus.presence))
+	for playerID := range b.bus.presence {
+		playerIDs = append(playerIDs, playerID)
+	}
+	slices.Sort(playerIDs)
+	return playerIDs, nil
+}
+
+func (b *fakeBackplane) Publish(playerID string, message []byte) (bool, error) {
+	b.bus.mu.Lock()
+	owner, ok := b.bus.presence[playerID]
+	b.bus.mu.Unlock()
+	if !ok || owner.deliver == nil {
+		return false, nil
+	}
+	owner.deliver(playerID, message)
+	return true, nil
+}
+
+func (b *fakeBackplane) Disconnect(playerID, reason string) (bool, error) {
+	b.bus.mu.Lock()
+	owner, ok := b.bus.presence[playerID]
+	b.bus.mu.Unlock()
+	if !ok || owner.disconnect == nil {
+		return false, nil
+	}
+	owner.disconnect(playerID, reason)
+	return true, nil
+}
+
+func (b *fakeBackplane) Broadcast(message []byte) error {
+	b.bus.mu.Lock()
+	instances := slices.Clone(b.bus.instances)
+	b.bus.mu.Unlock()
+	for _, instance := range instances {
+		instance.broadcast(message)
+	}
+	return nil
+}
+
+func (b *fakeBackplane) Subscribe(
+	deliver func(playerID string, message []byte),
+	disconnect func(playerID, reason string),
+	broadcast func(message []byte),
+) error {
+	b.deliver = deliver
+	b.disconnect = disconnect
+	b.broadcast = broadcast
+	b.bus.mu.Lock()
+	b.bus.instances = append(b.bus.instances, b)
+	b.bus.mu.Unlock()
+	return nil
+}
+
+func (b *fakeBackplane) Close() error {
+	return nil
+}
+
+func TestWebSocketManager_SendToPlayerOnOtherInstance(t *testing.T) {
+	bus := &fakeBus{presence: make(map[string]*fakeBackplane), holds: make(map[string]*fakeBackplane)}
+	instanceA := NewWebSocketManager()
+	instanceB := NewWebSocketManager()
+	if err := instanceA.SetBackplane(&fakeBackplane{bus: bus}); err != nil {
+		t.Fatalf("SetBackplane failed: %v", err)
+	}
+	if err := instanceB.SetBackplane(&fakeBackplane{bus: bus}); err != nil {
+		t.Fatalf("SetBackplane failed: %v", err)
+	}
+
+	// The player's socket lives on instance B
+	var serverConn *websocket.Conn
+	connected := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Failed to upgrade connection: %v", err)
+			return
+		}
+		serverConn = conn
+		instanceB.AddConnection("player1", conn)
+		close(connected)
+	}))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	client, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+	<-connected
+
+	// Instance A has no local socket and routes through the backplane
+	instanceA.SendToPlayer("player1", []byte(`{"type":"routed"}`))
+
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	_, data, err := client.ReadMessage()
+	if err != nil {
+		t.Fatalf("Failed to read routed message: %v", err)
+	}
+	if string(data) != `{"type":"routed"}` {
+		t.Errorf("Expected routed message, got %s", data)
+	}
+
+	// Closing the socket on B drops the player's presence
+	instanceB.RemoveConnectionIfCurrent("player1", serverConn)
+	bus.mu.Lock()
+	_, present := bus.presence["player1"]
+	bus.mu.Unlock()
+	if present {
+		t.Error("Expected presence to be removed with the connection")
+	}
+}
+
+func TestWebSocketManager_ReachesPlayersOnOtherInstances(t *testing.T) {
+	bus := &fakeBus{presence: make(map[string]*fakeBackplane), holds: make(map[string]*fakeBackplane)}
+	instanceA := NewWebSocketManager()
+	instanceB := NewWebSocketManager()
+	if err := instanceA.SetBackplane(&fakeBackplane{bus: bus}); err != nil {
+		t.Fatalf("SetBackplane failed: %v", err)
+	}
+	if err := instanceB.SetBackplane(&fakeBackplane{bus: bus}); err != nil {
+		t.Fatalf("SetBackplane failed: %v", err)
+	}
+
+	// The player's socket lives on instance B
+	connected := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Failed to upgrade connection: %v", err)
+			return
+		}
+		instanceB.AddConnection("player1", conn)
+		close(connected)
+	}))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	client, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+	<-connected
+
+	playerIDs, err := instanceA.ConnectedPlayerIDsAnywhere()
+	if err != nil || !slices.Equal(playerIDs, []string{"player1"}) {
+		t.Errorf("Expected instance A to list player1, got %v (err %v)", playerIDs, err)
+	}
+	if local := instanceA.ConnectedPlayerIDs(); len(local) != 0 {
+		t.Errorf("Expected no players connected to instance A itself, got %v", local)
+	}
+
+	// A broadcast from A reaches B's players
+	instanceA.BroadcastToAllInstances([]byte(`{"type":"announcement"}`))
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	_, data, err := client.ReadMessage()
+	if err != nil {
+		t.Fatalf("Failed to read broadcast: %v", err)
+	}
+	if string(data) != `{"type":"announcement"}` {
+		t.Errorf("Expected broadcast message, got %s", data)
+	}
+
+	// So does closing the player's connection
+	if !instanceA.CloseConnectionAnywhere("player1", "Kicked") {
+		t.Fatal("Expected the player to be found on instance B")
+	}
+	_, _, err = client.ReadMessage()
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) || closeErr.Code != websocket.ClosePolicyViolation || closeErr.Text != "Kicked" {
+		t.Errorf("Expected a policy violation close with the reason, got %v", err)
+	}
+	if instanceB.HasConnection("player1") {
+		t.Error("Expected instance B to drop the connection")
+	}
+	if instanceA.CloseConnectionAnywhere("player1", "Kicked") {
+		t.Error("Expected no connection left to close")
+	}
+}
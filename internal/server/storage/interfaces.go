@@ -1,6 +1,45 @@
 package storage
 
-import "github.com/briancain/go-tetris/pkg/models"
+import (
+	"errors"
+	"time"
+
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+// ErrUsernameTaken is returned by stores that enforce unique usernames
+var ErrUsernameTaken = errors.New("username already in use")
+
+// Room store errors
+var (
+	ErrRoomNotFound  = errors.New("room not found")
+	ErrRoomFull      = errors.New("room is full")
+	ErrRoomCodeTaken = errors.New("room code already in use")
+)
+
+// Account store errors
+var (
+	ErrAccountNotFound = errors.New("account not found")
+	ErrAccountExists   = errors.New("account already exists")
+)
+
+// ErrBanNotFound is returned when a username isn't banned
+var ErrBanNotFound = errors.New("ban not found")
+
+// Replay store errors
+var (
+	ErrReplayNotFound = errors.New("replay not found")
+	ErrReplayFull     = errors.New("replay is full")
+)
+
+// GameOutcome is a player's result in a finished game
+type GameOutcome int
+
+const (
+	OutcomeDraw GameOutcome = iota
+	OutcomeWin
+	OutcomeLoss
+)
 
 // PlayerStore handles player data persistence
 type PlayerStore interface {
@@ -11,8 +50,77 @@ type PlayerStore interface {
 	UpdatePlayer(player *models.Player) error
 	DeletePlayer(id string) error
 	GetAllPlayers() ([]*models.Player, error)
+
+	// RecordGameResult atomically adds a finished game to a player's stats,
+	// stores their new rating and returns the updated player. Placement is
+	// the player's finish in a battle royale, or zero for a duel. Stats should
+	// only change through this method so concurrent UpdatePlayer calls can't
+	// overwrite them.
+	RecordGameResult(playerID string, outcome GameOutcome, score, placement int, rating models.Rating) (*models.Player, error)
+
+	// RecordSeriesResult atomically adds a finished match series to a
+	// player's stats and returns the updated player
+	RecordSeriesResult(playerID string, won bool) (*models.Player, error)
+
+	// SetSessionToken replaces a player's session token, invalidating the
+	// old one. An empty token ends the session without deleting the player.
+	SetSessionToken(playerID, token string) error
+
+	// The methods below each write only their own fields, so they never undo
+	// a change another instance made to the rest of the player. UpdatePlayer
+	// leaves these fields alone.
+
+	// TouchPlayer records a player's activity and keeps their session alive
+	TouchPlayer(playerID string, at time.Time) error
+
+	// SetPlayerGameID routes a player to a game, or to none when gameID is empty
+	SetPlayerGameID(playerID, gameID string) error
+
+	// SetPlayerQueue marks a player as waiting in a matchmaking queue since queuedAt
+	SetPlayerQueue(playerID, queue string, queuedAt time.Time) error
+
+	// ClearPlayerQueue marks a player as no longer waiting, but only if they're
+	// still in queue; an empty queue clears whichever one they're in
+	ClearPlayerQueue(playerID, queue string) error
 }
 
+// AccountStore handles registered account persistence. Accounts are keyed by
+// username and never expire.
+type AccountStore interface {
+	CreateAccount(account *models.Account) error
+	GetAccount(username string) (*models.Account, error)
+	UpdateAccount(account *models.Account) error
+}
+
+// BanStore handles banned usernames. Bans never expire; they last until
+// lifted with Unban.
+type BanStore interface {
+	// Ban adds or replaces a ban
+	Ban(ban *models.Ban) error
+	// Unban lifts a ban, returning ErrBanNotFound if there wasn't one
+	Unban(username string) error
+	GetBan(username string) (*models.Ban, error)
+	// ListBans returns every ban, oldest first
+	ListBans() ([]*models.Ban, error)
+}
+
+// MatchHistoryStore keeps registered players' finished games for good,
+// separately from game sessions, which expire soon after a game ends
+type MatchHistoryStore interface {
+	// AddPlayerMatch adds a finished game to the front of a player's history
+	// and counts it in their record against each opponent
+	AddPlayerMatch(playerID string, match *models.MatchRecord) error
+	// GetPlayerMatches returns up to limit of a player's games, newest
+	// first, after skipping offset. A non-empty opponent username limits
+	// it to games against that player.
+	GetPlayerMatches(playerID, opponent string, offset, limit int) ([]*models.MatchRecord, error)
+	// GetHeadToHead returns a player's record against an opponent by username
+	GetHeadToHead(playerID, opponent string) (*models.HeadToHead, error)
+}
+
+// PlayerGameHistory is how many recent games a GameStore indexes per player
+const PlayerGameHistory = 50
+
 // GameStore handles game session persistence
 type GameStore interface {
 	CreateGame(game *models.GameSession) error
@@ -21,12 +129,76 @@ type GameStore interface {
 	DeleteGame(id string) error
 	GetActiveGames() ([]*models.GameSession, error)
 	GetAllGames() ([]*models.GameSession, error)
+
+	// GetLastGame returns the most recently created game a player was in
+	GetLastGame(playerID string) (*models.GameSession, error)
+	// GetPlayerGames returns up to limit of a player's most recent games,
+	// newest first. Games that have since expired are skipped.
+	GetPlayerGames(playerID string, limit int) ([]*models.GameSession, error)
+
+	// Match series group the games of a best-of-N match
+	CreateSeries(series *models.MatchSeries) error
+	GetSeries(id string) (*models.MatchSeries, error)
+	UpdateSeries(series *models.MatchSeries) error
+	DeleteSeries(id string) error
 }
 
-// QueueStore handles matchmaking queue
+// MaxReplayEvents is how many events a ReplayStore records per game. Later
+// events are dropped and the replay is marked truncated.
+const MaxReplayEvents = 20000
+
+// DefaultReplayRetention is how long a ReplayStore keeps a replay after it
+// was last written, unless configured otherwise
+const DefaultReplayRetention = 7 * 24 * time.Hour
+
+// ReplayStore records each game's event log for playback. Replays expire once
+// they haven't been written for the store's retention period.
+type ReplayStore interface {
+	// CreateReplay starts recording a game; the replay should have no events
+	CreateReplay(replay *models.Replay) error
+	// AppendReplayEvent adds an event to a game's replay, returning
+	// ErrReplayFull once it holds MaxReplayEvents
+	AppendReplayEvent(gameID string, event *models.ReplayEvent) error
+	// FinishReplay records a finished game's winner and final seats
+	FinishReplay(gameID, winnerID string, players []*models.ReplayPlayer) error
+	// GetReplay returns a replay with all of its events
+	GetReplay(gameID string) (*models.Replay, error)
+	// DeleteReplay removes a replay and its events, returning
+	// ErrReplayNotFound if there is none
+	DeleteReplay(gameID string) error
+	// DeleteExpiredReplays removes replays past their retention, returning
+	// how many it removed. Stores whose backend expires them itself may
+	// always return zero.
+	DeleteExpiredReplays() (int, error)
+}
+
+// QueueStore handles matchmaking queues. Each queue is identified by a key
+// (see models.QueueOptions.Key) and keeps players in join order.
 type QueueStore interface {
-	AddToQueue(playerID string) error
-	RemoveFromQueue(playerID string) error
-	GetQueuedPlayers() ([]string, error)
-	GetQueuePosition(playerID string) (int, error)
+	AddToQueue(queue, playerID string) error
+	RemoveFromQueue(queue, playerID string) error
+	GetQueuedPlayers(queue string) ([]string, error)
+	GetQueuePosition(queue, playerID string) (int, error)
+	// RemovePair atomically removes two players only if both are still
+	// queued, reporting whether it did. A player is never claimed twice,
+	// even when several instances match from the same queue.
+	RemovePair(queue, player1ID, player2ID string) (bool, error)
+	// RestoreToQueue puts a claimed player back where they were: right
+	// behind the last of ahead (the players queued ahead of them when they
+	// were claimed) that is still queued, or at the front if none are
+	RestoreToQueue(queue, playerID string, ahead []string) error
+}
+
+// RoomStore handles private rooms
+type RoomStore interface {
+	// CreateRoom stores a new room, returning ErrRoomCodeTaken if its code is in use
+	CreateRoom(room *models.Room) error
+	GetRoom(code string) (*models.Room, error)
+	// JoinRoom atomically seats a guest if the room has a free seat,
+	// returning ErrRoomFull if other guests took them first
+	JoinRoom(code, playerID string) (*models.Room, error)
+	// TakeRoom atomically removes and returns a room, so only one caller
+	// can start its match
+	TakeRoom(code string) (*models.Room, error)
+	DeleteRoom(code string) error
 }
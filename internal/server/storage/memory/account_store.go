@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+// AccountStore implements in-memory account storage. Accounts are returned as
+// copies so credential changes only take effect through UpdateAccount.
+type AccountStore struct {
+	accounts map[string]*models.Account // username -> account
+	mu       sync.RWMutex
+}
+
+// NewAccountStore creates a new in-memory account store
+func NewAccountStore() *AccountStore {
+	return &AccountStore{
+		accounts: make(map[string]*models.Account),
+	}
+}
+
+// CreateAccount stores a new account
+func (s *AccountStore) CreateAccount(account *models.Account) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.accounts[account.Username]; exists {
+		return storage.ErrAccountExists
+	}
+
+	copied := *account
+	s.accounts[account.Username] = &copied
+	return nil
+}
+
+// GetAccount retrieves an account by username
+func (s *AccountStore) GetAccount(username string) (*models.Account, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	account, exists := s.accounts[username]
+	if !exists {
+		return nil, storage.ErrAccountNotFound
+	}
+
+	copied := *account
+	return &copied, nil
+}
+
+// UpdateAccount updates an existing account
+func (s *AccountStore) UpdateAccount(account *models.Account) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.accounts[account.Username]; !exists {
+		return storage.ErrAccountNotFound
+	}
+
+	copied := *account
+	s.accounts[account.Username] = &copied
+	return nil
+}
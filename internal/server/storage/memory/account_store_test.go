@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func TestAccountStore(t *testing.T) {
+	store := NewAccountStore()
+
+	if _, err := store.GetAccount("alice"); !errors.Is(err, storage.ErrAccountNotFound) {
+		t.Errorf("Expected ErrAccountNotFound, got %v", err)
+	}
+	if err := store.UpdateAccount(&models.Account{Username: "alice"}); !errors.Is(err, storage.ErrAccountNotFound) {
+		t.Errorf("Expected ErrAccountNotFound, got %v", err)
+	}
+
+	account := &models.Account{Username: "alice", PlayerID: "p1", PasswordHash: "hash"}
+	if err := store.CreateAccount(account); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := store.CreateAccount(account); !errors.Is(err, storage.ErrAccountExists) {
+		t.Errorf("Expected ErrAccountExists, got %v", err)
+	}
+
+	// Changes only stick through UpdateAccount
+	retrieved, _ := store.GetAccount("alice")
+	retrieved.APIKeyHash = "key"
+	if stored, _ := store.GetAccount("alice"); stored.APIKeyHash != "" {
+		t.Error("Expected the store to return a copy")
+	}
+	if err := store.UpdateAccount(retrieved); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stored, _ := store.GetAccount("alice"); stored.APIKeyHash != "key" || stored.PlayerID != "p1" {
+		t.Errorf("Expected updated account, got %+v", stored)
+	}
+}
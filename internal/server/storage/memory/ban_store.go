@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+// BanStore implements in-memory ban storage
+type BanStore struct {
+	bans map[string]*models.Ban // username -> ban
+	mu   sync.RWMutex
+}
+
+// NewBanStore creates a new in-memory ban store
+func NewBanStore() *BanStore {
+	return &BanStore{
+		bans: make(map[string]*models.Ban),
+	}
+}
+
+// Ban adds or replaces a ban
+func (s *BanStore) Ban(ban *models.Ban) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := *ban
+	s.bans[ban.Username] = &copied
+	return nil
+}
+
+// Unban lifts a ban
+func (s *BanStore) Unban(username string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.bans[username]; !exists {
+		return storage.ErrBanNotFound
+	}
+	delete(s.bans, username)
+	return nil
+}
+
+// GetBan retrieves a username's ban
+func (s *BanStore) GetBan(username string) (*models.Ban, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ban, exists := s.bans[username]
+	if !exists {
+		return nil, storage.ErrBanNotFound
+	}
+
+	copied := *ban
+	return &copied, nil
+}
+
+// ListBans returns every ban, oldest first
+func (s *BanStore) ListBans() ([]*models.Ban, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	bans := make([]*models.Ban, 0, len(s.bans))
+	for _, ban := range s.bans {
+		copied := *ban
+		bans = append(bans, &copied)
+	}
+
+	slices.SortFunc(bans, func(a, b *models.Ban) int {
+		if c := a.BannedAt.Compare(b.BannedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Username, b.Username)
+	})
+	return bans, nil
+}
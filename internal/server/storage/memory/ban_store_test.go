@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func TestBanStore(t *testing.T) {
+	store := NewBanStore()
+
+	if _, err := store.GetBan("alice"); !errors.Is(err, storage.ErrBanNotFound) {
+		t.Errorf("Expected ErrBanNotFound, got %v", err)
+	}
+	if err := store.Unban("alice"); !errors.Is(err, storage.ErrBanNotFound) {
+		t.Errorf("Expected ErrBanNotFound, got %v", err)
+	}
+
+	now := time.Now()
+	if err := store.Ban(&models.Ban{Username: "bob", Reason: "spam", BannedAt: now.Add(time.Minute)}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := store.Ban(&models.Ban{Username: "alice", Reason: "cheating", BannedAt: now}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ban, err := store.GetBan("alice")
+	if err != nil || ban.Reason != "cheating" {
+		t.Fatalf("Expected alice's ban, got %+v (%v)", ban, err)
+	}
+
+	bans, _ := store.ListBans()
+	if len(bans) != 2 || bans[0].Username != "alice" || bans[1].Username != "bob" {
+		t.Errorf("Expected bans oldest first, got %+v", bans)
+	}
+
+	if err := store.Unban("alice"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := store.GetBan("alice"); !errors.Is(err, storage.ErrBanNotFound) {
+		t.Errorf("Expected the ban to be lifted, got %v", err)
+	}
+}
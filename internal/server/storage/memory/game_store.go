@@ -2,21 +2,27 @@ package memory
 
 import (
 	"errors"
+	"slices"
 	"sync"
 
+	"github.com/briancain/go-tetris/internal/server/storage"
 	"github.com/briancain/go-tetris/pkg/models"
 )
 
 // GameStore implements in-memory game session storage
 type GameStore struct {
-	games map[string]*models.GameSession
-	mu    sync.RWMutex
+	games       map[string]*models.GameSession
+	series      map[string]*models.MatchSeries
+	playerGames map[string][]string // playerID -> recent game IDs, newest first
+	mu          sync.RWMutex
 }
 
 // NewGameStore creates a new in-memory game store
 func NewGameStore() *GameStore {
 	return &GameStore{
-		games: make(map[string]*models.GameSession),
+		games:       make(map[string]*models.GameSession),
+		series:      make(map[string]*models.MatchSeries),
+		playerGames: make(map[string][]string),
 	}
 }
 
@@ -30,9 +36,22 @@ func (s *GameStore) CreateGame(game *models.GameSession) error {
 	}
 
 	s.games[game.ID] = game
+	for _, playerID := range game.PlayerIDs() {
+		s.indexPlayerGame(playerID, game.ID)
+	}
 	return nil
 }
 
+// indexPlayerGame records a game as a player's most recent. The caller must
+// hold the write lock.
+func (s *GameStore) indexPlayerGame(playerID, gameID string) {
+	gameIDs := append([]string{gameID}, s.playerGames[playerID]...)
+	if len(gameIDs) > storage.PlayerGameHistory {
+		gameIDs = gameIDs[:storage.PlayerGameHistory]
+	}
+	s.playerGames[playerID] = gameIDs
+}
+
 // GetGame retrieves a game session by ID
 func (s *GameStore) GetGame(id string) (*models.GameSession, error) {
 	s.mu.RLock()
@@ -64,14 +83,31 @@ func (s *GameStore) DeleteGame(id string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, exists := s.games[id]; !exists {
+	game, exists := s.games[id]
+	if !exists {
 		return errors.New("game not found")
 	}
 
 	delete(s.games, id)
+	for _, playerID := range game.PlayerIDs() {
+		s.unindexPlayerGame(playerID, id)
+	}
 	return nil
 }
 
+// unindexPlayerGame drops a deleted game from a player's recent games,
+// forgetting the player once none are left. The caller must hold the write lock.
+func (s *GameStore) unindexPlayerGame(playerID, gameID string) {
+	gameIDs := slices.DeleteFunc(s.playerGames[playerID], func(id string) bool {
+		return id == gameID
+	})
+	if len(gameIDs) == 0 {
+		delete(s.playerGames, playerID)
+		return
+	}
+	s.playerGames[playerID] = gameIDs
+}
+
 // GetActiveGames returns all active game sessions
 func (s *GameStore) GetActiveGames() ([]*models.GameSession, error) {
 	s.mu.RLock()
@@ -79,7 +115,7 @@ func (s *GameStore) GetActiveGames() ([]*models.GameSession, error) {
 
 	var activeGames []*models.GameSession
 	for _, game := range s.games {
-		if game.Status == models.GameStatusActive || game.Status == models.GameStatusWaiting {
+		if game.Status == models.GameStatusActive || game.Status == models.GameStatusWaiting || game.Status == models.GameStatusPaused {
 			activeGames = append(activeGames, game)
 		}
 	}
@@ -99,3 +135,85 @@ func (s *GameStore) GetAllGames() ([]*models.GameSession, error) {
 
 	return allGames, nil
 }
+
+// GetLastGame returns the most recently created game a player was in
+func (s *GameStore) GetLastGame(playerID string) (*models.GameSession, error) {
+	games, err := s.GetPlayerGames(playerID, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(games) == 0 {
+		return nil, errors.New("game not found")
+	}
+	return games[0], nil
+}
+
+// GetPlayerGames returns up to limit of a player's most recent games, newest first
+func (s *GameStore) GetPlayerGames(playerID string, limit int) ([]*models.GameSession, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var games []*models.GameSession
+	for _, gameID := range s.playerGames[playerID] {
+		if len(games) >= limit {
+			break
+		}
+		if game, exists := s.games[gameID]; exists {
+			games = append(games, game)
+		}
+	}
+
+	return games, nil
+}
+
+// CreateSeries stores a new match series
+func (s *GameStore) CreateSeries(series *models.MatchSeries) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.series[series.ID]; exists {
+		return errors.New("series already exists")
+	}
+
+	s.series[series.ID] = series
+	return nil
+}
+
+// GetSeries retrieves a match series by ID
+func (s *GameStore) GetSeries(id string) (*models.MatchSeries, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	series, exists := s.series[id]
+	if !exists {
+		return nil, errors.New("series not found")
+	}
+
+	return series, nil
+}
+
+// UpdateSeries updates an existing match series
+func (s *GameStore) UpdateSeries(series *models.MatchSeries) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.series[series.ID]; !exists {
+		return errors.New("series not found")
+	}
+
+	s.series[series.ID] = series
+	return nil
+}
+
+// DeleteSeries removes a match series
+func (s *GameStore) DeleteSeries(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.series[id]; !exists {
+		return errors.New("series not found")
+	}
+
+	delete(s.series, id)
+	return nil
+}
@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func TestGameStore_PlayerGameIndex(t *testing.T) {
+	store := NewGameStore()
+
+	alice := &models.Player{ID: "alice"}
+	bob := &models.Player{ID: "bob"}
+	carol := &models.Player{ID: "carol"}
+
+	store.CreateGame(&models.GameSession{ID: "game1", Players: models.NewSeats(alice, bob)})
+	store.CreateGame(&models.GameSession{ID: "game2", Players: models.NewSeats(carol, alice)})
+	store.CreateGame(&models.GameSession{ID: "game3", Players: models.NewSeats(bob, carol)})
+
+	last, err := store.GetLastGame("alice")
+	if err != nil {
+		t.Fatalf("GetLastGame failed: %v", err)
+	}
+	if last.ID != "game2" {
+		t.Errorf("Expected alice's last game to be game2, got %s", last.ID)
+	}
+
+	games, err := store.GetPlayerGames("bob", 10)
+	if err != nil {
+		t.Fatalf("GetPlayerGames failed: %v", err)
+	}
+	if len(games) != 2 || games[0].ID != "game3" || games[1].ID != "game1" {
+		t.Errorf("Expected bob's games newest first [game3 game1], got %v", gameIDs(games))
+	}
+
+	// Deleted games drop out of the index
+	store.DeleteGame("game2")
+	last, err = store.GetLastGame("alice")
+	if err != nil || last.ID != "game1" {
+		t.Errorf("Expected alice's last game to fall back to game1, got %v (err %v)", last, err)
+	}
+
+	if _, err := store.GetLastGame("nobody"); err == nil {
+		t.Error("Expected error for a player without games")
+	}
+
+	// Players are forgotten once their last game is deleted
+	store.DeleteGame("game1")
+	store.DeleteGame("game3")
+	if len(store.playerGames) != 0 {
+		t.Errorf("Expected an empty index once every game is deleted, got %v", store.playerGames)
+	}
+}
+
+func TestGameStore_DeleteSeries(t *testing.T) {
+	store := NewGameStore()
+	store.CreateSeries(&models.MatchSeries{ID: "series1", BestOf: 3})
+
+	if err := store.DeleteSeries("series1"); err != nil {
+		t.Fatalf("DeleteSeries failed: %v", err)
+	}
+	if _, err := store.GetSeries("series1"); err == nil {
+		t.Error("Expected the deleted series to be gone")
+	}
+	if err := store.DeleteSeries("series1"); err == nil {
+		t.Error("Expected error deleting a missing series")
+	}
+}
+
+func gameIDs(games []*models.GameSession) []string {
+	ids := make([]string, len(games))
+	for i, game := range games {
+		ids[i] = game.ID
+	}
+	return ids
+}
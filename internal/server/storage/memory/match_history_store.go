@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+// MatchHistoryStore implements in-memory match history. Records are never
+// changed once added, so they are shared rather than copied.
+type MatchHistoryStore struct {
+	matches map[string][]*models.MatchRecord // playerID -> finished games, newest first
+	mu      sync.RWMutex
+}
+
+// NewMatchHistoryStore creates a new in-memory match history store
+func NewMatchHistoryStore() *MatchHistoryStore {
+	return &MatchHistoryStore{
+		matches: make(map[string][]*models.MatchRecord),
+	}
+}
+
+// AddPlayerMatch adds a finished game to the front of a player's history
+func (s *MatchHistoryStore) AddPlayerMatch(playerID string, match *models.MatchRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.matches[playerID] = append([]*models.MatchRecord{match}, s.matches[playerID]...)
+	return nil
+}
+
+// GetPlayerMatches returns a page of a player's history, newest first,
+// optionally only games against an opponent by username
+func (s *MatchHistoryStore) GetPlayerMatches(playerID, opponent string, offset, limit int) ([]*models.MatchRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matches := []*models.MatchRecord{}
+	skipped := 0
+	for _, match := range s.matches[playerID] {
+		if len(matches) >= limit {
+			break
+		}
+		if opponent != "" && !playedAgainst(match, playerID, opponent) {
+			continue
+		}
+		if skipped < offset {
+			skipped++
+			continue
+		}
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
+
+// GetHeadToHead returns a player's record against an opponent by username
+func (s *MatchHistoryStore) GetHeadToHead(playerID, opponent string) (*models.HeadToHead, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	record := &models.HeadToHead{}
+	for _, match := range s.matches[playerID] {
+		if playedAgainst(match, playerID, opponent) {
+			record.Add(match.Outcome(playerID))
+		}
+	}
+	return record, nil
+}
+
+// playedAgainst reports whether a player faced someone by username in a match
+func playedAgainst(match *models.MatchRecord, playerID, username string) bool {
+	for _, opponent := range match.Opponents(playerID) {
+		if opponent.Username == username {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func TestMatchHistoryStore(t *testing.T) {
+	store := NewMatchHistoryStore()
+
+	// Alice beats Bob, loses to Carol, then draws with Bob
+	match := func(id, opponentID, opponent string, alicePlacement, opponentPlacement int) *models.MatchRecord {
+		return &models.MatchRecord{GameID: id, Players: []*models.MatchPlayer{
+			{ID: "alice", Username: "Alice", Placement: alicePlacement},
+			{ID: opponentID, Username: opponent, Placement: opponentPlacement},
+		}}
+	}
+	store.AddPlayerMatch("alice", match("g1", "bob", "Bob", 1, 2))
+	store.AddPlayerMatch("alice", match("g2", "carol", "Carol", 2, 1))
+	store.AddPlayerMatch("alice", match("g3", "bob", "Bob", 0, 0))
+
+	page, err := store.GetPlayerMatches("alice", "", 1, 5)
+	if err != nil {
+		t.Fatalf("GetPlayerMatches failed: %v", err)
+	}
+	if len(page) != 2 || page[0].GameID != "g2" || page[1].GameID != "g1" {
+		t.Errorf("Expected g2 then g1 after skipping the newest, got %+v", page)
+	}
+
+	against, _ := store.GetPlayerMatches("alice", "Bob", 0, 5)
+	if len(against) != 2 || against[0].GameID != "g3" || against[1].GameID != "g1" {
+		t.Errorf("Expected the two games against Bob, got %+v", against)
+	}
+
+	record, err := store.GetHeadToHead("alice", "Bob")
+	if err != nil {
+		t.Fatalf("GetHeadToHead failed: %v", err)
+	}
+	if *record != (models.HeadToHead{Wins: 1, Draws: 1}) {
+		t.Errorf("Expected a 1-0-1 record against Bob, got %+v", record)
+	}
+
+	if empty, _ := store.GetPlayerMatches("bob", "", 0, 5); len(empty) != 0 {
+		t.Errorf("Expected no history for a player never added, got %+v", empty)
+	}
+}
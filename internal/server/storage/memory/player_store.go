@@ -3,7 +3,9 @@ package memory
 import (
 	"errors"
 	"sync"
+	"time"
 
+	"github.com/briancain/go-tetris/internal/server/storage"
 	"github.com/briancain/go-tetris/pkg/models"
 )
 
@@ -81,15 +83,23 @@ func (s *PlayerStore) GetPlayerByToken(token string) (*models.Player, error) {
 	return s.GetPlayer(playerID)
 }
 
-// UpdatePlayer updates an existing player
+// UpdatePlayer updates an existing player. A copy doesn't overwrite the
+// stored player's game or queue.
 func (s *PlayerStore) UpdatePlayer(player *models.Player) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, exists := s.players[player.ID]; !exists {
+	stored, exists := s.players[player.ID]
+	if !exists {
 		return errors.New("player not found")
 	}
 
+	if stored != player {
+		player.GameID = stored.GameID
+		player.InQueue = stored.InQueue
+		player.Queue = stored.Queue
+		player.QueuedAt = stored.QueuedAt
+	}
 	s.players[player.ID] = player
 	return nil
 }
@@ -121,3 +131,129 @@ func (s *PlayerStore) GetAllPlayers() ([]*models.Player, error) {
 
 	return players, nil
 }
+
+// RecordGameResult adds a finished game to a player's stats
+func (s *PlayerStore) RecordGameResult(playerID string, outcome storage.GameOutcome, score, placement int, rating models.Rating) (*models.Player, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	player, exists := s.players[playerID]
+	if !exists {
+		return nil, errors.New("player not found")
+	}
+
+	player.TotalGames++
+	switch outcome {
+	case storage.OutcomeWin:
+		player.Wins++
+	case storage.OutcomeLoss:
+		player.Losses++
+	}
+	if score > player.HighScore {
+		player.HighScore = score
+	}
+	if placement > 0 {
+		player.RoyaleGames++
+		player.PlacementTotal += placement
+	}
+	player.Rating = rating
+
+	return player, nil
+}
+
+// RecordSeriesResult adds a finished match series to a player's stats
+func (s *PlayerStore) RecordSeriesResult(playerID string, won bool) (*models.Player, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	player, exists := s.players[playerID]
+	if !exists {
+		return nil, errors.New("player not found")
+	}
+
+	player.SeriesPlayed++
+	if won {
+		player.SeriesWins++
+	}
+
+	return player, nil
+}
+
+// SetSessionToken replaces a player's session token
+func (s *PlayerStore) SetSessionToken(playerID, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	player, exists := s.players[playerID]
+	if !exists {
+		return errors.New("player not found")
+	}
+
+	delete(s.tokens, player.SessionToken)
+	player.SessionToken = token
+	if token != "" {
+		s.tokens[token] = playerID
+	}
+	return nil
+}
+
+// TouchPlayer records a player's activity
+func (s *PlayerStore) TouchPlayer(playerID string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	player, exists := s.players[playerID]
+	if !exists {
+		return errors.New("player not found")
+	}
+
+	player.LastActivity = at
+	return nil
+}
+
+// SetPlayerGameID routes a player to a game
+func (s *PlayerStore) SetPlayerGameID(playerID, gameID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	player, exists := s.players[playerID]
+	if !exists {
+		return errors.New("player not found")
+	}
+
+	player.GameID = gameID
+	return nil
+}
+
+// SetPlayerQueue marks a player as waiting in a queue
+func (s *PlayerStore) SetPlayerQueue(playerID, queue string, queuedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	player, exists := s.players[playerID]
+	if !exists {
+		return errors.New("player not found")
+	}
+
+	player.InQueue = true
+	player.Queue = queue
+	player.QueuedAt = queuedAt
+	return nil
+}
+
+// ClearPlayerQueue marks a player as no longer waiting in queue
+func (s *PlayerStore) ClearPlayerQueue(playerID, queue string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	player, exists := s.players[playerID]
+	if !exists {
+		return errors.New("player not found")
+	}
+
+	if queue == "" || player.Queue == queue {
+		player.InQueue = false
+		player.Queue = ""
+	}
+	return nil
+}
@@ -2,7 +2,9 @@ package memory
 
 import (
 	"testing"
+	"time"
 
+	"github.com/briancain/go-tetris/internal/server/storage"
 	"github.com/briancain/go-tetris/pkg/models"
 )
 
@@ -91,6 +93,35 @@ func TestPlayerStore_UpdatePlayer(t *testing.T) {
 	}
 }
 
+func TestPlayerStore_UpdatePlayerKeepsGameAndQueue(t *testing.T) {
+	store := NewPlayerStore()
+	store.CreatePlayer(&models.Player{ID: "test-id", Username: "testuser", SessionToken: "test-token"})
+
+	store.SetPlayerQueue("test-id", "classic", time.Now())
+	store.SetPlayerGameID("test-id", "game-1")
+
+	// A copy from before those changes doesn't undo them
+	stale := &models.Player{ID: "test-id", Username: "testuser", SessionToken: "test-token"}
+	if err := store.UpdatePlayer(stale); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	retrieved, _ := store.GetPlayer("test-id")
+	if retrieved.GameID != "game-1" || !retrieved.InQueue || retrieved.Queue != "classic" {
+		t.Errorf("Expected game and queue to survive update, got %+v", retrieved)
+	}
+
+	// Clearing another queue leaves the player waiting in theirs
+	store.ClearPlayerQueue("test-id", "sprint")
+	if retrieved, _ := store.GetPlayer("test-id"); retrieved.Queue != "classic" {
+		t.Errorf("Expected player to stay in classic, got %q", retrieved.Queue)
+	}
+	store.ClearPlayerQueue("test-id", "")
+	if retrieved, _ := store.GetPlayer("test-id"); retrieved.InQueue || retrieved.Queue != "" {
+		t.Errorf("Expected player out of the queue, got %+v", retrieved)
+	}
+}
+
 func TestPlayerStore_DeletePlayer(t *testing.T) {
 	store := NewPlayerStore()
 
@@ -121,3 +152,42 @@ func TestPlayerStore_DeletePlayer(t *testing.T) {
 		t.Error("Expected error for deleted player token")
 	}
 }
+
+func TestPlayerStore_RecordGameResult(t *testing.T) {
+	store := NewPlayerStore()
+
+	player := &models.Player{ID: "test-id", Username: "testuser", HighScore: 900}
+	store.CreatePlayer(player)
+
+	rating := models.Rating{Value: 1662.3, Deviation: 290.2, Volatility: 0.06}
+	updated, err := store.RecordGameResult("test-id", storage.OutcomeWin, 1200, 0, rating)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if updated.TotalGames != 1 || updated.Wins != 1 || updated.Losses != 0 {
+		t.Errorf("Expected 1 game and 1 win, got %d games, %d wins, %d losses", updated.TotalGames, updated.Wins, updated.Losses)
+	}
+	if updated.HighScore != 1200 {
+		t.Errorf("Expected high score 1200, got %d", updated.HighScore)
+	}
+	if updated.Rating != rating {
+		t.Errorf("Expected rating %+v, got %+v", rating, updated.Rating)
+	}
+
+	// Draws count as a game without a win or loss, and lower scores keep the high score
+	updated, _ = store.RecordGameResult("test-id", storage.OutcomeDraw, 100, 0, rating)
+	if updated.TotalGames != 2 || updated.Wins != 1 || updated.Losses != 0 || updated.HighScore != 1200 {
+		t.Errorf("Unexpected stats after draw: %+v", updated)
+	}
+
+	// Battle royale placements are averaged
+	store.RecordGameResult("test-id", storage.OutcomeLoss, 0, 3, rating)
+	updated, _ = store.RecordGameResult("test-id", storage.OutcomeWin, 0, 1, rating)
+	if updated.RoyaleGames != 2 || updated.AveragePlacement() != 2 {
+		t.Errorf("Expected 2 royale games averaging 2nd, got %d games averaging %v", updated.RoyaleGames, updated.AveragePlacement())
+	}
+
+	if _, err := store.RecordGameResult("missing", storage.OutcomeWin, 0, 0, rating); err == nil {
+		t.Error("Expected error for unknown player")
+	}
+}
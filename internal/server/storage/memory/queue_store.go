@@ -1,46 +1,48 @@
 package memory
 
 import (
+	"slices"
 	"sync"
 )
 
-// QueueStore implements in-memory matchmaking queue
+// QueueStore implements in-memory matchmaking queues
 type QueueStore struct {
-	queue []string
-	mu    sync.RWMutex
+	queues map[string][]string
+	mu     sync.RWMutex
 }
 
 // NewQueueStore creates a new in-memory queue store
 func NewQueueStore() *QueueStore {
 	return &QueueStore{
-		queue: make([]string, 0),
+		queues: make(map[string][]string),
 	}
 }
 
-// AddToQueue adds a player to the matchmaking queue
-func (s *QueueStore) AddToQueue(playerID string) error {
+// AddToQueue adds a player to a matchmaking queue
+func (s *QueueStore) AddToQueue(queue, playerID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	// Check if player is already in queue
-	for _, id := range s.queue {
+	for _, id := range s.queues[queue] {
 		if id == playerID {
 			return nil // Already in queue
 		}
 	}
 
-	s.queue = append(s.queue, playerID)
+	s.queues[queue] = append(s.queues[queue], playerID)
 	return nil
 }
 
-// RemoveFromQueue removes a player from the queue
-func (s *QueueStore) RemoveFromQueue(playerID string) error {
+// RemoveFromQueue removes a player from a queue
+func (s *QueueStore) RemoveFromQueue(queue, playerID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	for i, id := range s.queue {
+	players := s.queues[queue]
+	for i, id := range players {
 		if id == playerID {
-			s.queue = append(s.queue[:i], s.queue[i+1:]...)
+			s.setQueue(queue, append(players[:i], players[i+1:]...))
 			return nil
 		}
 	}
@@ -48,23 +50,23 @@ func (s *QueueStore) RemoveFromQueue(playerID string) error {
 	return nil // Not in queue, no error
 }
 
-// GetQueuedPlayers returns all players in the queue
-func (s *QueueStore) GetQueuedPlayers() ([]string, error) {
+// GetQueuedPlayers returns all players in a queue
+func (s *QueueStore) GetQueuedPlayers(queue string) ([]string, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	// Return a copy to avoid race conditions
-	result := make([]string, len(s.queue))
-	copy(result, s.queue)
+	result := make([]string, len(s.queues[queue]))
+	copy(result, s.queues[queue])
 	return result, nil
 }
 
-// GetQueuePosition returns the position of a player in the queue (0-based)
-func (s *QueueStore) GetQueuePosition(playerID string) (int, error) {
+// GetQueuePosition returns the position of a player in a queue (0-based)
+func (s *QueueStore) GetQueuePosition(queue, playerID string) (int, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	for i, id := range s.queue {
+	for i, id := range s.queues[queue] {
 		if id == playerID {
 			return i, nil
 		}
@@ -72,3 +74,57 @@ func (s *QueueStore) GetQueuePosition(playerID string) (int, error) {
 
 	return -1, nil // Not in queue
 }
+
+// RemovePair removes two players if both are still in the queue
+func (s *QueueStore) RemovePair(queue, player1ID, player2ID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	players := s.queues[queue]
+	remaining := make([]string, 0, len(players))
+	found := 0
+	for _, id := range players {
+		if id == player1ID || id == player2ID {
+			found++
+			continue
+		}
+		remaining = append(remaining, id)
+	}
+
+	if player1ID == player2ID || found != 2 {
+		return false, nil
+	}
+
+	s.setQueue(queue, remaining)
+	return true, nil
+}
+
+// RestoreToQueue puts a claimed player back behind the last of ahead still queued
+func (s *QueueStore) RestoreToQueue(queue, playerID string, ahead []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	players := slices.DeleteFunc(slices.Clone(s.queues[queue]), func(id string) bool {
+		return id == playerID
+	})
+
+	position := 0
+	for i := len(ahead) - 1; i >= 0; i-- {
+		if index := slices.Index(players, ahead[i]); index >= 0 {
+			position = index + 1
+			break
+		}
+	}
+
+	s.setQueue(queue, slices.Insert(players, position, playerID))
+	return nil
+}
+
+// setQueue replaces a queue's players, dropping empty queues
+func (s *QueueStore) setQueue(queue string, players []string) {
+	if len(players) == 0 {
+		delete(s.queues, queue)
+		return
+	}
+	s.queues[queue] = players
+}
@@ -1,25 +1,30 @@
 package memory
 
 import (
+	"fmt"
+	"slices"
+	"sync"
 	"testing"
 )
 
+const testQueue = "ranked:classic"
+
 func TestQueueStore_AddAndGet(t *testing.T) {
 	store := NewQueueStore()
 
 	// Add players to queue
-	err := store.AddToQueue("player1")
+	err := store.AddToQueue(testQueue, "player1")
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
 
-	err = store.AddToQueue("player2")
+	err = store.AddToQueue(testQueue, "player2")
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
 
 	// Get queued players
-	players, err := store.GetQueuedPlayers()
+	players, err := store.GetQueuedPlayers(testQueue)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -37,12 +42,12 @@ func TestQueueStore_GetPosition(t *testing.T) {
 	store := NewQueueStore()
 
 	// Add players
-	store.AddToQueue("player1")
-	store.AddToQueue("player2")
-	store.AddToQueue("player3")
+	store.AddToQueue(testQueue, "player1")
+	store.AddToQueue(testQueue, "player2")
+	store.AddToQueue(testQueue, "player3")
 
 	// Check positions
-	pos1, err := store.GetQueuePosition("player1")
+	pos1, err := store.GetQueuePosition(testQueue, "player1")
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -50,7 +55,7 @@ func TestQueueStore_GetPosition(t *testing.T) {
 		t.Errorf("Expected position 0 for player1, got %d", pos1)
 	}
 
-	pos2, err := store.GetQueuePosition("player2")
+	pos2, err := store.GetQueuePosition(testQueue, "player2")
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -59,7 +64,7 @@ func TestQueueStore_GetPosition(t *testing.T) {
 	}
 
 	// Check non-existent player
-	pos, err := store.GetQueuePosition("nonexistent")
+	pos, err := store.GetQueuePosition(testQueue, "nonexistent")
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -72,18 +77,18 @@ func TestQueueStore_RemoveFromQueue(t *testing.T) {
 	store := NewQueueStore()
 
 	// Add players
-	store.AddToQueue("player1")
-	store.AddToQueue("player2")
-	store.AddToQueue("player3")
+	store.AddToQueue(testQueue, "player1")
+	store.AddToQueue(testQueue, "player2")
+	store.AddToQueue(testQueue, "player3")
 
 	// Remove middle player
-	err := store.RemoveFromQueue("player2")
+	err := store.RemoveFromQueue(testQueue, "player2")
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
 
 	// Check remaining players
-	players, _ := store.GetQueuedPlayers()
+	players, _ := store.GetQueuedPlayers(testQueue)
 	if len(players) != 2 {
 		t.Errorf("Expected 2 players after removal, got %d", len(players))
 	}
@@ -93,7 +98,7 @@ func TestQueueStore_RemoveFromQueue(t *testing.T) {
 	}
 
 	// Check positions updated
-	pos3, _ := store.GetQueuePosition("player3")
+	pos3, _ := store.GetQueuePosition(testQueue, "player3")
 	if pos3 != 1 {
 		t.Errorf("Expected player3 to be at position 1, got %d", pos3)
 	}
@@ -103,12 +108,133 @@ func TestQueueStore_AddDuplicate(t *testing.T) {
 	store := NewQueueStore()
 
 	// Add player twice
-	store.AddToQueue("player1")
-	store.AddToQueue("player1")
+	store.AddToQueue(testQueue, "player1")
+	store.AddToQueue(testQueue, "player1")
 
 	// Should only be in queue once
-	players, _ := store.GetQueuedPlayers()
+	players, _ := store.GetQueuedPlayers(testQueue)
 	if len(players) != 1 {
 		t.Errorf("Expected 1 player (no duplicates), got %d", len(players))
 	}
 }
+
+func TestQueueStore_RemovePairConcurrent(t *testing.T) {
+	store := NewQueueStore()
+
+	const numPlayers = 1000
+	for i := 0; i < numPlayers; i++ {
+		store.AddToQueue(testQueue, fmt.Sprintf("player%d", i))
+	}
+
+	// Many matchmakers race to claim the same players; each must be claimed once
+	var mu sync.Mutex
+	seen := make(map[string]int)
+	var wg sync.WaitGroup
+	for w := 0; w < 16; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				queued, err := store.GetQueuedPlayers(testQueue)
+				if err != nil {
+					t.Errorf("GetQueuedPlayers failed: %v", err)
+					return
+				}
+				if len(queued) < 2 {
+					return
+				}
+				claimed, err := store.RemovePair(testQueue, queued[0], queued[1])
+				if err != nil {
+					t.Errorf("RemovePair failed: %v", err)
+					return
+				}
+				if claimed {
+					mu.Lock()
+					seen[queued[0]]++
+					seen[queued[1]]++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != numPlayers {
+		t.Errorf("Expected %d players claimed, got %d", numPlayers, len(seen))
+	}
+	for playerID, count := range seen {
+		if count != 1 {
+			t.Errorf("Player %s claimed %d times", playerID, count)
+		}
+	}
+}
+
+func TestQueueStore_RemovePairRequiresBothQueued(t *testing.T) {
+	store := NewQueueStore()
+	store.AddToQueue(testQueue, "player1")
+	store.AddToQueue(testQueue, "player2")
+
+	claimed, err := store.RemovePair(testQueue, "player1", "missing")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if claimed {
+		t.Error("Expected no claim when one player isn't queued")
+	}
+
+	// The queued player keeps their place
+	if pos, _ := store.GetQueuePosition(testQueue, "player1"); pos != 0 {
+		t.Errorf("Expected player1 to stay queued, got position %d", pos)
+	}
+
+	claimed, _ = store.RemovePair(testQueue, "player2", "player1")
+	if !claimed {
+		t.Error("Expected both queued players to be claimed")
+	}
+	if players, _ := store.GetQueuedPlayers(testQueue); len(players) != 0 {
+		t.Errorf("Expected empty queue, got %v", players)
+	}
+}
+
+func TestQueueStore_RestoreToQueue(t *testing.T) {
+	store := NewQueueStore()
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		store.AddToQueue(testQueue, id)
+	}
+	store.RemovePair(testQueue, "b", "d")
+
+	// The queue moves on before the match falls through
+	store.RemoveFromQueue(testQueue, "a")
+	store.AddToQueue(testQueue, "f")
+
+	store.RestoreToQueue(testQueue, "d", []string{"a", "b", "c"})
+	store.RestoreToQueue(testQueue, "b", []string{"a"})
+
+	players, _ := store.GetQueuedPlayers(testQueue)
+	if !slices.Equal(players, []string{"b", "c", "d", "e", "f"}) {
+		t.Errorf("Expected claimed players back in their places, got %v", players)
+	}
+}
+
+func TestQueueStore_QueuesAreIndependent(t *testing.T) {
+	store := NewQueueStore()
+
+	store.AddToQueue("ranked:classic", "player1")
+	store.AddToQueue("casual:classic", "player2")
+	store.AddToQueue("casual:classic", "player3")
+
+	ranked, _ := store.GetQueuedPlayers("ranked:classic")
+	if len(ranked) != 1 || ranked[0] != "player1" {
+		t.Errorf("Expected [player1] in ranked queue, got %v", ranked)
+	}
+
+	// Players in different queues can't be claimed together
+	claimed, _ := store.RemovePair("casual:classic", "player1", "player2")
+	if claimed {
+		t.Error("Expected no claim across queues")
+	}
+
+	if pos, _ := store.GetQueuePosition("casual:classic", "player3"); pos != 1 {
+		t.Errorf("Expected player3 at position 1 in casual queue, got %d", pos)
+	}
+}
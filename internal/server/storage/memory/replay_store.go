@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+// ReplayStore implements in-memory replay storage. Replays are returned as
+// copies since events keep being appended while a game is running. Like the
+// Redis store, a replay expires once it hasn't been written for the
+// retention period.
+type ReplayStore struct {
+	replays   map[string]*models.Replay
+	expires   map[string]time.Time
+	retention time.Duration
+	mu        sync.RWMutex
+}
+
+// NewReplayStore creates a new in-memory replay store keeping replays for
+// storage.DefaultReplayRetention
+func NewReplayStore() *ReplayStore {
+	return &ReplayStore{
+		replays:   make(map[string]*models.Replay),
+		expires:   make(map[string]time.Time),
+		retention: storage.DefaultReplayRetention,
+	}
+}
+
+// SetRetention sets how long replays are kept after they were last written
+func (s *ReplayStore) SetRetention(retention time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.retention = retention
+}
+
+// CreateReplay starts recording a game
+func (s *ReplayStore) CreateReplay(replay *models.Replay) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.replays[replay.GameID] = copyReplay(replay)
+	s.expires[replay.GameID] = time.Now().Add(s.retention)
+	return nil
+}
+
+// AppendReplayEvent adds an event to a game's replay
+func (s *ReplayStore) AppendReplayEvent(gameID string, event *models.ReplayEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	replay, exists := s.replay(gameID)
+	if !exists {
+		return storage.ErrReplayNotFound
+	}
+	if len(replay.Events) >= storage.MaxReplayEvents {
+		replay.Truncated = true
+		return storage.ErrReplayFull
+	}
+
+	replay.Events = append(replay.Events, event)
+	s.expires[gameID] = time.Now().Add(s.retention)
+	return nil
+}
+
+// FinishReplay records a finished game's result. The replay is kept for the
+// retention period from now.
+func (s *ReplayStore) FinishReplay(gameID, winnerID string, players []*models.ReplayPlayer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	replay, exists := s.replay(gameID)
+	if !exists {
+		return storage.ErrReplayNotFound
+	}
+
+	replay.WinnerID = winnerID
+	replay.Players = players
+	replay.Finished = true
+	replay.EndedAt = time.Now()
+	s.expires[gameID] = replay.EndedAt.Add(s.retention)
+	return nil
+}
+
+// GetReplay retrieves a game's replay
+func (s *ReplayStore) GetReplay(gameID string) (*models.Replay, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	replay, exists := s.replay(gameID)
+	if !exists {
+		return nil, storage.ErrReplayNotFound
+	}
+
+	return copyReplay(replay), nil
+}
+
+// DeleteReplay removes a game's replay
+func (s *ReplayStore) DeleteReplay(gameID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.replay(gameID); !exists {
+		return storage.ErrReplayNotFound
+	}
+
+	delete(s.replays, gameID)
+	delete(s.expires, gameID)
+	return nil
+}
+
+// DeleteExpiredReplays removes replays past their retention
+func (s *ReplayStore) DeleteExpiredReplays() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	deleted := 0
+	for gameID, expires := range s.expires {
+		if now.Before(expires) {
+			continue
+		}
+		delete(s.replays, gameID)
+		delete(s.expires, gameID)
+		deleted++
+	}
+	return deleted, nil
+}
+
+// replay returns a game's replay unless it has expired. The caller must hold s.mu.
+func (s *ReplayStore) replay(gameID string) (*models.Replay, bool) {
+	replay, exists := s.replays[gameID]
+	if !exists || !time.Now().Before(s.expires[gameID]) {
+		return nil, false
+	}
+	return replay, true
+}
+
+// copyReplay copies a replay's fields and slices. Events and players are
+// never changed once stored, so they are shared.
+func copyReplay(replay *models.Replay) *models.Replay {
+	result := *replay
+	result.Players = append([]*models.ReplayPlayer(nil), replay.Players...)
+	result.Events = append([]*models.ReplayEvent(nil), replay.Events...)
+	return &result
+}
@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func TestReplayStore_RecordAndFinish(t *testing.T) {
+	store := NewReplayStore()
+
+	if err := store.AppendReplayEvent("missing", &models.ReplayEvent{}); !errors.Is(err, storage.ErrReplayNotFound) {
+		t.Errorf("Expected ErrReplayNotFound, got %v", err)
+	}
+
+	replay := &models.Replay{GameID: "game1", Seed: 42, Players: []*models.ReplayPlayer{{ID: "p1"}, {ID: "p2"}}}
+	if err := store.CreateReplay(replay); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	store.AppendReplayEvent("game1", &models.ReplayEvent{Type: models.ReplayEventMove, PlayerID: "p1"})
+	store.AppendReplayEvent("game1", &models.ReplayEvent{Type: models.ReplayEventState, PlayerID: "p2"})
+
+	// A copy taken mid-game doesn't see later events
+	running, _ := store.GetReplay("game1")
+	store.AppendReplayEvent("game1", &models.ReplayEvent{Type: models.ReplayEventLost, PlayerID: "p2", Placement: 2})
+	if len(running.Events) != 2 || running.Finished {
+		t.Errorf("Expected an unfinished copy with 2 events, got %+v", running)
+	}
+
+	results := []*models.ReplayPlayer{{ID: "p1", Score: 900, Placement: 1}, {ID: "p2", Score: 300, Placement: 2}}
+	if err := store.FinishReplay("game1", "p1", results); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	finished, err := store.GetReplay("game1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !finished.Finished || finished.WinnerID != "p1" || finished.Players[0].Score != 900 || finished.EndedAt.IsZero() {
+		t.Errorf("Expected the result to be recorded, got %+v", finished)
+	}
+	if len(finished.Events) != 3 || finished.Events[2].Type != models.ReplayEventLost {
+		t.Errorf("Expected 3 events ending with the elimination, got %d", len(finished.Events))
+	}
+
+	if err := store.DeleteReplay("game1"); err != nil {
+		t.Fatalf("DeleteReplay failed: %v", err)
+	}
+	if _, err := store.GetReplay("game1"); !errors.Is(err, storage.ErrReplayNotFound) {
+		t.Errorf("Expected ErrReplayNotFound after deleting, got %v", err)
+	}
+	if err := store.DeleteReplay("game1"); !errors.Is(err, storage.ErrReplayNotFound) {
+		t.Errorf("Expected ErrReplayNotFound deleting twice, got %v", err)
+	}
+}
+
+func TestReplayStore_Truncates(t *testing.T) {
+	store := NewReplayStore()
+	store.CreateReplay(&models.Replay{GameID: "long"})
+
+	for i := 0; i < storage.MaxReplayEvents; i++ {
+		if err := store.AppendReplayEvent("long", &models.ReplayEvent{Type: models.ReplayEventMove}); err != nil {
+			t.Fatalf("Expected event %d to be recorded, got %v", i, err)
+		}
+	}
+	if err := store.AppendReplayEvent("long", &models.ReplayEvent{}); !errors.Is(err, storage.ErrReplayFull) {
+		t.Errorf("Expected ErrReplayFull, got %v", err)
+	}
+
+	replay, _ := store.GetReplay("long")
+	if !replay.Truncated || len(replay.Events) != storage.MaxReplayEvents {
+		t.Errorf("Expected a truncated replay of %d events, got %d (truncated %v)", storage.MaxReplayEvents, len(replay.Events), replay.Truncated)
+	}
+}
+
+func TestReplayStore_Expires(t *testing.T) {
+	store := NewReplayStore()
+	store.SetRetention(time.Hour)
+	store.CreateReplay(&models.Replay{GameID: "kept"})
+
+	store.SetRetention(time.Millisecond)
+	store.CreateReplay(&models.Replay{GameID: "expired"})
+	time.Sleep(5 * time.Millisecond)
+
+	if _, err := store.GetReplay("expired"); !errors.Is(err, storage.ErrReplayNotFound) {
+		t.Errorf("Expected ErrReplayNotFound for an expired replay, got %v", err)
+	}
+	if err := store.AppendReplayEvent("expired", &models.ReplayEvent{}); !errors.Is(err, storage.ErrReplayNotFound) {
+		t.Errorf("Expected ErrReplayNotFound appending to an expired replay, got %v", err)
+	}
+
+	deleted, err := store.DeleteExpiredReplays()
+	if err != nil || deleted != 1 {
+		t.Errorf("Expected 1 expired replay deleted, got %d (%v)", deleted, err)
+	}
+	if _, err := store.GetReplay("kept"); err != nil {
+		t.Errorf("Expected the replay within its retention to be kept, got %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+// RoomStore implements in-memory private room storage. Rooms are returned as
+// copies so a caller can't seat a guest without going through JoinRoom.
+type RoomStore struct {
+	rooms map[string]*models.Room
+	mu    sync.Mutex
+}
+
+// NewRoomStore creates a new in-memory room store
+func NewRoomStore() *RoomStore {
+	return &RoomStore{
+		rooms: make(map[string]*models.Room),
+	}
+}
+
+// CreateRoom stores a new room
+func (s *RoomStore) CreateRoom(room *models.Room) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.rooms[room.Code]; exists {
+		return storage.ErrRoomCodeTaken
+	}
+
+	s.rooms[room.Code] = copyRoom(room)
+	return nil
+}
+
+// GetRoom retrieves a room by code
+func (s *RoomStore) GetRoom(code string) (*models.Room, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, exists := s.rooms[code]
+	if !exists {
+		return nil, storage.ErrRoomNotFound
+	}
+
+	return copyRoom(room), nil
+}
+
+// JoinRoom seats a guest if the room has a free seat
+func (s *RoomStore) JoinRoom(code, playerID string) (*models.Room, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, exists := s.rooms[code]
+	if !exists {
+		return nil, storage.ErrRoomNotFound
+	}
+	for _, guestID := range room.GuestIDs {
+		if guestID == playerID {
+			return copyRoom(room), nil
+		}
+	}
+	if room.Full() {
+		return nil, storage.ErrRoomFull
+	}
+
+	room.GuestIDs = append(room.GuestIDs, playerID)
+	return copyRoom(room), nil
+}
+
+// TakeRoom removes and returns a room
+func (s *RoomStore) TakeRoom(code string) (*models.Room, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, exists := s.rooms[code]
+	if !exists {
+		return nil, storage.ErrRoomNotFound
+	}
+
+	delete(s.rooms, code)
+	return room, nil
+}
+
+// DeleteRoom removes a room
+func (s *RoomStore) DeleteRoom(code string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.rooms, code)
+	return nil
+}
+
+// copyRoom copies a room, including its guest list
+func copyRoom(room *models.Room) *models.Room {
+	result := *room
+	result.GuestIDs = append([]string(nil), room.GuestIDs...)
+	return &result
+}
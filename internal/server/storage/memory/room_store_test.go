@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func TestRoomStore_JoinRoom(t *testing.T) {
+	store := NewRoomStore()
+
+	room := &models.Room{Code: "ABC234", HostID: "host", Settings: models.RoomSettings{Mode: "classic", MaxPlayers: 3}}
+	if err := store.CreateRoom(room); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := store.CreateRoom(room); !errors.Is(err, storage.ErrRoomCodeTaken) {
+		t.Errorf("Expected ErrRoomCodeTaken for duplicate code, got %v", err)
+	}
+
+	joined, err := store.JoinRoom("ABC234", "guest1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(joined.GuestIDs) != 1 || joined.GuestIDs[0] != "guest1" || joined.Full() {
+		t.Errorf("Unexpected room after join: %+v", joined)
+	}
+
+	// Joining twice keeps a single seat
+	if joined, _ = store.JoinRoom("ABC234", "guest1"); len(joined.GuestIDs) != 1 {
+		t.Errorf("Expected rejoining to be a no-op, got %+v", joined.GuestIDs)
+	}
+
+	joined, err = store.JoinRoom("ABC234", "guest2")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !joined.Full() {
+		t.Errorf("Expected room to be full, got %+v", joined.PlayerIDs())
+	}
+
+	if _, err := store.JoinRoom("ABC234", "guest3"); !errors.Is(err, storage.ErrRoomFull) {
+		t.Errorf("Expected ErrRoomFull, got %v", err)
+	}
+
+	store.DeleteRoom("ABC234")
+	if _, err := store.JoinRoom("ABC234", "guest2"); !errors.Is(err, storage.ErrRoomNotFound) {
+		t.Errorf("Expected ErrRoomNotFound after delete, got %v", err)
+	}
+}
+
+func TestRoomStore_TakeRoom(t *testing.T) {
+	store := NewRoomStore()
+
+	store.CreateRoom(&models.Room{Code: "TAKE23", HostID: "host", Settings: models.RoomSettings{MaxPlayers: 4}})
+	store.JoinRoom("TAKE23", "guest1")
+
+	room, err := store.TakeRoom("TAKE23")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(room.PlayerIDs()) != 2 {
+		t.Errorf("Expected host and guest, got %+v", room.PlayerIDs())
+	}
+
+	// A room can only be taken once
+	if _, err := store.TakeRoom("TAKE23"); !errors.Is(err, storage.ErrRoomNotFound) {
+		t.Errorf("Expected ErrRoomNotFound, got %v", err)
+	}
+}
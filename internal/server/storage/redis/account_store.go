@@ -0,0 +1,92 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+const accountKeyPrefix = "account:"
+
+// AccountStore implements Redis-based account storage. Each account is a hash
+// keyed by username with no TTL.
+type AccountStore struct {
+	client *Client
+}
+
+// NewAccountStore creates a new Redis account store
+func NewAccountStore(client *Client) *AccountStore {
+	return &AccountStore{client: client}
+}
+
+// CreateAccount stores a new account
+func (s *AccountStore) CreateAccount(account *models.Account) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	accountKey := accountKeyPrefix + account.Username
+
+	// Claim the username first so concurrent registrations can't share it
+	created, err := s.client.HSetNX(ctx, accountKey, "username", account.Username).Result()
+	if err != nil {
+		return err
+	}
+	if !created {
+		return storage.ErrAccountExists
+	}
+
+	return s.client.HSet(ctx, accountKey, accountFields(account)).Err()
+}
+
+// GetAccount retrieves an account by username
+func (s *AccountStore) GetAccount(username string) (*models.Account, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	values, err := s.client.HGetAll(ctx, accountKeyPrefix+username).Result()
+	if err != nil {
+		return nil, err
+	}
+	if values["playerId"] == "" {
+		return nil, storage.ErrAccountNotFound // Missing, or still being created
+	}
+
+	account := &models.Account{
+		Username:     values["username"],
+		PlayerID:     values["playerId"],
+		PasswordHash: values["passwordHash"],
+		APIKeyHash:   values["apiKeyHash"],
+	}
+	account.CreatedAt, _ = time.Parse(time.RFC3339Nano, values["createdAt"])
+	return account, nil
+}
+
+// UpdateAccount updates an existing account
+func (s *AccountStore) UpdateAccount(account *models.Account) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	accountKey := accountKeyPrefix + account.Username
+
+	exists, err := s.client.Exists(ctx, accountKey).Result()
+	if err != nil {
+		return err
+	}
+	if exists == 0 {
+		return storage.ErrAccountNotFound
+	}
+
+	return s.client.HSet(ctx, accountKey, accountFields(account)).Err()
+}
+
+// accountFields returns an account's hash fields
+func accountFields(account *models.Account) map[string]interface{} {
+	return map[string]interface{}{
+		"playerId":     account.PlayerID,
+		"passwordHash": account.PasswordHash,
+		"apiKeyHash":   account.APIKeyHash,
+		"createdAt":    account.CreatedAt.Format(time.RFC3339Nano),
+	}
+}
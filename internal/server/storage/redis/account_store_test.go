@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func TestAccountStore(t *testing.T) {
+	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	store := NewAccountStore(client)
+
+	account := &models.Account{
+		Username:     "redisaccount",
+		PlayerID:     "redis-account-player",
+		PasswordHash: "hash",
+		CreatedAt:    time.Now(),
+	}
+	err := store.CreateAccount(account)
+	if err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+	defer client.Del(t.Context(), accountKeyPrefix+account.Username)
+
+	if err := store.CreateAccount(account); !errors.Is(err, storage.ErrAccountExists) {
+		t.Errorf("Expected ErrAccountExists, got %v", err)
+	}
+	if _, err := store.GetAccount("redisnobody"); !errors.Is(err, storage.ErrAccountNotFound) {
+		t.Errorf("Expected ErrAccountNotFound, got %v", err)
+	}
+	if err := store.UpdateAccount(&models.Account{Username: "redisnobody"}); !errors.Is(err, storage.ErrAccountNotFound) {
+		t.Errorf("Expected ErrAccountNotFound, got %v", err)
+	}
+
+	account.APIKeyHash = "key"
+	if err := store.UpdateAccount(account); err != nil {
+		t.Fatalf("UpdateAccount failed: %v", err)
+	}
+
+	retrieved, err := store.GetAccount(account.Username)
+	if err != nil {
+		t.Fatalf("GetAccount failed: %v", err)
+	}
+	if retrieved.PlayerID != account.PlayerID || retrieved.PasswordHash != "hash" || retrieved.APIKeyHash != "key" {
+		t.Errorf("Expected %+v, got %+v", account, retrieved)
+	}
+	if !retrieved.CreatedAt.Equal(account.CreatedAt) {
+		t.Errorf("Expected createdAt %v, got %v", account.CreatedAt, retrieved.CreatedAt)
+	}
+}
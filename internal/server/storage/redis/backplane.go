@@ -0,0 +1,372 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/briancain/go-tetris/internal/server/logger"
+)
+
+const (
+	presenceKeyPrefix     = "presence:"
+	disconnectKeyPrefix   = "disconnect:"
+	instanceChannelPrefix = "instance:"
+	broadcastChannel      = "broadcast"
+	presenceTTL           = 60 * time.Second // Presence outlives a crashed instance by at most this long
+	presenceRefresh       = 20 * time.Second
+)
+
+// deleteOwnedScript deletes a key only if it still names this instance, so a
+// late disconnect can't erase a reconnect on another instance and an expiring
+// grace timer can't clear a hold set after a later disconnect
+var deleteOwnedScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// refreshOwnedScript extends a key's TTL (ARGV[2], in milliseconds) only if it
+// still names this instance, so refreshing can't take back a player who has
+// since reconnected to another instance
+var refreshOwnedScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// routedMessage is the envelope published between instances. A close asks
+// the instance holding the player's socket to close it with Reason instead
+// of delivering Message.
+type routedMessage struct {
+	PlayerID string          `json:"playerId,omitempty"`
+	Message  json.RawMessage `json:"message,omitempty"`
+	Close    bool            `json:"close,omitempty"`
+	Reason   string          `json:"reason,omitempty"`
+}
+
+// Backplane routes WebSocket messages between server instances. Each player's
+// presence key names the instance holding their socket, and every instance
+// subscribes to its own channel for messages routed to it and to a shared
+// channel for messages to every player.
+type Backplane struct {
+	client     *Client
+	instanceID string
+
+	mu      sync.Mutex
+	players map[string]struct{} // Players registered by this instance
+	pubsub  *redis.PubSub
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewBackplane creates a backplane with a unique ID for this instance
+func NewBackplane(client *Client) *Backplane {
+	return &Backplane{
+		client:     client,
+		instanceID: newInstanceID(),
+		players:    make(map[string]struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// InstanceID returns the ID other instances use to route messages here
+func (b *Backplane) InstanceID() string {
+	return b.instanceID
+}
+
+// Register records that a player's socket is held by this instance
+func (b *Backplane) Register(playerID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	b.mu.Lock()
+	b.players[playerID] = struct{}{}
+	b.mu.Unlock()
+
+	return b.client.Set(ctx, presenceKeyPrefix+playerID, b.instanceID, presenceTTL).Err()
+}
+
+// Unregister removes a player's presence if it still points at this instance
+func (b *Backplane) Unregister(playerID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	b.mu.Lock()
+	delete(b.players, playerID)
+	b.mu.Unlock()
+
+	return deleteOwnedScript.Run(ctx, b.client, []string{presenceKeyPrefix + playerID}, b.instanceID).Err()
+}
+
+// Connected reports whether any instance holds the player's socket
+func (b *Backplane) Connected(playerID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	count, err := b.client.Exists(ctx, presenceKeyPrefix+playerID).Result()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// HoldDisconnect records that a player who dropped from this instance may
+// still reconnect, for up to ttl. Whichever instance they reconnect through
+// releases the hold.
+func (b *Backplane) HoldDisconnect(playerID string, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return b.client.Set(ctx, disconnectKeyPrefix+playerID, b.instanceID, ttl).Err()
+}
+
+// ReleaseDisconnect removes a player's disconnect hold, whichever instance
+// set it, reporting whether one was pending
+func (b *Backplane) ReleaseDisconnect(playerID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	deleted, err := b.client.Del(ctx, disconnectKeyPrefix+playerID).Result()
+	if err != nil {
+		return false, err
+	}
+	return deleted > 0, nil
+}
+
+// ExpireDisconnect removes a player's disconnect hold if this instance set
+// it, reporting whether it was still pending
+func (b *Backplane) ExpireDisconnect(playerID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	deleted, err := deleteOwnedScript.Run(ctx, b.client, []string{disconnectKeyPrefix + playerID}, b.instanceID).Int()
+	if err != nil {
+		return false, err
+	}
+	return deleted > 0, nil
+}
+
+// ConnectedPlayers returns the IDs of players connected to any instance, sorted
+func (b *Backplane) ConnectedPlayers() ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var playerIDs []string
+	iter := b.client.Scan(ctx, 0, presenceKeyPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		playerIDs = append(playerIDs, strings.TrimPrefix(iter.Val(), presenceKeyPrefix))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.Sort(playerIDs)
+	return playerIDs, nil
+}
+
+// Publish routes a message to the instance holding the player's socket. It
+// returns false when the player isn't connected to any instance.
+func (b *Backplane) Publish(playerID string, message []byte) (bool, error) {
+	return b.route(playerID, routedMessage{PlayerID: playerID, Message: message})
+}
+
+// Disconnect asks the instance holding the player's socket to close it with
+// reason. It returns false when the player isn't connected to any instance.
+func (b *Backplane) Disconnect(playerID, reason string) (bool, error) {
+	return b.route(playerID, routedMessage{PlayerID: playerID, Close: true, Reason: reason})
+}
+
+// Broadcast sends a message to every player on every instance, this one included
+func (b *Backplane) Broadcast(message []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	payload, err := json.Marshal(routedMessage{Message: message})
+	if err != nil {
+		return err
+	}
+	return b.client.Publish(ctx, broadcastChannel, payload).Err()
+}
+
+// route publishes an envelope to the instance holding the player's socket
+func (b *Backplane) route(playerID string, routed routedMessage) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	instanceID, err := b.client.Get(ctx, presenceKeyPrefix+playerID).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	payload, err := json.Marshal(routed)
+	if err != nil {
+		return false, err
+	}
+
+	receivers, err := b.client.Publish(ctx, instanceChannelPrefix+instanceID, payload).Result()
+	if err != nil {
+		return false, err
+	}
+
+	// Nobody listening means the owning instance is gone and its presence is stale
+	return receivers > 0, nil
+}
+
+// Subscribe starts handing over what other instances route here: messages
+// to deliver to a player, players to disconnect and messages to broadcast to
+// every local player. It keeps local players' presence fresh until Close.
+func (b *Backplane) Subscribe(
+	deliver func(playerID string, message []byte),
+	disconnect func(playerID, reason string),
+	broadcast func(message []byte),
+) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	channels := []string{instanceChannelPrefix + b.instanceID, broadcastChannel}
+	pubsub := b.client.Subscribe(ctx, channels...)
+
+	// Wait for every subscription to be confirmed so no routed message is missed
+	for range channels {
+		if _, err := pubsub.Receive(ctx); err != nil {
+			pubsub.Close()
+			return err
+		}
+	}
+
+	b.mu.Lock()
+	b.pubsub = pubsub
+	b.mu.Unlock()
+
+	b.wg.Add(2)
+	go b.receive(pubsub.Channel(), deliver, disconnect, broadcast)
+	go b.refreshPresence()
+
+	logger.Logger.Info("Backplane subscribed", "instanceID", b.instanceID)
+	return nil
+}
+
+// receive hands routed messages to the local handlers
+func (b *Backplane) receive(
+	messages <-chan *redis.Message,
+	deliver func(playerID string, message []byte),
+	disconnect func(playerID, reason string),
+	broadcast func(message []byte),
+) {
+	defer b.wg.Done()
+
+	for msg := range messages {
+		var routed routedMessage
+		if err := json.Unmarshal([]byte(msg.Payload), &routed); err != nil {
+			logger.Logger.Warn("Dropping malformed routed message",
+				"instanceID", b.instanceID,
+				"error", err,
+			)
+			continue
+		}
+
+		switch {
+		case msg.Channel == broadcastChannel:
+			broadcast(routed.Message)
+		case routed.Close:
+			disconnect(routed.PlayerID, routed.Reason)
+		default:
+			deliver(routed.PlayerID, routed.Message)
+		}
+	}
+}
+
+// refreshPresence extends presence keys for players connected here
+func (b *Backplane) refreshPresence() {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(presenceRefresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-ticker.C:
+			b.mu.Lock()
+			playerIDs := make([]string, 0, len(b.players))
+			for playerID := range b.players {
+				playerIDs = append(playerIDs, playerID)
+			}
+			b.mu.Unlock()
+
+			if len(playerIDs) == 0 {
+				continue
+			}
+
+			if err := b.refreshOwned(playerIDs); err != nil {
+				logger.Logger.Error("Failed to refresh player presence",
+					"instanceID", b.instanceID,
+					"error", err,
+				)
+			}
+		}
+	}
+}
+
+// refreshOwned extends the presence of players whose presence still names
+// this instance
+func (b *Backplane) refreshOwned(playerIDs []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := b.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, playerID := range playerIDs {
+			refreshOwnedScript.Eval(ctx, pipe, []string{presenceKeyPrefix + playerID}, b.instanceID, presenceTTL.Milliseconds())
+		}
+		return nil
+	})
+	return err
+}
+
+// Close stops receiving routed messages and refreshing presence
+func (b *Backplane) Close() error {
+	b.mu.Lock()
+	pubsub := b.pubsub
+	b.pubsub = nil
+	b.mu.Unlock()
+
+	if pubsub == nil {
+		return nil
+	}
+
+	close(b.done)
+	err := pubsub.Close()
+	b.wg.Wait()
+	return err
+}
+
+// newInstanceID combines the hostname with random bytes so restarted or
+// co-located instances never share a channel
+func newInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "server"
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return host + "-" + time.Now().Format("150405.000000")
+	}
+	return host + "-" + hex.EncodeToString(suffix)
+}
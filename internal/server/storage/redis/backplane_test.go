@@ -0,0 +1,263 @@
+package redis
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type routedDelivery struct {
+	playerID string
+	message  string
+}
+
+func TestBackplane_RoutesBetweenInstances(t *testing.T) {
+	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	if err := client.HealthCheck(); err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+
+	instanceA := NewBackplane(client)
+	instanceB := NewBackplane(client)
+	if instanceA.InstanceID() == instanceB.InstanceID() {
+		t.Fatal("Expected instances to have distinct IDs")
+	}
+
+	deliveredA := make(chan routedDelivery, 1)
+	deliveredB := make(chan routedDelivery, 1)
+	if err := instanceA.Subscribe(func(playerID string, message []byte) {
+		deliveredA <- routedDelivery{playerID, string(message)}
+	}, func(string, string) {}, func([]byte) {}); err != nil {
+		t.Fatalf("Subscribe A failed: %v", err)
+	}
+	defer instanceA.Close()
+	if err := instanceB.Subscribe(func(playerID string, message []byte) {
+		deliveredB <- routedDelivery{playerID, string(message)}
+	}, func(string, string) {}, func([]byte) {}); err != nil {
+		t.Fatalf("Subscribe B failed: %v", err)
+	}
+	defer instanceB.Close()
+
+	// Player is connected to instance B; instance A sends to them
+	if err := instanceB.Register("backplane-player"); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	defer instanceB.Unregister("backplane-player")
+
+	delivered, err := instanceA.Publish("backplane-player", []byte(`{"type":"game_move"}`))
+	if err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if !delivered {
+		t.Fatal("Expected message to be routed to instance B")
+	}
+
+	select {
+	case got := <-deliveredB:
+		if got.playerID != "backplane-player" || got.message != `{"type":"game_move"}` {
+			t.Errorf("Unexpected delivery: %+v", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Instance B never received the routed message")
+	}
+
+	select {
+	case got := <-deliveredA:
+		t.Errorf("Instance A should not receive messages for B's players, got %+v", got)
+	default:
+	}
+}
+
+func TestBackplane_UnregisterOnlyRemovesOwnPresence(t *testing.T) {
+	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	if err := client.HealthCheck(); err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+
+	instanceA := NewBackplane(client)
+	instanceB := NewBackplane(client)
+	if err := instanceB.Subscribe(func(string, []byte) {}, func(string, string) {}, func([]byte) {}); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	defer instanceB.Close()
+
+	// Player reconnects to B before A notices the old socket closed
+	if err := instanceA.Register("moving-player"); err != nil {
+		t.Fatalf("Register A failed: %v", err)
+	}
+	if err := instanceB.Register("moving-player"); err != nil {
+		t.Fatalf("Register B failed: %v", err)
+	}
+	defer instanceB.Unregister("moving-player")
+
+	if err := instanceA.Unregister("moving-player"); err != nil {
+		t.Fatalf("Unregister A failed: %v", err)
+	}
+
+	delivered, err := instanceA.Publish("moving-player", []byte(`{}`))
+	if err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if !delivered {
+		t.Error("Stale unregister should not remove presence held by another instance")
+	}
+
+	if err := instanceB.Unregister("moving-player"); err != nil {
+		t.Fatalf("Unregister B failed: %v", err)
+	}
+	delivered, err = instanceA.Publish("moving-player", []byte(`{}`))
+	if err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if delivered {
+		t.Error("Expected no delivery once the player disconnected everywhere")
+	}
+}
+
+func TestBackplane_RefreshOnlyExtendsOwnPresence(t *testing.T) {
+	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	if err := client.HealthCheck(); err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+
+	instanceA := NewBackplane(client)
+	instanceB := NewBackplane(client)
+
+	// Player reconnects to B while A still thinks they're connected there
+	if err := instanceA.Register("refreshed-player"); err != nil {
+		t.Fatalf("Register A failed: %v", err)
+	}
+	if err := instanceB.Register("refreshed-player"); err != nil {
+		t.Fatalf("Register B failed: %v", err)
+	}
+	defer instanceB.Unregister("refreshed-player")
+
+	if err := instanceA.refreshOwned([]string{"refreshed-player"}); err != nil {
+		t.Fatalf("refreshOwned failed: %v", err)
+	}
+	owner := client.Get(t.Context(), presenceKeyPrefix+"refreshed-player").Val()
+	if owner != instanceB.InstanceID() {
+		t.Errorf("Expected presence to stay with instance B, got %q", owner)
+	}
+
+	if err := instanceB.refreshOwned([]string{"refreshed-player"}); err != nil {
+		t.Fatalf("refreshOwned failed: %v", err)
+	}
+	if ttl := client.TTL(t.Context(), presenceKeyPrefix+"refreshed-player").Val(); ttl <= 0 || ttl > presenceTTL {
+		t.Errorf("Expected presence TTL to be refreshed, got %v", ttl)
+	}
+}
+
+func TestBackplane_DisconnectsAndBroadcasts(t *testing.T) {
+	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	if err := client.HealthCheck(); err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+
+	instanceA := NewBackplane(client)
+	instanceB := NewBackplane(client)
+
+	disconnectedB := make(chan routedDelivery, 1)
+	broadcastA := make(chan string, 1)
+	broadcastB := make(chan string, 1)
+	if err := instanceA.Subscribe(func(string, []byte) {}, func(string, string) {}, func(message []byte) {
+		broadcastA <- string(message)
+	}); err != nil {
+		t.Fatalf("Subscribe A failed: %v", err)
+	}
+	defer instanceA.Close()
+	if err := instanceB.Subscribe(func(playerID string, message []byte) {
+		t.Errorf("Expected no delivery for a disconnect, got %s for %s", message, playerID)
+	}, func(playerID, reason string) {
+		disconnectedB <- routedDelivery{playerID, reason}
+	}, func(message []byte) {
+		broadcastB <- string(message)
+	}); err != nil {
+		t.Fatalf("Subscribe B failed: %v", err)
+	}
+	defer instanceB.Close()
+
+	if err := instanceB.Register("kicked-player"); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	defer instanceB.Unregister("kicked-player")
+
+	playerIDs, err := instanceA.ConnectedPlayers()
+	if err != nil || !slices.Contains(playerIDs, "kicked-player") {
+		t.Errorf("Expected A to list B's player, got %v (err %v)", playerIDs, err)
+	}
+
+	// A kicks B's player
+	connected, err := instanceA.Disconnect("kicked-player", "Kicked")
+	if err != nil || !connected {
+		t.Fatalf("Expected the disconnect to reach B, got %v (err %v)", connected, err)
+	}
+	select {
+	case got := <-disconnectedB:
+		if got.playerID != "kicked-player" || got.message != "Kicked" {
+			t.Errorf("Unexpected disconnect: %+v", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Instance B never received the disconnect")
+	}
+	if connected, err := instanceA.Disconnect("absent-player", "Kicked"); err != nil || connected {
+		t.Errorf("Expected no disconnect for a player who isn't connected, got %v (err %v)", connected, err)
+	}
+
+	// A broadcast reaches every instance, the sender included
+	if err := instanceA.Broadcast([]byte(`{"type":"announcement"}`)); err != nil {
+		t.Fatalf("Broadcast failed: %v", err)
+	}
+	for name, received := range map[string]chan string{"A": broadcastA, "B": broadcastB} {
+		select {
+		case got := <-received:
+			if got != `{"type":"announcement"}` {
+				t.Errorf("Unexpected broadcast on %s: %s", name, got)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Instance %s never received the broadcast", name)
+		}
+	}
+}
+
+func TestBackplane_DisconnectHolds(t *testing.T) {
+	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	if err := client.HealthCheck(); err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+
+	instanceA := NewBackplane(client)
+	instanceB := NewBackplane(client)
+
+	// The player drops from A and comes back through B
+	if err := instanceA.HoldDisconnect("dropped-player", time.Minute); err != nil {
+		t.Fatalf("HoldDisconnect failed: %v", err)
+	}
+	if err := instanceB.Register("dropped-player"); err != nil {
+		t.Fatalf("Register B failed: %v", err)
+	}
+	defer instanceB.Unregister("dropped-player")
+
+	if connected, err := instanceA.Connected("dropped-player"); err != nil || !connected {
+		t.Errorf("Expected A to see the player connected to B, got %v (err %v)", connected, err)
+	}
+	if released, err := instanceB.ReleaseDisconnect("dropped-player"); err != nil || !released {
+		t.Fatalf("Expected B to release A's hold, got %v (err %v)", released, err)
+	}
+	if expired, err := instanceA.ExpireDisconnect("dropped-player"); err != nil || expired {
+		t.Errorf("Expected A's timer to find its hold released, got %v (err %v)", expired, err)
+	}
+
+	// A hold set by B after a later drop isn't A's to expire
+	if err := instanceB.HoldDisconnect("dropped-player", time.Minute); err != nil {
+		t.Fatalf("HoldDisconnect failed: %v", err)
+	}
+	if expired, err := instanceA.ExpireDisconnect("dropped-player"); err != nil || expired {
+		t.Errorf("Expected A not to expire B's hold, got %v (err %v)", expired, err)
+	}
+	if expired, err := instanceB.ExpireDisconnect("dropped-player"); err != nil || !expired {
+		t.Errorf("Expected B to expire its own hold, got %v (err %v)", expired, err)
+	}
+}
@@ -0,0 +1,103 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+const bansKey = "bans"
+
+// BanStore implements Redis-based ban storage. Every ban is a JSON field of
+// one hash, keyed by username, with no TTL.
+type BanStore struct {
+	client *Client
+}
+
+// NewBanStore creates a new Redis ban store
+func NewBanStore(client *Client) *BanStore {
+	return &BanStore{client: client}
+}
+
+// Ban adds or replaces a ban
+func (s *BanStore) Ban(ban *models.Ban) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	data, err := json.Marshal(ban)
+	if err != nil {
+		return err
+	}
+	return s.client.HSet(ctx, bansKey, ban.Username, data).Err()
+}
+
+// Unban lifts a ban
+func (s *BanStore) Unban(username string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	removed, err := s.client.HDel(ctx, bansKey, username).Result()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return storage.ErrBanNotFound
+	}
+	return nil
+}
+
+// GetBan retrieves a username's ban
+func (s *BanStore) GetBan(username string) (*models.Ban, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	data, err := s.client.HGet(ctx, bansKey, username).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, storage.ErrBanNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ban models.Ban
+	if err := json.Unmarshal([]byte(data), &ban); err != nil {
+		return nil, err
+	}
+	return &ban, nil
+}
+
+// ListBans returns every ban, oldest first
+func (s *BanStore) ListBans() ([]*models.Ban, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	values, err := s.client.HGetAll(ctx, bansKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	bans := make([]*models.Ban, 0, len(values))
+	for _, data := range values {
+		var ban models.Ban
+		if err := json.Unmarshal([]byte(data), &ban); err != nil {
+			return nil, err
+		}
+		bans = append(bans, &ban)
+	}
+
+	slices.SortFunc(bans, func(a, b *models.Ban) int {
+		if c := a.BannedAt.Compare(b.BannedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Username, b.Username)
+	})
+	return bans, nil
+}
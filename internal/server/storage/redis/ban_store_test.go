@@ -0,0 +1,54 @@
+package redis
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func TestBanStore(t *testing.T) {
+	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	store := NewBanStore(client)
+
+	ban := &models.Ban{Username: "redisbanned", Reason: "cheating", BannedAt: time.Now()}
+	err := store.Ban(ban)
+	if err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+	defer client.HDel(t.Context(), bansKey, ban.Username)
+
+	retrieved, err := store.GetBan(ban.Username)
+	if err != nil {
+		t.Fatalf("GetBan failed: %v", err)
+	}
+	if retrieved.Reason != "cheating" || !retrieved.BannedAt.Equal(ban.BannedAt) {
+		t.Errorf("Expected %+v, got %+v", ban, retrieved)
+	}
+
+	bans, err := store.ListBans()
+	if err != nil {
+		t.Fatalf("ListBans failed: %v", err)
+	}
+	found := false
+	for _, listed := range bans {
+		found = found || listed.Username == ban.Username
+	}
+	if !found {
+		t.Errorf("Expected %s in %+v", ban.Username, bans)
+	}
+
+	if err := store.Unban(ban.Username); err != nil {
+		t.Fatalf("Unban failed: %v", err)
+	}
+	if _, err := store.GetBan(ban.Username); !errors.Is(err, storage.ErrBanNotFound) {
+		t.Errorf("Expected ErrBanNotFound, got %v", err)
+	}
+	if err := store.Unban(ban.Username); !errors.Is(err, storage.ErrBanNotFound) {
+		t.Errorf("Expected ErrBanNotFound, got %v", err)
+	}
+}
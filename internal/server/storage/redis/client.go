@@ -2,12 +2,14 @@ package redis
 
 import (
 	"context"
+	"net"
 	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 
 	"github.com/briancain/go-tetris/internal/server/logger"
+	"github.com/briancain/go-tetris/internal/server/metrics"
 )
 
 // redisLogger adapts Redis client logging to our structured logger
@@ -26,6 +28,37 @@ func (l *redisLogger) Printf(ctx context.Context, format string, v ...interface{
 	}
 }
 
+// metricsHook records the latency of every Redis command, labelled by
+// command name so Lua scripts show up as evalsha
+type metricsHook struct{}
+
+func (metricsHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		start := time.Now()
+		conn, err := next(ctx, network, addr)
+		metrics.StorageOperationDuration.WithLabelValues("dial").Observe(time.Since(start).Seconds())
+		return conn, err
+	}
+}
+
+func (metricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		metrics.StorageOperationDuration.WithLabelValues(cmd.Name()).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+func (metricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		metrics.StorageOperationDuration.WithLabelValues("pipeline").Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
 // Client wraps Redis client with health check capability
 type Client struct {
 	*redis.Client
@@ -45,6 +78,7 @@ func NewClient(redisURL string) (*Client, error) {
 	opts.ConnMaxIdleTime = 5 * time.Minute
 
 	client := redis.NewClient(opts)
+	client.AddHook(metricsHook{})
 
 	// Use our structured logger for Redis client logs
 	redis.SetLogger(&redisLogger{})
@@ -6,14 +6,19 @@ import (
 	"errors"
 	"time"
 
+	"github.com/redis/go-redis/v9"
+
+	"github.com/briancain/go-tetris/internal/server/storage"
 	"github.com/briancain/go-tetris/pkg/models"
 )
 
 const (
-	gameKeyPrefix  = "game:"
-	activeGamesKey = "games:active"
-	allGamesKey    = "games:all"
-	gameSessionTTL = 2 * time.Hour // Games expire after 2 hours of inactivity
+	gameKeyPrefix        = "game:"
+	seriesKeyPrefix      = "series:"
+	playerGamesKeyPrefix = "games:player:" // List of a player's recent game IDs, newest first
+	activeGamesKey       = "games:active"
+	allGamesKey          = "games:all"
+	gameSessionTTL       = 2 * time.Hour // Games expire after 2 hours of inactivity
 )
 
 // GameStore implements Redis-based game session storage
@@ -48,20 +53,20 @@ func (s *GameStore) CreateGame(game *models.GameSession) error {
 		return err
 	}
 
-	// Store game with TTL
-	err = s.client.Set(ctx, gameKey, data, gameSessionTTL).Err()
-	if err != nil {
-		return err
-	}
-
-	// Add to active games set
-	err = s.client.SAdd(ctx, activeGamesKey, game.ID).Err()
-	if err != nil {
-		return err
-	}
-
-	// Add to all games set
-	return s.client.SAdd(ctx, allGamesKey, game.ID).Err()
+	// Store game with TTL, add it to the game sets and index it by player
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, gameKey, data, gameSessionTTL)
+		pipe.SAdd(ctx, activeGamesKey, game.ID)
+		pipe.SAdd(ctx, allGamesKey, game.ID)
+		for _, playerID := range game.PlayerIDs() {
+			playerGamesKey := playerGamesKeyPrefix + playerID
+			pipe.LPush(ctx, playerGamesKey, game.ID)
+			pipe.LTrim(ctx, playerGamesKey, 0, storage.PlayerGameHistory-1)
+			pipe.Expire(ctx, playerGamesKey, gameSessionTTL)
+		}
+		return nil
+	})
+	return err
 }
 
 // GetGame retrieves a game session by ID
@@ -194,6 +199,122 @@ func (s *GameStore) GetAllGames() ([]*models.GameSession, error) {
 	return games, nil
 }
 
+// GetLastGame returns the most recently created game a player was in
+func (s *GameStore) GetLastGame(playerID string) (*models.GameSession, error) {
+	games, err := s.GetPlayerGames(playerID, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(games) == 0 {
+		return nil, errors.New("game not found")
+	}
+	return games[0], nil
+}
+
+// GetPlayerGames returns up to limit of a player's most recent games, newest first
+func (s *GameStore) GetPlayerGames(playerID string, limit int) ([]*models.GameSession, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	playerGamesKey := playerGamesKeyPrefix + playerID
+	gameIDs, err := s.client.LRange(ctx, playerGamesKey, 0, storage.PlayerGameHistory-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var games []*models.GameSession
+	for _, id := range gameIDs {
+		if len(games) >= limit {
+			break
+		}
+		game, err := s.GetGame(id)
+		if err != nil {
+			// Game expired, drop it from the player's index
+			s.client.LRem(ctx, playerGamesKey, 0, id)
+			continue
+		}
+		games = append(games, game)
+	}
+
+	return games, nil
+}
+
+// CreateSeries stores a new match series
+func (s *GameStore) CreateSeries(series *models.MatchSeries) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	data, err := json.Marshal(series)
+	if err != nil {
+		return err
+	}
+
+	created, err := s.client.SetNX(ctx, seriesKeyPrefix+series.ID, data, gameSessionTTL).Result()
+	if err != nil {
+		return err
+	}
+	if !created {
+		return errors.New("series already exists")
+	}
+	return nil
+}
+
+// GetSeries retrieves a match series by ID
+func (s *GameStore) GetSeries(id string) (*models.MatchSeries, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	data, err := s.client.Get(ctx, seriesKeyPrefix+id).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, errors.New("series not found")
+		}
+		return nil, err
+	}
+
+	var series models.MatchSeries
+	if err := json.Unmarshal([]byte(data), &series); err != nil {
+		return nil, err
+	}
+
+	return &series, nil
+}
+
+// UpdateSeries updates an existing match series, refreshing its TTL
+func (s *GameStore) UpdateSeries(series *models.MatchSeries) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	data, err := json.Marshal(series)
+	if err != nil {
+		return err
+	}
+
+	updated, err := s.client.SetXX(ctx, seriesKeyPrefix+series.ID, data, gameSessionTTL).Result()
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return errors.New("series not found")
+	}
+	return nil
+}
+
+// DeleteSeries removes a match series
+func (s *GameStore) DeleteSeries(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	deleted, err := s.client.Del(ctx, seriesKeyPrefix+id).Result()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errors.New("series not found")
+	}
+	return nil
+}
+
 // HealthCheck implements storage.HealthChecker
 func (s *GameStore) HealthCheck() error {
 	return s.client.HealthCheck()
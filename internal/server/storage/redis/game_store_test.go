@@ -1,6 +1,7 @@
 package redis
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -127,3 +128,93 @@ func TestGameStore_HealthCheck(t *testing.T) {
 		t.Skipf("Redis not available for testing: %v", err)
 	}
 }
+
+func TestGameStore_Series(t *testing.T) {
+	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	if err := client.HealthCheck(); err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+	store := NewGameStore(client)
+	defer client.Del(context.Background(), seriesKeyPrefix+"test-series-1")
+
+	series := &models.MatchSeries{
+		ID:        "test-series-1",
+		Player1ID: "series-p1",
+		Player2ID: "series-p2",
+		BestOf:    5,
+		GameIDs:   []string{"series-game-1"},
+		Status:    models.SeriesStatusActive,
+		CreatedAt: time.Now(),
+	}
+	if err := store.CreateSeries(series); err != nil {
+		t.Fatalf("CreateSeries failed: %v", err)
+	}
+	if err := store.CreateSeries(series); err == nil {
+		t.Error("Expected error creating a duplicate series")
+	}
+
+	series.RecordWin("series-p2")
+	series.GameIDs = append(series.GameIDs, "series-game-2")
+	if err := store.UpdateSeries(series); err != nil {
+		t.Fatalf("UpdateSeries failed: %v", err)
+	}
+
+	retrieved, err := store.GetSeries(series.ID)
+	if err != nil {
+		t.Fatalf("GetSeries failed: %v", err)
+	}
+	if retrieved.Player2Wins != 1 || len(retrieved.GameIDs) != 2 || retrieved.BestOf != 5 {
+		t.Errorf("Unexpected series after update: %+v", retrieved)
+	}
+
+	if err := store.UpdateSeries(&models.MatchSeries{ID: "missing-series"}); err == nil {
+		t.Error("Expected error updating a missing series")
+	}
+
+	if err := store.DeleteSeries(series.ID); err != nil {
+		t.Fatalf("DeleteSeries failed: %v", err)
+	}
+	if _, err := store.GetSeries(series.ID); err == nil {
+		t.Error("Expected the deleted series to be gone")
+	}
+	if err := store.DeleteSeries(series.ID); err == nil {
+		t.Error("Expected error deleting a missing series")
+	}
+}
+
+func TestGameStore_PlayerGameIndex(t *testing.T) {
+	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	if err := client.HealthCheck(); err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+	store := NewGameStore(client)
+
+	alice := &models.Player{ID: "index-alice"}
+	bob := &models.Player{ID: "index-bob"}
+	defer client.Del(context.Background(), playerGamesKeyPrefix+alice.ID, playerGamesKeyPrefix+bob.ID)
+
+	for _, id := range []string{"index-game-1", "index-game-2"} {
+		if err := store.CreateGame(&models.GameSession{ID: id, Players: models.NewSeats(alice, bob), CreatedAt: time.Now()}); err != nil {
+			t.Fatalf("CreateGame failed: %v", err)
+		}
+		defer store.DeleteGame(id)
+	}
+
+	last, err := store.GetLastGame(bob.ID)
+	if err != nil {
+		t.Fatalf("GetLastGame failed: %v", err)
+	}
+	if last.ID != "index-game-2" {
+		t.Errorf("Expected last game index-game-2, got %s", last.ID)
+	}
+
+	// Expired games are skipped and dropped from the index
+	store.DeleteGame("index-game-2")
+	games, err := store.GetPlayerGames(alice.ID, 10)
+	if err != nil {
+		t.Fatalf("GetPlayerGames failed: %v", err)
+	}
+	if len(games) != 1 || games[0].ID != "index-game-1" {
+		t.Errorf("Expected only index-game-1 after the newer game expired, got %d games", len(games))
+	}
+}
@@ -0,0 +1,107 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+const (
+	historyKeyPrefix    = "history:player:" // List of a player's finished games, newest first
+	historyVsKeyPrefix  = "history:vs:"     // The same, limited to games against one opponent
+	headToHeadKeyPrefix = "h2h:"            // Hash of a player's wins, losses and draws against one opponent
+)
+
+// MatchHistoryStore implements Redis-based match history. Each player's
+// games are a list of JSON records, with a list and a results hash per
+// opponent for head-to-head lookups. None of the keys expire.
+type MatchHistoryStore struct {
+	client *Client
+}
+
+// NewMatchHistoryStore creates a new Redis match history store
+func NewMatchHistoryStore(client *Client) *MatchHistoryStore {
+	return &MatchHistoryStore{client: client}
+}
+
+// historyVsKey returns the list of a player's games against an opponent
+func historyVsKey(playerID, opponent string) string {
+	return historyVsKeyPrefix + playerID + ":" + opponent
+}
+
+// headToHeadKey returns the hash counting a player's results against an opponent
+func headToHeadKey(playerID, opponent string) string {
+	return headToHeadKeyPrefix + playerID + ":" + opponent
+}
+
+// AddPlayerMatch adds a finished game to the front of a player's history
+func (s *MatchHistoryStore) AddPlayerMatch(playerID string, match *models.MatchRecord) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	data, err := json.Marshal(match)
+	if err != nil {
+		return err
+	}
+
+	outcome := string(match.Outcome(playerID))
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LPush(ctx, historyKeyPrefix+playerID, data)
+		for _, opponent := range match.Opponents(playerID) {
+			pipe.LPush(ctx, historyVsKey(playerID, opponent.Username), data)
+			pipe.HIncrBy(ctx, headToHeadKey(playerID, opponent.Username), outcome, 1)
+		}
+		return nil
+	})
+	return err
+}
+
+// GetPlayerMatches returns a page of a player's history, newest first,
+// optionally only games against an opponent by username
+func (s *MatchHistoryStore) GetPlayerMatches(playerID, opponent string, offset, limit int) ([]*models.MatchRecord, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if limit <= 0 {
+		return []*models.MatchRecord{}, nil
+	}
+
+	key := historyKeyPrefix + playerID
+	if opponent != "" {
+		key = historyVsKey(playerID, opponent)
+	}
+	values, err := s.client.LRange(ctx, key, int64(offset), int64(offset+limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]*models.MatchRecord, 0, len(values))
+	for _, data := range values {
+		var match models.MatchRecord
+		if err := json.Unmarshal([]byte(data), &match); err != nil {
+			return nil, err
+		}
+		matches = append(matches, &match)
+	}
+	return matches, nil
+}
+
+// GetHeadToHead returns a player's record against an opponent by username
+func (s *MatchHistoryStore) GetHeadToHead(playerID, opponent string) (*models.HeadToHead, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var counts struct {
+		Wins   int `redis:"win"`
+		Losses int `redis:"loss"`
+		Draws  int `redis:"draw"`
+	}
+	if err := s.client.HGetAll(ctx, headToHeadKey(playerID, opponent)).Scan(&counts); err != nil {
+		return nil, err
+	}
+	return &models.HeadToHead{Wins: counts.Wins, Losses: counts.Losses, Draws: counts.Draws}, nil
+}
@@ -0,0 +1,70 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func TestMatchHistoryStore(t *testing.T) {
+	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	if err := client.HealthCheck(); err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+	store := NewMatchHistoryStore(client)
+
+	playerID := "redis-history-alice"
+	defer client.Del(t.Context(),
+		historyKeyPrefix+playerID,
+		historyVsKey(playerID, "Bob"), historyVsKey(playerID, "Carol"),
+		headToHeadKey(playerID, "Bob"), headToHeadKey(playerID, "Carol"),
+	)
+
+	// Alice beats Bob, loses to Carol, then draws with Bob
+	match := func(id, opponent string, alicePlacement, opponentPlacement int) *models.MatchRecord {
+		return &models.MatchRecord{GameID: id, Players: []*models.MatchPlayer{
+			{ID: playerID, Username: "Alice", Score: 900, Placement: alicePlacement},
+			{ID: "redis-history-" + opponent, Username: opponent, Placement: opponentPlacement},
+		}}
+	}
+	for _, m := range []*models.MatchRecord{
+		match("g1", "Bob", 1, 2),
+		match("g2", "Carol", 2, 1),
+		match("g3", "Bob", 0, 0),
+	} {
+		if err := store.AddPlayerMatch(playerID, m); err != nil {
+			t.Fatalf("AddPlayerMatch failed: %v", err)
+		}
+	}
+
+	page, err := store.GetPlayerMatches(playerID, "", 1, 5)
+	if err != nil {
+		t.Fatalf("GetPlayerMatches failed: %v", err)
+	}
+	if len(page) != 2 || page[0].GameID != "g2" || page[1].GameID != "g1" || page[1].Players[0].Score != 900 {
+		t.Errorf("Expected g2 then g1 after skipping the newest, got %+v", page)
+	}
+
+	against, err := store.GetPlayerMatches(playerID, "Bob", 0, 5)
+	if err != nil {
+		t.Fatalf("GetPlayerMatches failed: %v", err)
+	}
+	if len(against) != 2 || against[0].GameID != "g3" || against[1].GameID != "g1" {
+		t.Errorf("Expected the two games against Bob, got %+v", against)
+	}
+
+	record, err := store.GetHeadToHead(playerID, "Bob")
+	if err != nil {
+		t.Fatalf("GetHeadToHead failed: %v", err)
+	}
+	if *record != (models.HeadToHead{Wins: 1, Draws: 1}) {
+		t.Errorf("Expected a 1-0-1 record against Bob, got %+v", record)
+	}
+
+	// History is kept for good
+	if ttl := client.TTL(t.Context(), historyKeyPrefix+playerID).Val(); ttl != -1 {
+		t.Errorf("Expected match history not to expire, got TTL %s", ttl)
+	}
+}
@@ -0,0 +1,432 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+const (
+	playerKeyPrefix   = "player:"
+	usernameKeyPrefix = "player:username:"
+	tokenKeyPrefix    = "player:token:"
+	allPlayersKey     = "players:all"
+	sessionTTL        = 24 * time.Hour // Sessions expire after a day without activity
+)
+
+// recordResultScript updates stats in place so results reported by different
+// instances at the same time are never lost
+var recordResultScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return redis.error_reply('player not found')
+end
+redis.call('HINCRBY', KEYS[1], 'totalGames', 1)
+if ARGV[1] == 'win' then
+	redis.call('HINCRBY', KEYS[1], 'wins', 1)
+elseif ARGV[1] == 'loss' then
+	redis.call('HINCRBY', KEYS[1], 'losses', 1)
+end
+local score = tonumber(ARGV[2])
+local highScore = tonumber(redis.call('HGET', KEYS[1], 'highScore') or '0')
+if score > highScore then
+	redis.call('HSET', KEYS[1], 'highScore', score)
+end
+redis.call('HSET', KEYS[1], 'rating', ARGV[3], 'ratingDeviation', ARGV[4], 'ratingVolatility', ARGV[5])
+local placement = tonumber(ARGV[6])
+if placement > 0 then
+	redis.call('HINCRBY', KEYS[1], 'royaleGames', 1)
+	redis.call('HINCRBY', KEYS[1], 'placementTotal', placement)
+end
+return 1
+`)
+
+// recordSeriesScript updates series stats in place, like recordResultScript
+var recordSeriesScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return redis.error_reply('player not found')
+end
+redis.call('HINCRBY', KEYS[1], 'seriesPlayed', 1)
+if ARGV[1] == '1' then
+	redis.call('HINCRBY', KEYS[1], 'seriesWins', 1)
+end
+return 1
+`)
+
+// updateFieldsScript sets fields on an existing player and refreshes their
+// session TTLs in one step, so an update racing a delete can't bring back a
+// partial hash. ARGV[1] is the session TTL in milliseconds, ARGV[2] and
+// ARGV[3] the username and token key prefixes, and the rest field/value pairs.
+var updateFieldsScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return redis.error_reply('player not found')
+end
+redis.call('HSET', KEYS[1], unpack(ARGV, 4))
+local player = redis.call('HMGET', KEYS[1], 'username', 'sessionToken', 'registered')
+if player[3] ~= 'true' then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+	if player[1] then
+		redis.call('PEXPIRE', ARGV[2] .. player[1], ARGV[1])
+	end
+end
+if player[2] and player[2] ~= '' then
+	redis.call('PEXPIRE', ARGV[3] .. player[2], ARGV[1])
+end
+return 1
+`)
+
+// clearQueueScript marks a player as no longer waiting, but only if they're
+// still in the queue ARGV[1] (any queue when it's empty)
+var clearQueueScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return redis.error_reply('player not found')
+end
+if ARGV[1] ~= '' and redis.call('HGET', KEYS[1], 'queue') ~= ARGV[1] then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'inQueue', 'false', 'queue', '')
+return 1
+`)
+
+// PlayerStore implements Redis-based player storage. Each player is a hash
+// with secondary keys indexing it by username and session token; all three
+// share a TTL that is refreshed whenever the player is updated. Registered
+// players and their usernames never expire, only their session tokens do.
+type PlayerStore struct {
+	client *Client
+}
+
+// NewPlayerStore creates a new Redis player store
+func NewPlayerStore(client *Client) *PlayerStore {
+	return &PlayerStore{client: client}
+}
+
+// CreatePlayer stores a new player, reserving their username
+func (s *PlayerStore) CreatePlayer(player *models.Player) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	playerKey := playerKeyPrefix + player.ID
+
+	// Check if player already exists
+	exists, err := s.client.Exists(ctx, playerKey).Result()
+	if err != nil {
+		return err
+	}
+	if exists > 0 {
+		return errors.New("player already exists")
+	}
+
+	// Reserve the username atomically so two instances can't both hand it out
+	ttl := playerTTL(player)
+	reserved, err := s.client.SetNX(ctx, usernameKeyPrefix+player.Username, player.ID, ttl).Result()
+	if err != nil {
+		return err
+	}
+	if !reserved {
+		return storage.ErrUsernameTaken
+	}
+
+	fields := playerFields(player)
+	fields["sessionToken"] = player.SessionToken
+	fields["inQueue"] = strconv.FormatBool(player.InQueue)
+	fields["queuedAt"] = player.QueuedAt.Format(time.RFC3339Nano)
+	fields["queue"] = player.Queue
+	fields["gameId"] = player.GameID
+	fields["totalGames"] = player.TotalGames
+	fields["wins"] = player.Wins
+	fields["losses"] = player.Losses
+	fields["highScore"] = player.HighScore
+	fields["rating"] = player.Rating.Value
+	fields["ratingDeviation"] = player.Rating.Deviation
+	fields["ratingVolatility"] = player.Rating.Volatility
+	fields["seriesPlayed"] = player.SeriesPlayed
+	fields["seriesWins"] = player.SeriesWins
+	fields["royaleGames"] = player.RoyaleGames
+	fields["placementTotal"] = player.PlacementTotal
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, playerKey, fields)
+		if ttl > 0 {
+			pipe.Expire(ctx, playerKey, ttl)
+		}
+		pipe.Set(ctx, tokenKeyPrefix+player.SessionToken, player.ID, sessionTTL)
+		pipe.SAdd(ctx, allPlayersKey, player.ID)
+		return nil
+	})
+	return err
+}
+
+// GetPlayer retrieves a player by ID
+func (s *PlayerStore) GetPlayer(id string) (*models.Player, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	values, err := s.client.HGetAll(ctx, playerKeyPrefix+id).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, errors.New("player not found")
+	}
+
+	return playerFromHash(values), nil
+}
+
+// GetPlayerByUsername retrieves a player by username
+func (s *PlayerStore) GetPlayerByUsername(username string) (*models.Player, error) {
+	return s.getPlayerByIndex(usernameKeyPrefix+username, "player not found")
+}
+
+// GetPlayerByToken retrieves a player by session token
+func (s *PlayerStore) GetPlayerByToken(token string) (*models.Player, error) {
+	player, err := s.getPlayerByIndex(tokenKeyPrefix+token, "invalid token")
+	if err != nil {
+		return nil, err
+	}
+	if player.SessionToken != token {
+		return nil, errors.New("invalid token") // Replaced by a newer session
+	}
+	return player, nil
+}
+
+// getPlayerByIndex resolves a secondary index key to a player
+func (s *PlayerStore) getPlayerByIndex(indexKey, notFound string) (*models.Player, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	playerID, err := s.client.Get(ctx, indexKey).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, errors.New(notFound)
+		}
+		return nil, err
+	}
+
+	player, err := s.GetPlayer(playerID)
+	if err != nil {
+		return nil, errors.New(notFound)
+	}
+	return player, nil
+}
+
+// UpdatePlayer updates an existing player's session and profile fields and
+// refreshes their session TTL. Stats, game and queue are left alone; they
+// have their own methods so a stale copy can't overwrite them.
+func (s *PlayerStore) UpdatePlayer(player *models.Player) error {
+	return s.updateFields(player.ID, playerFields(player))
+}
+
+// DeletePlayer removes a player and their indexes
+func (s *PlayerStore) DeletePlayer(id string) error {
+	player, err := s.GetPlayer(id)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, playerKeyPrefix+id, tokenKeyPrefix+player.SessionToken)
+		pipe.SRem(ctx, allPlayersKey, id)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Only release the username if it still points at this player
+	owner, err := s.client.Get(ctx, usernameKeyPrefix+player.Username).Result()
+	if err == nil && owner == id {
+		s.client.Del(ctx, usernameKeyPrefix+player.Username)
+	}
+	return nil
+}
+
+// GetAllPlayers returns all players
+func (s *PlayerStore) GetAllPlayers() ([]*models.Player, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	playerIDs, err := s.client.SMembers(ctx, allPlayersKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var players []*models.Player
+	for _, id := range playerIDs {
+		player, err := s.GetPlayer(id)
+		if err != nil {
+			// Session expired, remove from players set
+			s.client.SRem(ctx, allPlayersKey, id)
+			continue
+		}
+		players = append(players, player)
+	}
+
+	return players, nil
+}
+
+// RecordGameResult atomically adds a finished game to a player's stats
+func (s *PlayerStore) RecordGameResult(playerID string, outcome storage.GameOutcome, score, placement int, rating models.Rating) (*models.Player, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result := "draw"
+	switch outcome {
+	case storage.OutcomeWin:
+		result = "win"
+	case storage.OutcomeLoss:
+		result = "loss"
+	}
+
+	err := recordResultScript.Run(ctx, s.client, []string{playerKeyPrefix + playerID},
+		result, score, rating.Value, rating.Deviation, rating.Volatility, placement,
+	).Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetPlayer(playerID)
+}
+
+// RecordSeriesResult atomically adds a finished match series to a player's stats
+func (s *PlayerStore) RecordSeriesResult(playerID string, won bool) (*models.Player, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := recordSeriesScript.Run(ctx, s.client, []string{playerKeyPrefix + playerID}, won).Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetPlayer(playerID)
+}
+
+// SetSessionToken replaces a player's session token
+func (s *PlayerStore) SetSessionToken(playerID, token string) error {
+	player, err := s.GetPlayer(playerID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, playerKeyPrefix+playerID, "sessionToken", token)
+		if player.SessionToken != "" {
+			pipe.Del(ctx, tokenKeyPrefix+player.SessionToken)
+		}
+		if token != "" {
+			pipe.Set(ctx, tokenKeyPrefix+token, playerID, sessionTTL)
+		}
+		return nil
+	})
+	return err
+}
+
+// TouchPlayer records a player's activity and refreshes their session TTL
+func (s *PlayerStore) TouchPlayer(playerID string, at time.Time) error {
+	return s.updateFields(playerID, map[string]interface{}{
+		"lastActivity": at.Format(time.RFC3339Nano),
+	})
+}
+
+// SetPlayerGameID routes a player to a game
+func (s *PlayerStore) SetPlayerGameID(playerID, gameID string) error {
+	return s.updateFields(playerID, map[string]interface{}{
+		"gameId": gameID,
+	})
+}
+
+// SetPlayerQueue marks a player as waiting in a queue
+func (s *PlayerStore) SetPlayerQueue(playerID, queue string, queuedAt time.Time) error {
+	return s.updateFields(playerID, map[string]interface{}{
+		"inQueue":  "true",
+		"queue":    queue,
+		"queuedAt": queuedAt.Format(time.RFC3339Nano),
+	})
+}
+
+// ClearPlayerQueue marks a player as no longer waiting in queue
+func (s *PlayerStore) ClearPlayerQueue(playerID, queue string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return clearQueueScript.Run(ctx, s.client, []string{playerKeyPrefix + playerID}, queue).Err()
+}
+
+// updateFields atomically sets fields on an existing player
+func (s *PlayerStore) updateFields(playerID string, fields map[string]interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	args := []interface{}{sessionTTL.Milliseconds(), usernameKeyPrefix, tokenKeyPrefix}
+	for field, value := range fields {
+		args = append(args, field, value)
+	}
+	return updateFieldsScript.Run(ctx, s.client, []string{playerKeyPrefix + playerID}, args...).Err()
+}
+
+// HealthCheck implements storage.HealthChecker
+func (s *PlayerStore) HealthCheck() error {
+	return s.client.HealthCheck()
+}
+
+// playerFields returns the hash fields UpdatePlayer is allowed to overwrite.
+// The session token is left out so a stale copy can't revive a replaced one.
+func playerFields(player *models.Player) map[string]interface{} {
+	return map[string]interface{}{
+		"id":           player.ID,
+		"username":     player.Username,
+		"connectedAt":  player.ConnectedAt.Format(time.RFC3339Nano),
+		"lastActivity": player.LastActivity.Format(time.RFC3339Nano),
+		"registered":   strconv.FormatBool(player.Registered),
+	}
+}
+
+// playerTTL returns how long a player's hash and username are kept without
+// activity. Registered players are kept indefinitely.
+func playerTTL(player *models.Player) time.Duration {
+	if player.Registered {
+		return 0
+	}
+	return sessionTTL
+}
+
+// playerFromHash rebuilds a player from its hash fields
+func playerFromHash(values map[string]string) *models.Player {
+	player := &models.Player{
+		ID:           values["id"],
+		Username:     values["username"],
+		SessionToken: values["sessionToken"],
+		GameID:       values["gameId"],
+		Queue:        values["queue"],
+	}
+
+	player.ConnectedAt, _ = time.Parse(time.RFC3339Nano, values["connectedAt"])
+	player.LastActivity, _ = time.Parse(time.RFC3339Nano, values["lastActivity"])
+	player.InQueue, _ = strconv.ParseBool(values["inQueue"])
+	player.Registered, _ = strconv.ParseBool(values["registered"])
+	player.QueuedAt, _ = time.Parse(time.RFC3339Nano, values["queuedAt"])
+	player.TotalGames, _ = strconv.Atoi(values["totalGames"])
+	player.Wins, _ = strconv.Atoi(values["wins"])
+	player.Losses, _ = strconv.Atoi(values["losses"])
+	player.HighScore, _ = strconv.Atoi(values["highScore"])
+	player.Rating.Value, _ = strconv.ParseFloat(values["rating"], 64)
+	player.Rating.Deviation, _ = strconv.ParseFloat(values["ratingDeviation"], 64)
+	player.Rating.Volatility, _ = strconv.ParseFloat(values["ratingVolatility"], 64)
+	player.SeriesPlayed, _ = strconv.Atoi(values["seriesPlayed"])
+	player.SeriesWins, _ = strconv.Atoi(values["seriesWins"])
+	player.RoyaleGames, _ = strconv.Atoi(values["royaleGames"])
+	player.PlacementTotal, _ = strconv.Atoi(values["placementTotal"])
+
+	return player
+}
@@ -0,0 +1,305 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func TestPlayerStore_CreateAndLookup(t *testing.T) {
+	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	store := NewPlayerStore(client)
+
+	player := &models.Player{
+		ID:           "test-player-1",
+		Username:     "redisuser1",
+		SessionToken: "redis-token-1",
+		ConnectedAt:  time.Now(),
+		LastActivity: time.Now(),
+	}
+
+	err := store.CreatePlayer(player)
+	if err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+	defer store.DeletePlayer(player.ID)
+
+	retrieved, err := store.GetPlayer(player.ID)
+	if err != nil {
+		t.Fatalf("GetPlayer failed: %v", err)
+	}
+	if retrieved.Username != player.Username {
+		t.Errorf("Expected username %s, got %s", player.Username, retrieved.Username)
+	}
+
+	byName, err := store.GetPlayerByUsername("redisuser1")
+	if err != nil || byName.ID != player.ID {
+		t.Errorf("Expected lookup by username to find %s, got %v (%v)", player.ID, byName, err)
+	}
+
+	byToken, err := store.GetPlayerByToken("redis-token-1")
+	if err != nil || byToken.ID != player.ID {
+		t.Errorf("Expected lookup by token to find %s, got %v (%v)", player.ID, byToken, err)
+	}
+
+	// Username is reserved for the life of the session
+	duplicate := &models.Player{ID: "test-player-dup", Username: "redisuser1", SessionToken: "redis-token-dup"}
+	if err := store.CreatePlayer(duplicate); err != storage.ErrUsernameTaken {
+		t.Errorf("Expected ErrUsernameTaken, got %v", err)
+	}
+
+	// Indexes are removed with the player
+	err = store.DeletePlayer(player.ID)
+	if err != nil {
+		t.Fatalf("DeletePlayer failed: %v", err)
+	}
+	if _, err := store.GetPlayerByToken("redis-token-1"); err == nil {
+		t.Error("Expected token lookup to fail after delete")
+	}
+	if _, err := store.GetPlayerByUsername("redisuser1"); err == nil {
+		t.Error("Expected username lookup to fail after delete")
+	}
+}
+
+func TestPlayerStore_UpdatePlayerKeepsStats(t *testing.T) {
+	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	store := NewPlayerStore(client)
+
+	player := &models.Player{ID: "test-player-2", Username: "redisuser2", SessionToken: "redis-token-2"}
+	err := store.CreatePlayer(player)
+	if err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+	defer store.DeletePlayer(player.ID)
+
+	rating := models.Rating{Value: 1662.3, Deviation: 290.2, Volatility: 0.06}
+	_, err = store.RecordGameResult(player.ID, storage.OutcomeWin, 1500, 2, rating)
+	if err != nil {
+		t.Fatalf("RecordGameResult failed: %v", err)
+	}
+
+	if err := store.SetPlayerGameID(player.ID, "some-game"); err != nil {
+		t.Fatalf("SetPlayerGameID failed: %v", err)
+	}
+
+	// A stale copy must not overwrite stats or the player's game
+	player.LastActivity = time.Now()
+	err = store.UpdatePlayer(player)
+	if err != nil {
+		t.Fatalf("UpdatePlayer failed: %v", err)
+	}
+
+	retrieved, err := store.GetPlayer(player.ID)
+	if err != nil {
+		t.Fatalf("GetPlayer failed: %v", err)
+	}
+	if !retrieved.LastActivity.Equal(player.LastActivity) {
+		t.Errorf("Expected last activity %v, got %v", player.LastActivity, retrieved.LastActivity)
+	}
+	if retrieved.GameID != "some-game" {
+		t.Errorf("Expected game to survive update, got %q", retrieved.GameID)
+	}
+	if retrieved.Wins != 1 || retrieved.HighScore != 1500 {
+		t.Errorf("Expected stats to survive update, got wins=%d highScore=%d", retrieved.Wins, retrieved.HighScore)
+	}
+	if retrieved.Rating != rating {
+		t.Errorf("Expected rating %+v, got %+v", rating, retrieved.Rating)
+	}
+	if retrieved.RoyaleGames != 1 || retrieved.PlacementTotal != 2 {
+		t.Errorf("Expected placement to survive update, got %d games totalling %d", retrieved.RoyaleGames, retrieved.PlacementTotal)
+	}
+
+	ttl := client.TTL(t.Context(), playerKeyPrefix+player.ID).Val()
+	if ttl <= 0 || ttl > sessionTTL {
+		t.Errorf("Expected session TTL to be set, got %v", ttl)
+	}
+}
+
+func TestPlayerStore_FieldUpdates(t *testing.T) {
+	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	store := NewPlayerStore(client)
+
+	player := &models.Player{ID: "test-player-fields", Username: "redisfields", SessionToken: "redis-token-fields"}
+	err := store.CreatePlayer(player)
+	if err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+	defer store.DeletePlayer(player.ID)
+
+	queuedAt := time.Now().Add(-time.Minute)
+	if err := store.SetPlayerQueue(player.ID, "classic", queuedAt); err != nil {
+		t.Fatalf("SetPlayerQueue failed: %v", err)
+	}
+	if err := store.SetPlayerGameID(player.ID, "game-1"); err != nil {
+		t.Fatalf("SetPlayerGameID failed: %v", err)
+	}
+	activity := time.Now()
+	if err := store.TouchPlayer(player.ID, activity); err != nil {
+		t.Fatalf("TouchPlayer failed: %v", err)
+	}
+
+	retrieved, err := store.GetPlayer(player.ID)
+	if err != nil {
+		t.Fatalf("GetPlayer failed: %v", err)
+	}
+	if retrieved.GameID != "game-1" || !retrieved.InQueue || retrieved.Queue != "classic" || !retrieved.QueuedAt.Equal(queuedAt) {
+		t.Errorf("Expected game and queue to survive a touch, got %+v", retrieved)
+	}
+	if !retrieved.LastActivity.Equal(activity) {
+		t.Errorf("Expected last activity %v, got %v", activity, retrieved.LastActivity)
+	}
+
+	// Clearing another queue leaves the player waiting in theirs
+	if err := store.ClearPlayerQueue(player.ID, "sprint"); err != nil {
+		t.Fatalf("ClearPlayerQueue failed: %v", err)
+	}
+	if retrieved, _ := store.GetPlayer(player.ID); retrieved.Queue != "classic" {
+		t.Errorf("Expected player to stay in classic, got %q", retrieved.Queue)
+	}
+	if err := store.ClearPlayerQueue(player.ID, "classic"); err != nil {
+		t.Fatalf("ClearPlayerQueue failed: %v", err)
+	}
+	if retrieved, _ := store.GetPlayer(player.ID); retrieved.InQueue || retrieved.Queue != "" {
+		t.Errorf("Expected player out of the queue, got inQueue=%v queue=%q", retrieved.InQueue, retrieved.Queue)
+	}
+
+	// Updates racing a delete must not bring back a partial player
+	if err := store.DeletePlayer(player.ID); err != nil {
+		t.Fatalf("DeletePlayer failed: %v", err)
+	}
+	if err := store.TouchPlayer(player.ID, time.Now()); err == nil {
+		t.Error("Expected an error touching a deleted player")
+	}
+	if err := store.UpdatePlayer(player); err == nil {
+		t.Error("Expected an error updating a deleted player")
+	}
+	if exists := client.Exists(t.Context(), playerKeyPrefix+player.ID).Val(); exists != 0 {
+		t.Error("Expected deleted player to stay deleted")
+	}
+}
+
+func TestPlayerStore_RecordGameResultConcurrent(t *testing.T) {
+	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	store := NewPlayerStore(client)
+
+	player := &models.Player{ID: "test-player-3", Username: "redisuser3", SessionToken: "redis-token-3"}
+	err := store.CreatePlayer(player)
+	if err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+	defer store.DeletePlayer(player.ID)
+
+	const numResults = 20
+	var wg sync.WaitGroup
+	for i := 0; i < numResults; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			outcome := storage.OutcomeWin
+			if i%2 == 1 {
+				outcome = storage.OutcomeLoss
+			}
+			if _, err := store.RecordGameResult(player.ID, outcome, i*100, 0, models.Rating{}); err != nil {
+				t.Errorf("RecordGameResult failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	retrieved, err := store.GetPlayer(player.ID)
+	if err != nil {
+		t.Fatalf("GetPlayer failed: %v", err)
+	}
+	if retrieved.TotalGames != numResults || retrieved.Wins != numResults/2 || retrieved.Losses != numResults/2 {
+		t.Errorf("Expected %d games split evenly, got total=%d wins=%d losses=%d",
+			numResults, retrieved.TotalGames, retrieved.Wins, retrieved.Losses)
+	}
+	if retrieved.HighScore != (numResults-1)*100 {
+		t.Errorf("Expected high score %d, got %d", (numResults-1)*100, retrieved.HighScore)
+	}
+}
+
+func TestPlayerStore_RecordSeriesResult(t *testing.T) {
+	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	store := NewPlayerStore(client)
+
+	player := &models.Player{ID: "test-player-4", Username: "redisuser4", SessionToken: "redis-token-4"}
+	err := store.CreatePlayer(player)
+	if err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+	defer store.DeletePlayer(player.ID)
+
+	if _, err := store.RecordSeriesResult(player.ID, true); err != nil {
+		t.Fatalf("RecordSeriesResult failed: %v", err)
+	}
+	updated, err := store.RecordSeriesResult(player.ID, false)
+	if err != nil {
+		t.Fatalf("RecordSeriesResult failed: %v", err)
+	}
+
+	if updated.SeriesPlayed != 2 || updated.SeriesWins != 1 {
+		t.Errorf("Expected 1 of 2 series won, got %d of %d", updated.SeriesWins, updated.SeriesPlayed)
+	}
+	if _, err := store.RecordSeriesResult("missing-player", true); err == nil {
+		t.Error("Expected error recording a series for a missing player")
+	}
+}
+
+func TestPlayerStore_RegisteredSession(t *testing.T) {
+	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	store := NewPlayerStore(client)
+
+	player := &models.Player{
+		ID:           "test-player-registered",
+		Username:     "redisregistered",
+		SessionToken: "redis-token-old",
+		Registered:   true,
+	}
+	err := store.CreatePlayer(player)
+	if err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+	defer store.DeletePlayer(player.ID)
+
+	// Registered players don't expire
+	ctx := context.Background()
+	if ttl := client.TTL(ctx, playerKeyPrefix+player.ID).Val(); ttl != -1 {
+		t.Errorf("Expected no TTL on a registered player, got %v", ttl)
+	}
+	if ttl := client.TTL(ctx, usernameKeyPrefix+player.Username).Val(); ttl != -1 {
+		t.Errorf("Expected no TTL on a registered username, got %v", ttl)
+	}
+
+	if err := store.SetSessionToken(player.ID, "redis-token-new"); err != nil {
+		t.Fatalf("SetSessionToken failed: %v", err)
+	}
+
+	// A stale copy written back can't revive the old token
+	if err := store.UpdatePlayer(player); err != nil {
+		t.Fatalf("UpdatePlayer failed: %v", err)
+	}
+	if _, err := store.GetPlayerByToken("redis-token-old"); err == nil {
+		t.Error("Expected old token to be invalid")
+	}
+	if retrieved, err := store.GetPlayerByToken("redis-token-new"); err != nil || !retrieved.Registered {
+		t.Errorf("Expected new token to find the registered player, got %v", err)
+	}
+
+	// Ending the session keeps the player
+	if err := store.SetSessionToken(player.ID, ""); err != nil {
+		t.Fatalf("SetSessionToken failed: %v", err)
+	}
+	if _, err := store.GetPlayerByToken("redis-token-new"); err == nil {
+		t.Error("Expected token to be invalid after the session ended")
+	}
+	if _, err := store.GetPlayerByUsername(player.Username); err != nil {
+		t.Errorf("Expected registered player to remain, got %v", err)
+	}
+}
@@ -3,9 +3,45 @@ package redis
 import (
 	"context"
 	"time"
+
+	"github.com/redis/go-redis/v9"
 )
 
-const queueKey = "matchmaking:queue"
+const queueKeyPrefix = "matchmaking:queue:"
+
+// queueKey returns the list holding a queue's players
+func queueKey(queue string) string {
+	return queueKeyPrefix + queue
+}
+
+// removePairScript claims two players in one step so concurrent matchmakers
+// on different instances can never pair the same player
+var removePairScript = redis.NewScript(`
+local found1, found2 = false, false
+for _, id in ipairs(redis.call('LRANGE', KEYS[1], 0, -1)) do
+	if id == ARGV[1] then found1 = true end
+	if id == ARGV[2] then found2 = true end
+end
+if not (found1 and found2) then
+	return 0
+end
+redis.call('LREM', KEYS[1], 0, ARGV[1])
+redis.call('LREM', KEYS[1], 0, ARGV[2])
+return 1
+`)
+
+// restoreScript puts a claimed player (ARGV[1]) back right behind the last of
+// the players ahead of them (ARGV[2..]) still queued, or at the front
+var restoreScript = redis.NewScript(`
+redis.call('LREM', KEYS[1], 0, ARGV[1])
+for i = #ARGV, 2, -1 do
+	if redis.call('LINSERT', KEYS[1], 'AFTER', ARGV[i], ARGV[1]) > 0 then
+		return 1
+	end
+end
+redis.call('LPUSH', KEYS[1], ARGV[1])
+return 1
+`)
 
 // QueueStore implements Redis-based matchmaking queue
 type QueueStore struct {
@@ -17,38 +53,43 @@ func NewQueueStore(client *Client) *QueueStore {
 	return &QueueStore{client: client}
 }
 
-// AddToQueue adds a player to the matchmaking queue
-func (s *QueueStore) AddToQueue(playerID string) error {
+// AddToQueue adds a player to a matchmaking queue
+func (s *QueueStore) AddToQueue(queue, playerID string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	// Remove player if already in queue, then add to end
-	s.client.LRem(ctx, queueKey, 0, playerID)
-	return s.client.RPush(ctx, queueKey, playerID).Err()
+	// Remove player if already in queue, then add to end, in one transaction
+	// so a concurrent add can't leave them queued twice
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LRem(ctx, queueKey(queue), 0, playerID)
+		pipe.RPush(ctx, queueKey(queue), playerID)
+		return nil
+	})
+	return err
 }
 
-// RemoveFromQueue removes a player from the queue
-func (s *QueueStore) RemoveFromQueue(playerID string) error {
+// RemoveFromQueue removes a player from a queue
+func (s *QueueStore) RemoveFromQueue(queue, playerID string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	return s.client.LRem(ctx, queueKey, 0, playerID).Err()
+	return s.client.LRem(ctx, queueKey(queue), 0, playerID).Err()
 }
 
-// GetQueuedPlayers returns all players in the queue
-func (s *QueueStore) GetQueuedPlayers() ([]string, error) {
+// GetQueuedPlayers returns all players in a queue
+func (s *QueueStore) GetQueuedPlayers(queue string) ([]string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	return s.client.LRange(ctx, queueKey, 0, -1).Result()
+	return s.client.LRange(ctx, queueKey(queue), 0, -1).Result()
 }
 
-// GetQueuePosition returns the position of a player in the queue (0-based)
-func (s *QueueStore) GetQueuePosition(playerID string) (int, error) {
+// GetQueuePosition returns the position of a player in a queue (0-based)
+func (s *QueueStore) GetQueuePosition(queue, playerID string) (int, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	players, err := s.client.LRange(ctx, queueKey, 0, -1).Result()
+	players, err := s.client.LRange(ctx, queueKey(queue), 0, -1).Result()
 	if err != nil {
 		return -1, err
 	}
@@ -62,6 +103,36 @@ func (s *QueueStore) GetQueuePosition(playerID string) (int, error) {
 	return -1, nil // Not in queue
 }
 
+// RemovePair atomically removes two players if both are still in the queue
+func (s *QueueStore) RemovePair(queue, player1ID, player2ID string) (bool, error) {
+	if player1ID == player2ID {
+		return false, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	removed, err := removePairScript.Run(ctx, s.client, []string{queueKey(queue)}, player1ID, player2ID).Int()
+	if err != nil {
+		return false, err
+	}
+	return removed == 1, nil
+}
+
+// RestoreToQueue atomically puts a claimed player back behind the last of
+// ahead still queued
+func (s *QueueStore) RestoreToQueue(queue, playerID string, ahead []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	args := make([]interface{}, 0, len(ahead)+1)
+	args = append(args, playerID)
+	for _, id := range ahead {
+		args = append(args, id)
+	}
+	return restoreScript.Run(ctx, s.client, []string{queueKey(queue)}, args...).Err()
+}
+
 // HealthCheck implements storage.HealthChecker
 func (s *QueueStore) HealthCheck() error {
 	return s.client.HealthCheck()
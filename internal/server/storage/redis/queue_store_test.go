@@ -2,11 +2,16 @@ package redis
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"sync"
 	"testing"
 
 	"github.com/redis/go-redis/v9"
 )
 
+const testQueue = "ranked:classic"
+
 func TestQueueStore_AddToQueue(t *testing.T) {
 	// Use Redis mock for unit testing
 	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
@@ -14,16 +19,16 @@ func TestQueueStore_AddToQueue(t *testing.T) {
 
 	// Clear entire queue before test
 	ctx := context.Background()
-	client.Del(ctx, "matchmaking:queue")
+	client.Del(ctx, queueKey(testQueue))
 
 	// Test adding player to queue
-	err := store.AddToQueue("player1")
+	err := store.AddToQueue(testQueue, "player1")
 	if err != nil {
 		t.Skipf("Redis not available for testing: %v", err)
 	}
 
 	// Test getting queue position
-	pos, err := store.GetQueuePosition("player1")
+	pos, err := store.GetQueuePosition(testQueue, "player1")
 	if err != nil {
 		t.Fatalf("GetQueuePosition failed: %v", err)
 	}
@@ -32,7 +37,7 @@ func TestQueueStore_AddToQueue(t *testing.T) {
 	}
 
 	// Clean up
-	store.RemoveFromQueue("player1")
+	store.RemoveFromQueue(testQueue, "player1")
 }
 
 func TestQueueStore_GetQueuedPlayers(t *testing.T) {
@@ -40,16 +45,16 @@ func TestQueueStore_GetQueuedPlayers(t *testing.T) {
 	store := NewQueueStore(client)
 
 	// Test with empty queue
-	players, err := store.GetQueuedPlayers()
+	players, err := store.GetQueuedPlayers(testQueue)
 	if err != nil {
 		t.Skipf("Redis not available for testing: %v", err)
 	}
 
 	// Add players
-	store.AddToQueue("player1")
-	store.AddToQueue("player2")
+	store.AddToQueue(testQueue, "player1")
+	store.AddToQueue(testQueue, "player2")
 
-	players, err = store.GetQueuedPlayers()
+	players, err = store.GetQueuedPlayers(testQueue)
 	if err != nil {
 		t.Fatalf("GetQueuedPlayers failed: %v", err)
 	}
@@ -59,8 +64,8 @@ func TestQueueStore_GetQueuedPlayers(t *testing.T) {
 	}
 
 	// Clean up
-	store.RemoveFromQueue("player1")
-	store.RemoveFromQueue("player2")
+	store.RemoveFromQueue(testQueue, "player1")
+	store.RemoveFromQueue(testQueue, "player2")
 }
 
 func TestQueueStore_HealthCheck(t *testing.T) {
@@ -72,3 +77,102 @@ func TestQueueStore_HealthCheck(t *testing.T) {
 		t.Skipf("Redis not available for testing: %v", err)
 	}
 }
+
+func TestQueueStore_RemovePairConcurrent(t *testing.T) {
+	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	if err := client.HealthCheck(); err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+
+	ctx := context.Background()
+	client.Del(ctx, queueKey(testQueue))
+	defer client.Del(ctx, queueKey(testQueue))
+
+	const numPlayers = 400
+	seeder := NewQueueStore(client)
+	for i := 0; i < numPlayers; i++ {
+		if err := seeder.AddToQueue(testQueue, fmt.Sprintf("claim-player%d", i)); err != nil {
+			t.Fatalf("AddToQueue failed: %v", err)
+		}
+	}
+
+	// Each worker has its own client, like matchmakers on separate instances
+	var mu sync.Mutex
+	seen := make(map[string]int)
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			instance := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+			defer instance.Close()
+			store := NewQueueStore(instance)
+			for {
+				queued, err := store.GetQueuedPlayers(testQueue)
+				if err != nil {
+					t.Errorf("GetQueuedPlayers failed: %v", err)
+					return
+				}
+				if len(queued) < 2 {
+					return
+				}
+				claimed, err := store.RemovePair(testQueue, queued[0], queued[1])
+				if err != nil {
+					t.Errorf("RemovePair failed: %v", err)
+					return
+				}
+				if claimed {
+					mu.Lock()
+					seen[queued[0]]++
+					seen[queued[1]]++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != numPlayers {
+		t.Errorf("Expected %d players claimed, got %d", numPlayers, len(seen))
+	}
+	for playerID, count := range seen {
+		if count != 1 {
+			t.Errorf("Player %s claimed %d times", playerID, count)
+		}
+	}
+}
+
+func TestQueueStore_RestoreToQueue(t *testing.T) {
+	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	if err := client.HealthCheck(); err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+
+	ctx := context.Background()
+	client.Del(ctx, queueKey(testQueue))
+	defer client.Del(ctx, queueKey(testQueue))
+
+	store := NewQueueStore(client)
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		store.AddToQueue(testQueue, id)
+	}
+	if claimed, err := store.RemovePair(testQueue, "b", "d"); err != nil || !claimed {
+		t.Fatalf("Expected b and d to be claimed, got %v (%v)", claimed, err)
+	}
+
+	// The queue moves on before the match falls through
+	store.RemoveFromQueue(testQueue, "a")
+	store.AddToQueue(testQueue, "f")
+
+	if err := store.RestoreToQueue(testQueue, "d", []string{"a", "b", "c"}); err != nil {
+		t.Fatalf("RestoreToQueue failed: %v", err)
+	}
+	if err := store.RestoreToQueue(testQueue, "b", []string{"a"}); err != nil {
+		t.Fatalf("RestoreToQueue failed: %v", err)
+	}
+
+	players, _ := store.GetQueuedPlayers(testQueue)
+	if !slices.Equal(players, []string{"b", "c", "d", "e", "f"}) {
+		t.Errorf("Expected claimed players back in their places, got %v", players)
+	}
+}
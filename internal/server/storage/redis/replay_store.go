@@ -0,0 +1,199 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+const replayKeyPrefix = "replay:"
+
+// replayEventsKey returns the list holding a replay's events
+func replayEventsKey(gameID string) string {
+	return replayKeyPrefix + gameID + ":events"
+}
+
+// appendEventScript appends an event unless the replay is missing or full,
+// marking it truncated once full. Returns 0 if missing, 1 if full and 2 once
+// appended.
+var appendEventScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+if redis.call('LLEN', KEYS[2]) >= tonumber(ARGV[2]) then
+	redis.call('HSET', KEYS[1], 'truncated', '1')
+	return 1
+end
+redis.call('RPUSH', KEYS[2], ARGV[1])
+redis.call('PEXPIRE', KEYS[2], ARGV[3])
+return 2
+`)
+
+// ReplayStore implements Redis-based replay storage. Each replay is a hash
+// holding the game's details and result, plus a list of its events. Both
+// keys expire once the replay hasn't been written for the retention period.
+type ReplayStore struct {
+	client    *Client
+	retention time.Duration
+}
+
+// NewReplayStore creates a new Redis replay store keeping replays for
+// storage.DefaultReplayRetention
+func NewReplayStore(client *Client) *ReplayStore {
+	return &ReplayStore{client: client, retention: storage.DefaultReplayRetention}
+}
+
+// SetRetention sets how long replays are kept after they were last written
+func (s *ReplayStore) SetRetention(retention time.Duration) {
+	s.retention = retention
+}
+
+// CreateReplay starts recording a game
+func (s *ReplayStore) CreateReplay(replay *models.Replay) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	header := *replay
+	header.Events = nil
+	data, err := json.Marshal(&header)
+	if err != nil {
+		return err
+	}
+
+	replayKey := replayKeyPrefix + replay.GameID
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, replayKey, replayEventsKey(replay.GameID))
+		pipe.HSet(ctx, replayKey, "data", data)
+		pipe.Expire(ctx, replayKey, s.retention)
+		return nil
+	})
+	return err
+}
+
+// AppendReplayEvent adds an event to a game's replay
+func (s *ReplayStore) AppendReplayEvent(gameID string, event *models.ReplayEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	keys := []string{replayKeyPrefix + gameID, replayEventsKey(gameID)}
+	result, err := appendEventScript.Run(ctx, s.client, keys,
+		data, storage.MaxReplayEvents, s.retention.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+
+	switch result {
+	case 0:
+		return storage.ErrReplayNotFound
+	case 1:
+		return storage.ErrReplayFull
+	}
+	return nil
+}
+
+// FinishReplay records a finished game's result. The replay is kept for the
+// retention period from now.
+func (s *ReplayStore) FinishReplay(gameID, winnerID string, players []*models.ReplayPlayer) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	replayKey := replayKeyPrefix + gameID
+	data, err := s.client.HGet(ctx, replayKey, "data").Bytes()
+	if errors.Is(err, redis.Nil) {
+		return storage.ErrReplayNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	var replay models.Replay
+	if err := json.Unmarshal(data, &replay); err != nil {
+		return err
+	}
+	replay.WinnerID = winnerID
+	replay.Players = players
+	replay.Finished = true
+	replay.EndedAt = time.Now()
+
+	data, err = json.Marshal(&replay)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, replayKey, "data", data)
+		pipe.Expire(ctx, replayKey, s.retention)
+		pipe.Expire(ctx, replayEventsKey(gameID), s.retention)
+		return nil
+	})
+	return err
+}
+
+// GetReplay retrieves a game's replay with all of its events
+func (s *ReplayStore) GetReplay(gameID string) (*models.Replay, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var header *redis.MapStringStringCmd
+	var events *redis.StringSliceCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		header = pipe.HGetAll(ctx, replayKeyPrefix+gameID)
+		events = pipe.LRange(ctx, replayEventsKey(gameID), 0, -1)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	values := header.Val()
+	if values["data"] == "" {
+		return nil, storage.ErrReplayNotFound
+	}
+
+	var replay models.Replay
+	if err := json.Unmarshal([]byte(values["data"]), &replay); err != nil {
+		return nil, err
+	}
+	replay.Truncated = values["truncated"] == "1"
+
+	replay.Events = make([]*models.ReplayEvent, 0, len(events.Val()))
+	for _, data := range events.Val() {
+		var event models.ReplayEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return nil, err
+		}
+		replay.Events = append(replay.Events, &event)
+	}
+	return &replay, nil
+}
+
+// DeleteReplay removes a game's replay and its events
+func (s *ReplayStore) DeleteReplay(gameID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	deleted, err := s.client.Del(ctx, replayKeyPrefix+gameID, replayEventsKey(gameID)).Result()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return storage.ErrReplayNotFound
+	}
+	return nil
+}
+
+// DeleteExpiredReplays does nothing; Redis expires replays itself
+func (s *ReplayStore) DeleteExpiredReplays() (int, error) {
+	return 0, nil
+}
@@ -0,0 +1,109 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func TestReplayStore_RecordAndFinish(t *testing.T) {
+	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	store := NewReplayStore(client)
+	store.SetRetention(time.Hour)
+
+	replay := &models.Replay{GameID: "redis-replay-1", Seed: 9007199254740993, Players: []*models.ReplayPlayer{{ID: "p1"}, {ID: "p2"}}}
+	if err := store.CreateReplay(replay); err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+	defer client.Del(context.Background(), replayKeyPrefix+replay.GameID, replayEventsKey(replay.GameID))
+
+	if err := store.AppendReplayEvent("redis-replay-missing", &models.ReplayEvent{}); !errors.Is(err, storage.ErrReplayNotFound) {
+		t.Errorf("Expected ErrReplayNotFound, got %v", err)
+	}
+
+	move := &models.GameMove{MoveType: "left", Piece: &models.Piece{Type: 3, X: 4}}
+	state := &models.GameState{Board: [][]int{{0, 1}}, Score: 100}
+	if err := store.AppendReplayEvent(replay.GameID, &models.ReplayEvent{Type: models.ReplayEventMove, PlayerID: "p1", Move: move}); err != nil {
+		t.Fatalf("AppendReplayEvent failed: %v", err)
+	}
+	store.AppendReplayEvent(replay.GameID, &models.ReplayEvent{Type: models.ReplayEventState, PlayerID: "p2", State: state})
+
+	results := []*models.ReplayPlayer{{ID: "p1", Score: 900, Placement: 1}, {ID: "p2", Score: 100, Placement: 2}}
+	if err := store.FinishReplay(replay.GameID, "p1", results); err != nil {
+		t.Fatalf("FinishReplay failed: %v", err)
+	}
+
+	retrieved, err := store.GetReplay(replay.GameID)
+	if err != nil {
+		t.Fatalf("GetReplay failed: %v", err)
+	}
+	if retrieved.Seed != replay.Seed || !retrieved.Finished || retrieved.WinnerID != "p1" || retrieved.Players[1].Score != 100 {
+		t.Errorf("Expected the game's details and result, got %+v", retrieved)
+	}
+	if len(retrieved.Events) != 2 || retrieved.Events[0].Move.Piece.X != 4 || retrieved.Events[1].State.Score != 100 {
+		t.Errorf("Expected the move then the state, got %+v", retrieved.Events)
+	}
+	if retrieved.Truncated {
+		t.Error("Expected a short replay not to be truncated")
+	}
+
+	if _, err := store.GetReplay("redis-replay-missing"); !errors.Is(err, storage.ErrReplayNotFound) {
+		t.Errorf("Expected ErrReplayNotFound, got %v", err)
+	}
+
+	// Both keys expire on their own after the retention period
+	for _, key := range []string{replayKeyPrefix + replay.GameID, replayEventsKey(replay.GameID)} {
+		if ttl := client.TTL(context.Background(), key).Val(); ttl <= 0 || ttl > time.Hour {
+			t.Errorf("Expected %s to expire within an hour, got TTL %s", key, ttl)
+		}
+	}
+
+	if err := store.DeleteReplay(replay.GameID); err != nil {
+		t.Fatalf("DeleteReplay failed: %v", err)
+	}
+	if exists := client.Exists(context.Background(), replayKeyPrefix+replay.GameID, replayEventsKey(replay.GameID)).Val(); exists != 0 {
+		t.Errorf("Expected both replay keys to be deleted, %d remain", exists)
+	}
+	if err := store.DeleteReplay(replay.GameID); !errors.Is(err, storage.ErrReplayNotFound) {
+		t.Errorf("Expected ErrReplayNotFound deleting twice, got %v", err)
+	}
+}
+
+func TestReplayStore_Truncates(t *testing.T) {
+	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	store := NewReplayStore(client)
+
+	replay := &models.Replay{GameID: "redis-replay-long"}
+	if err := store.CreateReplay(replay); err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+	ctx := context.Background()
+	defer client.Del(ctx, replayKeyPrefix+replay.GameID, replayEventsKey(replay.GameID))
+
+	// Fill the event list in one command
+	events := make([]interface{}, storage.MaxReplayEvents)
+	for i := range events {
+		events[i] = `{"type":"move"}`
+	}
+	if err := client.RPush(ctx, replayEventsKey(replay.GameID), events...).Err(); err != nil {
+		t.Fatalf("Failed to fill replay: %v", err)
+	}
+
+	if err := store.AppendReplayEvent(replay.GameID, &models.ReplayEvent{}); !errors.Is(err, storage.ErrReplayFull) {
+		t.Errorf("Expected ErrReplayFull, got %v", err)
+	}
+
+	retrieved, err := store.GetReplay(replay.GameID)
+	if err != nil {
+		t.Fatalf("GetReplay failed: %v", err)
+	}
+	if !retrieved.Truncated || len(retrieved.Events) != storage.MaxReplayEvents {
+		t.Errorf("Expected a truncated replay of %d events, got %d (truncated %v)", storage.MaxReplayEvents, len(retrieved.Events), retrieved.Truncated)
+	}
+}
@@ -0,0 +1,179 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+const (
+	roomKeyPrefix = "room:"
+	roomTTL       = time.Hour // Unused rooms expire on their own
+)
+
+// joinRoomScript seats a guest only if the room exists and has a free seat,
+// so guests joining through different instances can't overfill it. Guests
+// are kept as a comma separated list; a missing list means the room is still
+// being created.
+var joinRoomScript = redis.NewScript(`
+local guests = redis.call('HGET', KEYS[1], 'guestIds')
+if not guests then
+	return 0
+end
+local count = 0
+for guest in string.gmatch(guests, '[^,]+') do
+	if guest == ARGV[1] then
+		return 2
+	end
+	count = count + 1
+end
+local maxPlayers = tonumber(redis.call('HGET', KEYS[1], 'maxPlayers') or '2')
+if count + 1 >= maxPlayers then
+	return 1
+end
+if guests ~= '' then
+	guests = guests .. ','
+end
+redis.call('HSET', KEYS[1], 'guestIds', guests .. ARGV[1])
+return 2
+`)
+
+// RoomStore implements Redis-based private room storage. Each room is a hash
+// that expires an hour after creation.
+type RoomStore struct {
+	client *Client
+}
+
+// NewRoomStore creates a new Redis room store
+func NewRoomStore(client *Client) *RoomStore {
+	return &RoomStore{client: client}
+}
+
+// CreateRoom stores a new room
+func (s *RoomStore) CreateRoom(room *models.Room) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	roomKey := roomKeyPrefix + room.Code
+
+	// Claim the code first so concurrent creators can't share it
+	created, err := s.client.HSetNX(ctx, roomKey, "code", room.Code).Result()
+	if err != nil {
+		return err
+	}
+	if !created {
+		return storage.ErrRoomCodeTaken
+	}
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, roomKey, map[string]interface{}{
+			"hostId":     room.HostID,
+			"guestIds":   strings.Join(room.GuestIDs, ","),
+			"mode":       room.Settings.Mode,
+			"seed":       room.Settings.Seed,
+			"garbage":    strconv.FormatBool(room.Settings.Garbage),
+			"bestOf":     room.Settings.BestOf,
+			"maxPlayers": room.Settings.MaxPlayers,
+			"targeting":  string(room.Settings.Targeting),
+			"createdAt":  room.CreatedAt.Format(time.RFC3339Nano),
+		})
+		pipe.Expire(ctx, roomKey, roomTTL)
+		return nil
+	})
+	return err
+}
+
+// GetRoom retrieves a room by code
+func (s *RoomStore) GetRoom(code string) (*models.Room, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	values, err := s.client.HGetAll(ctx, roomKeyPrefix+code).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, storage.ErrRoomNotFound
+	}
+
+	return roomFromHash(values), nil
+}
+
+// JoinRoom atomically seats a guest if the room has a free seat
+func (s *RoomStore) JoinRoom(code, playerID string) (*models.Room, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := joinRoomScript.Run(ctx, s.client, []string{roomKeyPrefix + code}, playerID).Int()
+	if err != nil {
+		return nil, err
+	}
+
+	switch result {
+	case 0:
+		return nil, storage.ErrRoomNotFound
+	case 1:
+		return nil, storage.ErrRoomFull
+	}
+	return s.GetRoom(code)
+}
+
+// TakeRoom atomically removes and returns a room
+func (s *RoomStore) TakeRoom(code string) (*models.Room, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	roomKey := roomKeyPrefix + code
+
+	var values *redis.MapStringStringCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		values = pipe.HGetAll(ctx, roomKey)
+		pipe.Del(ctx, roomKey)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(values.Val()) == 0 {
+		return nil, storage.ErrRoomNotFound
+	}
+
+	return roomFromHash(values.Val()), nil
+}
+
+// DeleteRoom removes a room
+func (s *RoomStore) DeleteRoom(code string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return s.client.Del(ctx, roomKeyPrefix+code).Err()
+}
+
+// roomFromHash rebuilds a room from its hash fields
+func roomFromHash(values map[string]string) *models.Room {
+	room := &models.Room{
+		Code:   values["code"],
+		HostID: values["hostId"],
+		Settings: models.RoomSettings{
+			Mode:      values["mode"],
+			Targeting: models.TargetingStrategy(values["targeting"]),
+		},
+	}
+	if values["guestIds"] != "" {
+		room.GuestIDs = strings.Split(values["guestIds"], ",")
+	}
+
+	room.Settings.Seed, _ = strconv.ParseInt(values["seed"], 10, 64)
+	room.Settings.Garbage, _ = strconv.ParseBool(values["garbage"])
+	room.Settings.BestOf, _ = strconv.Atoi(values["bestOf"])
+	room.Settings.MaxPlayers, _ = strconv.Atoi(values["maxPlayers"])
+	room.CreatedAt, _ = time.Parse(time.RFC3339Nano, values["createdAt"])
+
+	return room
+}
@@ -0,0 +1,122 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/briancain/go-tetris/internal/server/storage"
+	"github.com/briancain/go-tetris/pkg/models"
+)
+
+func TestRoomStore_CreateAndGet(t *testing.T) {
+	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	store := NewRoomStore(client)
+
+	room := &models.Room{
+		Code:      "REDIS2",
+		HostID:    "host",
+		Settings:  models.RoomSettings{Mode: "classic", Seed: 9007199254740993, Garbage: true, BestOf: 5, MaxPlayers: 2, Targeting: models.TargetKOs},
+		CreatedAt: time.Now(),
+	}
+	err := store.CreateRoom(room)
+	if err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+	defer store.DeleteRoom(room.Code)
+
+	if err := store.CreateRoom(room); !errors.Is(err, storage.ErrRoomCodeTaken) {
+		t.Errorf("Expected ErrRoomCodeTaken for duplicate code, got %v", err)
+	}
+
+	retrieved, err := store.GetRoom(room.Code)
+	if err != nil {
+		t.Fatalf("GetRoom failed: %v", err)
+	}
+	if retrieved.HostID != "host" || retrieved.Settings != room.Settings {
+		t.Errorf("Expected %+v, got %+v", room.Settings, retrieved.Settings)
+	}
+}
+
+func TestRoomStore_JoinRoomConcurrent(t *testing.T) {
+	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	store := NewRoomStore(client)
+
+	room := &models.Room{Code: "RACE23", HostID: "host", Settings: models.RoomSettings{Mode: "classic", BestOf: 1, MaxPlayers: 4}}
+	err := store.CreateRoom(room)
+	if err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+	defer store.DeleteRoom(room.Code)
+
+	// Many guests race for the three seats
+	var joined atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := store.JoinRoom(room.Code, fmt.Sprintf("guest%d", i))
+			if err == nil {
+				joined.Add(1)
+			} else if !errors.Is(err, storage.ErrRoomFull) {
+				t.Errorf("Expected ErrRoomFull, got %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if joined.Load() != 3 {
+		t.Errorf("Expected exactly three guests to join, got %d", joined.Load())
+	}
+
+	retrieved, err := store.GetRoom(room.Code)
+	if err != nil {
+		t.Fatalf("GetRoom failed: %v", err)
+	}
+	if len(retrieved.GuestIDs) != 3 || !retrieved.Full() {
+		t.Errorf("Expected a full room with three guests, got %+v", retrieved.GuestIDs)
+	}
+
+	if _, err := store.JoinRoom("NOROOM", "guest"); !errors.Is(err, storage.ErrRoomNotFound) {
+		t.Errorf("Expected ErrRoomNotFound, got %v", err)
+	}
+}
+
+func TestRoomStore_TakeRoom(t *testing.T) {
+	client := &Client{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	store := NewRoomStore(client)
+
+	room := &models.Room{Code: "TAKE23", HostID: "host", Settings: models.RoomSettings{Mode: "classic", MaxPlayers: 3}}
+	err := store.CreateRoom(room)
+	if err != nil {
+		t.Skipf("Redis not available for testing: %v", err)
+	}
+	defer store.DeleteRoom(room.Code)
+
+	if _, err := store.JoinRoom(room.Code, "guest1"); err != nil {
+		t.Fatalf("JoinRoom failed: %v", err)
+	}
+	// Joining twice keeps a single seat
+	if _, err := store.JoinRoom(room.Code, "guest1"); err != nil {
+		t.Fatalf("JoinRoom failed: %v", err)
+	}
+
+	taken, err := store.TakeRoom(room.Code)
+	if err != nil {
+		t.Fatalf("TakeRoom failed: %v", err)
+	}
+	if len(taken.GuestIDs) != 1 || taken.GuestIDs[0] != "guest1" || taken.Settings.MaxPlayers != 3 {
+		t.Errorf("Unexpected room: %+v", taken)
+	}
+
+	// A room can only be taken once
+	if _, err := store.TakeRoom(room.Code); !errors.Is(err, storage.ErrRoomNotFound) {
+		t.Errorf("Expected ErrRoomNotFound, got %v", err)
+	}
+}
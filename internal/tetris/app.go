@@ -7,6 +7,20 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
+// targetingKeys choose a garbage targeting strategy during a battle royale
+var targetingKeys = map[ebiten.Key]string{
+	ebiten.Key1: TargetRandom,
+	ebiten.Key2: TargetAttackers,
+	ebiten.Key3: TargetKOs,
+	ebiten.Key4: TargetBadges,
+}
+
+// lobbyKeys pick an entry from a numbered list, such as the spectate lobby
+var lobbyKeys = []ebiten.Key{
+	ebiten.Key1, ebiten.Key2, ebiten.Key3, ebiten.Key4, ebiten.Key5,
+	ebiten.Key6, ebiten.Key7, ebiten.Key8, ebiten.Key9,
+}
+
 // App is the main game application
 type App struct {
 	game     *Game
@@ -39,6 +53,7 @@ func (g *App) Update() error {
 		}
 		if inpututil.IsKeyJustPressed(ebiten.Key2) {
 			// Multiplayer
+			g.game.SpectateMode = false
 			g.game.State = StateMultiplayerSetup
 		}
 		if inpututil.IsKeyJustPressed(ebiten.Key3) {
@@ -46,6 +61,11 @@ func (g *App) Update() error {
 			g.game.State = StateHighScores
 			go g.game.FetchLeaderboard() // Fetch server leaderboard
 		}
+		if inpututil.IsKeyJustPressed(ebiten.Key4) {
+			// Watch Live - log in, then pick a game
+			g.game.SpectateMode = true
+			g.game.State = StateMultiplayerSetup
+		}
 		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
 			// Quit - handled by OS/window manager
 		}
@@ -53,18 +73,32 @@ func (g *App) Update() error {
 		// Handle text input for username
 		g.handleTextInput()
 
+		if inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+			// Switch between the username and password fields
+			g.game.PasswordFocused = !g.game.PasswordFocused
+		}
 		if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
 			// Start multiplayer connection
 			go g.game.StartMultiplayerConnection()
 		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyF2) {
+			// Register an account, then connect
+			go g.game.StartMultiplayerRegistration()
+		}
 		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
 			// Back to main menu
 			g.game.State = StateMainMenu
 			g.game.UsernameInput = ""
+			g.game.PasswordInput = ""
+			g.game.PasswordFocused = false
 			g.game.ConnectionStatus = ""
 		}
 		if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) {
-			g.game.RemoveFromUsernameInput()
+			if g.game.PasswordFocused {
+				g.game.RemoveFromPasswordInput()
+			} else {
+				g.game.RemoveFromUsernameInput()
+			}
 		}
 	case StateMatchmaking:
 		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
@@ -101,6 +135,15 @@ func (g *App) Update() error {
 		if ebiten.IsKeyPressed(ebiten.KeyDown) {
 			g.game.SoftDrop()
 		}
+
+		// Garbage targeting in a battle royale
+		if g.game.IsBattleRoyale() {
+			for key, strategy := range targetingKeys {
+				if inpututil.IsKeyJustPressed(key) {
+					g.game.SetTargeting(strategy)
+				}
+			}
+		}
 	case StatePaused:
 		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
 			g.game.TogglePause()
@@ -116,10 +159,43 @@ func (g *App) Update() error {
 	case StateRematchWaiting:
 		// Just wait for server response
 	case StateHighScores:
+		for i, key := range lobbyKeys {
+			if inpututil.IsKeyJustPressed(key) && i < min(len(g.game.Leaderboard), leaderboardShown) {
+				// View a player's profile
+				username := g.game.Leaderboard[i].Username
+				g.game.OpenProfile(username)
+				go g.game.FetchProfile(username)
+			}
+		}
 		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
 			// Back to main menu
 			g.game.State = StateMainMenu
 		}
+	case StateProfile:
+		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+			// Back to the leaderboard
+			g.game.State = StateHighScores
+		}
+	case StateSpectateLobby:
+		for i, key := range lobbyKeys {
+			if inpututil.IsKeyJustPressed(key) {
+				g.game.SpectateGame(i)
+			}
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyR) {
+			go g.game.RefreshLiveGames()
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+			// Back to main menu
+			g.game.State = StateMainMenu
+			g.game.ConnectionStatus = ""
+		}
+	case StateSpectating:
+		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+			// Back to the list of live games
+			g.game.StopSpectating()
+			go g.game.RefreshLiveGames()
+		}
 	}
 
 	return nil
@@ -135,11 +211,17 @@ func (g *App) Layout(_, _ int) (int, int) {
 	return 640, 480 // Fixed game resolution
 }
 
-// handleTextInput processes text input for username entry
+// handleTextInput processes text input for username and password entry
 func (g *App) handleTextInput() {
 	// Get typed characters
 	runes := ebiten.AppendInputChars(nil)
 	for _, r := range runes {
+		if g.game.PasswordFocused {
+			if unicode.IsPrint(r) {
+				g.game.AddToPasswordInput(r)
+			}
+			continue
+		}
 		// Only allow alphanumeric characters and basic symbols
 		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' {
 			g.game.AddToUsernameInput(r)
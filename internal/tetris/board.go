@@ -134,6 +134,38 @@ func (b *Board) ClearLines() int {
 	return linesCleared
 }
 
+// AddGarbage pushes the stack up and fills the bottom rows with garbage,
+// leaving one hole per row. It returns false if blocks were pushed off the top.
+func (b *Board) AddGarbage(lines, hole int) bool {
+	if lines <= 0 {
+		return true
+	}
+	if lines > BoardHeightWithBuffer {
+		lines = BoardHeightWithBuffer
+	}
+	if hole < 0 || hole >= BoardWidth {
+		hole = 0
+	}
+
+	fits := true
+	for y := 0; y < lines; y++ {
+		for x := 0; x < BoardWidth; x++ {
+			if b.Cells[y][x] != Empty {
+				fits = false
+			}
+		}
+	}
+
+	copy(b.Cells[:], b.Cells[lines:])
+	for y := BoardHeightWithBuffer - lines; y < BoardHeightWithBuffer; y++ {
+		for x := 0; x < BoardWidth; x++ {
+			b.Cells[y][x] = Locked
+		}
+		b.Cells[y][hole] = Empty
+	}
+	return fits
+}
+
 // isLineFull checks if a line is completely filled
 func (b *Board) isLineFull(y int) bool {
 	for x := 0; x < BoardWidth; x++ {
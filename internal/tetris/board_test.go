@@ -145,3 +145,35 @@ func TestIsLineFull(t *testing.T) {
 		t.Error("Expected full line to be full")
 	}
 }
+
+func TestAddGarbage(t *testing.T) {
+	board := NewBoard()
+	bottom := BoardHeightWithBuffer - 1
+	board.Cells[bottom][0] = CyanI
+
+	if !board.AddGarbage(2, 7) {
+		t.Error("Expected garbage to fit under a low stack")
+	}
+
+	// The stack moves up and the garbage has one hole per row
+	if board.Cells[bottom-2][0] != CyanI {
+		t.Error("Expected the stack to be pushed up by two rows")
+	}
+	for _, y := range []int{bottom, bottom - 1} {
+		for x := 0; x < BoardWidth; x++ {
+			want := Locked
+			if x == 7 {
+				want = Empty
+			}
+			if board.Cells[y][x] != want {
+				t.Errorf("Expected %v at [%d][%d], got %v", want, y, x, board.Cells[y][x])
+			}
+		}
+	}
+
+	// Blocks pushed off the top mean the player has topped out
+	board.Cells[0][4] = RedZ
+	if board.AddGarbage(1, 0) {
+		t.Error("Expected garbage pushing blocks off the top not to fit")
+	}
+}
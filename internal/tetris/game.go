@@ -18,8 +18,14 @@ const (
 	StateGameOver
 	StateRematchWaiting
 	StateHighScores
+	StateSpectateLobby
+	StateSpectating
+	StateProfile
 )
 
+// AnnouncementDuration is how long a server announcement stays on screen
+const AnnouncementDuration = 10 * time.Second
+
 // LeaderboardEntry represents a leaderboard entry
 type LeaderboardEntry struct {
 	Rank       int    `json:"rank"`
@@ -56,20 +62,39 @@ type Game struct {
 
 	// Multiplayer fields
 	MultiplayerMode   bool               `json:"multiplayerMode"`
-	MultiplayerClient *MultiplayerClient `json:"-"` // Don't serialize WebSocket connection
-	OpponentBoard     [][]Cell           `json:"opponentBoard,omitempty"`
-	OpponentScore     int                `json:"opponentScore,omitempty"`
-	OpponentLevel     int                `json:"opponentLevel,omitempty"`
-	OpponentLines     int                `json:"opponentLines,omitempty"`
+	MultiplayerClient *MultiplayerClient `json:"-"`                   // Don't serialize WebSocket connection
+	Opponents         []*Opponent        `json:"opponents,omitempty"` // Everyone else in the match, in seat order
 	LocalPlayerLost   bool               `json:"localPlayerLost,omitempty"`
-	OpponentLost      bool               `json:"opponentLost,omitempty"`
 	LoserScore        int                `json:"loserScore,omitempty"`
+	Placement         int                `json:"placement,omitempty"`      // Our finishing position in a battle royale
+	Targeting         string             `json:"targeting,omitempty"`      // Garbage targeting strategy chosen this game
+	PendingGarbage    int                `json:"pendingGarbage,omitempty"` // Garbage lines waiting to rise
 	RematchRequested  bool               `json:"rematchRequested,omitempty"`
+	ConnectionPaused  bool               `json:"connectionPaused,omitempty"` // Waiting on a dropped connection to come back
+	PauseReason       string             `json:"pauseReason,omitempty"`
+
+	// Latest server announcement, shown as a banner until AnnouncementUntil
+	Announcement      string    `json:"announcement,omitempty"`
+	AnnouncementUntil time.Time `json:"announcementUntil,omitempty"`
+
+	// Match series state; SeriesBestOf is zero outside a series
+	SeriesBestOf       int  `json:"seriesBestOf,omitempty"`
+	SeriesWins         int  `json:"seriesWins,omitempty"`
+	SeriesOpponentWins int  `json:"seriesOpponentWins,omitempty"`
+	SeriesOver         bool `json:"seriesOver,omitempty"`
+	SeriesWon          bool `json:"seriesWon,omitempty"`
+
+	// Spectator state; watched players are tracked as Opponents
+	SpectateMode    bool       `json:"spectateMode,omitempty"` // Connecting to watch rather than play
+	Spectating      bool       `json:"spectating,omitempty"`
+	SpectatedWinner string     `json:"spectatedWinner,omitempty"`
+	LiveGames       []LiveGame `json:"liveGames,omitempty"`
 
 	// UI state
 	UsernameInput    string `json:"usernameInput,omitempty"`
+	PasswordInput    string `json:"-"`                         // Optional; logs in to a registered account
+	PasswordFocused  bool   `json:"passwordFocused,omitempty"` // Typing goes to the password field
 	ConnectionStatus string `json:"connectionStatus,omitempty"`
-	OpponentName     string `json:"opponentName,omitempty"`
 
 	// Local high score (for single player)
 	LocalHighScore int `json:"localHighScore,omitempty"`
@@ -78,10 +103,17 @@ type Game struct {
 	Leaderboard []LeaderboardEntry `json:"leaderboard,omitempty"`
 	ServerURL   string             `json:"serverURL,omitempty"`
 
+	// Player profile opened from the leaderboard
+	Profile           *PlayerProfile    `json:"profile,omitempty"`
+	ProfileGames      []PlayerGameEntry `json:"profileGames,omitempty"`
+	ProfileHeadToHead *HeadToHead       `json:"profileHeadToHead,omitempty"` // Their record against us
+	ProfileStatus     string            `json:"profileStatus,omitempty"`
+
 	// Performance optimization: reusable slices
-	boardBuffer     [][]Cell // Reusable board slice for multiplayer
-	ghostY          int      // Cached ghost piece Y position
-	ghostCacheValid bool     // Whether ghost cache is valid
+	boardBuffer     [][]Cell        // Reusable board slice for multiplayer
+	pendingGarbage  []garbageAttack // Garbage queued by opponents
+	ghostY          int             // Cached ghost piece Y position
+	ghostCacheValid bool            // Whether ghost cache is valid
 }
 
 // NewGame creates a new Tetris game
@@ -143,9 +175,14 @@ func (g *Game) Start() {
 	g.LastClearWasTSpin = false
 	g.LastWasBackToBack = false
 	g.LocalPlayerLost = false
-	g.OpponentLost = false
 	g.LoserScore = 0
+	g.Placement = 0
+	g.Targeting = ""
+	g.pendingGarbage = g.pendingGarbage[:0]
+	g.PendingGarbage = 0
 	g.RematchRequested = false
+	g.ConnectionPaused = false
+	g.PauseReason = ""
 	g.updateDropInterval()
 
 	// Generate fresh pieces and validate spawn position
@@ -169,6 +206,10 @@ func (g *Game) canProcessInput() bool {
 	if g.MultiplayerMode && g.LocalPlayerLost {
 		return false
 	}
+	// Freeze while either player is reconnecting
+	if g.MultiplayerMode && g.ConnectionPaused {
+		return false
+	}
 	return true
 }
 
@@ -204,6 +245,7 @@ func (g *Game) moveDown() {
 	// Check if the move is valid
 	if g.Board.IsValidPosition(testPiece, testPiece.X, testPiece.Y) {
 		g.CurrentPiece.Move(0, 1)
+		g.sendMoveToServer("down")
 		return
 	}
 
@@ -216,8 +258,16 @@ func (g *Game) moveDown() {
 		g.addScore(linesCleared)
 	}
 
+	// Garbage from opponents rises once the piece has settled
+	if len(g.pendingGarbage) > 0 && !g.applyPendingGarbage() {
+		g.handleLocalGameOver()
+	}
+
 	// Spawn next piece and check for game over
 	g.spawnNextPiece()
+
+	// Send updated state to server
+	g.sendStateToServer()
 }
 
 // MoveLeft moves the current piece left
@@ -353,11 +403,16 @@ func (g *Game) HardDrop() {
 		g.addScore(linesCleared)
 	}
 
-	// Send updated state to server
-	g.sendStateToServer()
+	// Garbage from opponents rises once the piece has settled
+	if len(g.pendingGarbage) > 0 && !g.applyPendingGarbage() {
+		g.handleLocalGameOver()
+	}
 
 	// Spawn next piece and check for game over
 	g.spawnNextPiece()
+
+	// Send updated state to server
+	g.sendStateToServer()
 }
 
 // SoftDrop accelerates the piece downward
@@ -373,6 +428,7 @@ func (g *Game) SoftDrop() bool {
 	if g.Board.IsValidPosition(testPiece, testPiece.X, testPiece.Y) {
 		g.CurrentPiece.Move(0, 1)
 		g.Score++ // Small bonus for soft drop
+		g.sendMoveToServer("soft_drop")
 		return true
 	}
 
@@ -571,6 +627,9 @@ func (g *Game) HoldPiece() bool {
 		return false
 	}
 
+	// Hold changes the current, held and next pieces at once
+	g.sendStateToServer()
+
 	return true
 }
 
@@ -652,15 +711,6 @@ func (g *Game) GetGhostPieceY() int {
 	return ghostY
 }
 
-// clearOpponentBoard clears the opponent board without reallocating
-func (g *Game) clearOpponentBoard() {
-	for i := range g.OpponentBoard {
-		for j := range g.OpponentBoard[i] {
-			g.OpponentBoard[i][j] = Empty
-		}
-	}
-}
-
 // EnableMultiplayer enables multiplayer mode with server connection
 func (g *Game) EnableMultiplayer(serverURL string) error {
 	if g.MultiplayerClient != nil {
@@ -670,17 +720,6 @@ func (g *Game) EnableMultiplayer(serverURL string) error {
 	g.MultiplayerClient = NewMultiplayerClient(serverURL)
 	g.MultiplayerMode = true
 
-	// Initialize opponent board only if not already allocated
-	if g.OpponentBoard == nil {
-		g.OpponentBoard = make([][]Cell, BoardHeightWithBuffer)
-		for i := range g.OpponentBoard {
-			g.OpponentBoard[i] = make([]Cell, BoardWidth)
-		}
-	} else {
-		// Reuse existing board, just clear it
-		g.clearOpponentBoard()
-	}
-
 	return nil
 }
 
@@ -698,6 +737,26 @@ func (g *Game) ConnectToServer(username string) error {
 	return g.MultiplayerClient.Connect()
 }
 
+// ConnectToAccount connects to the multiplayer server as a registered
+// account, creating the account first if register is set
+func (g *Game) ConnectToAccount(username, password string, register bool) error {
+	if g.MultiplayerClient == nil {
+		return fmt.Errorf("multiplayer not enabled")
+	}
+
+	var err error
+	if register {
+		err = g.MultiplayerClient.Register(username, password)
+	} else {
+		err = g.MultiplayerClient.LoginWithPassword(username, password)
+	}
+	if err != nil {
+		return err
+	}
+
+	return g.MultiplayerClient.Connect()
+}
+
 // JoinMatchmaking joins the matchmaking queue
 func (g *Game) JoinMatchmaking() error {
 	if g.MultiplayerClient == nil {
@@ -752,6 +811,30 @@ func (g *Game) handleMultiplayerMessage(message map[string]interface{}) {
 		g.handleRematchStart(message)
 	case "opponent_disconnected":
 		g.handleOpponentDisconnected(message)
+	case "connection_lost":
+		g.pauseForConnection("Connection lost - reconnecting...")
+	case "reconnect_failed":
+		g.handleReconnectFailed(message)
+	case "opponent_reconnecting":
+		g.pauseForConnection("Opponent disconnected - waiting for them to reconnect...")
+	case "opponent_reconnected":
+		g.handleOpponentReconnected(message)
+	case "game_resync":
+		g.handleGameResync(message)
+	case "series_update":
+		g.handleSeriesScore(message)
+	case "series_over":
+		g.handleSeriesOver(message)
+	case "garbage":
+		g.handleGarbage(message)
+	case "spectate_start":
+		g.handleSpectateStart(message)
+	case "spectate_error":
+		g.handleSpectateError(message)
+	case "announcement", "server_draining":
+		g.handleAnnouncement(message)
+	case "queue_drained":
+		g.handleQueueDrained(message)
 	}
 }
 
@@ -765,67 +848,60 @@ func (g *Game) handleMatchFound(message map[string]interface{}) {
 		log.Printf("Game: Using server seed: %.0f", seed)
 	}
 
-	opponent, ok := message["opponent"].(string)
-	if ok {
-		g.OpponentName = opponent
-		log.Printf("Game: Matched with opponent: %s", opponent)
+	g.setOpponents(message)
+	for _, opponent := range g.Opponents {
+		log.Printf("Game: Matched with opponent: %s", opponent.Name)
 	}
 
+	g.resetSeries(message)
+
 	// Start the game - this will change state to StatePlaying
 	g.Start()
 }
 
 // handleOpponentMove processes opponent move
 func (g *Game) handleOpponentMove(message map[string]interface{}) {
-	moveType, ok := message["moveType"].(string)
-	if ok {
-		log.Printf("Game: Opponent move: %s", moveType)
-		// In a full implementation, you might want to show opponent moves visually
+	// Track the opponent's falling piece so it can be drawn between state updates
+	opponent := g.opponentFor(message)
+	if piece := pieceFromMessage(message["piece"]); piece != nil && opponent != nil {
+		opponent.Piece = piece
 	}
 }
 
 // handleOpponentState processes opponent game state
 func (g *Game) handleOpponentState(message map[string]interface{}) {
-	// Update opponent score
-	if score, ok := message["score"].(float64); ok {
-		g.OpponentScore = int(score)
-	}
-
-	// Update opponent level
-	if level, ok := message["level"].(float64); ok {
-		g.OpponentLevel = int(level)
+	if opponent := g.opponentFor(message); opponent != nil {
+		opponent.applyState(message)
 	}
+}
 
-	// Update opponent lines
-	if lines, ok := message["lines"].(float64); ok {
-		g.OpponentLines = int(lines)
+// handleGameOver processes game over message
+func (g *Game) handleGameOver(message map[string]interface{}) {
+	winnerID, ok := message["winnerId"].(string)
+	if ok {
+		log.Printf("Game: Game over, winner: %s", winnerID)
 	}
 
-	// Update opponent board
-	if boardInterface, ok := message["board"].([]interface{}); ok {
-		for i, rowInterface := range boardInterface {
-			if i >= len(g.OpponentBoard) {
-				break
+	// Record everyone's finishing positions
+	if results, ok := message["players"].([]interface{}); ok {
+		for _, value := range results {
+			result, ok := value.(map[string]interface{})
+			if !ok {
+				continue
 			}
-			if row, ok := rowInterface.([]interface{}); ok {
-				for j, cellInterface := range row {
-					if j >= len(g.OpponentBoard[i]) {
-						break
-					}
-					if cellValue, ok := cellInterface.(float64); ok {
-						g.OpponentBoard[i][j] = Cell(int(cellValue))
-					}
-				}
+			placement, _ := result["placement"].(float64)
+			if g.MultiplayerClient != nil && result["id"] == g.MultiplayerClient.playerID {
+				g.Placement = int(placement)
+			} else if opponent := g.opponentFor(map[string]interface{}{"playerId": result["id"]}); opponent != nil {
+				opponent.Placement = int(placement)
 			}
 		}
 	}
-}
 
-// handleGameOver processes game over message
-func (g *Game) handleGameOver(message map[string]interface{}) {
-	winnerID, ok := message["winnerId"].(string)
-	if ok {
-		log.Printf("Game: Game over, winner: %s", winnerID)
+	// Spectators keep watching for the next game of a series
+	if g.Spectating {
+		g.handleSpectatedGameOver(message)
+		return
 	}
 
 	// End the game
@@ -844,21 +920,25 @@ func (g *Game) handlePlayerLost(message map[string]interface{}) {
 		g.LoserScore = int(loserScore)
 	}
 
+	placement, _ := message["placement"].(float64)
+
 	if g.MultiplayerClient != nil && playerID == g.MultiplayerClient.playerID {
 		// We lost - enter spectator mode but don't end game yet
 		g.LocalPlayerLost = true
+		g.Placement = int(placement)
 		log.Printf("Game: Local player lost (score: %d), waiting for opponent to beat score", g.LoserScore)
-	} else {
-		// Opponent lost - we continue playing until we beat their score
-		g.OpponentLost = true
-		log.Printf("Game: Opponent lost (score: %d), continue playing to beat their score", g.LoserScore)
+	} else if opponent := g.opponentFor(message); opponent != nil {
+		// Opponent lost - in a duel we continue playing until we beat their score
+		opponent.Lost = true
+		opponent.Placement = int(placement)
+		log.Printf("Game: %s lost (score: %d), %d opponent(s) left", opponent.Name, g.LoserScore, g.OpponentsLeft())
 	}
 }
 
 // sendMoveToServer sends a move to the server
 func (g *Game) sendMoveToServer(moveType string) {
 	if g.MultiplayerClient != nil && g.MultiplayerClient.IsConnected() {
-		_ = g.MultiplayerClient.SendGameMove(moveType)
+		_ = g.MultiplayerClient.SendGameMove(moveType, g.CurrentPiece)
 	}
 }
 
@@ -868,6 +948,11 @@ func (g *Game) RequestRematch() {
 		return
 	}
 
+	// The next series game starts on its own
+	if g.SeriesInProgress() {
+		return
+	}
+
 	g.RematchRequested = true
 	g.State = StateRematchWaiting
 
@@ -890,17 +975,74 @@ func (g *Game) handleRematchRequest(_ map[string]interface{}) {
 
 // handleRematchStart processes rematch start from server
 func (g *Game) handleRematchStart(message map[string]interface{}) {
+	if g.Spectating {
+		g.handleSpectatedRematch(message)
+		return
+	}
+
 	seed, ok := message["seed"].(float64)
 	if ok {
 		g.PieceGen.SetSeed(int64(seed))
 		log.Printf("Game: Rematch starting with seed: %d", int64(seed))
 	}
 
+	g.setOpponents(message)
+
+	// Series games arrive as rematches; only a rematch after the series ends starts a new one
+	if !g.SeriesInProgress() {
+		g.resetSeries(message)
+	}
+
 	// Reset game state for rematch
 	g.Start()
 	log.Printf("Game: Rematch started")
 }
 
+// SeriesInProgress reports whether the current match series has games left
+func (g *Game) SeriesInProgress() bool {
+	return g.SeriesBestOf > 0 && !g.SeriesOver
+}
+
+// resetSeries clears the series score when a new match starts
+func (g *Game) resetSeries(message map[string]interface{}) {
+	g.SeriesBestOf = 0
+	g.SeriesWins = 0
+	g.SeriesOpponentWins = 0
+	g.SeriesOver = false
+	g.SeriesWon = false
+
+	if seriesID, _ := message["seriesId"].(string); seriesID == "" {
+		return
+	}
+	if bestOf, ok := message["bestOf"].(float64); ok {
+		g.SeriesBestOf = int(bestOf)
+	}
+}
+
+// handleSeriesScore updates the series score after a game
+func (g *Game) handleSeriesScore(message map[string]interface{}) {
+	if bestOf, ok := message["bestOf"].(float64); ok {
+		g.SeriesBestOf = int(bestOf)
+	}
+	if wins, ok := message["wins"].(float64); ok {
+		g.SeriesWins = int(wins)
+	}
+	if opponentWins, ok := message["opponentWins"].(float64); ok {
+		g.SeriesOpponentWins = int(opponentWins)
+	}
+	log.Printf("Game: Series score %d-%d (best of %d)", g.SeriesWins, g.SeriesOpponentWins, g.SeriesBestOf)
+}
+
+// handleSeriesOver records the final series result
+func (g *Game) handleSeriesOver(message map[string]interface{}) {
+	g.handleSeriesScore(message)
+	g.SeriesOver = true
+
+	winnerID, _ := message["winnerId"].(string)
+	g.SeriesWon = g.MultiplayerClient != nil && winnerID != "" && winnerID == g.MultiplayerClient.playerID
+	log.Printf("Game: Series over, winner: %s", winnerID)
+}
+
 // FetchLeaderboard fetches the server leaderboard
 func (g *Game) FetchLeaderboard() {
 	// Make HTTP request to leaderboard endpoint
@@ -935,6 +1077,123 @@ func (g *Game) FetchLeaderboard() {
 func (g *Game) handleOpponentDisconnected(_ map[string]interface{}) {
 	log.Printf("Game: Opponent disconnected - You win!")
 	g.State = StateGameOver
+	g.ConnectionPaused = false
+	g.PauseReason = ""
+}
+
+// handleAnnouncement shows a message from the server operators
+func (g *Game) handleAnnouncement(message map[string]interface{}) {
+	text, _ := message["message"].(string)
+	if text == "" {
+		return
+	}
+	log.Printf("Game: Server announcement: %s", text)
+	g.Announcement = text
+	g.AnnouncementUntil = time.Now().Add(AnnouncementDuration)
+}
+
+// handleQueueDrained returns to the menu when the server empties the
+// matchmaking queue we were waiting in
+func (g *Game) handleQueueDrained(message map[string]interface{}) {
+	g.handleAnnouncement(message)
+	if g.State == StateMatchmaking {
+		g.State = StateMainMenu
+	}
+}
+
+// ActiveAnnouncement returns the server announcement to show, if any
+func (g *Game) ActiveAnnouncement() string {
+	if g.Announcement == "" || time.Now().After(g.AnnouncementUntil) {
+		return ""
+	}
+	return g.Announcement
+}
+
+// pauseForConnection freezes play while a connection is being restored
+func (g *Game) pauseForConnection(reason string) {
+	log.Printf("Game: Paused - %s", reason)
+	g.ConnectionPaused = true
+	g.PauseReason = reason
+}
+
+// resumeFromConnectionPause unfreezes play once both players are connected
+func (g *Game) resumeFromConnectionPause() {
+	if !g.ConnectionPaused {
+		return
+	}
+
+	log.Printf("Game: Resumed after reconnect")
+	g.ConnectionPaused = false
+	g.PauseReason = ""
+	g.DropTimer = time.Now() // Don't drop immediately for the time spent paused
+}
+
+// handleReconnectFailed processes giving up on a dropped connection
+func (g *Game) handleReconnectFailed(_ map[string]interface{}) {
+	log.Printf("Game: Could not reconnect to server")
+	g.ConnectionPaused = false
+	g.PauseReason = ""
+	g.ConnectionStatus = "Lost connection to server"
+	if g.State == StatePlaying || g.State == StateRematchWaiting {
+		g.State = StateGameOver
+	}
+}
+
+// handleOpponentReconnected processes the opponent coming back
+func (g *Game) handleOpponentReconnected(message map[string]interface{}) {
+	// We may still be waiting on our own connection
+	if status, ok := message["status"].(string); ok && status != "active" {
+		return
+	}
+	g.resumeFromConnectionPause()
+}
+
+// handleGameResync restores the match after our own reconnect
+func (g *Game) handleGameResync(message map[string]interface{}) {
+	gameID, _ := message["gameId"].(string)
+	status, _ := message["status"].(string)
+
+	// The game was decided while we were away
+	if gameID == "" || status == "finished" {
+		g.ConnectionPaused = false
+		g.PauseReason = ""
+		if g.State == StatePlaying {
+			g.State = StateGameOver
+		}
+		return
+	}
+
+	if opponents, ok := message["opponents"].([]interface{}); ok {
+		for _, value := range opponents {
+			entry, ok := value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			opponent := g.opponentFor(map[string]interface{}{"playerId": entry["id"]})
+			if opponent == nil {
+				continue
+			}
+			if username, ok := entry["username"].(string); ok {
+				opponent.Name = username
+			}
+			if score, ok := entry["score"].(float64); ok {
+				opponent.Score = int(score)
+			}
+			opponent.Lost, _ = entry["lost"].(bool)
+			if state, ok := entry["state"].(map[string]interface{}); ok {
+				opponent.applyState(state)
+			}
+		}
+	}
+
+	if status == "active" {
+		g.resumeFromConnectionPause()
+	} else {
+		g.pauseForConnection("Opponent disconnected - waiting for them to reconnect...")
+	}
+
+	// Let the opponent catch up with anything we did while disconnected
+	g.sendStateToServer()
 }
 
 // sendStateToServer sends current game state to server
@@ -953,7 +1212,7 @@ func (g *Game) sendStateToServer() {
 			copy(g.boardBuffer[i], row[:])
 		}
 
-		_ = g.MultiplayerClient.SendGameState(g.boardBuffer, g.Score, g.Level, g.LinesCleared)
+		_ = g.MultiplayerClient.SendGameState(g.boardBuffer, g.Score, g.Level, g.LinesCleared, g.CurrentPiece, g.HeldPiece, g.NextPiece)
 	}
 }
 
@@ -971,8 +1230,35 @@ func (g *Game) RemoveFromUsernameInput() {
 	}
 }
 
-// StartMultiplayerConnection attempts to connect to the multiplayer server
+// AddToPasswordInput adds a character to the password input
+func (g *Game) AddToPasswordInput(char rune) {
+	if len(g.PasswordInput) < 72 { // The server's limit
+		g.PasswordInput += string(char)
+	}
+}
+
+// RemoveFromPasswordInput removes the last character from password input
+func (g *Game) RemoveFromPasswordInput() {
+	if len(g.PasswordInput) > 0 {
+		g.PasswordInput = g.PasswordInput[:len(g.PasswordInput)-1]
+	}
+}
+
+// StartMultiplayerConnection attempts to connect to the multiplayer server,
+// as a guest unless a password was entered
 func (g *Game) StartMultiplayerConnection() {
+	g.startMultiplayerConnection(false)
+}
+
+// StartMultiplayerRegistration registers the entered username and password as
+// an account, then connects like StartMultiplayerConnection
+func (g *Game) StartMultiplayerRegistration() {
+	g.startMultiplayerConnection(true)
+}
+
+// startMultiplayerConnection logs in or registers, then joins matchmaking or
+// the spectate lobby
+func (g *Game) startMultiplayerConnection(register bool) {
 	if len(g.UsernameInput) < 2 {
 		g.ConnectionStatus = "Username must be at least 2 characters"
 		return
@@ -981,6 +1267,10 @@ func (g *Game) StartMultiplayerConnection() {
 		g.ConnectionStatus = "Username too long (max 12 characters)"
 		return
 	}
+	if register && len(g.PasswordInput) < 8 {
+		g.ConnectionStatus = "Password must be at least 8 characters"
+		return
+	}
 
 	g.ConnectionStatus = "Connecting to server..."
 
@@ -993,13 +1283,25 @@ func (g *Game) StartMultiplayerConnection() {
 	}
 
 	// Connect to server
-	err = g.ConnectToServer(g.UsernameInput)
+	if register || g.PasswordInput != "" {
+		err = g.ConnectToAccount(g.UsernameInput, g.PasswordInput, register)
+	} else {
+		err = g.ConnectToServer(g.UsernameInput)
+	}
+	g.PasswordInput = ""
 	if err != nil {
 		log.Printf("Multiplayer: Failed to connect to server: %v", err)
 		g.ConnectionStatus = err.Error()
 		return
 	}
 
+	// Spectators pick a game to watch instead of queueing
+	if g.SpectateMode {
+		g.RefreshLiveGames()
+		g.State = StateSpectateLobby
+		return
+	}
+
 	// Join matchmaking
 	err = g.JoinMatchmaking()
 	if err != nil {
@@ -335,6 +335,7 @@ func TestHandlePlayerLost(t *testing.T) {
 	game := NewGame()
 	game.MultiplayerMode = true
 	game.MultiplayerClient = &MultiplayerClient{playerID: "player1"}
+	game.setOpponents(map[string]interface{}{"opponent": "rival", "opponentId": "player2"})
 
 	// Test when local player loses
 	message := map[string]interface{}{
@@ -352,7 +353,6 @@ func TestHandlePlayerLost(t *testing.T) {
 
 	// Reset
 	game.LocalPlayerLost = false
-	game.OpponentLost = false
 	game.LoserScore = 0
 
 	// Test when opponent loses
@@ -360,7 +360,7 @@ func TestHandlePlayerLost(t *testing.T) {
 	message["loserScore"] = float64(2000)
 	game.handlePlayerLost(message)
 
-	if !game.OpponentLost {
+	if !game.OpponentLost() {
 		t.Error("OpponentLost should be true when opponent loses")
 	}
 	if game.LocalPlayerLost {
@@ -403,8 +403,9 @@ func TestGameStartResetsMultiplayerFlags(t *testing.T) {
 
 	// Set multiplayer flags
 	game.LocalPlayerLost = true
-	game.OpponentLost = true
 	game.LoserScore = 1000
+	game.Placement = 3
+	game.handleGarbage(map[string]interface{}{"lines": float64(2), "hole": float64(4)})
 
 	// Start new game
 	game.Start()
@@ -412,8 +413,11 @@ func TestGameStartResetsMultiplayerFlags(t *testing.T) {
 	if game.LocalPlayerLost {
 		t.Error("LocalPlayerLost should be reset to false on game start")
 	}
-	if game.OpponentLost {
-		t.Error("OpponentLost should be reset to false on game start")
+	if game.Placement != 0 {
+		t.Error("Placement should be reset to 0 on game start")
+	}
+	if game.PendingGarbage != 0 || len(game.pendingGarbage) != 0 {
+		t.Error("Pending garbage should be dropped on game start")
 	}
 	if game.LoserScore != 0 {
 		t.Error("LoserScore should be reset to 0 on game start")
@@ -460,3 +464,53 @@ func TestHandleOpponentDisconnected(t *testing.T) {
 		t.Error("Game should end when opponent disconnects")
 	}
 }
+
+func TestSeriesMessages(t *testing.T) {
+	game := NewGame()
+	game.MultiplayerMode = true
+	game.MultiplayerClient = &MultiplayerClient{playerID: "me"}
+
+	game.handleMultiplayerMessage(map[string]interface{}{
+		"type": "match_found", "seed": float64(7), "opponent": "rival", "seriesId": "s1", "bestOf": float64(5),
+	})
+	if game.SeriesBestOf != 5 || !game.SeriesInProgress() {
+		t.Fatalf("Expected a best-of-5 series in progress, got bestOf=%d", game.SeriesBestOf)
+	}
+
+	game.handleMultiplayerMessage(map[string]interface{}{
+		"type": "series_update", "seriesId": "s1", "bestOf": float64(5), "wins": float64(1), "opponentWins": float64(0),
+	})
+	if game.SeriesWins != 1 || game.SeriesOpponentWins != 0 {
+		t.Errorf("Expected series score 1-0, got %d-%d", game.SeriesWins, game.SeriesOpponentWins)
+	}
+
+	// The next series game keeps the score
+	game.handleMultiplayerMessage(map[string]interface{}{
+		"type": "rematch_start", "seed": float64(8), "seriesId": "s1", "bestOf": float64(5),
+	})
+	if game.SeriesWins != 1 {
+		t.Errorf("Expected series score to carry over, got %d wins", game.SeriesWins)
+	}
+
+	// Rematches are refused mid-series; the server starts the next game
+	game.State = StateGameOver
+	game.RequestRematch()
+	if game.State == StateRematchWaiting {
+		t.Error("Expected rematch request to be ignored during a series")
+	}
+
+	game.handleMultiplayerMessage(map[string]interface{}{
+		"type": "series_over", "seriesId": "s1", "bestOf": float64(5), "wins": float64(3), "opponentWins": float64(1), "winnerId": "me",
+	})
+	if !game.SeriesOver || !game.SeriesWon || game.SeriesInProgress() {
+		t.Errorf("Expected series won and over, got over=%v won=%v", game.SeriesOver, game.SeriesWon)
+	}
+
+	// A rematch after the series starts a fresh one
+	game.handleMultiplayerMessage(map[string]interface{}{
+		"type": "rematch_start", "seed": float64(9), "seriesId": "s2", "bestOf": float64(5),
+	})
+	if game.SeriesOver || game.SeriesWins != 0 || game.SeriesBestOf != 5 {
+		t.Errorf("Expected a fresh series, got over=%v wins=%d bestOf=%d", game.SeriesOver, game.SeriesWins, game.SeriesBestOf)
+	}
+}
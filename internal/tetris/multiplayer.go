@@ -8,6 +8,9 @@ import (
 	"log"
 	"net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -16,14 +19,36 @@ import (
 type MultiplayerClient struct {
 	conn         *websocket.Conn
 	serverURL    string
-	sessionToken string
+	sessionToken string     // Signed access token
+	refreshToken string     // Exchanged for a new access token before it expires
+	tokenExpiry  time.Time  // When sessionToken expires; zero if the server didn't say
+	authMu       sync.Mutex // Protects the tokens, which reconnects may refresh
 	playerID     string
 	username     string
 	gameID       string
 	connected    bool
+	closed       bool // Set by Close so a deliberate disconnect isn't retried
 	messages     chan map[string]interface{}
+	writeMu      sync.Mutex   // Serializes writes between the game loop and heartbeat
+	rtt          atomic.Int64 // Last measured round-trip time in nanoseconds
 }
 
+// Heartbeat timing. The client pings every heartbeatInterval and treats the
+// connection as dead if nothing arrives within readTimeout; the server pings
+// on its own schedule, so a healthy connection always has traffic.
+const (
+	heartbeatInterval = 5 * time.Second
+	readTimeout       = 30 * time.Second
+)
+
+// Reconnect backoff for dropped connections; the server holds the game
+// open for its grace period while we retry with the same session token
+const (
+	reconnectInitialDelay = 500 * time.Millisecond
+	reconnectMaxDelay     = 8 * time.Second
+	reconnectMaxAttempts  = 8
+)
+
 // NewMultiplayerClient creates a new multiplayer client
 func NewMultiplayerClient(serverURL string) *MultiplayerClient {
 	return &MultiplayerClient{
@@ -32,20 +57,38 @@ func NewMultiplayerClient(serverURL string) *MultiplayerClient {
 	}
 }
 
-// Login authenticates with the server
+// Login authenticates with the server as a guest
 func (mc *MultiplayerClient) Login(username string) error {
-	// Prepare login request
-	loginReq := map[string]string{
+	return mc.authenticate("/api/auth/login", map[string]string{
 		"username": username,
-	}
+	})
+}
+
+// LoginWithPassword authenticates with the server as a registered account
+func (mc *MultiplayerClient) LoginWithPassword(username, password string) error {
+	return mc.authenticate("/api/auth/login", map[string]string{
+		"username": username,
+		"password": password,
+	})
+}
+
+// Register creates an account on the server and logs in as it
+func (mc *MultiplayerClient) Register(username, password string) error {
+	return mc.authenticate("/api/auth/register", map[string]string{
+		"username": username,
+		"password": password,
+	})
+}
 
+// authenticate posts credentials to an auth endpoint and stores the session
+func (mc *MultiplayerClient) authenticate(path string, loginReq map[string]string) error {
 	jsonData, err := json.Marshal(loginReq)
 	if err != nil {
 		return fmt.Errorf("failed to marshal login request: %v", err)
 	}
 
 	// Make HTTP request to login endpoint
-	resp, err := http.Post(mc.serverURL+"/api/auth/login", "application/json", bytes.NewBuffer(jsonData))
+	resp, err := http.Post(mc.serverURL+path, "application/json", bytes.NewBuffer(jsonData))
 	if err != nil {
 		return fmt.Errorf("failed to make login request: %v", err)
 	}
@@ -57,32 +100,95 @@ func (mc *MultiplayerClient) Login(username string) error {
 	}
 
 	// Parse response
-	var loginResp struct {
-		PlayerID     string `json:"playerId"`
-		Username     string `json:"username"`
-		SessionToken string `json:"sessionToken"`
-	}
-
+	var loginResp loginResponse
 	err = json.NewDecoder(resp.Body).Decode(&loginResp)
 	if err != nil {
 		return fmt.Errorf("failed to parse login response: %v", err)
 	}
 
 	// Store authentication info
+	mc.authMu.Lock()
 	mc.username = loginResp.Username
-	mc.sessionToken = loginResp.SessionToken
 	mc.playerID = loginResp.PlayerID
+	mc.storeTokens(loginResp)
+	mc.authMu.Unlock()
 
-	log.Printf("Multiplayer: Logged in as %s (ID: %s)", mc.username, mc.playerID)
+	log.Printf("Multiplayer: Logged in as %s (ID: %s, registered: %t)", mc.username, mc.playerID, loginResp.Registered)
 	return nil
 }
 
+// loginResponse is the session the server returns on login or refresh
+type loginResponse struct {
+	PlayerID     string    `json:"playerId"`
+	Username     string    `json:"username"`
+	SessionToken string    `json:"sessionToken"`
+	RefreshToken string    `json:"refreshToken"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+	Registered   bool      `json:"registered"`
+}
+
+// tokenRefreshMargin is how long before expiry an access token is refreshed
+const tokenRefreshMargin = time.Minute
+
+// storeTokens keeps a session's tokens; callers hold authMu
+func (mc *MultiplayerClient) storeTokens(session loginResponse) {
+	mc.sessionToken = session.SessionToken
+	mc.refreshToken = session.RefreshToken
+	mc.tokenExpiry = session.ExpiresAt
+}
+
+// accessToken returns a session token that is good for at least
+// tokenRefreshMargin, refreshing it first if needed
+func (mc *MultiplayerClient) accessToken() (string, error) {
+	mc.authMu.Lock()
+	defer mc.authMu.Unlock()
+
+	if mc.sessionToken == "" {
+		return "", fmt.Errorf("not logged in")
+	}
+	if mc.tokenExpiry.IsZero() || time.Until(mc.tokenExpiry) > tokenRefreshMargin {
+		return mc.sessionToken, nil
+	}
+
+	jsonData, err := json.Marshal(map[string]string{"refreshToken": mc.refreshToken})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal refresh request: %v", err)
+	}
+
+	resp, err := http.Post(mc.serverURL+"/api/auth/refresh", "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh session: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("session expired, please log in again: %s", strings.TrimSpace(string(body)))
+	}
+
+	var session loginResponse
+	if err := json.NewDecoder(resp.Body).Decode(&session); err != nil {
+		return "", fmt.Errorf("failed to parse refresh response: %v", err)
+	}
+	mc.storeTokens(session)
+
+	log.Printf("Multiplayer: Refreshed session token")
+	return mc.sessionToken, nil
+}
+
+// wsProtocols returns the WebSocket subprotocols to offer: the game's own and
+// one carrying the session token, which keeps the token out of the URL
+func wsProtocols(token string) []string {
+	return []string{"tetris", "bearer." + token}
+}
+
 // Connect is implemented in multiplayer_native.go and multiplayer_wasm.go
 
 // JoinQueue joins the matchmaking queue
 func (mc *MultiplayerClient) JoinQueue() error {
-	if mc.sessionToken == "" {
-		return fmt.Errorf("not logged in")
+	token, err := mc.accessToken()
+	if err != nil {
+		return err
 	}
 
 	// Make HTTP request to join queue
@@ -91,7 +197,7 @@ func (mc *MultiplayerClient) JoinQueue() error {
 		return fmt.Errorf("failed to create request: %v", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+mc.sessionToken)
+	req.Header.Set("Authorization", "Bearer "+token)
 
 	client := &http.Client{}
 	resp, err := client.Do(req)
@@ -109,8 +215,8 @@ func (mc *MultiplayerClient) JoinQueue() error {
 	return nil
 }
 
-// SendGameMove sends a move to the server
-func (mc *MultiplayerClient) SendGameMove(moveType string) error {
+// SendGameMove sends a move to the server along with the resulting piece position
+func (mc *MultiplayerClient) SendGameMove(moveType string, piece *Piece) error {
 	if !mc.connected {
 		return nil // Silently ignore if not connected
 	}
@@ -119,12 +225,15 @@ func (mc *MultiplayerClient) SendGameMove(moveType string) error {
 		"type":     "game_move",
 		"moveType": moveType,
 	}
+	if piece != nil {
+		message["piece"] = pieceToMessage(piece)
+	}
 
 	return mc.sendMessage(message)
 }
 
 // SendGameState sends current game state to server
-func (mc *MultiplayerClient) SendGameState(board [][]Cell, score, level, lines int) error {
+func (mc *MultiplayerClient) SendGameState(board [][]Cell, score, level, lines int, current, held, next *Piece) error {
 	if !mc.connected {
 		return nil // Silently ignore if not connected
 	}
@@ -146,9 +255,60 @@ func (mc *MultiplayerClient) SendGameState(board [][]Cell, score, level, lines i
 		"lines": lines,
 	}
 
+	// Pieces are optional; nil values are sent as JSON null so the
+	// opponent can clear an empty hold slot
+	message["currentPiece"] = pieceToMessage(current)
+	message["holdPiece"] = pieceToMessage(held)
+	message["nextPiece"] = pieceToMessage(next)
+
 	return mc.sendMessage(message)
 }
 
+// pieceToMessage converts a piece to its wire representation
+func pieceToMessage(piece *Piece) map[string]interface{} {
+	if piece == nil {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"type":     int(piece.Type),
+		"x":        piece.X,
+		"y":        piece.Y,
+		"rotation": piece.RotationState,
+	}
+}
+
+// pieceFromMessage rebuilds a piece from its wire representation
+func pieceFromMessage(value interface{}) *Piece {
+	data, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	pieceType, ok := data["type"].(float64)
+	if !ok || pieceType < float64(TypeI) || pieceType > float64(TypeZ) {
+		return nil
+	}
+
+	piece := NewPiece(PieceType(pieceType))
+
+	// Replay rotations so the shape matches the sender's orientation
+	if rotation, ok := data["rotation"].(float64); ok {
+		for i := 0; i < int(rotation)%4; i++ {
+			piece.Rotate()
+		}
+	}
+
+	if x, ok := data["x"].(float64); ok {
+		piece.X = int(x)
+	}
+	if y, ok := data["y"].(float64); ok {
+		piece.Y = int(y)
+	}
+
+	return piece
+}
+
 // GetMessage returns the next message from the server (non-blocking)
 func (mc *MultiplayerClient) GetMessage() map[string]interface{} {
 	select {
@@ -161,6 +321,7 @@ func (mc *MultiplayerClient) GetMessage() map[string]interface{} {
 
 // Close closes the connection
 func (mc *MultiplayerClient) Close() {
+	mc.closed = true
 	if mc.conn != nil {
 		mc.conn.Close()
 		mc.connected = false
@@ -191,36 +352,180 @@ func (mc *MultiplayerClient) SendGameOver() error {
 	return mc.sendMessage(message)
 }
 
+// SendTargeting asks the server to send our garbage using a targeting strategy
+func (mc *MultiplayerClient) SendTargeting(strategy string) error {
+	if !mc.connected {
+		return nil // Silently ignore if not connected
+	}
+
+	message := map[string]interface{}{
+		"type":     "set_targeting",
+		"strategy": strategy,
+	}
+
+	return mc.sendMessage(message)
+}
+
+// ListLiveGames fetches the games in progress that can be spectated
+func (mc *MultiplayerClient) ListLiveGames() ([]LiveGame, error) {
+	token, err := mc.accessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", mc.serverURL+"/api/games/live", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list live games: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("list live games failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var games []LiveGame
+	if err := json.NewDecoder(resp.Body).Decode(&games); err != nil {
+		return nil, fmt.Errorf("failed to parse live games: %v", err)
+	}
+	return games, nil
+}
+
+// SendSpectate asks the server to stream a live game to us
+func (mc *MultiplayerClient) SendSpectate(gameID string) error {
+	if !mc.connected {
+		return fmt.Errorf("not connected")
+	}
+
+	message := map[string]interface{}{
+		"type":   "spectate",
+		"gameId": gameID,
+	}
+
+	return mc.sendMessage(message)
+}
+
+// SendStopSpectating stops the stream of the game we are watching
+func (mc *MultiplayerClient) SendStopSpectating() error {
+	if !mc.connected {
+		return nil // Silently ignore if not connected
+	}
+
+	message := map[string]interface{}{
+		"type": "stop_spectating",
+	}
+
+	return mc.sendMessage(message)
+}
+
 // GetUsername returns the username
 func (mc *MultiplayerClient) GetUsername() string {
 	return mc.username
 }
 
+// GetRTT returns the last measured round-trip time to the server
+func (mc *MultiplayerClient) GetRTT() time.Duration {
+	return time.Duration(mc.rtt.Load())
+}
+
 // sendMessage sends a message via WebSocket
 func (mc *MultiplayerClient) sendMessage(message map[string]interface{}) error {
 	if mc.conn == nil {
 		return fmt.Errorf("not connected")
 	}
 
+	mc.writeMu.Lock()
+	defer mc.writeMu.Unlock()
 	return mc.conn.WriteJSON(message)
 }
 
+// heartbeat pings the server until conn is replaced or closed
+func (mc *MultiplayerClient) heartbeat(conn *websocket.Conn) {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if mc.conn != conn || !mc.connected {
+			return
+		}
+
+		message := map[string]interface{}{
+			"type":      "ping",
+			"timestamp": time.Now().UnixMilli(),
+		}
+		if err := mc.sendMessage(message); err != nil {
+			log.Printf("Multiplayer: Heartbeat failed: %v", err)
+			return
+		}
+	}
+}
+
+// handlePong records the round-trip time from an echoed ping timestamp
+func (mc *MultiplayerClient) handlePong(message map[string]interface{}) {
+	sentAt, ok := message["timestamp"].(float64)
+	if !ok {
+		return
+	}
+
+	rtt := time.Since(time.UnixMilli(int64(sentAt)))
+	if rtt >= 0 {
+		mc.rtt.Store(int64(rtt))
+	}
+}
+
+// handleRateLimited resends a game over the server refused for arriving
+// too fast, since the match can't end without it. Other messages are either
+// superseded by the next state update or retried by the player.
+func (mc *MultiplayerClient) handleRateLimited(message map[string]interface{}) {
+	messageType, _ := message["messageType"].(string)
+	retryAfter, _ := message["retryAfterMs"].(float64)
+	log.Printf("Multiplayer: Server rate limited %q message, retry after %.0fms", messageType, retryAfter)
+
+	if messageType != "game_over" {
+		return
+	}
+	time.AfterFunc(time.Duration(retryAfter)*time.Millisecond, func() {
+		if mc.gameID == "" {
+			return // The game ended while we waited
+		}
+		if err := mc.SendGameOver(); err != nil {
+			log.Printf("Multiplayer: Failed to resend game over: %v", err)
+		}
+	})
+}
+
 // readMessages reads incoming WebSocket messages
 func (mc *MultiplayerClient) readMessages() {
+	conn := mc.conn
 	defer func() {
+		conn.Close()
 		mc.connected = false
-		if mc.conn != nil {
-			mc.conn.Close()
-		}
+		mc.handleConnectionLost()
 	}()
 
+	// Server pings count as traffic, so answer them and extend the deadline
+	conn.SetReadDeadline(time.Now().Add(readTimeout))
+	conn.SetPingHandler(func(appData string) error {
+		conn.SetReadDeadline(time.Now().Add(readTimeout))
+		return conn.WriteControl(websocket.PongMessage, []byte(appData), time.Now().Add(time.Second))
+	})
+
 	for {
 		var message map[string]interface{}
-		err := mc.conn.ReadJSON(&message)
+		err := conn.ReadJSON(&message)
 		if err != nil {
 			log.Printf("Multiplayer: Connection error: %v", err)
 			break
 		}
+		conn.SetReadDeadline(time.Now().Add(readTimeout))
 
 		// Handle special messages
 		if msgType, ok := message["type"].(string); ok {
@@ -235,18 +540,71 @@ func (mc *MultiplayerClient) readMessages() {
 					mc.gameID = gameID
 					log.Printf("Multiplayer: Rematch started! Game ID: %s", gameID)
 				}
+			case "pong":
+				mc.handlePong(message)
+				continue // Heartbeat traffic isn't for the game loop
+			case "rate_limited":
+				mc.handleRateLimited(message)
+				continue
+			case "game_resync":
+				gameID, _ := message["gameId"].(string)
+				mc.gameID = gameID
+				log.Printf("Multiplayer: Resynced after reconnect (game: %q)", gameID)
 			case "game_over":
 				mc.gameID = ""
 				log.Printf("Multiplayer: Game over")
 			}
 		}
 
-		// Send to message channel
-		select {
-		case mc.messages <- message:
-		default:
-			// Channel full, drop message
-			log.Printf("Multiplayer: Message channel full, dropping message")
+		mc.pushMessage(message)
+	}
+}
+
+// pushMessage queues a message for the game loop
+func (mc *MultiplayerClient) pushMessage(message map[string]interface{}) {
+	select {
+	case mc.messages <- message:
+	default:
+		// Channel full, drop message
+		log.Printf("Multiplayer: Message channel full, dropping message")
+	}
+}
+
+// handleConnectionLost tells the game the connection dropped and starts
+// reconnecting, unless the client was closed on purpose
+func (mc *MultiplayerClient) handleConnectionLost() {
+	if mc.closed {
+		return
+	}
+
+	mc.pushMessage(map[string]interface{}{"type": "connection_lost"})
+	go mc.reconnect()
+}
+
+// reconnect retries Connect with exponential backoff, reporting the outcome
+// as a "reconnected" or "reconnect_failed" message
+func (mc *MultiplayerClient) reconnect() {
+	delay := reconnectInitialDelay
+	for attempt := 1; attempt <= reconnectMaxAttempts; attempt++ {
+		time.Sleep(delay)
+		if mc.closed {
+			return
+		}
+
+		err := mc.Connect()
+		if err == nil {
+			log.Printf("Multiplayer: Reconnected after %d attempt(s)", attempt)
+			mc.pushMessage(map[string]interface{}{"type": "reconnected"})
+			return
+		}
+		log.Printf("Multiplayer: Reconnect attempt %d failed: %v", attempt, err)
+
+		delay *= 2
+		if delay > reconnectMaxDelay {
+			delay = reconnectMaxDelay
 		}
 	}
+
+	log.Printf("Multiplayer: Giving up on reconnecting")
+	mc.pushMessage(map[string]interface{}{"type": "reconnect_failed"})
 }
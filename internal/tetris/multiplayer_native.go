@@ -13,8 +13,9 @@ import (
 
 // Connect establishes WebSocket connection
 func (mc *MultiplayerClient) Connect() error {
-	if mc.sessionToken == "" {
-		return fmt.Errorf("must login before connecting")
+	token, err := mc.accessToken()
+	if err != nil {
+		return err
 	}
 
 	u, err := url.Parse(mc.serverURL)
@@ -28,9 +29,11 @@ func (mc *MultiplayerClient) Connect() error {
 		u.Scheme = "ws"
 	}
 	u.Path = "/ws"
-	u.RawQuery = "token=" + mc.sessionToken
 
-	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	dialer := *websocket.DefaultDialer
+	dialer.Subprotocols = wsProtocols(token)
+
+	conn, _, err := dialer.Dial(u.String(), nil)
 	if err != nil {
 		return fmt.Errorf("failed to connect to server: %v", err)
 	}
@@ -38,8 +41,9 @@ func (mc *MultiplayerClient) Connect() error {
 	mc.conn = conn
 	mc.connected = true
 
-	// Start message reader
+	// Start message reader and heartbeat
 	go mc.readMessages()
+	go mc.heartbeat(conn)
 
 	log.Printf("Multiplayer: Connected to server")
 	return nil
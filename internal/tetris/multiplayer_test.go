@@ -1,7 +1,11 @@
 package tetris
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestMultiplayerClient_Creation(t *testing.T) {
@@ -27,6 +31,53 @@ func TestMultiplayerClient_Login(t *testing.T) {
 	t.Skip("Skipping login test - requires running server (use integration tests instead)")
 }
 
+func TestMultiplayerClient_RefreshesExpiringToken(t *testing.T) {
+	refreshes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+		if r.URL.Path != "/api/auth/refresh" || req["refreshToken"] != "refresh-1" {
+			http.Error(w, "Invalid or expired refresh token", http.StatusUnauthorized)
+			return
+		}
+		refreshes++
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sessionToken": "access-2",
+			"refreshToken": "refresh-2",
+			"expiresAt":    time.Now().Add(15 * time.Minute),
+		})
+	}))
+	defer server.Close()
+
+	client := NewMultiplayerClient(server.URL)
+	if _, err := client.accessToken(); err == nil {
+		t.Error("Expected error before logging in")
+	}
+
+	// A token with plenty of time left is used as is
+	client.sessionToken = "access-1"
+	client.refreshToken = "refresh-1"
+	client.tokenExpiry = time.Now().Add(10 * time.Minute)
+	if token, err := client.accessToken(); err != nil || token != "access-1" || refreshes != 0 {
+		t.Errorf("Expected the current token without a refresh, got %q (%v)", token, err)
+	}
+
+	// One about to expire is refreshed first
+	client.tokenExpiry = time.Now().Add(10 * time.Second)
+	if token, err := client.accessToken(); err != nil || token != "access-2" || refreshes != 1 {
+		t.Errorf("Expected a refreshed token, got %q (%v)", token, err)
+	}
+	if client.refreshToken != "refresh-2" {
+		t.Errorf("Expected the new refresh token to be kept, got %q", client.refreshToken)
+	}
+
+	// A rejected refresh means logging in again
+	client.tokenExpiry = time.Now()
+	if _, err := client.accessToken(); err == nil {
+		t.Error("Expected error when the refresh is rejected")
+	}
+}
+
 func TestGame_EnableMultiplayer(t *testing.T) {
 	game := NewGame()
 
@@ -42,19 +93,6 @@ func TestGame_EnableMultiplayer(t *testing.T) {
 	if game.MultiplayerClient == nil {
 		t.Error("Expected multiplayer client to be created")
 	}
-
-	if game.OpponentBoard == nil {
-		t.Error("Expected opponent board to be initialized")
-	}
-
-	// Check opponent board dimensions
-	if len(game.OpponentBoard) != BoardHeightWithBuffer {
-		t.Errorf("Expected opponent board height %d, got %d", BoardHeightWithBuffer, len(game.OpponentBoard))
-	}
-
-	if len(game.OpponentBoard[0]) != BoardWidth {
-		t.Errorf("Expected opponent board width %d, got %d", BoardWidth, len(game.OpponentBoard[0]))
-	}
 }
 
 func TestGame_MultiplayerMessageHandling(t *testing.T) {
@@ -76,6 +114,15 @@ func TestGame_MultiplayerMessageHandling(t *testing.T) {
 		t.Error("Expected game to be in playing state after match found")
 	}
 
+	// A duel has one opponent with a full-size board
+	if len(game.Opponents) != 1 || game.Opponents[0].Name != "testopponent" {
+		t.Fatalf("Expected one opponent named testopponent, got %+v", game.Opponents)
+	}
+	opponent := game.Opponents[0]
+	if len(opponent.Board) != BoardHeightWithBuffer || len(opponent.Board[0]) != BoardWidth {
+		t.Errorf("Expected a %dx%d opponent board, got %dx%d", BoardWidth, BoardHeightWithBuffer, len(opponent.Board[0]), len(opponent.Board))
+	}
+
 	// Test opponent state message
 	stateMsg := map[string]interface{}{
 		"type":  "game_state",
@@ -91,16 +138,159 @@ func TestGame_MultiplayerMessageHandling(t *testing.T) {
 	game.handleMultiplayerMessage(stateMsg)
 
 	// Verify opponent state was updated
-	if game.OpponentScore != 1500 {
-		t.Errorf("Expected opponent score 1500, got %d", game.OpponentScore)
+	if opponent.Score != 1500 {
+		t.Errorf("Expected opponent score 1500, got %d", opponent.Score)
+	}
+
+	if opponent.Level != 5 {
+		t.Errorf("Expected opponent level 5, got %d", opponent.Level)
+	}
+
+	if opponent.Lines != 12 {
+		t.Errorf("Expected opponent lines 12, got %d", opponent.Lines)
+	}
+}
+
+func TestGame_BattleRoyaleRouting(t *testing.T) {
+	game := NewGame()
+	game.EnableMultiplayer("http://localhost:8080")
+	game.MultiplayerClient.playerID = "me"
+
+	game.handleMultiplayerMessage(map[string]interface{}{
+		"type":   "match_found",
+		"gameId": "royale",
+		"seed":   float64(7),
+		"players": []interface{}{
+			map[string]interface{}{"id": "me", "username": "Me"},
+			map[string]interface{}{"id": "p2", "username": "Two"},
+			map[string]interface{}{"id": "p3", "username": "Three"},
+		},
+	})
+
+	if !game.IsBattleRoyale() || len(game.Opponents) != 2 {
+		t.Fatalf("Expected two opponents, got %+v", game.Opponents)
+	}
+
+	// Updates go to the opponent that sent them
+	game.handleMultiplayerMessage(map[string]interface{}{"type": "game_state", "playerId": "p3", "score": float64(900)})
+	if game.Opponents[0].Score != 0 || game.Opponents[1].Score != 900 {
+		t.Errorf("Expected only p3's score to change, got %d and %d", game.Opponents[0].Score, game.Opponents[1].Score)
+	}
+
+	game.handleMultiplayerMessage(map[string]interface{}{"type": "player_lost", "playerId": "p2", "placement": float64(3)})
+	if !game.Opponents[0].Lost || game.Opponents[0].Placement != 3 || game.OpponentsLeft() != 1 {
+		t.Errorf("Expected p2 out in 3rd, got %+v", game.Opponents[0])
+	}
+	if game.OpponentLost() {
+		t.Error("Expected the match to go on while an opponent is standing")
+	}
+
+	game.handleMultiplayerMessage(map[string]interface{}{
+		"type":     "game_over",
+		"winnerId": "me",
+		"players": []interface{}{
+			map[string]interface{}{"id": "me", "placement": float64(1)},
+			map[string]interface{}{"id": "p3", "placement": float64(2)},
+			map[string]interface{}{"id": "p2", "placement": float64(3)},
+		},
+	})
+	if game.Placement != 1 || game.Opponents[1].Placement != 2 {
+		t.Errorf("Expected final placements 1 and 2, got %d and %d", game.Placement, game.Opponents[1].Placement)
+	}
+}
+
+func TestGame_SpectatorView(t *testing.T) {
+	game := NewGame()
+	game.EnableMultiplayer("http://localhost:8080")
+	game.MultiplayerClient.playerID = "watcher"
+	game.State = StateSpectateLobby
+
+	game.handleMultiplayerMessage(map[string]interface{}{
+		"type":   "spectate_start",
+		"gameId": "final",
+		"players": []interface{}{
+			map[string]interface{}{"id": "p1", "username": "One", "score": float64(400), "lines": float64(3)},
+			map[string]interface{}{"id": "p2", "username": "Two"},
+		},
+	})
+
+	if game.State != StateSpectating || !game.Spectating || len(game.Opponents) != 2 {
+		t.Fatalf("Expected to watch both players, got state %d with %+v", game.State, game.Opponents)
+	}
+	if game.Opponents[0].Score != 400 || game.Opponents[0].Lines != 3 {
+		t.Errorf("Expected the snapshot to be applied, got %+v", game.Opponents[0])
+	}
+
+	// Both players' streams update their own boards
+	game.handleMultiplayerMessage(map[string]interface{}{"type": "game_state", "playerId": "p2", "score": float64(700)})
+	if game.Opponents[1].Score != 700 || game.Opponents[0].Score != 400 {
+		t.Errorf("Expected only p2's score to change, got %d and %d", game.Opponents[0].Score, game.Opponents[1].Score)
+	}
+
+	// The result is shown without leaving the spectator view
+	game.handleMultiplayerMessage(map[string]interface{}{"type": "game_over", "winnerId": "p2"})
+	if game.State != StateSpectating || game.SpectatedWinner != "Two" {
+		t.Errorf("Expected to keep watching with Two as winner, got state %d winner %q", game.State, game.SpectatedWinner)
+	}
+
+	// The next game of a series is watched rather than played
+	game.handleMultiplayerMessage(map[string]interface{}{"type": "rematch_start", "gameId": "final2", "seed": float64(9)})
+	if game.State != StateSpectating || game.SpectatedWinner != "" || game.Opponents[1].Score != 0 {
+		t.Errorf("Expected a fresh spectated game, got state %d with %+v", game.State, game.Opponents[1])
+	}
+
+	game.StopSpectating()
+	if game.Spectating || game.State != StateSpectateLobby {
+		t.Errorf("Expected to be back in the lobby, got state %d", game.State)
+	}
+}
+
+func TestGame_ServerAnnouncements(t *testing.T) {
+	game := NewGame()
+	game.EnableMultiplayer("http://localhost:8080")
+	game.State = StateMatchmaking
+
+	game.handleMultiplayerMessage(map[string]interface{}{"type": "announcement", "message": "Restarting in 5 minutes"})
+	if game.ActiveAnnouncement() != "Restarting in 5 minutes" || game.State != StateMatchmaking {
+		t.Errorf("Expected the announcement to show without leaving matchmaking, got %q in state %d", game.ActiveAnnouncement(), game.State)
+	}
+
+	// A drained queue sends us back to the menu with the server's reason
+	game.handleMultiplayerMessage(map[string]interface{}{"type": "queue_drained", "message": "Matchmaking paused"})
+	if game.State != StateMainMenu || game.ActiveAnnouncement() != "Matchmaking paused" {
+		t.Errorf("Expected the main menu with the drain reason, got state %d and %q", game.State, game.ActiveAnnouncement())
+	}
+
+	// A server shutting down warns players the same way
+	game.handleMultiplayerMessage(map[string]interface{}{"type": "server_draining", "message": "The server is restarting"})
+	if game.ActiveAnnouncement() != "The server is restarting" {
+		t.Errorf("Expected the drain warning to show, got %q", game.ActiveAnnouncement())
 	}
 
-	if game.OpponentLevel != 5 {
-		t.Errorf("Expected opponent level 5, got %d", game.OpponentLevel)
+	game.AnnouncementUntil = time.Now().Add(-time.Second)
+	if game.ActiveAnnouncement() != "" {
+		t.Errorf("Expected the announcement to expire, got %q", game.ActiveAnnouncement())
 	}
+}
+
+func TestGame_GarbageRisesAfterLock(t *testing.T) {
+	game := NewGame()
+	game.Start()
 
-	if game.OpponentLines != 12 {
-		t.Errorf("Expected opponent lines 12, got %d", game.OpponentLines)
+	game.handleMultiplayerMessage(map[string]interface{}{"type": "garbage", "fromId": "p2", "lines": float64(2), "hole": float64(3)})
+	if game.PendingGarbage != 2 {
+		t.Fatalf("Expected 2 pending garbage lines, got %d", game.PendingGarbage)
+	}
+
+	game.HardDrop()
+
+	if game.PendingGarbage != 0 {
+		t.Errorf("Expected pending garbage to be applied, got %d", game.PendingGarbage)
+	}
+	for _, y := range []int{BoardHeightWithBuffer - 1, BoardHeightWithBuffer - 2} {
+		if game.Board.Cells[y][3] != Empty || game.Board.Cells[y][0] != Locked {
+			t.Errorf("Expected garbage row %d with a hole in column 3, got %v", y, game.Board.Cells[y])
+		}
 	}
 }
 
@@ -114,3 +304,159 @@ func TestGame_SendMoveToServer(t *testing.T) {
 	// Should work fine (though won't actually send since not connected)
 	game.sendStateToServer()
 }
+
+func TestGame_OpponentPieceTracking(t *testing.T) {
+	game := NewGame()
+	game.EnableMultiplayer("http://localhost:8080")
+	game.setOpponents(map[string]interface{}{"opponent": "rival"})
+	game.Start()
+	opponent := game.Opponents[0]
+
+	// Opponent moves a rotated T piece
+	moveMsg := map[string]interface{}{
+		"type":     "game_move",
+		"moveType": "left",
+		"piece": map[string]interface{}{
+			"type":     float64(TypeT),
+			"x":        float64(2),
+			"y":        float64(5),
+			"rotation": float64(1),
+		},
+	}
+
+	game.handleMultiplayerMessage(moveMsg)
+
+	if opponent.Piece == nil {
+		t.Fatal("Expected opponent piece to be set after game_move")
+	}
+	if opponent.Piece.Type != TypeT || opponent.Piece.X != 2 || opponent.Piece.Y != 5 {
+		t.Errorf("Unexpected opponent piece: type=%d x=%d y=%d", opponent.Piece.Type, opponent.Piece.X, opponent.Piece.Y)
+	}
+	if opponent.Piece.RotationState != RotationState1 {
+		t.Errorf("Expected rotation state 1, got %d", opponent.Piece.RotationState)
+	}
+
+	// State update carries hold/next and clears hold when null
+	stateMsg := map[string]interface{}{
+		"type":         "game_state",
+		"currentPiece": map[string]interface{}{"type": float64(TypeI), "x": float64(3), "y": float64(0), "rotation": float64(0)},
+		"holdPiece":    nil,
+		"nextPiece":    map[string]interface{}{"type": float64(TypeO), "x": float64(4), "y": float64(0), "rotation": float64(0)},
+	}
+
+	game.handleMultiplayerMessage(stateMsg)
+
+	if opponent.Piece == nil || opponent.Piece.Type != TypeI {
+		t.Error("Expected opponent current piece to be replaced by state update")
+	}
+	if opponent.HeldPiece != nil {
+		t.Error("Expected opponent hold piece to be cleared")
+	}
+	if opponent.NextPiece == nil || opponent.NextPiece.Type != TypeO {
+		t.Error("Expected opponent next piece to be set")
+	}
+
+	// Invalid piece data is ignored
+	game.handleMultiplayerMessage(map[string]interface{}{
+		"type":  "game_move",
+		"piece": map[string]interface{}{"type": float64(42)},
+	})
+	if opponent.Piece == nil || opponent.Piece.Type != TypeI {
+		t.Error("Expected invalid piece data to be ignored")
+	}
+}
+
+func TestGame_ReconnectPauseAndResync(t *testing.T) {
+	game := NewGame()
+	err := game.EnableMultiplayer("http://localhost:8080")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	game.setOpponents(map[string]interface{}{"opponent": "rival", "opponentId": "p2"})
+	game.Start()
+
+	// Our connection drops: play freezes
+	game.HandleMultiplayerMessage(map[string]interface{}{"type": "connection_lost"})
+	if !game.ConnectionPaused {
+		t.Fatal("Expected game to pause when the connection is lost")
+	}
+	if game.canProcessInput() {
+		t.Error("Expected input to be blocked while reconnecting")
+	}
+
+	// Server resyncs us into the still-active game
+	game.HandleMultiplayerMessage(map[string]interface{}{
+		"type":   "game_resync",
+		"gameId": "game1",
+		"status": "active",
+		"opponents": []interface{}{
+			map[string]interface{}{
+				"id":       "p2",
+				"username": "rival",
+				"score":    float64(700),
+				"state": map[string]interface{}{
+					"score":     float64(700),
+					"lines":     float64(6),
+					"holdPiece": map[string]interface{}{"type": float64(TypeT), "x": float64(0), "y": float64(0), "rotation": float64(0)},
+				},
+			},
+		},
+	})
+	if game.ConnectionPaused {
+		t.Error("Expected game to resume after resync")
+	}
+	opponent := game.Opponents[0]
+	if opponent.Name != "rival" || opponent.Score != 700 || opponent.Lines != 6 {
+		t.Errorf("Expected opponent restored from resync, got %s/%d/%d", opponent.Name, opponent.Score, opponent.Lines)
+	}
+	if opponent.HeldPiece == nil || opponent.HeldPiece.Type != TypeT {
+		t.Error("Expected opponent hold piece restored from resync")
+	}
+
+	// Opponent drops and comes back
+	game.HandleMultiplayerMessage(map[string]interface{}{"type": "opponent_reconnecting"})
+	if !game.ConnectionPaused {
+		t.Error("Expected game to pause while opponent reconnects")
+	}
+	game.HandleMultiplayerMessage(map[string]interface{}{"type": "opponent_reconnected", "status": "active"})
+	if game.ConnectionPaused {
+		t.Error("Expected game to resume when opponent reconnects")
+	}
+}
+
+func TestGame_ResyncAfterGameEnded(t *testing.T) {
+	game := NewGame()
+	err := game.EnableMultiplayer("http://localhost:8080")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	game.Start()
+
+	game.HandleMultiplayerMessage(map[string]interface{}{"type": "connection_lost"})
+	game.HandleMultiplayerMessage(map[string]interface{}{"type": "game_resync", "gameId": ""})
+
+	if game.State != StateGameOver {
+		t.Errorf("Expected game over when the game ended while away, got state %d", game.State)
+	}
+	if game.ConnectionPaused {
+		t.Error("Expected connection pause to be cleared")
+	}
+}
+
+func TestMultiplayerClient_HandlePong(t *testing.T) {
+	client := NewMultiplayerClient("http://localhost:8080")
+
+	if client.GetRTT() != 0 {
+		t.Error("Expected no RTT before any pong")
+	}
+
+	client.handlePong(map[string]interface{}{
+		"type":      "pong",
+		"timestamp": float64(time.Now().Add(-40 * time.Millisecond).UnixMilli()),
+	})
+
+	rtt := client.GetRTT()
+	if rtt < 40*time.Millisecond || rtt > time.Second {
+		t.Errorf("Expected RTT around 40ms, got %v", rtt)
+	}
+}
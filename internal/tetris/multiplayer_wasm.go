@@ -12,8 +12,9 @@ import (
 
 // Connect establishes WebSocket connection using browser WebSocket API
 func (mc *MultiplayerClient) Connect() error {
-	if mc.sessionToken == "" {
-		return fmt.Errorf("must login before connecting")
+	token, err := mc.accessToken()
+	if err != nil {
+		return err
 	}
 
 	// Get WebSocket URL from JavaScript config
@@ -23,9 +24,13 @@ func (mc *MultiplayerClient) Connect() error {
 		wsBaseURL = "ws://localhost:8080"
 	}
 
-	wsURL := wsBaseURL + "/ws?token=" + mc.sessionToken
+	wsURL := wsBaseURL + "/ws"
 
-	ws := js.Global().Get("WebSocket").New(wsURL)
+	protocols := []interface{}{}
+	for _, protocol := range wsProtocols(token) {
+		protocols = append(protocols, protocol)
+	}
+	ws := js.Global().Get("WebSocket").New(wsURL, protocols)
 
 	// Wait for connection
 	openChan := make(chan error, 1)
@@ -43,7 +48,7 @@ func (mc *MultiplayerClient) Connect() error {
 	ws.Set("onopen", onOpen)
 	ws.Set("onerror", onError)
 
-	err := <-openChan
+	err = <-openChan
 	onOpen.Release()
 	onError.Release()
 
@@ -67,6 +72,13 @@ func (mc *MultiplayerClient) Connect() error {
 	})
 	ws.Set("onmessage", onMessage)
 
+	onClose := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		mc.connected = false
+		mc.handleConnectionLost()
+		return nil
+	})
+	ws.Set("onclose", onClose)
+
 	mc.connected = true
 	log.Printf("Multiplayer: Connected to server")
 	return nil
@@ -0,0 +1,216 @@
+package tetris
+
+import "log"
+
+// Opponent tracks another player in a multiplayer match
+type Opponent struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Board     [][]Cell `json:"board,omitempty"`
+	Score     int      `json:"score,omitempty"`
+	Level     int      `json:"level,omitempty"`
+	Lines     int      `json:"lines,omitempty"`
+	Piece     *Piece   `json:"-"` // Falling piece
+	HeldPiece *Piece   `json:"-"`
+	NextPiece *Piece   `json:"-"`
+	Lost      bool     `json:"lost,omitempty"`
+	Placement int      `json:"placement,omitempty"` // Finishing position in a battle royale
+}
+
+// Garbage targeting strategies, matching the server's
+const (
+	TargetRandom    = "random"
+	TargetAttackers = "attackers"
+	TargetKOs       = "kos"
+	TargetBadges    = "badges"
+)
+
+// garbageAttack is garbage waiting to be added under the stack
+type garbageAttack struct {
+	lines int
+	hole  int
+}
+
+// reset clears an opponent's board and stats for a new game
+func (o *Opponent) reset() {
+	if o.Board == nil {
+		o.Board = make([][]Cell, BoardHeightWithBuffer)
+		for i := range o.Board {
+			o.Board[i] = make([]Cell, BoardWidth)
+		}
+	}
+	for i := range o.Board {
+		for j := range o.Board[i] {
+			o.Board[i][j] = Empty
+		}
+	}
+
+	o.Score = 0
+	o.Level = 0
+	o.Lines = 0
+	o.Piece = nil
+	o.HeldPiece = nil
+	o.NextPiece = nil
+	o.Lost = false
+	o.Placement = 0
+}
+
+// applyState updates an opponent from a game state message
+func (o *Opponent) applyState(message map[string]interface{}) {
+	if score, ok := message["score"].(float64); ok {
+		o.Score = int(score)
+	}
+	if level, ok := message["level"].(float64); ok {
+		o.Level = int(level)
+	}
+	if lines, ok := message["lines"].(float64); ok {
+		o.Lines = int(lines)
+	}
+
+	// A null piece clears the slot
+	if value, ok := message["currentPiece"]; ok {
+		o.Piece = pieceFromMessage(value)
+	}
+	if value, ok := message["holdPiece"]; ok {
+		o.HeldPiece = pieceFromMessage(value)
+	}
+	if value, ok := message["nextPiece"]; ok {
+		o.NextPiece = pieceFromMessage(value)
+	}
+
+	if boardInterface, ok := message["board"].([]interface{}); ok {
+		for i, rowInterface := range boardInterface {
+			if i >= len(o.Board) {
+				break
+			}
+			if row, ok := rowInterface.([]interface{}); ok {
+				for j, cellInterface := range row {
+					if j >= len(o.Board[i]) {
+						break
+					}
+					if cellValue, ok := cellInterface.(float64); ok {
+						o.Board[i][j] = Cell(int(cellValue))
+					}
+				}
+			}
+		}
+	}
+}
+
+// setOpponents sets up an opponent per player in a match's roster, reusing
+// boards from the last match. Servers that don't send a roster only name the
+// single opponent.
+func (g *Game) setOpponents(message map[string]interface{}) {
+	type rosterEntry struct{ id, name string }
+	var roster []rosterEntry
+
+	if players, ok := message["players"].([]interface{}); ok {
+		for _, value := range players {
+			player, ok := value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			id, _ := player["id"].(string)
+			if g.MultiplayerClient != nil && id == g.MultiplayerClient.playerID {
+				continue
+			}
+			name, _ := player["username"].(string)
+			roster = append(roster, rosterEntry{id, name})
+		}
+	} else if name, ok := message["opponent"].(string); ok {
+		id, _ := message["opponentId"].(string)
+		roster = append(roster, rosterEntry{id, name})
+	} else {
+		// A rematch keeps the same players
+		for _, opponent := range g.Opponents {
+			roster = append(roster, rosterEntry{opponent.ID, opponent.Name})
+		}
+	}
+
+	opponents := make([]*Opponent, len(roster))
+	for i, entry := range roster {
+		if i < len(g.Opponents) {
+			opponents[i] = g.Opponents[i]
+		} else {
+			opponents[i] = &Opponent{}
+		}
+		opponents[i].ID = entry.id
+		opponents[i].Name = entry.name
+		opponents[i].reset()
+	}
+	g.Opponents = opponents
+}
+
+// opponentFor finds the opponent a message is about. Messages without a
+// player ID come from a duel and belong to the only opponent.
+func (g *Game) opponentFor(message map[string]interface{}) *Opponent {
+	playerID, _ := message["playerId"].(string)
+	for _, opponent := range g.Opponents {
+		if opponent.ID == playerID {
+			return opponent
+		}
+	}
+	if playerID == "" && len(g.Opponents) == 1 {
+		return g.Opponents[0]
+	}
+	return nil
+}
+
+// IsBattleRoyale reports whether the match has more than one opponent
+func (g *Game) IsBattleRoyale() bool {
+	return len(g.Opponents) > 1
+}
+
+// OpponentsLeft returns how many opponents are still playing
+func (g *Game) OpponentsLeft() int {
+	left := 0
+	for _, opponent := range g.Opponents {
+		if !opponent.Lost {
+			left++
+		}
+	}
+	return left
+}
+
+// OpponentLost reports whether every opponent has topped out
+func (g *Game) OpponentLost() bool {
+	return len(g.Opponents) > 0 && g.OpponentsLeft() == 0
+}
+
+// SetTargeting chooses who our garbage is sent to in a battle royale
+func (g *Game) SetTargeting(strategy string) {
+	if !g.MultiplayerMode || g.MultiplayerClient == nil || g.State != StatePlaying {
+		return
+	}
+
+	g.Targeting = strategy
+	if g.MultiplayerClient.IsConnected() {
+		_ = g.MultiplayerClient.SendTargeting(strategy)
+	}
+	log.Printf("Game: Targeting %s", strategy)
+}
+
+// handleGarbage queues garbage sent by an opponent; it rises under the
+// stack when our next piece locks
+func (g *Game) handleGarbage(message map[string]interface{}) {
+	lines, _ := message["lines"].(float64)
+	hole, _ := message["hole"].(float64)
+	if lines <= 0 {
+		return
+	}
+
+	g.pendingGarbage = append(g.pendingGarbage, garbageAttack{lines: int(lines), hole: int(hole)})
+	g.PendingGarbage += int(lines)
+}
+
+// applyPendingGarbage adds queued garbage under the stack. It reports false if
+// the garbage pushed blocks off the top of the board.
+func (g *Game) applyPendingGarbage() bool {
+	fits := true
+	for _, attack := range g.pendingGarbage {
+		fits = g.Board.AddGarbage(attack.lines, attack.hole) && fits
+	}
+	g.pendingGarbage = g.pendingGarbage[:0]
+	g.PendingGarbage = 0
+	return fits
+}
@@ -60,36 +60,34 @@ func TestGhostCacheOptimization(t *testing.T) {
 
 func TestOpponentBoardReuse(t *testing.T) {
 	game := NewGame()
+	roster := map[string]interface{}{"opponent": "rival", "opponentId": "p2"}
 
-	// First enable should allocate
-	err := game.EnableMultiplayer("http://localhost:8080")
-	if err != nil {
-		t.Fatalf("Failed to enable multiplayer: %v", err)
-	}
-
-	if game.OpponentBoard == nil {
-		t.Error("Opponent board should be allocated")
+	// The first match allocates the opponent's board
+	game.setOpponents(roster)
+	if len(game.Opponents) != 1 || game.Opponents[0].Board == nil {
+		t.Fatal("Opponent board should be allocated")
 	}
 
 	// Store pointer to first slice for comparison
-	originalBoardPtr := &game.OpponentBoard[0][0]
+	originalBoardPtr := &game.Opponents[0].Board[0][0]
+	game.Opponents[0].Board[5][5] = PurpleT
 
-	// Second enable should reuse, not reallocate
-	err = game.EnableMultiplayer("http://localhost:8080")
-	if err != nil {
-		t.Fatalf("Failed to enable multiplayer second time: %v", err)
-	}
+	// A rematch should reuse, not reallocate
+	game.setOpponents(map[string]interface{}{})
 
 	// Check if same memory is reused by comparing first element address
-	newBoardPtr := &game.OpponentBoard[0][0]
+	newBoardPtr := &game.Opponents[0].Board[0][0]
 	if originalBoardPtr != newBoardPtr {
 		t.Error("Opponent board should be reused, not reallocated")
 	}
+	if game.Opponents[0].Name != "rival" {
+		t.Errorf("Expected rematch to keep the opponent, got %q", game.Opponents[0].Name)
+	}
 
 	// Board should be cleared
-	for i := range game.OpponentBoard {
-		for j := range game.OpponentBoard[i] {
-			if game.OpponentBoard[i][j] != Empty {
+	for i := range game.Opponents[0].Board {
+		for j := range game.Opponents[0].Board[i] {
+			if game.Opponents[0].Board[i][j] != Empty {
 				t.Errorf("Opponent board should be cleared at [%d][%d]", i, j)
 			}
 		}
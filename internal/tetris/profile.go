@@ -0,0 +1,123 @@
+package tetris
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// leaderboardShown is how many leaderboard entries fit on the high scores screen
+	leaderboardShown = 8
+	// profileGamesShown is how many recent games the profile screen lists
+	profileGamesShown = 8
+)
+
+// PlayerProfile represents a player's stats and recent form
+type PlayerProfile struct {
+	Username         string   `json:"username"`
+	Rating           int      `json:"rating"`
+	RatingDeviation  int      `json:"ratingDeviation"`
+	TotalGames       int      `json:"totalGames"`
+	Wins             int      `json:"wins"`
+	Losses           int      `json:"losses"`
+	Draws            int      `json:"draws"`
+	WinRate          float64  `json:"winRate"`
+	HighScore        int      `json:"highScore"`
+	SeriesPlayed     int      `json:"seriesPlayed"`
+	SeriesWins       int      `json:"seriesWins"`
+	RoyaleGames      int      `json:"royaleGames"`
+	AveragePlacement float64  `json:"averagePlacement,omitempty"`
+	RecentForm       []string `json:"recentForm"` // "win", "loss" or "draw", newest first
+}
+
+// PlayerGameEntry represents a finished game from the profile player's point of view
+type PlayerGameEntry struct {
+	GameID    string `json:"gameId"`
+	Result    string `json:"result"`
+	Score     int    `json:"score"`
+	Placement int    `json:"placement,omitempty"`
+	Opponents []struct {
+		Username string `json:"username"`
+		Score    int    `json:"score"`
+	} `json:"opponents"`
+	Winner          string    `json:"winner,omitempty"`
+	DurationSeconds int       `json:"durationSeconds"`
+	PlayedAt        time.Time `json:"playedAt"`
+}
+
+// HeadToHead represents a player's record against one opponent
+type HeadToHead struct {
+	Opponent string `json:"opponent"`
+	Wins     int    `json:"wins"`
+	Losses   int    `json:"losses"`
+	Draws    int    `json:"draws"`
+}
+
+// playerGamesResponse is a page of a player's match history
+type playerGamesResponse struct {
+	Games      []PlayerGameEntry `json:"games"`
+	HeadToHead *HeadToHead       `json:"headToHead,omitempty"`
+}
+
+// OpenProfile switches to a player's profile screen; FetchProfile fills it in
+func (g *Game) OpenProfile(username string) {
+	g.Profile = nil
+	g.ProfileGames = nil
+	g.ProfileHeadToHead = nil
+	g.ProfileStatus = "Loading profile..."
+	g.State = StateProfile
+}
+
+// FetchProfile fetches a player's profile and recent games. When we are
+// logged in as someone else, our head-to-head record against them is fetched
+// too.
+func (g *Game) FetchProfile(username string) {
+	playerURL := g.ServerURL + "/api/players/" + url.PathEscape(username)
+
+	var profile PlayerProfile
+	if err := fetchJSON(playerURL, &profile); err != nil {
+		log.Printf("Failed to fetch profile for %s: %v", username, err)
+		g.ProfileStatus = "Profile not available"
+		return
+	}
+
+	var history playerGamesResponse
+	if err := fetchJSON(fmt.Sprintf("%s/games?limit=%d", playerURL, profileGamesShown), &history); err != nil {
+		log.Printf("Failed to fetch games for %s: %v", username, err)
+	}
+
+	var headToHead *HeadToHead
+	if g.MultiplayerClient != nil {
+		if me := g.MultiplayerClient.GetUsername(); me != "" && me != username {
+			var against playerGamesResponse
+			err := fetchJSON(playerURL+"/games?limit=1&opponent="+url.QueryEscape(me), &against)
+			if err == nil {
+				headToHead = against.HeadToHead
+			}
+		}
+	}
+
+	g.Profile = &profile
+	g.ProfileGames = history.Games
+	g.ProfileHeadToHead = headToHead
+	g.ProfileStatus = ""
+	log.Printf("Fetched profile for %s with %d recent game(s)", username, len(history.Games))
+}
+
+// fetchJSON decodes the JSON response of a GET request into value
+func fetchJSON(requestURL string, value interface{}) error {
+	resp, err := http.Get(requestURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request failed with status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(value)
+}
@@ -0,0 +1,121 @@
+package tetris
+
+import (
+	"log"
+	"time"
+)
+
+// LiveGame is a game in progress that can be spectated
+type LiveGame struct {
+	GameID    string    `json:"gameId"`
+	Players   []string  `json:"players"`
+	Mode      string    `json:"mode,omitempty"`
+	Ranked    bool      `json:"ranked"`
+	BestOf    int       `json:"bestOf,omitempty"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// RefreshLiveGames fetches the games that can be watched for the spectate lobby
+func (g *Game) RefreshLiveGames() {
+	if g.MultiplayerClient == nil {
+		return
+	}
+
+	games, err := g.MultiplayerClient.ListLiveGames()
+	if err != nil {
+		log.Printf("Failed to fetch live games: %v", err)
+		g.ConnectionStatus = "Could not load live games"
+		g.LiveGames = []LiveGame{}
+		return
+	}
+
+	g.LiveGames = games
+	g.ConnectionStatus = ""
+	log.Printf("Fetched %d live game(s)", len(games))
+}
+
+// SpectateGame asks to watch a game from the lobby list by its position
+func (g *Game) SpectateGame(index int) {
+	if g.MultiplayerClient == nil || index < 0 || index >= len(g.LiveGames) {
+		return
+	}
+
+	gameID := g.LiveGames[index].GameID
+	if err := g.MultiplayerClient.SendSpectate(gameID); err != nil {
+		log.Printf("Failed to spectate game %s: %v", gameID, err)
+		g.ConnectionStatus = "Connection error occurred"
+		return
+	}
+	g.ConnectionStatus = "Joining game..."
+}
+
+// StopSpectating leaves the game being watched and returns to the lobby
+func (g *Game) StopSpectating() {
+	if g.MultiplayerClient != nil {
+		_ = g.MultiplayerClient.SendStopSpectating()
+	}
+
+	g.Spectating = false
+	g.SpectatedWinner = ""
+	g.Opponents = nil
+	g.State = StateSpectateLobby
+	log.Printf("Game: Stopped spectating")
+}
+
+// handleSpectateStart shows every player's board once the server starts
+// streaming a game to us. Everyone in the roster is drawn as an opponent.
+func (g *Game) handleSpectateStart(message map[string]interface{}) {
+	g.Spectating = true
+	g.SpectatedWinner = ""
+	g.ConnectionStatus = ""
+	g.setOpponents(message)
+
+	// Catch up with the state of each seat so far
+	if players, ok := message["players"].([]interface{}); ok {
+		for _, value := range players {
+			entry, ok := value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			opponent := g.opponentFor(map[string]interface{}{"playerId": entry["id"]})
+			if opponent == nil {
+				continue
+			}
+			opponent.applyState(entry)
+			opponent.Lost, _ = entry["lost"].(bool)
+			if placement, ok := entry["placement"].(float64); ok {
+				opponent.Placement = int(placement)
+			}
+		}
+	}
+
+	g.State = StateSpectating
+	log.Printf("Game: Spectating game with %d player(s)", len(g.Opponents))
+}
+
+// handleSpectateError reports a game we could not watch in the lobby
+func (g *Game) handleSpectateError(message map[string]interface{}) {
+	reason, _ := message["error"].(string)
+	log.Printf("Game: Could not spectate: %s", reason)
+	g.ConnectionStatus = "Could not watch game: " + reason
+	go g.RefreshLiveGames()
+}
+
+// handleSpectatedGameOver records the winner of the game being watched
+func (g *Game) handleSpectatedGameOver(message map[string]interface{}) {
+	winnerID, _ := message["winnerId"].(string)
+	if opponent := g.opponentFor(map[string]interface{}{"playerId": winnerID}); opponent != nil && winnerID != "" {
+		g.SpectatedWinner = opponent.Name
+	} else {
+		g.SpectatedWinner = "Draw"
+	}
+}
+
+// handleSpectatedRematch switches to the next game between the players being
+// watched, such as the next game of a series
+func (g *Game) handleSpectatedRematch(message map[string]interface{}) {
+	g.SpectatedWinner = ""
+	g.setOpponents(message)
+	log.Printf("Game: Watching the next game")
+}
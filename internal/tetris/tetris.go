@@ -1,5 +1,7 @@
 package tetris
 
+import "time"
+
 // Package tetris implements a Tetris game engine.
 // It provides the core game logic, board representation,
 // piece movement, and scoring mechanics.
@@ -34,6 +36,15 @@ func (g *Game) GetLastWasBackToBack() bool {
 	return g.LastWasBackToBack
 }
 
+// GetPing returns the measured round-trip time to the multiplayer server,
+// or zero when unknown
+func (g *Game) GetPing() time.Duration {
+	if g.MultiplayerClient == nil {
+		return 0
+	}
+	return g.MultiplayerClient.GetRTT()
+}
+
 // IsGameOver returns true if the game is over
 func (g *Game) IsGameOver() bool {
 	return g.State == StateGameOver
@@ -51,7 +62,8 @@ func (g *Game) IsPlaying() bool {
 
 // IsInMenu returns true if the game is in the menu
 func (g *Game) IsInMenu() bool {
-	return g.State == StateMainMenu || g.State == StateMultiplayerSetup || g.State == StateMatchmaking
+	return g.State == StateMainMenu || g.State == StateMultiplayerSetup || g.State == StateMatchmaking ||
+		g.State == StateSpectateLobby
 }
 
 // GetHeldPiece returns a copy of the held piece for display
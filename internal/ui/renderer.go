@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"image/color"
 	"strings"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text" // nolint:staticcheck // Using deprecated API for compatibility
@@ -39,6 +40,16 @@ const (
 	OpponentBoardY   = 280
 	OpponentCellSize = 8 // Smaller cells for opponent board
 
+	// Opponent hold/next previews (to the right of the opponent board)
+	OpponentPreviewX = OpponentBoardX + tetris.BoardWidth*OpponentCellSize + 12
+	OpponentPreviewY = OpponentBoardY
+
+	// Battle royale opponents share the same corner as a grid of mini boards
+	MiniBoardCellSize = 4
+	MiniBoardColumns  = 4
+	MiniBoardSpacingX = tetris.BoardWidth*MiniBoardCellSize + 4
+	MiniBoardSpacingY = tetris.BoardHeight*MiniBoardCellSize + 16
+
 	// UI colors
 	BackgroundColor = 0x1A1A1AFF
 )
@@ -98,6 +109,8 @@ func (r *Renderer) Draw(screen *ebiten.Image) {
 		r.drawGame(screen)
 		if r.game.State == tetris.StatePaused {
 			r.drawPauseOverlay(screen)
+		} else if r.game.ConnectionPaused {
+			r.drawReconnectingOverlay(screen)
 		}
 	case tetris.StateGameOver:
 		r.drawGame(screen)
@@ -107,7 +120,37 @@ func (r *Renderer) Draw(screen *ebiten.Image) {
 		r.drawRematchWaitingOverlay(screen)
 	case tetris.StateHighScores:
 		r.drawHighScores(screen)
+	case tetris.StateSpectateLobby:
+		r.drawSpectateLobby(screen)
+	case tetris.StateSpectating:
+		r.drawSpectating(screen)
+	case tetris.StateProfile:
+		r.drawProfile(screen)
 	}
+
+	r.drawAnnouncement(screen)
+}
+
+// drawAnnouncement draws the latest server announcement as a banner across
+// the top of every screen
+func (r *Renderer) drawAnnouncement(screen *ebiten.Image) {
+	msg := r.game.ActiveAnnouncement()
+	if msg == "" {
+		return
+	}
+
+	vector.DrawFilledRect(
+		screen,
+		0,
+		0,
+		float32(ScreenWidth),
+		28,
+		color.RGBA{0, 0, 0, 200},
+		false,
+	)
+
+	x := max((ScreenWidth-len(msg)*7)/2, 4)
+	text.Draw(screen, msg, r.font, x, 19, color.RGBA{255, 215, 0, 255}) // nolint:staticcheck // Using deprecated API for compatibility
 }
 
 // drawMainMenu draws the main menu with game mode options
@@ -160,11 +203,16 @@ func (r *Renderer) drawMainMenu(screen *ebiten.Image) {
 	y = menuStartY + 40
 	text.Draw(screen, msg, r.font, x, y, color.White) // nolint:staticcheck // Using deprecated API for compatibility
 
-	msg = "ESC. Quit"
+	msg = "4. Watch Live"
 	x = (ScreenWidth - len(msg)*7) / 2
 	y = menuStartY + 60
 	text.Draw(screen, msg, r.font, x, y, color.White) // nolint:staticcheck // Using deprecated API for compatibility
 
+	msg = "ESC. Quit"
+	x = (ScreenWidth - len(msg)*7) / 2
+	y = menuStartY + 80
+	text.Draw(screen, msg, r.font, x, y, color.White) // nolint:staticcheck // Using deprecated API for compatibility
+
 	msg = "Controls:"
 	x = (ScreenWidth - len(msg)*7) / 2
 	y = menuStartY + 120
@@ -272,9 +320,10 @@ func (r *Renderer) drawGame(screen *ebiten.Image) {
 	// Draw the held piece
 	r.drawHeldPiece(screen)
 
-	// Draw opponent board if in multiplayer
+	// Draw opponent boards and incoming garbage if in multiplayer
 	if r.game.MultiplayerMode {
-		r.drawOpponentBoard(screen)
+		r.drawOpponentBoards(screen)
+		r.drawGarbageMeter(screen)
 	}
 
 	// Draw game stats
@@ -518,42 +567,134 @@ func (r *Renderer) drawHeldPiece(screen *ebiten.Image) {
 	}
 }
 
-// drawOpponentBoard draws the opponent's board in the bottom left
-func (r *Renderer) drawOpponentBoard(screen *ebiten.Image) {
-	if len(r.game.OpponentBoard) == 0 {
+// drawOpponentBoards draws the opponents in the bottom left. A duel shows the
+// opponent's full board; a battle royale shows every opponent as a mini board.
+func (r *Renderer) drawOpponentBoards(screen *ebiten.Image) {
+	opponents := r.game.Opponents
+	if len(opponents) == 0 {
 		return // No opponent data yet
 	}
 
+	if len(opponents) == 1 {
+		r.drawOpponentBoard(screen, opponents[0])
+		return
+	}
+
+	for i, opponent := range opponents {
+		x := OpponentBoardX + (i%MiniBoardColumns)*MiniBoardSpacingX
+		y := OpponentBoardY + (i/MiniBoardColumns)*MiniBoardSpacingY
+
+		nameColor := color.RGBA{255, 100, 100, 255} // Light red
+		if opponent.Lost {
+			nameColor = color.RGBA{128, 128, 128, 255} // Gray if lost
+		}
+		name := opponent.Name
+		if len(name) > 6 {
+			name = name[:6]
+		}
+		text.Draw(screen, name, r.font, x, y-4, nameColor) // nolint:staticcheck // Using deprecated API for compatibility
+
+		r.drawBoardCells(screen, opponent, x, y, MiniBoardCellSize)
+
+		if opponent.Lost && opponent.Placement > 0 {
+			text.Draw(screen, fmt.Sprintf("#%d", opponent.Placement), r.font, x+8, y+40, color.RGBA{255, 0, 0, 255}) // nolint:staticcheck // Using deprecated API for compatibility
+		}
+	}
+}
+
+// drawOpponentBoard draws a duel opponent's board with their hold and next pieces
+func (r *Renderer) drawOpponentBoard(screen *ebiten.Image, opponent *tetris.Opponent) {
 	// Draw title
 	text.Draw(screen, "Opponent:", r.font, OpponentBoardX, OpponentBoardY-15, color.White)
 
+	r.drawBoardCells(screen, opponent, OpponentBoardX, OpponentBoardY, OpponentCellSize)
+
+	// Draw the opponent's hold and next pieces
+	text.Draw(screen, "Hold:", r.font, OpponentPreviewX, OpponentPreviewY+10, color.White)
+	r.drawSmallPiece(screen, opponent.HeldPiece, OpponentPreviewX, OpponentPreviewY+18)
+	text.Draw(screen, "Next:", r.font, OpponentPreviewX, OpponentPreviewY+70, color.White)
+	r.drawSmallPiece(screen, opponent.NextPiece, OpponentPreviewX, OpponentPreviewY+78)
+}
+
+// drawBoardCells draws an opponent's board and falling piece with a border
+func (r *Renderer) drawBoardCells(screen *ebiten.Image, opponent *tetris.Opponent, boardX, boardY, cellSize int) {
 	// Draw border
 	borderColor := color.RGBA{100, 100, 100, 255}
-	boardWidth := tetris.BoardWidth * OpponentCellSize
-	boardHeight := tetris.BoardHeight * OpponentCellSize
+	boardWidth := tetris.BoardWidth * cellSize
+	boardHeight := tetris.BoardHeight * cellSize
 
 	// Border outline
-	vector.DrawFilledRect(screen, float32(OpponentBoardX-1), float32(OpponentBoardY-1), float32(boardWidth+2), 1, borderColor, false)           // Top
-	vector.DrawFilledRect(screen, float32(OpponentBoardX-1), float32(OpponentBoardY-1), 1, float32(boardHeight+2), borderColor, false)          // Left
-	vector.DrawFilledRect(screen, float32(OpponentBoardX+boardWidth), float32(OpponentBoardY-1), 1, float32(boardHeight+2), borderColor, false) // Right
-	vector.DrawFilledRect(screen, float32(OpponentBoardX-1), float32(OpponentBoardY+boardHeight), float32(boardWidth+2), 1, borderColor, false) // Bottom
+	vector.DrawFilledRect(screen, float32(boardX-1), float32(boardY-1), float32(boardWidth+2), 1, borderColor, false)           // Top
+	vector.DrawFilledRect(screen, float32(boardX-1), float32(boardY-1), 1, float32(boardHeight+2), borderColor, false)          // Left
+	vector.DrawFilledRect(screen, float32(boardX+boardWidth), float32(boardY-1), 1, float32(boardHeight+2), borderColor, false) // Right
+	vector.DrawFilledRect(screen, float32(boardX-1), float32(boardY+boardHeight), float32(boardWidth+2), 1, borderColor, false) // Bottom
 
-	// Draw the opponent board (only visible rows, skip buffer rows)
+	// Draw the board (only visible rows, skip buffer rows)
 	bufferRows := tetris.BoardHeightWithBuffer - tetris.BoardHeight
+	emptyColor := color.RGBA{20, 20, 20, 255}
 	for y := 0; y < tetris.BoardHeight; y++ {
-		for x := 0; x < tetris.BoardWidth; x++ {
-			cellX := OpponentBoardX + x*OpponentCellSize
-			cellY := OpponentBoardY + y*OpponentCellSize
-
-			// Access the correct row (skip buffer rows at top)
-			boardRow := y + bufferRows
-			if r.game.OpponentBoard[boardRow][x] != tetris.Empty {
-				// Draw filled cell
-				cellColor := pieceColors[r.game.OpponentBoard[boardRow][x]]
-				r.drawSmallCell(screen, cellX, cellY, cellColor)
-			} else {
-				// Draw empty cell
-				r.drawSmallEmptyCell(screen, cellX, cellY)
+		boardRow := y + bufferRows
+		if boardRow >= len(opponent.Board) {
+			break
+		}
+		for x := 0; x < tetris.BoardWidth && x < len(opponent.Board[boardRow]); x++ {
+			cellColor := emptyColor
+			if cell := opponent.Board[boardRow][x]; cell != tetris.Empty {
+				cellColor = pieceColors[cell]
+			}
+			r.drawSizedCell(screen, boardX+x*cellSize, boardY+y*cellSize, cellSize, cellColor)
+		}
+	}
+
+	// Draw the falling piece on top of the board
+	if piece := opponent.Piece; piece != nil {
+		pieceColor := pieceColors[piece.Type]
+		for i := 0; i < len(piece.Shape); i++ {
+			for j := 0; j < len(piece.Shape[i]); j++ {
+				visualY := piece.Y + i - bufferRows
+				x := piece.X + j
+				if !piece.Shape[i][j] || visualY < 0 || visualY >= tetris.BoardHeight || x < 0 || x >= tetris.BoardWidth {
+					continue
+				}
+				r.drawSizedCell(screen, boardX+x*cellSize, boardY+visualY*cellSize, cellSize, pieceColor)
+			}
+		}
+	}
+}
+
+// drawGarbageMeter draws incoming garbage as a red bar beside the board
+func (r *Renderer) drawGarbageMeter(screen *ebiten.Image) {
+	lines := r.game.PendingGarbage
+	if lines <= 0 {
+		return
+	}
+	if lines > tetris.BoardHeight {
+		lines = tetris.BoardHeight
+	}
+
+	height := lines * CellSize
+	vector.DrawFilledRect(
+		screen,
+		float32(BoardX-6),
+		float32(BoardY+tetris.BoardHeight*CellSize-height),
+		4,
+		float32(height),
+		color.RGBA{255, 0, 0, 255},
+		false,
+	)
+}
+
+// drawSmallPiece draws a piece preview using opponent-sized cells
+func (r *Renderer) drawSmallPiece(screen *ebiten.Image, piece *tetris.Piece, x, y int) {
+	if piece == nil {
+		return
+	}
+
+	pieceColor := pieceColors[piece.Type]
+	for i := 0; i < len(piece.Shape); i++ {
+		for j := 0; j < len(piece.Shape[i]); j++ {
+			if piece.Shape[i][j] {
+				r.drawSmallCell(screen, x+j*OpponentCellSize, y+i*OpponentCellSize, pieceColor)
 			}
 		}
 	}
@@ -561,13 +702,12 @@ func (r *Renderer) drawOpponentBoard(screen *ebiten.Image) {
 
 // drawSmallCell draws a small colored cell for opponent board
 func (r *Renderer) drawSmallCell(screen *ebiten.Image, x, y int, clr color.RGBA) {
-	vector.DrawFilledRect(screen, float32(x), float32(y), float32(OpponentCellSize), float32(OpponentCellSize), clr, false)
+	r.drawSizedCell(screen, x, y, OpponentCellSize, clr)
 }
 
-// drawSmallEmptyCell draws a small empty cell for opponent board
-func (r *Renderer) drawSmallEmptyCell(screen *ebiten.Image, x, y int) {
-	emptyColor := color.RGBA{20, 20, 20, 255}
-	vector.DrawFilledRect(screen, float32(x), float32(y), float32(OpponentCellSize), float32(OpponentCellSize), emptyColor, false)
+// drawSizedCell draws a borderless cell of any size for opponent boards
+func (r *Renderer) drawSizedCell(screen *ebiten.Image, x, y, size int, clr color.RGBA) {
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(size), float32(size), clr, false)
 }
 
 // drawGameStats draws the game statistics
@@ -616,23 +756,35 @@ func (r *Renderer) drawGameStats(screen *ebiten.Image) {
 			text.Draw(screen, "(LOST)", r.font, PreviewX+5, PreviewY+125, color.RGBA{255, 0, 0, 255}) // Red
 		}
 
-		// Opponent name
-		opponentName := r.game.OpponentName
-		if opponentName == "" {
-			opponentName = "Opponent"
-		}
-		opponentColor := color.RGBA{255, 100, 100, 255} // Light red
-		if r.game.OpponentLost {
-			opponentColor = color.RGBA{128, 128, 128, 255} // Gray if lost
-		}
-		text.Draw(screen, "Opponent:", r.font, PreviewX-5, PreviewY+145, opponentColor)
-		text.Draw(screen, opponentName, r.font, PreviewX+5, PreviewY+160, opponentColor)
-		if r.game.OpponentLost {
-			text.Draw(screen, "(LOST)", r.font, PreviewX+5, PreviewY+175, color.RGBA{255, 0, 0, 255}) // Red
+		if r.game.IsBattleRoyale() {
+			// Players left and who our garbage goes to
+			text.Draw(screen, "Opponents:", r.font, PreviewX-5, PreviewY+145, color.RGBA{255, 100, 100, 255})
+			text.Draw(screen, fmt.Sprintf("%d left", r.game.OpponentsLeft()), r.font, PreviewX+5, PreviewY+160, color.RGBA{255, 100, 100, 255})
+			targeting := r.game.Targeting
+			if targeting == "" {
+				targeting = "default"
+			}
+			text.Draw(screen, "Target (1-4):", r.font, PreviewX-5, PreviewY+190, color.White)
+			text.Draw(screen, targeting, r.font, PreviewX+5, PreviewY+205, color.RGBA{255, 215, 0, 255}) // Gold
+		} else {
+			// Opponent name
+			opponentName := "Opponent"
+			if len(r.game.Opponents) > 0 && r.game.Opponents[0].Name != "" {
+				opponentName = r.game.Opponents[0].Name
+			}
+			opponentColor := color.RGBA{255, 100, 100, 255} // Light red
+			if r.game.OpponentLost() {
+				opponentColor = color.RGBA{128, 128, 128, 255} // Gray if lost
+			}
+			text.Draw(screen, "Opponent:", r.font, PreviewX-5, PreviewY+145, opponentColor)
+			text.Draw(screen, opponentName, r.font, PreviewX+5, PreviewY+160, opponentColor)
+			if r.game.OpponentLost() {
+				text.Draw(screen, "(LOST)", r.font, PreviewX+5, PreviewY+175, color.RGBA{255, 0, 0, 255}) // Red
+			}
 		}
 
 		// Show target score if someone lost
-		if (r.game.LocalPlayerLost || r.game.OpponentLost) && r.game.LoserScore > 0 {
+		if r.showBeatScore() {
 			text.Draw(screen, "Beat Score:", r.font, PreviewX-5, PreviewY+190, color.RGBA{255, 215, 0, 255}) // Gold
 			text.Draw(screen, fmt.Sprintf("%d", r.game.LoserScore), r.font, PreviewX+5, PreviewY+205, color.RGBA{255, 215, 0, 255})
 		}
@@ -642,8 +794,8 @@ func (r *Renderer) drawGameStats(screen *ebiten.Image) {
 	scoreY := PreviewY + 100 // Start right after preview area for single player
 	if r.game.MultiplayerMode {
 		scoreY = PreviewY + 220 // Adjust for player status and target score
-		if (r.game.LocalPlayerLost || r.game.OpponentLost) && r.game.LoserScore > 0 {
-			scoreY = PreviewY + 240 // Extra space for target score
+		if r.showBeatScore() || r.game.IsBattleRoyale() {
+			scoreY = PreviewY + 240 // Extra space for target score or targeting
 		}
 	}
 	text.Draw(screen, "Score:", r.font, PreviewX-5, scoreY, color.White)                                // nolint:staticcheck // Using deprecated API for compatibility
@@ -674,6 +826,23 @@ func (r *Renderer) drawGameStats(screen *ebiten.Image) {
 		text.Draw(screen, "High Score:", r.font, PreviewX-5, linesY+80, color.RGBA{255, 215, 0, 255}) // Gold
 		text.Draw(screen, fmt.Sprintf("%d", r.game.LocalHighScore), r.font, PreviewX+5, linesY+100, color.RGBA{255, 215, 0, 255})
 	}
+
+	// Draw connection latency in multiplayer
+	if r.game.MultiplayerMode {
+		if ping := r.game.GetPing(); ping > 0 {
+			pingColor := color.RGBA{0, 255, 0, 255} // Green
+			if ping > 150*time.Millisecond {
+				pingColor = color.RGBA{255, 100, 100, 255} // Light red
+			}
+			text.Draw(screen, "Ping:", r.font, PreviewX-5, linesY+80, color.White)                                  // nolint:staticcheck // Using deprecated API for compatibility
+			text.Draw(screen, fmt.Sprintf("%d ms", ping.Milliseconds()), r.font, PreviewX+5, linesY+100, pingColor) // nolint:staticcheck // Using deprecated API for compatibility
+		}
+	}
+}
+
+// showBeatScore reports whether a duel survivor has a score to beat
+func (r *Renderer) showBeatScore() bool {
+	return !r.game.IsBattleRoyale() && (r.game.LocalPlayerLost || r.game.OpponentLost()) && r.game.LoserScore > 0
 }
 
 // drawPauseOverlay draws the pause screen overlay
@@ -701,6 +870,30 @@ func (r *Renderer) drawPauseOverlay(screen *ebiten.Image) {
 	text.Draw(screen, msg, r.font, x, y, color.White) // nolint:staticcheck // Using deprecated API for compatibility
 }
 
+// drawReconnectingOverlay draws the overlay shown while a connection is restored
+func (r *Renderer) drawReconnectingOverlay(screen *ebiten.Image) {
+	// Semi-transparent overlay
+	vector.DrawFilledRect(
+		screen,
+		0,
+		0,
+		float32(ScreenWidth),
+		float32(ScreenHeight),
+		color.RGBA{0, 0, 0, 128},
+		false,
+	)
+
+	msg := "RECONNECTING"
+	x := (ScreenWidth - len(msg)*7) / 2
+	y := ScreenHeight/2 - 10
+	text.Draw(screen, msg, r.font, x, y, color.White) // nolint:staticcheck // Using deprecated API for compatibility
+
+	msg = r.game.PauseReason
+	x = (ScreenWidth - len(msg)*7) / 2
+	y += 30
+	text.Draw(screen, msg, r.font, x, y, color.RGBA{255, 215, 0, 255}) // Gold
+}
+
 // drawGameOverOverlay draws the game over screen overlay
 func (r *Renderer) drawGameOverOverlay(screen *ebiten.Image) {
 	// Semi-transparent overlay
@@ -718,12 +911,17 @@ func (r *Renderer) drawGameOverOverlay(screen *ebiten.Image) {
 	y := ScreenHeight/2 - 60
 
 	// Show different messages based on multiplayer state
-	if r.game.MultiplayerMode {
-		if r.game.LocalPlayerLost && !r.game.OpponentLost {
+	if r.game.MultiplayerMode && r.game.IsBattleRoyale() {
+		msg = "YOU WON!"
+		if r.game.Placement > 1 {
+			msg = fmt.Sprintf("YOU PLACED #%d OF %d", r.game.Placement, len(r.game.Opponents)+1)
+		}
+	} else if r.game.MultiplayerMode {
+		if r.game.LocalPlayerLost && !r.game.OpponentLost() {
 			msg = "YOU LOST"
-		} else if r.game.OpponentLost && !r.game.LocalPlayerLost {
+		} else if r.game.OpponentLost() && !r.game.LocalPlayerLost {
 			msg = "YOU WON!"
-		} else if r.game.LocalPlayerLost && r.game.OpponentLost {
+		} else if r.game.LocalPlayerLost && r.game.OpponentLost() {
 			msg = "BOTH LOST"
 		} else {
 			// Check if we won by opponent disconnect (no loss flags set but game over)
@@ -742,8 +940,24 @@ func (r *Renderer) drawGameOverOverlay(screen *ebiten.Image) {
 	y += 30
 	text.Draw(screen, msg, r.font, x, y, color.White) // nolint:staticcheck // Using deprecated API for compatibility
 
+	// Series score
+	if r.game.MultiplayerMode && r.game.SeriesBestOf > 0 {
+		label := "Series"
+		if r.game.SeriesOver && r.game.SeriesWon {
+			label = "SERIES WON"
+		} else if r.game.SeriesOver {
+			label = "SERIES LOST"
+		}
+		msg = fmt.Sprintf("%s: %d-%d (Best of %d)", label, r.game.SeriesWins, r.game.SeriesOpponentWins, r.game.SeriesBestOf)
+		x = (ScreenWidth - len(msg)*7) / 2
+		y += 30
+		text.Draw(screen, msg, r.font, x, y, color.RGBA{255, 215, 0, 255}) // nolint:staticcheck // Using deprecated API for compatibility
+	}
+
 	// Restart instructions
-	if r.game.MultiplayerMode {
+	if r.game.MultiplayerMode && r.game.SeriesInProgress() {
+		msg = "Next game starting soon..."
+	} else if r.game.MultiplayerMode {
 		msg = "Press ENTER for rematch"
 	} else {
 		msg = "Press ENTER to play again"
@@ -832,6 +1046,107 @@ func (r *Renderer) drawHighScores(screen *ebiten.Image) {
 
 	// Back instruction
 	msg = "Press ESC to return to menu"
+	if len(r.game.Leaderboard) > 0 {
+		msg = "1-8 to view a profile | ESC to return to menu"
+	}
+	x = (ScreenWidth - len(msg)*7) / 2
+	y = ScreenHeight - 40
+	text.Draw(screen, msg, r.font, x, y, color.White)
+}
+
+// drawProfile draws a player's stats, recent form and recent games
+func (r *Renderer) drawProfile(screen *ebiten.Image) {
+	profile := r.game.Profile
+	if profile == nil {
+		msg := r.game.ProfileStatus
+		x := (ScreenWidth - len(msg)*7) / 2
+		text.Draw(screen, msg, r.font, x, ScreenHeight/2, color.RGBA{128, 128, 128, 255}) // Gray
+
+		msg = "ESC to back"
+		x = (ScreenWidth - len(msg)*7) / 2
+		text.Draw(screen, msg, r.font, x, ScreenHeight-40, color.White)
+		return
+	}
+
+	// Title
+	msg := strings.ToUpper(profile.Username)
+	x := (ScreenWidth - len(msg)*7) / 2
+	y := 40
+	text.Draw(screen, msg, r.font, x, y, color.White)
+
+	// Stats
+	lines := []string{
+		fmt.Sprintf("Rating: %d (+/-%d)   High Score: %d", profile.Rating, profile.RatingDeviation, profile.HighScore),
+		fmt.Sprintf("Games: %d   W/L/D: %d/%d/%d   Win Rate: %.0f%%",
+			profile.TotalGames, profile.Wins, profile.Losses, profile.Draws, profile.WinRate*100),
+	}
+	if profile.SeriesPlayed > 0 {
+		lines = append(lines, fmt.Sprintf("Series: %d won of %d", profile.SeriesWins, profile.SeriesPlayed))
+	}
+	if profile.RoyaleGames > 0 {
+		lines = append(lines, fmt.Sprintf("Battle Royale: %d games, average place %.1f", profile.RoyaleGames, profile.AveragePlacement))
+	}
+	y += 20
+	for _, line := range lines {
+		y += 18
+		x = (ScreenWidth - len(line)*7) / 2
+		text.Draw(screen, line, r.font, x, y, color.White)
+	}
+
+	// Recent form, newest first
+	if len(profile.RecentForm) > 0 {
+		form := make([]string, len(profile.RecentForm))
+		for i, result := range profile.RecentForm {
+			form[i] = strings.ToUpper(result[:1])
+		}
+		msg = "Form: " + strings.Join(form, " ")
+		x = (ScreenWidth - len(msg)*7) / 2
+		y += 18
+		text.Draw(screen, msg, r.font, x, y, color.RGBA{255, 215, 0, 255}) // Gold
+	}
+
+	// Their record against us
+	if h2h := r.game.ProfileHeadToHead; h2h != nil {
+		msg = fmt.Sprintf("Against you: %d W / %d L / %d D", h2h.Wins, h2h.Losses, h2h.Draws)
+		x = (ScreenWidth - len(msg)*7) / 2
+		y += 24
+		text.Draw(screen, msg, r.font, x, y, color.RGBA{0, 255, 255, 255}) // Cyan
+	}
+
+	// Recent games
+	y += 30
+	msg = "Recent Games:"
+	x = (ScreenWidth - len(msg)*7) / 2
+	text.Draw(screen, msg, r.font, x, y, color.White)
+	if len(r.game.ProfileGames) == 0 {
+		msg = "No games played yet"
+		x = (ScreenWidth - len(msg)*7) / 2
+		y += 18
+		text.Draw(screen, msg, r.font, x, y, color.RGBA{128, 128, 128, 255}) // Gray
+	}
+	for _, game := range r.game.ProfileGames {
+		opponents := make([]string, len(game.Opponents))
+		for i, opponent := range game.Opponents {
+			opponents[i] = opponent.Username
+		}
+		msg = fmt.Sprintf("%-4s %6d  vs %s", strings.ToUpper(game.Result), game.Score, strings.Join(opponents, ", "))
+		if len(msg) > 60 {
+			msg = msg[:57] + "..."
+		}
+
+		resultColor := color.RGBA{128, 128, 128, 255} // Gray for draws
+		switch game.Result {
+		case "win":
+			resultColor = color.RGBA{0, 255, 0, 255} // Green
+		case "loss":
+			resultColor = color.RGBA{255, 0, 0, 255} // Red
+		}
+		x = (ScreenWidth - 60*7) / 2
+		y += 18
+		text.Draw(screen, msg, r.font, x, y, resultColor)
+	}
+
+	msg = "ESC to back"
 	x = (ScreenWidth - len(msg)*7) / 2
 	y = ScreenHeight - 40
 	text.Draw(screen, msg, r.font, x, y, color.White)
@@ -840,6 +1155,9 @@ func (r *Renderer) drawHighScores(screen *ebiten.Image) {
 // drawMultiplayerSetup draws the multiplayer setup screen
 func (r *Renderer) drawMultiplayerSetup(screen *ebiten.Image) {
 	msg := "MULTIPLAYER SETUP"
+	if r.game.SpectateMode {
+		msg = "WATCH LIVE"
+	}
 	x := (ScreenWidth - len(msg)*7) / 2
 	y := ScreenHeight / 4
 	text.Draw(screen, msg, r.font, x, y, color.White) // nolint:staticcheck // Using deprecated API for compatibility
@@ -851,9 +1169,7 @@ func (r *Renderer) drawMultiplayerSetup(screen *ebiten.Image) {
 
 	// Show username input with cursor
 	username := r.game.UsernameInput
-	if username == "" {
-		username = "_"
-	} else {
+	if !r.game.PasswordFocused {
 		username += "_" // Show cursor
 	}
 	x = (ScreenWidth - len(username)*7) / 2
@@ -866,24 +1182,41 @@ func (r *Renderer) drawMultiplayerSetup(screen *ebiten.Image) {
 	y = ScreenHeight/2 + 10
 	text.Draw(screen, charCount, r.font, x, y, color.RGBA{128, 128, 128, 255}) // Gray text
 
+	// Password is optional; leaving it empty plays as a guest
+	msg = "Password (blank for guest):"
+	x = (ScreenWidth - len(msg)*7) / 2
+	y = ScreenHeight/2 + 35
+	text.Draw(screen, msg, r.font, x, y, color.White) // nolint:staticcheck // Using deprecated API for compatibility
+
+	password := strings.Repeat("*", len(r.game.PasswordInput))
+	if r.game.PasswordFocused {
+		password += "_" // Show cursor
+	}
+	x = (ScreenWidth - len(password)*7) / 2
+	y = ScreenHeight/2 + 55
+	text.Draw(screen, password, r.font, x, y, color.RGBA{255, 255, 0, 255}) // Yellow text
+
 	// Show connection status if any
 	if r.game.ConnectionStatus != "" {
 		status := r.game.ConnectionStatus
 		x = (ScreenWidth - len(status)*7) / 2
-		y = ScreenHeight/2 + 30
+		y = ScreenHeight/2 + 80
 		statusColor := color.RGBA{255, 255, 255, 255} // White
 		if status == "Connection error occurred" ||
 			status == "Username must be at least 2 characters" ||
 			status == "Username too long (max 12 characters)" ||
-			strings.Contains(status, "already in use") {
+			status == "Password must be at least 8 characters" ||
+			strings.Contains(status, "already in use") ||
+			strings.Contains(status, "registered") ||
+			strings.Contains(status, "Invalid username or password") {
 			statusColor = color.RGBA{255, 0, 0, 255} // Red for errors
 		}
 		text.Draw(screen, status, r.font, x, y, statusColor) // nolint:staticcheck // Using deprecated API for compatibility
 	}
 
-	msg = "ENTER to connect | ESC to back"
+	msg = "TAB to switch field | ENTER to connect | F2 to register | ESC to back"
 	x = (ScreenWidth - len(msg)*7) / 2
-	y = ScreenHeight/2 + 60
+	y = ScreenHeight/2 + 110
 	text.Draw(screen, msg, r.font, x, y, color.White) // nolint:staticcheck // Using deprecated API for compatibility
 }
 
@@ -912,3 +1245,114 @@ func (r *Renderer) drawMatchmaking(screen *ebiten.Image) {
 	y = ScreenHeight/2 + 40
 	text.Draw(screen, msg, r.font, x, y, color.White) // nolint:staticcheck // Using deprecated API for compatibility
 }
+
+// drawSpectateLobby draws the list of live games that can be watched
+func (r *Renderer) drawSpectateLobby(screen *ebiten.Image) {
+	msg := "LIVE GAMES"
+	x := (ScreenWidth - len(msg)*7) / 2
+	y := 40
+	text.Draw(screen, msg, r.font, x, y, color.White) // nolint:staticcheck // Using deprecated API for compatibility
+
+	y += 40
+	switch {
+	case r.game.LiveGames == nil:
+		msg = "Loading live games..."
+		x = (ScreenWidth - len(msg)*7) / 2
+		text.Draw(screen, msg, r.font, x, y, color.RGBA{128, 128, 128, 255}) // Gray
+	case len(r.game.LiveGames) == 0:
+		msg = "No games in progress"
+		x = (ScreenWidth - len(msg)*7) / 2
+		text.Draw(screen, msg, r.font, x, y, color.RGBA{128, 128, 128, 255}) // Gray
+	default:
+		for i, game := range r.game.LiveGames {
+			if i >= 9 { // One per number key
+				break
+			}
+			msg = fmt.Sprintf("%d. %s", i+1, strings.Join(game.Players, " vs "))
+			if game.BestOf > 1 {
+				msg += fmt.Sprintf(" (best of %d)", game.BestOf)
+			}
+			if len(msg) > 80 {
+				msg = msg[:77] + "..."
+			}
+			x = (ScreenWidth - len(msg)*7) / 2
+			text.Draw(screen, msg, r.font, x, y, color.White) // nolint:staticcheck // Using deprecated API for compatibility
+			y += 20
+		}
+	}
+
+	if r.game.ConnectionStatus != "" {
+		status := r.game.ConnectionStatus
+		x = (ScreenWidth - len(status)*7) / 2
+		y = ScreenHeight - 70
+		text.Draw(screen, status, r.font, x, y, color.RGBA{255, 255, 0, 255}) // Yellow text
+	}
+
+	msg = "1-9 to watch | R to refresh | ESC to back"
+	x = (ScreenWidth - len(msg)*7) / 2
+	y = ScreenHeight - 40
+	text.Draw(screen, msg, r.font, x, y, color.White) // nolint:staticcheck // Using deprecated API for compatibility
+}
+
+// drawSpectating draws every watched player's board side by side, scaled to
+// fit the screen
+func (r *Renderer) drawSpectating(screen *ebiten.Image) {
+	const (
+		gap       = 20
+		boardTop  = 70
+		maxHeight = ScreenHeight - boardTop - 40
+	)
+
+	opponents := r.game.Opponents
+	if len(opponents) == 0 {
+		return
+	}
+
+	cellSize := (ScreenWidth - 40 - gap*(len(opponents)-1)) / (len(opponents) * tetris.BoardWidth)
+	cellSize = min(cellSize, maxHeight/tetris.BoardHeight)
+
+	boardWidth := tetris.BoardWidth * cellSize
+	totalWidth := len(opponents)*boardWidth + (len(opponents)-1)*gap
+	startX := (ScreenWidth - totalWidth) / 2
+
+	// Title, then the result once the game is decided
+	msg := "LIVE"
+	titleColor := color.RGBA{255, 0, 0, 255}
+	if r.game.SpectatedWinner != "" {
+		msg = "WINNER: " + r.game.SpectatedWinner
+		titleColor = color.RGBA{255, 215, 0, 255} // Gold
+	}
+	x := (ScreenWidth - len(msg)*7) / 2
+	text.Draw(screen, msg, r.font, x, 24, titleColor) // nolint:staticcheck // Using deprecated API for compatibility
+
+	for i, opponent := range opponents {
+		boardX := startX + i*(boardWidth+gap)
+
+		nameColor := color.RGBA{255, 255, 255, 255}
+		if opponent.Lost {
+			nameColor = color.RGBA{128, 128, 128, 255} // Gray if lost
+		}
+		name := opponent.Name
+		if maxChars := boardWidth / 7; len(name) > maxChars {
+			name = name[:maxChars]
+		}
+		text.Draw(screen, name, r.font, boardX, boardTop-24, nameColor) // nolint:staticcheck // Using deprecated API for compatibility
+
+		score := fmt.Sprintf("%d", opponent.Score)
+		if boardWidth >= 140 {
+			score = fmt.Sprintf("Score %d  Lines %d", opponent.Score, opponent.Lines)
+		}
+		text.Draw(screen, score, r.font, boardX, boardTop-8, color.RGBA{200, 200, 200, 255}) // nolint:staticcheck // Using deprecated API for compatibility
+
+		r.drawBoardCells(screen, opponent, boardX, boardTop, cellSize)
+
+		if opponent.Placement > 0 {
+			placement := fmt.Sprintf("#%d", opponent.Placement)
+			text.Draw(screen, placement, r.font, boardX+4, boardTop+16, color.RGBA{255, 215, 0, 255}) // nolint:staticcheck // Using deprecated API for compatibility
+		}
+	}
+
+	msg = "ESC to stop watching"
+	x = (ScreenWidth - len(msg)*7) / 2
+	text.Draw(screen, msg, r.font, x, ScreenHeight-16, color.White) // nolint:staticcheck // Using deprecated API for compatibility
+}
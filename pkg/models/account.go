@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Account is a registered player's credentials. It owns a Player record that,
+// unlike a guest's, outlives the session so stats and ratings carry over
+// between logins.
+type Account struct {
+	Username     string    `json:"username"`
+	PlayerID     string    `json:"playerId"`
+	PasswordHash string    `json:"passwordHash"`         // bcrypt
+	APIKeyHash   string    `json:"apiKeyHash,omitempty"` // SHA-256 of the current API key, if one was issued
+	CreatedAt    time.Time `json:"createdAt"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// Ban stops a username from logging in, whether it belongs to a registered
+// account or is picked by a guest
+type Ban struct {
+	Username string    `json:"username"`
+	Reason   string    `json:"reason,omitempty"`
+	BannedAt time.Time `json:"bannedAt"`
+}
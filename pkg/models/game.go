@@ -2,20 +2,115 @@ package models
 
 import "time"
 
-// GameSession represents an active game between two players
+// GameSession represents an active game between two or more players
 type GameSession struct {
-	ID                string     `json:"id"`
-	Player1           *Player    `json:"player1"`
-	Player2           *Player    `json:"player2"`
-	Player1Lost       bool       `json:"player1Lost"`
-	Player2Lost       bool       `json:"player2Lost"`
-	Player1Score      int        `json:"player1Score"`
-	Player2Score      int        `json:"player2Score"`
-	Player1RematchReq bool       `json:"player1RematchReq"`
-	Player2RematchReq bool       `json:"player2RematchReq"`
-	Seed              int64      `json:"seed"`
-	Status            GameStatus `json:"status"`
-	CreatedAt         time.Time  `json:"createdAt"`
+	ID         string            `json:"id"`
+	Players    []*SessionPlayer  `json:"players"` // In seat order; two for a duel, up to eight for a battle royale
+	Seed       int64             `json:"seed"`
+	Queue      string            `json:"queue,omitempty"` // Queue the match was made from
+	Mode       string            `json:"mode,omitempty"`
+	Ranked     bool              `json:"ranked"`
+	RoomCode   string            `json:"roomCode,omitempty"` // Private room the match was made from
+	Garbage    bool              `json:"garbage"`
+	Targeting  TargetingStrategy `json:"targeting,omitempty"` // Default garbage targeting for every seat
+	BestOf     int               `json:"bestOf,omitempty"`
+	SeriesID   string            `json:"seriesId,omitempty"`   // Match series this game belongs to, if any
+	Spectators []string          `json:"spectators,omitempty"` // IDs of players watching the game
+	Status     GameStatus        `json:"status"`
+	Cancelled  bool              `json:"cancelled,omitempty"` // Ended by an operator without a result
+	CreatedAt  time.Time         `json:"createdAt"`
+	EndedAt    time.Time         `json:"endedAt,omitempty"` // Set once the game is finished
+}
+
+// SessionPlayer is one player's seat in a game
+type SessionPlayer struct {
+	Player       *Player           `json:"player"`
+	Score        int               `json:"score"`
+	Lines        int               `json:"lines"` // Lines cleared so far, used to spot new clears
+	StackHeight  int               `json:"stackHeight"`
+	Lost         bool              `json:"lost"`
+	Placement    int               `json:"placement,omitempty"` // 1 for the winner; zero until decided
+	RematchReq   bool              `json:"rematchReq"`
+	Disconnected bool              `json:"disconnected,omitempty"` // Set while inside the reconnect grace window
+	Targeting    TargetingStrategy `json:"targeting,omitempty"`
+	TargetID     string            `json:"targetId,omitempty"`   // Who this player's garbage last went to
+	AttackerID   string            `json:"attackerId,omitempty"` // Who last sent garbage here; credited with the KO
+	KOs          int               `json:"kos"`
+	Badges       int               `json:"badges"` // Earned from KOs, including the victim's badges
+}
+
+// TargetingStrategy picks which opponents receive a player's garbage
+type TargetingStrategy string
+
+const (
+	TargetRandom    TargetingStrategy = "random"
+	TargetAttackers TargetingStrategy = "attackers" // Whoever is attacking you
+	TargetKOs       TargetingStrategy = "kos"       // Whoever is closest to topping out
+	TargetBadges    TargetingStrategy = "badges"    // Whoever holds the most badges
+)
+
+// Valid reports whether the strategy is one the server knows
+func (t TargetingStrategy) Valid() bool {
+	switch t {
+	case TargetRandom, TargetAttackers, TargetKOs, TargetBadges:
+		return true
+	}
+	return false
+}
+
+// NewSeats gives each player an empty seat, in order
+func NewSeats(players ...*Player) []*SessionPlayer {
+	seats := make([]*SessionPlayer, len(players))
+	for i, player := range players {
+		seats[i] = &SessionPlayer{Player: player}
+	}
+	return seats
+}
+
+// Seat returns a player's seat, or nil if they aren't in the game
+func (g *GameSession) Seat(playerID string) *SessionPlayer {
+	for _, seat := range g.Players {
+		if seat.Player.ID == playerID {
+			return seat
+		}
+	}
+	return nil
+}
+
+// PlayerIDs returns the IDs of every player in seat order
+func (g *GameSession) PlayerIDs() []string {
+	ids := make([]string, len(g.Players))
+	for i, seat := range g.Players {
+		ids[i] = seat.Player.ID
+	}
+	return ids
+}
+
+// Opponents returns every seat except the player's own
+func (g *GameSession) Opponents(playerID string) []*SessionPlayer {
+	opponents := make([]*SessionPlayer, 0, len(g.Players))
+	for _, seat := range g.Players {
+		if seat.Player.ID != playerID {
+			opponents = append(opponents, seat)
+		}
+	}
+	return opponents
+}
+
+// Alive returns the seats that haven't topped out
+func (g *GameSession) Alive() []*SessionPlayer {
+	alive := make([]*SessionPlayer, 0, len(g.Players))
+	for _, seat := range g.Players {
+		if !seat.Lost {
+			alive = append(alive, seat)
+		}
+	}
+	return alive
+}
+
+// IsDuel reports whether this is a two-player game
+func (g *GameSession) IsDuel() bool {
+	return len(g.Players) == 2
 }
 
 // GameStatus represents the current state of a game
@@ -24,6 +119,7 @@ type GameStatus string
 const (
 	GameStatusWaiting  GameStatus = "waiting"
 	GameStatusActive   GameStatus = "active"
+	GameStatusPaused   GameStatus = "paused" // A player dropped and may still reconnect
 	GameStatusFinished GameStatus = "finished"
 )
 
@@ -31,7 +127,8 @@ const (
 type GameMove struct {
 	PlayerID  string    `json:"playerId"`
 	GameID    string    `json:"gameId"`
-	MoveType  string    `json:"moveType"` // "left", "right", "rotate", "drop", "hold"
+	MoveType  string    `json:"moveType"` // "left", "right", "rotate", "down", "soft_drop", "hard_drop"
+	Piece     *Piece    `json:"piece,omitempty"`
 	Timestamp time.Time `json:"timestamp"`
 }
 
@@ -44,6 +141,8 @@ type GameState struct {
 	Level        int       `json:"level"`
 	Lines        int       `json:"lines"`
 	CurrentPiece *Piece    `json:"currentPiece,omitempty"`
+	HoldPiece    *Piece    `json:"holdPiece,omitempty"`
+	NextPiece    *Piece    `json:"nextPiece,omitempty"`
 	Timestamp    time.Time `json:"timestamp"`
 }
 
@@ -53,5 +152,5 @@ type Piece struct {
 	X        int      `json:"x"`
 	Y        int      `json:"y"`
 	Rotation int      `json:"rotation"`
-	Shape    [][]bool `json:"shape"`
+	Shape    [][]bool `json:"shape,omitempty"`
 }
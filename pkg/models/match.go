@@ -0,0 +1,109 @@
+package models
+
+import "time"
+
+// MatchRecord is a finished game as kept in match history, long after its
+// game session has expired
+type MatchRecord struct {
+	GameID    string         `json:"gameId"`
+	Mode      string         `json:"mode,omitempty"`
+	Ranked    bool           `json:"ranked"`
+	SeriesID  string         `json:"seriesId,omitempty"`
+	Players   []*MatchPlayer `json:"players"` // In seat order
+	StartedAt time.Time      `json:"startedAt"`
+	EndedAt   time.Time      `json:"endedAt"`
+}
+
+// MatchPlayer is one seat's result in a match record
+type MatchPlayer struct {
+	ID        string `json:"id"`
+	Username  string `json:"username"`
+	Score     int    `json:"score"`
+	Placement int    `json:"placement,omitempty"` // 1 for the winner; zero for a draw
+}
+
+// NewMatchRecord summarizes a finished game's result
+func NewMatchRecord(game *GameSession) *MatchRecord {
+	players := make([]*MatchPlayer, len(game.Players))
+	for i, seat := range game.Players {
+		players[i] = &MatchPlayer{
+			ID:        seat.Player.ID,
+			Username:  seat.Player.Username,
+			Score:     seat.Score,
+			Placement: seat.Placement,
+		}
+	}
+
+	return &MatchRecord{
+		GameID:    game.ID,
+		Mode:      game.Mode,
+		Ranked:    game.Ranked,
+		SeriesID:  game.SeriesID,
+		Players:   players,
+		StartedAt: game.CreatedAt,
+		EndedAt:   game.EndedAt,
+	}
+}
+
+// Player returns a player's seat in the match, or nil if they didn't play
+func (m *MatchRecord) Player(playerID string) *MatchPlayer {
+	for _, player := range m.Players {
+		if player.ID == playerID {
+			return player
+		}
+	}
+	return nil
+}
+
+// Opponents returns every seat other than the player's
+func (m *MatchRecord) Opponents(playerID string) []*MatchPlayer {
+	opponents := make([]*MatchPlayer, 0, len(m.Players)-1)
+	for _, player := range m.Players {
+		if player.ID != playerID {
+			opponents = append(opponents, player)
+		}
+	}
+	return opponents
+}
+
+// Outcome returns whether a player won, lost or drew the match. Drawn
+// matches have no placements.
+func (m *MatchRecord) Outcome(playerID string) MatchOutcome {
+	player := m.Player(playerID)
+	switch {
+	case player == nil || player.Placement == 0:
+		return MatchDraw
+	case player.Placement == 1:
+		return MatchWin
+	default:
+		return MatchLoss
+	}
+}
+
+// MatchOutcome is a match's result from one player's point of view
+type MatchOutcome string
+
+const (
+	MatchWin  MatchOutcome = "win"
+	MatchLoss MatchOutcome = "loss"
+	MatchDraw MatchOutcome = "draw"
+)
+
+// HeadToHead is a player's record against one opponent
+type HeadToHead struct {
+	Wins   int `json:"wins"`
+	Losses int `json:"losses"`
+	Draws  int `json:"draws"`
+}
+
+// Add counts an outcome in the record
+func (h *HeadToHead) Add(outcome MatchOutcome) {
+	switch outcome {
+	case MatchWin:
+		h.Wins++
+	case MatchLoss:
+		h.Losses++
+	default:
+		h.Draws++
+	}
+}
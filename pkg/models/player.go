@@ -10,10 +10,35 @@ type Player struct {
 	ConnectedAt  time.Time `json:"connectedAt"`
 	LastActivity time.Time `json:"lastActivity"`
 	InQueue      bool      `json:"inQueue"`
+	QueuedAt     time.Time `json:"queuedAt"`
+	Queue        string    `json:"queue,omitempty"` // Key of the queue the player is waiting in
 	GameID       string    `json:"gameId,omitempty"`
+	Registered   bool      `json:"registered"` // Owned by an Account rather than a guest session
 	// Stats
-	TotalGames int `json:"totalGames"`
-	Wins       int `json:"wins"`
-	Losses     int `json:"losses"`
-	HighScore  int `json:"highScore"`
+	TotalGames   int    `json:"totalGames"`
+	Wins         int    `json:"wins"`
+	Losses       int    `json:"losses"`
+	HighScore    int    `json:"highScore"`
+	Rating       Rating `json:"rating"`
+	SeriesPlayed int    `json:"seriesPlayed"`
+	SeriesWins   int    `json:"seriesWins"`
+	// Battle royale placements; wins and losses above count them too
+	RoyaleGames    int `json:"royaleGames"`
+	PlacementTotal int `json:"placementTotal"` // Sum of placements, for the average
+}
+
+// AveragePlacement returns the player's mean battle royale placement, or zero
+// if they haven't played one
+func (p *Player) AveragePlacement() float64 {
+	if p.RoyaleGames == 0 {
+		return 0
+	}
+	return float64(p.PlacementTotal) / float64(p.RoyaleGames)
+}
+
+// Rating is a Glicko-2 skill rating. A zero Rating means the player is unrated.
+type Rating struct {
+	Value      float64 `json:"value"`
+	Deviation  float64 `json:"deviation"`
+	Volatility float64 `json:"volatility"`
 }
@@ -0,0 +1,26 @@
+package models
+
+// Matchmaking queues
+const (
+	QueueRanked = "ranked" // Results update player ratings
+	QueueCasual = "casual" // Results only count towards stats
+
+	DefaultGameMode = "classic"
+)
+
+// QueueOptions selects the matchmaking queue a player joins. Players are only
+// matched with others who chose the same queue and mode.
+type QueueOptions struct {
+	Queue string `json:"queue"`
+	Mode  string `json:"mode"` // Game mode or ruleset, passed through to clients
+}
+
+// Key identifies the queue these options select
+func (o QueueOptions) Key() string {
+	return o.Queue + ":" + o.Mode
+}
+
+// Ranked reports whether games from this queue affect ratings
+func (o QueueOptions) Ranked() bool {
+	return o.Queue == QueueRanked
+}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// Replay is the recorded event log of a game, kept so finished games can be
+// played back and results checked
+type Replay struct {
+	GameID    string          `json:"gameId"`
+	Seed      int64           `json:"seed"` // Replays the same piece sequence for every player
+	Mode      string          `json:"mode,omitempty"`
+	Ranked    bool            `json:"ranked"`
+	SeriesID  string          `json:"seriesId,omitempty"`
+	Players   []*ReplayPlayer `json:"players"` // In seat order
+	Events    []*ReplayEvent  `json:"events"`  // In the order the server received them
+	WinnerID  string          `json:"winnerId,omitempty"`
+	Finished  bool            `json:"finished"`
+	Truncated bool            `json:"truncated,omitempty"` // Events past the size limit were dropped
+	StartedAt time.Time       `json:"startedAt"`
+	EndedAt   time.Time       `json:"endedAt,omitempty"`
+}
+
+// ReplayPlayer is one seat in a replay, with its result once the game finishes
+type ReplayPlayer struct {
+	ID        string `json:"id"`
+	Username  string `json:"username"`
+	Score     int    `json:"score"`
+	Placement int    `json:"placement,omitempty"`
+	KOs       int    `json:"kos"`
+}
+
+// Replay event types
+const (
+	ReplayEventMove  = "move"
+	ReplayEventState = "state"
+	ReplayEventLost  = "player_lost"
+)
+
+// ReplayEvent is a single move, state update or elimination in a replay
+type ReplayEvent struct {
+	Type      string     `json:"type"`
+	PlayerID  string     `json:"playerId"`
+	At        time.Time  `json:"at"` // When the server received it
+	Move      *GameMove  `json:"move,omitempty"`
+	State     *GameState `json:"state,omitempty"`
+	Placement int        `json:"placement,omitempty"` // Set for eliminations
+}
+
+// NewReplay starts an empty replay of a game's seats
+func NewReplay(game *GameSession) *Replay {
+	players := make([]*ReplayPlayer, len(game.Players))
+	for i, seat := range game.Players {
+		players[i] = &ReplayPlayer{
+			ID:       seat.Player.ID,
+			Username: seat.Player.Username,
+		}
+	}
+
+	return &Replay{
+		GameID:    game.ID,
+		Seed:      game.Seed,
+		Mode:      game.Mode,
+		Ranked:    game.Ranked,
+		SeriesID:  game.SeriesID,
+		Players:   players,
+		StartedAt: time.Now(),
+	}
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Room is a private lobby that a host shares by code. A duel starts as soon
+// as a guest joins; a battle royale starts when the room fills or the host
+// starts it.
+type Room struct {
+	Code      string       `json:"code"`
+	HostID    string       `json:"hostId"`
+	GuestIDs  []string     `json:"guestIds,omitempty"` // In join order
+	Settings  RoomSettings `json:"settings"`
+	CreatedAt time.Time    `json:"createdAt"`
+}
+
+// RoomSettings are chosen by the host when creating a room
+type RoomSettings struct {
+	Mode       string            `json:"mode"`
+	Seed       int64             `json:"seed,omitempty"` // Zero picks a random seed
+	Garbage    bool              `json:"garbage"`        // Whether cleared lines send garbage to opponents
+	BestOf     int               `json:"bestOf"`         // Games in the series; first to a majority wins
+	MaxPlayers int               `json:"maxPlayers"`     // Seats including the host
+	Targeting  TargetingStrategy `json:"targeting,omitempty"`
+}
+
+// PlayerIDs returns the host followed by the guests
+func (r *Room) PlayerIDs() []string {
+	return append([]string{r.HostID}, r.GuestIDs...)
+}
+
+// Full reports whether every seat is taken
+func (r *Room) Full() bool {
+	return 1+len(r.GuestIDs) >= r.Settings.MaxPlayers
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// MatchSeries groups the games of a best-of-N match between two players.
+// The next game starts automatically until one player reaches TargetWins.
+type MatchSeries struct {
+	ID          string       `json:"id"`
+	Player1ID   string       `json:"player1Id"`
+	Player2ID   string       `json:"player2Id"`
+	BestOf      int          `json:"bestOf"`
+	Player1Wins int          `json:"player1Wins"`
+	Player2Wins int          `json:"player2Wins"`
+	GameIDs     []string     `json:"gameIds"` // In play order; drawn games don't count toward the score
+	WinnerID    string       `json:"winnerId,omitempty"`
+	Status      SeriesStatus `json:"status"`
+	CreatedAt   time.Time    `json:"createdAt"`
+}
+
+// SeriesStatus represents the current state of a series
+type SeriesStatus string
+
+const (
+	SeriesStatusActive   SeriesStatus = "active"
+	SeriesStatusFinished SeriesStatus = "finished"
+)
+
+// TargetWins is the number of game wins that takes the series
+func (s *MatchSeries) TargetWins() int {
+	return s.BestOf/2 + 1
+}
+
+// RecordWin adds a game win for a player and finishes the series once they
+// reach the target. It reports whether the series is over.
+func (s *MatchSeries) RecordWin(playerID string) bool {
+	switch playerID {
+	case s.Player1ID:
+		s.Player1Wins++
+		if s.Player1Wins >= s.TargetWins() {
+			s.Finish(s.Player1ID)
+		}
+	case s.Player2ID:
+		s.Player2Wins++
+		if s.Player2Wins >= s.TargetWins() {
+			s.Finish(s.Player2ID)
+		}
+	}
+	return s.Status == SeriesStatusFinished
+}
+
+// Finish ends the series with the given winner
+func (s *MatchSeries) Finish(winnerID string) {
+	s.WinnerID = winnerID
+	s.Status = SeriesStatusFinished
+}
+
+// Wins returns a player's score followed by their opponent's
+func (s *MatchSeries) Wins(playerID string) (int, int) {
+	if playerID == s.Player2ID {
+		return s.Player2Wins, s.Player1Wins
+	}
+	return s.Player1Wins, s.Player2Wins
+}
@@ -71,6 +71,12 @@ resource "aws_ecs_task_definition" "app" {
         {
           name  = "CORS_ORIGINS"
           value = "https://${aws_cloudfront_distribution.website.domain_name},http://${aws_lb.main.dns_name}"
+        },
+        {
+          # The ALB runs in the public subnets; trust its X-Forwarded-For so
+          # clients are rate limited by their own address
+          name  = "TRUSTED_PROXIES"
+          value = join(",", aws_subnet.public[*].cidr_block)
         }
       ]
 
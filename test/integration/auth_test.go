@@ -48,6 +48,42 @@ func TestRealAuthentication(t *testing.T) {
 	t.Log("✅ Real authentication test passed")
 }
 
+func TestAccountAuthentication(t *testing.T) {
+	// Start test server
+	server := startTestServer()
+	defer server.Shutdown(context.Background())
+
+	// Register an account
+	client := tetris.NewMultiplayerClient("http://localhost:8081")
+	err := client.Register("accountplayer", "correct horse")
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	// Guests can't take the registered name
+	guest := tetris.NewMultiplayerClient("http://localhost:8081")
+	if err := guest.Login("accountplayer"); err == nil {
+		t.Error("Expected guest login with a registered username to fail")
+	}
+
+	// Wrong passwords are rejected
+	other := tetris.NewMultiplayerClient("http://localhost:8081")
+	if err := other.LoginWithPassword("accountplayer", "wrong password"); err == nil {
+		t.Error("Expected login with a wrong password to fail")
+	}
+
+	// The right password logs in to the same account
+	err = other.LoginWithPassword("accountplayer", "correct horse")
+	if err != nil {
+		t.Fatalf("Password login failed: %v", err)
+	}
+	if other.GetUsername() != "accountplayer" {
+		t.Errorf("Expected username 'accountplayer', got '%s'", other.GetUsername())
+	}
+
+	t.Log("✅ Account authentication test passed")
+}
+
 func TestFullGameAuthentication(t *testing.T) {
 	// Start test server
 	server := startTestServer()
@@ -69,9 +69,12 @@ func (c *TestClient) ConnectWebSocket() error {
 	u, _ := url.Parse(c.ServerURL)
 	u.Scheme = "ws"
 	u.Path = "/ws"
-	u.RawQuery = "token=" + c.SessionToken
 
-	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	// The session token travels as a subprotocol, not in the URL
+	dialer := *websocket.DefaultDialer
+	dialer.Subprotocols = []string{"tetris", "bearer." + c.SessionToken}
+
+	conn, _, err := dialer.Dial(u.String(), nil)
 	if err != nil {
 		return err
 	}
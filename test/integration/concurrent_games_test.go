@@ -157,3 +157,120 @@ func TestConcurrentGameMoves(t *testing.T) {
 
 	t.Log("✅ Concurrent game moves completed without errors")
 }
+
+func TestConcurrentMatchesStayIndependent(t *testing.T) {
+	// Start test server
+	server := startTestServer()
+	defer server.Shutdown(context.Background())
+
+	// Log in and connect enough players for 8 simultaneous matches
+	const matches = 8
+	clients := make([]*TestClient, matches*2)
+	for i := range clients {
+		client := NewTestClient(fmt.Sprintf("racer%02d", i+1), testServerURL)
+		if err := client.Login(); err != nil {
+			t.Fatalf("Client %d login failed: %v", i+1, err)
+		}
+		if err := client.ConnectWebSocket(); err != nil {
+			t.Fatalf("Client %d WebSocket connection failed: %v", i+1, err)
+		}
+		defer client.Close()
+		clients[i] = client
+	}
+
+	// Everyone queues at once
+	var wg sync.WaitGroup
+	for i, client := range clients {
+		wg.Add(1)
+		go func(i int, c *TestClient) {
+			defer wg.Done()
+			if err := c.JoinQueue(); err != nil {
+				t.Errorf("Client %d join queue failed: %v", i+1, err)
+			}
+		}(i, client)
+	}
+	wg.Wait()
+
+	// Every player is matched into a game of exactly two
+	games := make(map[string][]*TestClient)
+	gameOf := make(map[*TestClient]string)
+	for _, client := range clients {
+		matchMsg, err := client.WaitForMessage("match_found", 5*time.Second)
+		if err != nil {
+			t.Fatalf("%s didn't receive match_found: %v", client.Username, err)
+		}
+		gameID, _ := matchMsg["gameId"].(string)
+		games[gameID] = append(games[gameID], client)
+		gameOf[client] = gameID
+	}
+	if len(games) != matches {
+		t.Fatalf("Expected %d concurrent games, got %d", matches, len(games))
+	}
+	for gameID, players := range games {
+		if len(players) != 2 {
+			t.Fatalf("Game %s has %d players, expected 2", gameID, len(players))
+		}
+	}
+
+	// All games are played out at once: both players report a state, the
+	// first tops out and the second beats their score to win
+	for gameID, players := range games {
+		wg.Add(1)
+		go func(gameID string, loser, winner *TestClient) {
+			defer wg.Done()
+
+			if err := loser.SendGameState(nil, 100, 1, 0); err != nil {
+				t.Errorf("%s failed to send state: %v", loser.Username, err)
+				return
+			}
+			if err := winner.SendGameState(nil, 50, 1, 0); err != nil {
+				t.Errorf("%s failed to send state: %v", winner.Username, err)
+				return
+			}
+			if err := loser.WSConn.WriteJSON(map[string]interface{}{"type": "game_over", "gameId": gameID}); err != nil {
+				t.Errorf("%s failed to send game over: %v", loser.Username, err)
+				return
+			}
+			if _, err := winner.WaitForMessage("player_lost", 5*time.Second); err != nil {
+				t.Errorf("%s didn't see their opponent lose: %v", winner.Username, err)
+				return
+			}
+			if err := winner.SendGameState(nil, 200, 1, 0); err != nil {
+				t.Errorf("%s failed to send state: %v", winner.Username, err)
+			}
+		}(gameID, players[0], players[1])
+	}
+	wg.Wait()
+
+	for gameID, players := range games {
+		winnerID := players[1].PlayerID
+		for _, client := range players {
+			gameOver, err := client.WaitForMessage("game_over", 5*time.Second)
+			if err != nil {
+				t.Errorf("%s didn't receive game_over: %v", client.Username, err)
+				continue
+			}
+			if gameOver["gameId"] != gameID || gameOver["winnerId"] != winnerID {
+				t.Errorf("%s expected game %s won by %s, got %v", client.Username, gameID, winnerID, gameOver)
+			}
+		}
+	}
+
+	// Nobody saw a message from another game or a state from anyone but
+	// their own opponent
+	for _, players := range games {
+		for i, client := range players {
+			opponentID := players[1-i].PlayerID
+			for _, msg := range client.GetMessages() {
+				if gameID, ok := msg["gameId"].(string); ok && gameID != gameOf[client] {
+					t.Errorf("%s in game %s received a %v message for game %s", client.Username, gameOf[client], msg["type"], gameID)
+				}
+				if msg["type"] == "game_state" && msg["playerId"] != opponentID {
+					t.Errorf("%s received a state from %v, expected only %s", client.Username, msg["playerId"], opponentID)
+				}
+			}
+		}
+	}
+
+	t.Logf("✅ Played %d simultaneous matches without crosstalk", len(games))
+}
@@ -20,12 +20,13 @@ func startTestServer() *http.Server {
 	playerStore := memory.NewPlayerStore()
 	gameStore := memory.NewGameStore()
 	queueStore := memory.NewQueueStore()
+	roomStore := memory.NewRoomStore()
 
 	// Initialize services
-	authService := services.NewAuthService(playerStore)
+	authService := services.NewAuthService(playerStore, memory.NewAccountStore())
 	wsManager := services.NewWebSocketManager()
 	gameManager := services.NewGameManager(gameStore, playerStore, wsManager)
-	matchmakingService := services.NewMatchmakingService(playerStore, gameStore, queueStore, gameManager)
+	matchmakingService := services.NewMatchmakingService(playerStore, gameStore, queueStore, roomStore, gameManager)
 
 	// Initialize middleware
 	authMiddleware := middleware.NewAuthMiddleware(authService)
@@ -38,6 +39,7 @@ func startTestServer() *http.Server {
 	// Setup routes with logging middleware
 	mux := http.NewServeMux()
 	mux.HandleFunc("/api/auth/login", middleware.RequestLogging(authHandler.Login))
+	mux.HandleFunc("/api/auth/register", middleware.RequestLogging(authHandler.Register))
 	mux.HandleFunc("/api/auth/logout", middleware.RequestLogging(authMiddleware.RequireAuth(authHandler.Logout)))
 	mux.HandleFunc("/api/matchmaking/queue", middleware.RequestLogging(authMiddleware.RequireAuth(matchmakingHandler.JoinQueue)))
 	mux.HandleFunc("/api/matchmaking/queue/leave", middleware.RequestLogging(authMiddleware.RequireAuth(matchmakingHandler.LeaveQueue)))